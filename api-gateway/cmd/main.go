@@ -10,6 +10,9 @@ import (
 
 func main() {
 	cfg := config.LoadConfig()
+	if cfg.Admin.Token == "" {
+		log.Println("Warning: ADMIN_TOKEN is not set, admin routes are disabled")
+	}
 
 	router := routes.SetupRouter(cfg)
 
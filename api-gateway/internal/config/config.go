@@ -1,5 +1,7 @@
 package config
 
+import "os"
+
 type ServerConfig struct {
 	Port string `yaml:"port"`
 }
@@ -10,9 +12,16 @@ type ServicesConfig struct {
 	User      string `yaml:"user"`
 }
 
+// AdminConfig holds the shared admin token. It has no default: without
+// ADMIN_TOKEN the admin routes stay closed.
+type AdminConfig struct {
+	Token string `yaml:"token"`
+}
+
 type Config struct {
 	Server   ServerConfig   `yaml:"server"`
 	Services ServicesConfig `yaml:"services"`
+	Admin    AdminConfig    `yaml:"admin"`
 }
 
 func LoadConfig() *Config {
@@ -25,5 +34,8 @@ func LoadConfig() *Config {
 			Order:     "localhost:50052",
 			User:      "localhost:50053",
 		},
+		Admin: AdminConfig{
+			Token: os.Getenv("ADMIN_TOKEN"),
+		},
 	}
 }
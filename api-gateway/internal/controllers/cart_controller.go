@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	cart "proto/cart"
+)
+
+// SessionHeader carries the anonymous cart session before the user logs in.
+const SessionHeader = "X-Session-ID"
+
+type CartController struct {
+	client cart.CartServiceClient
+}
+
+func NewCartController(serviceAddr string) *CartController {
+	conn, err := grpc.Dial(serviceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		panic(err)
+	}
+
+	return &CartController{
+		client: cart.NewCartServiceClient(conn),
+	}
+}
+
+func (c *CartController) GetCart(ctx *gin.Context) {
+	owner, ok := cartOwner(ctx)
+	if !ok {
+		return
+	}
+
+	res, err := c.client.GetCart(ctx, &cart.GetCartRequest{Owner: owner})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res.Cart)
+}
+
+func (c *CartController) AddItem(ctx *gin.Context) {
+	owner, ok := cartOwner(ctx)
+	if !ok {
+		return
+	}
+	var body struct {
+		ProductID string `json:"product_id" binding:"required"`
+		Quantity  int32  `json:"quantity" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := c.client.AddItem(ctx, &cart.AddItemRequest{
+		Owner:     owner,
+		ProductId: body.ProductID,
+		Quantity:  body.Quantity,
+	})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res.Cart)
+}
+
+func (c *CartController) UpdateItem(ctx *gin.Context) {
+	owner, ok := cartOwner(ctx)
+	if !ok {
+		return
+	}
+	var body struct {
+		Quantity *int32 `json:"quantity" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := c.client.UpdateItem(ctx, &cart.UpdateItemRequest{
+		Owner:     owner,
+		ProductId: ctx.Param("product_id"),
+		Quantity:  *body.Quantity,
+	})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res.Cart)
+}
+
+func (c *CartController) RemoveItem(ctx *gin.Context) {
+	owner, ok := cartOwner(ctx)
+	if !ok {
+		return
+	}
+
+	res, err := c.client.RemoveItem(ctx, &cart.RemoveItemRequest{
+		Owner:     owner,
+		ProductId: ctx.Param("product_id"),
+	})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res.Cart)
+}
+
+// MergeCart moves the anonymous cart named by the session header into the
+// signed-in user's cart. Logging in with the header merges the cart already;
+// this is for a session that was started after the user logged in.
+func (c *CartController) MergeCart(ctx *gin.Context) {
+	sessionID := ctx.GetHeader(SessionHeader)
+	if sessionID == "" {
+		RespondWithError(ctx, http.StatusBadRequest, SessionHeader+" header is required")
+		return
+	}
+
+	res, err := c.client.MergeCart(ctx, &cart.MergeCartRequest{
+		UserId:    ctx.GetString("user_id"),
+		SessionId: sessionID,
+	})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res.Cart)
+}
+
+func (c *CartController) Checkout(ctx *gin.Context) {
+	var body struct {
+		Address string `json:"address"`
+	}
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			RespondWithError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	res, err := c.client.Checkout(ctx, &cart.CheckoutRequest{
+		UserId:  ctx.GetString("user_id"),
+		Address: body.Address,
+	})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res.Order)
+}
+
+// Reorder copies a past order into the cart, or into a new order when the
+// body asks for "create_order". The response lists what changed.
+func (c *CartController) Reorder(ctx *gin.Context) {
+	var body struct {
+		CreateOrder bool `json:"create_order"`
+	}
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			RespondWithError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	res, err := c.client.Reorder(ctx, &cart.ReorderRequest{
+		OrderId:     ctx.Param("id"),
+		UserId:      ctx.GetString("user_id"),
+		CreateOrder: body.CreateOrder,
+	})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func cartOwner(ctx *gin.Context) (*cart.CartOwner, bool) {
+	owner := &cart.CartOwner{
+		UserId:    ctx.GetString("user_id"),
+		SessionId: ctx.GetHeader(SessionHeader),
+	}
+	if owner.UserId == "" && owner.SessionId == "" {
+		RespondWithError(ctx, http.StatusBadRequest, "sign in or send the "+SessionHeader+" header")
+		return nil, false
+	}
+	return owner, true
+}
@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	inventory "proto/inventory"
+)
+
+// DeadLetterController lets admins inspect events that failed on their last
+// delivery and hand them back to their consumers.
+type DeadLetterController struct {
+	client inventory.InventoryServiceClient
+}
+
+func NewDeadLetterController(serviceAddr string) *DeadLetterController {
+	conn, err := grpc.Dial(serviceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		panic(err)
+	}
+
+	return &DeadLetterController{
+		client: inventory.NewInventoryServiceClient(conn),
+	}
+}
+
+// ListDeadLetters filters by the "source_subject" query parameter.
+func (c *DeadLetterController) ListDeadLetters(ctx *gin.Context) {
+	page, limit := ParsePaginationParams(ctx)
+
+	res, err := c.client.ListDeadLetters(ctx, &inventory.ListDeadLettersRequest{
+		SourceSubject: ctx.Query("source_subject"),
+		Page:          int32(page),
+		Limit:         int32(limit),
+	})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	letters := make([]gin.H, 0, len(res.DeadLetters))
+	for _, letter := range res.DeadLetters {
+		letters = append(letters, deadLetterView(letter))
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"dead_letters": letters,
+		"total":        res.Total,
+	})
+}
+
+func (c *DeadLetterController) GetDeadLetter(ctx *gin.Context) {
+	sequence, ok := parseSequence(ctx)
+	if !ok {
+		return
+	}
+
+	res, err := c.client.GetDeadLetter(ctx, &inventory.DeadLetterID{Sequence: sequence})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if res.DeadLetter == nil {
+		RespondWithError(ctx, http.StatusNotFound, "dead letter not found")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deadLetterView(res.DeadLetter))
+}
+
+func (c *DeadLetterController) RedriveDeadLetter(ctx *gin.Context) {
+	sequence, ok := parseSequence(ctx)
+	if !ok {
+		return
+	}
+
+	res, err := c.client.RedriveDeadLetter(ctx, &inventory.DeadLetterID{Sequence: sequence})
+	if err != nil {
+		RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if res.DeadLetter == nil {
+		RespondWithError(ctx, http.StatusNotFound, "dead letter not found")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deadLetterView(res.DeadLetter))
+}
+
+// PurgeDeadLetters removes the dead letter in the path, or those matching the
+// "source_subject" query parameter, or all of them.
+func (c *DeadLetterController) PurgeDeadLetters(ctx *gin.Context) {
+	req := &inventory.PurgeDeadLettersRequest{SourceSubject: ctx.Query("source_subject")}
+	if ctx.Param("sequence") != "" {
+		sequence, ok := parseSequence(ctx)
+		if !ok {
+			return
+		}
+		req.Sequence = sequence
+	}
+
+	res, err := c.client.PurgeDeadLetters(ctx, req)
+	if err != nil {
+		RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"purged": res.Purged})
+}
+
+func parseSequence(ctx *gin.Context) (uint64, bool) {
+	sequence, err := strconv.ParseUint(ctx.Param("sequence"), 10, 64)
+	if err != nil || sequence == 0 {
+		RespondWithError(ctx, http.StatusBadRequest, "invalid dead letter sequence")
+		return 0, false
+	}
+	return sequence, true
+}
+
+// deadLetterView shows a JSON event as is instead of base64.
+func deadLetterView(letter *inventory.DeadLetter) gin.H {
+	view := gin.H{
+		"sequence":         letter.Sequence,
+		"source_subject":   letter.SourceSubject,
+		"consumer":         letter.Consumer,
+		"error":            letter.Error,
+		"attempts":         letter.Attempts,
+		"first_failure_at": letter.FirstFailureAt,
+		"failed_at":        letter.FailedAt,
+		"data":             letter.Data,
+	}
+	if json.Valid(letter.Data) {
+		view["data"] = json.RawMessage(letter.Data)
+	}
+	return view
+}
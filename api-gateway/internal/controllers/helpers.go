@@ -1,9 +1,12 @@
 package controllers
 
 import (
+	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+
+	order "proto/order"
 )
 
 func RespondWithError(c *gin.Context, code int, message string) {
@@ -11,6 +14,21 @@ func RespondWithError(c *gin.Context, code int, message string) {
 	c.Abort()
 }
 
+// ownsOrder responds with 404 unless the order belongs to the logged-in user,
+// so that the orders of others are not revealed.
+func ownsOrder(ctx *gin.Context, client order.OrderServiceClient, orderID string) bool {
+	res, err := client.GetOrder(ctx, &order.OrderID{Id: orderID})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return false
+	}
+	if res.Order == nil || res.Order.UserId != ctx.GetString("user_id") {
+		RespondWithError(ctx, http.StatusNotFound, "order not found")
+		return false
+	}
+	return true
+}
+
 func ParseUintParam(c *gin.Context, param string) (uint, error) {
 	val := c.Param(param)
 	id, err := strconv.ParseUint(val, 10, 32)
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -75,6 +76,106 @@ func (c *OrderController) UpdateOrder(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, res.Order)
 }
 
+// ModifyOrder sets the quantities of order lines before the order is paid.
+// A quantity of 0 removes the line. Only the customer who placed the order
+// may change it.
+func (c *OrderController) ModifyOrder(ctx *gin.Context) {
+	var req order.ModifyOrderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.OrderId = ctx.Param("id")
+	if !ownsOrder(ctx, c.client, req.OrderId) {
+		return
+	}
+
+	res, err := c.client.ModifyOrder(ctx, &req)
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if res.Order == nil {
+		RespondWithError(ctx, http.StatusNotFound, "order not found")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *OrderController) SetSubstitutionPreference(ctx *gin.Context) {
+	var req order.SubstitutionPreferenceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.OrderId = ctx.Param("id")
+	req.ProductId = ctx.Param("product_id")
+	req.UserId = ctx.GetString("user_id")
+
+	res, err := c.client.SetSubstitutionPreference(ctx, &req)
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if res.Order == nil {
+		RespondWithError(ctx, http.StatusNotFound, "order not found")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res.Order)
+}
+
+// ProposeSubstitution is called by the picker, with the admin token, for a
+// missing product. Leaving out substitute_product_id drops the line.
+func (c *OrderController) ProposeSubstitution(ctx *gin.Context) {
+	var req order.ProposeSubstitutionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.OrderId = ctx.Param("id")
+
+	res, err := c.client.ProposeSubstitution(ctx, &req)
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if res.Order == nil {
+		RespondWithError(ctx, http.StatusNotFound, "order not found")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (c *OrderController) ApproveSubstitution(ctx *gin.Context) {
+	c.decideSubstitution(ctx, true)
+}
+
+func (c *OrderController) RejectSubstitution(ctx *gin.Context) {
+	c.decideSubstitution(ctx, false)
+}
+
+func (c *OrderController) decideSubstitution(ctx *gin.Context, approve bool) {
+	res, err := c.client.DecideSubstitution(ctx, &order.DecideSubstitutionRequest{
+		OrderId:        ctx.Param("id"),
+		UserId:         ctx.GetString("user_id"),
+		SubstitutionId: ctx.Param("substitution_id"),
+		Approve:        approve,
+	})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if res.Order == nil {
+		RespondWithError(ctx, http.StatusNotFound, "order not found")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
 func (c *OrderController) ListOrders(ctx *gin.Context) {
 	userID := ctx.Query("user_id")
 	if userID == "" {
@@ -90,3 +191,55 @@ func (c *OrderController) ListOrders(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, res.Orders)
 }
+
+// GetInvoice returns the order invoice as HTML or PDF. The format comes from
+// the "format" query parameter or the Accept header, and the language from
+// "lang" or Accept-Language. Only the customer who placed the order may
+// download it.
+func (c *OrderController) GetInvoice(ctx *gin.Context) {
+	if !ownsOrder(ctx, c.client, ctx.Param("id")) {
+		return
+	}
+
+	format := ctx.Query("format")
+	if format == "" {
+		switch ctx.NegotiateFormat("text/html", "application/pdf") {
+		case "text/html":
+			format = "html"
+		case "application/pdf":
+			format = "pdf"
+		default:
+			RespondWithError(ctx, http.StatusNotAcceptable, "invoices are available as text/html or application/pdf")
+			return
+		}
+	}
+
+	locale := ctx.Query("lang")
+	if locale == "" {
+		locale = ctx.GetHeader("Accept-Language")
+	}
+
+	res, err := c.client.GetInvoice(ctx, &order.InvoiceRequest{
+		OrderId:   ctx.Param("id"),
+		Locale:    locale,
+		Format:    format,
+		BuyerName: ctx.Query("buyer_name"),
+		BuyerBin:  ctx.Query("buyer_bin"),
+	})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if len(res.Content) == 0 {
+		RespondWithError(ctx, http.StatusNotFound, "order not found")
+		return
+	}
+
+	disposition := "inline"
+	if format == "pdf" {
+		disposition = "attachment"
+	}
+	ctx.Header("Content-Disposition", fmt.Sprintf(`%s; filename="%s"`, disposition, res.Filename))
+	ctx.Header("Vary", "Accept, Accept-Language")
+	ctx.Data(http.StatusOK, res.ContentType, res.Content)
+}
@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	order "proto/order"
+	payment "proto/payment"
+)
+
+type PaymentController struct {
+	client payment.PaymentServiceClient
+	orders order.OrderServiceClient
+}
+
+func NewPaymentController(serviceAddr string) *PaymentController {
+	conn, err := grpc.Dial(serviceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		panic(err)
+	}
+
+	return &PaymentController{
+		client: payment.NewPaymentServiceClient(conn),
+		orders: order.NewOrderServiceClient(conn),
+	}
+}
+
+// CreatePayment starts a payment for an order of the logged-in user.
+func (c *PaymentController) CreatePayment(ctx *gin.Context) {
+	var body struct {
+		Method string `json:"method" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !ownsOrder(ctx, c.orders, ctx.Param("id")) {
+		return
+	}
+
+	res, err := c.client.CreatePayment(ctx, &payment.CreatePaymentRequest{
+		OrderId: ctx.Param("id"),
+		Method:  body.Method,
+	})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res.Payment)
+}
+
+func (c *PaymentController) ListOrderPayments(ctx *gin.Context) {
+	if !ownsOrder(ctx, c.orders, ctx.Param("id")) {
+		return
+	}
+
+	res, err := c.client.ListOrderPayments(ctx, &payment.OrderPaymentsRequest{OrderId: ctx.Param("id")})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res.Payments)
+}
+
+// GetPayment answers 404 for a payment of another user's order, as for a
+// missing one.
+func (c *PaymentController) GetPayment(ctx *gin.Context) {
+	res, err := c.client.GetPayment(ctx, &payment.PaymentID{Id: ctx.Param("id")})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if res.Payment == nil {
+		RespondWithError(ctx, http.StatusNotFound, "payment not found")
+		return
+	}
+	if !ownsOrder(ctx, c.orders, res.Payment.OrderId) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res.Payment)
+}
+
+func (c *PaymentController) RefundOrder(ctx *gin.Context) {
+	var req payment.RefundRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.OrderId = ctx.Param("id")
+
+	res, err := c.client.Refund(ctx, &req)
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res)
+}
+
+// HandleCallback forwards a provider webhook untouched, since the signature
+// covers the exact request body.
+func (c *PaymentController) HandleCallback(ctx *gin.Context) {
+	payload, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := c.client.HandlePaymentCallback(ctx, &payment.PaymentCallbackRequest{
+		Provider:  ctx.Param("provider"),
+		Payload:   payload,
+		Signature: ctx.GetHeader("X-Signature"),
+	})
+	if err != nil {
+		RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !res.Accepted {
+		RespondWithError(ctx, http.StatusUnauthorized, res.Message)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res.Payment)
+}
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	rma "proto/rma"
+)
+
+type ReturnController struct {
+	client rma.ReturnServiceClient
+}
+
+func NewReturnController(serviceAddr string) *ReturnController {
+	conn, err := grpc.Dial(serviceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		panic(err)
+	}
+
+	return &ReturnController{
+		client: rma.NewReturnServiceClient(conn),
+	}
+}
+
+func (c *ReturnController) CreateReturn(ctx *gin.Context) {
+	var req rma.CreateReturnRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.OrderId = ctx.Param("id")
+	req.UserId = ctx.GetString("user_id")
+
+	res, err := c.client.CreateReturn(ctx, &req)
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res.Return)
+}
+
+func (c *ReturnController) ListMyReturns(ctx *gin.Context) {
+	c.list(ctx, &rma.ListReturnsRequest{UserId: ctx.GetString("user_id")})
+}
+
+func (c *ReturnController) GetMyReturn(ctx *gin.Context) {
+	c.get(ctx, ctx.GetString("user_id"))
+}
+
+// ListReturns lets admins filter returns by "status", "order_id" and
+// "user_id" query parameters.
+func (c *ReturnController) ListReturns(ctx *gin.Context) {
+	c.list(ctx, &rma.ListReturnsRequest{
+		UserId:  ctx.Query("user_id"),
+		OrderId: ctx.Query("order_id"),
+		Status:  ctx.Query("status"),
+	})
+}
+
+func (c *ReturnController) GetReturn(ctx *gin.Context) {
+	c.get(ctx, "")
+}
+
+func (c *ReturnController) ApproveReturn(ctx *gin.Context) {
+	c.review(ctx, c.client.ApproveReturn)
+}
+
+func (c *ReturnController) RejectReturn(ctx *gin.Context) {
+	c.review(ctx, c.client.RejectReturn)
+}
+
+func (c *ReturnController) RefundReturn(ctx *gin.Context) {
+	c.review(ctx, c.client.RefundReturn)
+}
+
+func (c *ReturnController) ReceiveReturn(ctx *gin.Context) {
+	var req rma.ReceiveReturnRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			RespondWithError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	req.Id = ctx.Param("id")
+
+	res, err := c.client.ReceiveReturn(ctx, &req)
+	c.respond(ctx, res, err)
+}
+
+func (c *ReturnController) get(ctx *gin.Context, userID string) {
+	res, err := c.client.GetReturn(ctx, &rma.GetReturnRequest{
+		Id:     ctx.Param("id"),
+		UserId: userID,
+	})
+	c.respond(ctx, res, err)
+}
+
+func (c *ReturnController) list(ctx *gin.Context, req *rma.ListReturnsRequest) {
+	res, err := c.client.ListReturns(ctx, req)
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res.Returns)
+}
+
+func (c *ReturnController) review(ctx *gin.Context, rpc func(context.Context, *rma.ReviewReturnRequest, ...grpc.CallOption) (*rma.ReturnResponse, error)) {
+	var req rma.ReviewReturnRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			RespondWithError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	req.Id = ctx.Param("id")
+
+	res, err := rpc(ctx, &req)
+	c.respond(ctx, res, err)
+}
+
+func (c *ReturnController) respond(ctx *gin.Context, res *rma.ReturnResponse, err error) {
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if res.Return == nil {
+		RespondWithError(ctx, http.StatusNotFound, "return not found")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res.Return)
+}
@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	inventory "proto/inventory"
+)
+
+type ReviewController struct {
+	client inventory.InventoryServiceClient
+}
+
+func NewReviewController(serviceAddr string) *ReviewController {
+	conn, err := grpc.Dial(serviceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		panic(err)
+	}
+
+	return &ReviewController{
+		client: inventory.NewInventoryServiceClient(conn),
+	}
+}
+
+func (c *ReviewController) CreateReview(ctx *gin.Context) {
+	var req inventory.CreateReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.ProductId = ctx.Param("id")
+	req.UserId = ctx.GetString("user_id")
+
+	res, err := c.client.CreateReview(ctx, &req)
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res.Review)
+}
+
+// ListProductReviews returns the approved reviews of a product, ordered by the
+// "sort" query parameter, together with its average rating.
+func (c *ReviewController) ListProductReviews(ctx *gin.Context) {
+	productID := ctx.Param("id")
+
+	product, err := c.client.GetProduct(ctx, &inventory.ProductID{Id: productID})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if product.Product == nil {
+		RespondWithError(ctx, http.StatusNotFound, "product not found")
+		return
+	}
+
+	res, ok := c.list(ctx, productID, "")
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"reviews":        res.Reviews,
+		"total":          res.Total,
+		"rating_average": product.Product.RatingAverage,
+		"rating_count":   product.Product.RatingCount,
+	})
+}
+
+// ListReviews lets admins browse reviews by "status" (pending by default) and
+// "product_id".
+func (c *ReviewController) ListReviews(ctx *gin.Context) {
+	res, ok := c.list(ctx, ctx.Query("product_id"), ctx.DefaultQuery("status", "pending"))
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"reviews": res.Reviews,
+		"total":   res.Total,
+	})
+}
+
+func (c *ReviewController) ApproveReview(ctx *gin.Context) {
+	c.moderate(ctx, true)
+}
+
+func (c *ReviewController) RejectReview(ctx *gin.Context) {
+	c.moderate(ctx, false)
+}
+
+func (c *ReviewController) list(ctx *gin.Context, productID, status string) (*inventory.ListReviewsResponse, bool) {
+	page, limit := ParsePaginationParams(ctx)
+
+	res, err := c.client.ListReviews(ctx, &inventory.ListReviewsRequest{
+		ProductId: productID,
+		Page:      int32(page),
+		Limit:     int32(limit),
+		Sort:      ctx.Query("sort"),
+		Status:    status,
+	})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	return res, true
+}
+
+func (c *ReviewController) moderate(ctx *gin.Context, approve bool) {
+	var req inventory.ModerateReviewRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			RespondWithError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	req.Id = ctx.Param("id")
+	req.Approve = approve
+
+	res, err := c.client.ModerateReview(ctx, &req)
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if res.Review == nil {
+		RespondWithError(ctx, http.StatusNotFound, "review not found")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res.Review)
+}
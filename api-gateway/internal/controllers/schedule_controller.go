@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	schedule "proto/schedule"
+)
+
+type ScheduleController struct {
+	client schedule.ScheduleServiceClient
+}
+
+func NewScheduleController(serviceAddr string) *ScheduleController {
+	conn, err := grpc.Dial(serviceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		panic(err)
+	}
+
+	return &ScheduleController{
+		client: schedule.NewScheduleServiceClient(conn),
+	}
+}
+
+func (c *ScheduleController) CreateSchedule(ctx *gin.Context) {
+	var req schedule.CreateScheduleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.UserId = ctx.GetString("user_id")
+
+	res, err := c.client.CreateSchedule(ctx, &req)
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res.Schedule)
+}
+
+func (c *ScheduleController) ListSchedules(ctx *gin.Context) {
+	res, err := c.client.ListSchedules(ctx, &schedule.ListSchedulesRequest{UserId: ctx.GetString("user_id")})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res.Schedules)
+}
+
+func (c *ScheduleController) GetSchedule(ctx *gin.Context) {
+	c.call(ctx, c.client.GetSchedule)
+}
+
+func (c *ScheduleController) PauseSchedule(ctx *gin.Context) {
+	c.call(ctx, c.client.PauseSchedule)
+}
+
+func (c *ScheduleController) ResumeSchedule(ctx *gin.Context) {
+	c.call(ctx, c.client.ResumeSchedule)
+}
+
+func (c *ScheduleController) SkipNextRun(ctx *gin.Context) {
+	c.call(ctx, c.client.SkipNextRun)
+}
+
+func (c *ScheduleController) CancelSchedule(ctx *gin.Context) {
+	c.call(ctx, c.client.CancelSchedule)
+}
+
+func (c *ScheduleController) call(ctx *gin.Context, rpc func(context.Context, *schedule.ScheduleRequest, ...grpc.CallOption) (*schedule.ScheduleResponse, error)) {
+	res, err := rpc(ctx, &schedule.ScheduleRequest{
+		Id:     ctx.Param("id"),
+		UserId: ctx.GetString("user_id"),
+	})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if res.Schedule == nil {
+		RespondWithError(ctx, http.StatusNotFound, "schedule not found")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res.Schedule)
+}
@@ -1,27 +1,37 @@
 package controllers
 
 import (
+	"log"
 	"net/http"
 
+	"api-gateway/internal/middlewares"
+
 	"github.com/gin-gonic/gin"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
+	cart "proto/cart"
 	user "proto/user"
 )
 
 type UserController struct {
 	client user.UserServiceClient
+	carts  cart.CartServiceClient
 }
 
-func NewUserController(serviceAddr string) *UserController {
+func NewUserController(serviceAddr, cartServiceAddr string) *UserController {
 	conn, err := grpc.Dial(serviceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		panic(err)
 	}
+	cartConn, err := grpc.Dial(cartServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		panic(err)
+	}
 
 	return &UserController{
 		client: user.NewUserServiceClient(conn),
+		carts:  cart.NewCartServiceClient(cartConn),
 	}
 }
 
@@ -41,6 +51,9 @@ func (c *UserController) RegisterUser(ctx *gin.Context) {
 	ctx.JSON(http.StatusCreated, res.User)
 }
 
+// AuthenticateUser logs the user in. When the request carries the anonymous
+// cart session in the X-Session-ID header, that cart is merged into the
+// user's cart and returned with the token.
 func (c *UserController) AuthenticateUser(ctx *gin.Context) {
 	var authReq user.AuthRequest
 	if err := ctx.ShouldBindJSON(&authReq); err != nil {
@@ -59,10 +72,25 @@ func (c *UserController) AuthenticateUser(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
+	body := gin.H{
 		"token":   res.Token,
 		"success": true,
-	})
+	}
+	if sessionID := ctx.GetHeader(SessionHeader); sessionID != "" {
+		// A cart that fails to merge stays under the session and can be
+		// merged later with POST /cart/merge, so the login still succeeds.
+		merged, err := c.carts.MergeCart(ctx, &cart.MergeCartRequest{
+			UserId:    middlewares.UserIDFromToken(res.Token),
+			SessionId: sessionID,
+		})
+		if err != nil {
+			log.Printf("Failed to merge session cart %s on login: %v", sessionID, err)
+		} else {
+			body["cart"] = merged.Cart
+		}
+	}
+
+	ctx.JSON(http.StatusOK, body)
 }
 
 func (c *UserController) GetUserProfile(ctx *gin.Context) {
@@ -96,3 +124,147 @@ func (c *UserController) UpdateUserProfile(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, res)
 }
+
+func (c *UserController) GetNotificationPreferences(ctx *gin.Context) {
+	res, err := c.client.GetNotificationPreferences(ctx, &user.UserID{Id: ctx.GetString("user_id")})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// UpdateNotificationPreferences replaces the preferences: channels left out
+// of the body are switched off.
+func (c *UserController) UpdateNotificationPreferences(ctx *gin.Context) {
+	var req user.NotificationPreferences
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.UserId = ctx.GetString("user_id")
+
+	res, err := c.client.UpdateNotificationPreferences(ctx, &req)
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// PreviewEmail renders an email template with sample data in the language
+// given by "lang". With "format=html" the HTML part is returned as a page.
+func (c *UserController) PreviewEmail(ctx *gin.Context) {
+	res, err := c.client.PreviewEmail(ctx, &user.PreviewEmailRequest{
+		Template: ctx.Param("template"),
+		Language: ctx.Query("lang"),
+	})
+	if err != nil {
+		RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if ctx.Query("format") == "html" {
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(res.Html))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *UserController) ListQueuedEmails(ctx *gin.Context) {
+	page, limit := ParsePaginationParams(ctx)
+
+	res, err := c.client.ListQueuedEmails(ctx, &user.ListQueuedEmailsRequest{
+		Status: ctx.DefaultQuery("status", "failed"),
+		Page:   int32(page),
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"emails": res.Emails,
+		"total":  res.Total,
+	})
+}
+
+func (c *UserController) ResendEmail(ctx *gin.Context) {
+	res, err := c.client.ResendEmail(ctx, &user.ResendEmailRequest{Id: ctx.Param("id")})
+	if err != nil {
+		RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *UserController) CreateWebhook(ctx *gin.Context) {
+	var req user.CreateWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := c.client.CreateWebhook(ctx, &req)
+	if err != nil {
+		RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (c *UserController) ListWebhooks(ctx *gin.Context) {
+	res, err := c.client.ListWebhooks(ctx, &user.ListWebhooksRequest{})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"webhooks": res.Webhooks})
+}
+
+func (c *UserController) DeleteWebhook(ctx *gin.Context) {
+	_, err := c.client.DeleteWebhook(ctx, &user.WebhookID{Id: ctx.Param("id")})
+	if err != nil {
+		RespondWithError(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusNoContent, nil)
+}
+
+func (c *UserController) ListWebhookDeliveries(ctx *gin.Context) {
+	page, limit := ParsePaginationParams(ctx)
+
+	res, err := c.client.ListWebhookDeliveries(ctx, &user.ListWebhookDeliveriesRequest{
+		SubscriptionId: ctx.Param("id"),
+		Status:         ctx.Query("status"),
+		Page:           int32(page),
+		Limit:          int32(limit),
+	})
+	if err != nil {
+		RespondWithError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"deliveries": res.Deliveries,
+		"total":      res.Total,
+	})
+}
+
+func (c *UserController) ReplayWebhookDelivery(ctx *gin.Context) {
+	res, err := c.client.ReplayWebhookDelivery(ctx, &user.WebhookID{Id: ctx.Param("id")})
+	if err != nil {
+		RespondWithError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
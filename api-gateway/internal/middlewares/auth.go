@@ -23,7 +23,24 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		c.Set("user_id", strings.TrimPrefix(token, "token_"))
+		c.Set("user_id", UserIDFromToken(token))
+		c.Next()
+	}
+}
+
+// UserIDFromToken returns the ID of the user a token was issued to.
+func UserIDFromToken(token string) string {
+	return strings.TrimPrefix(token, "token_")
+}
+
+// OptionalAuthMiddleware identifies the user when a token is sent and lets
+// anonymous requests through.
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if token != "" {
+			c.Set("user_id", UserIDFromToken(token))
+		}
 		c.Next()
 	}
 }
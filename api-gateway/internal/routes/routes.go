@@ -15,7 +15,13 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 
 	inventoryCtrl := controllers.NewInventoryController(cfg.Services.Inventory)
 	orderCtrl := controllers.NewOrderController(cfg.Services.Order)
-	userCtrl := controllers.NewUserController(cfg.Services.User)
+	userCtrl := controllers.NewUserController(cfg.Services.User, cfg.Services.Order)
+	paymentCtrl := controllers.NewPaymentController(cfg.Services.Order)
+	cartCtrl := controllers.NewCartController(cfg.Services.Order)
+	scheduleCtrl := controllers.NewScheduleController(cfg.Services.Order)
+	returnCtrl := controllers.NewReturnController(cfg.Services.Order)
+	reviewCtrl := controllers.NewReviewController(cfg.Services.Inventory)
+	deadLetterCtrl := controllers.NewDeadLetterController(cfg.Services.Inventory)
 
 	products := router.Group("/products")
 	{
@@ -24,6 +30,8 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 		products.PATCH(":id", inventoryCtrl.UpdateProduct)
 		products.DELETE(":id", inventoryCtrl.DeleteProduct)
 		products.GET("", inventoryCtrl.ListProducts)
+		products.GET(":id/reviews", reviewCtrl.ListProductReviews)
+		products.POST(":id/reviews", middlewares.AuthMiddleware(), reviewCtrl.CreateReview)
 	}
 
 	categories := router.Group("/categories")
@@ -42,6 +50,80 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 		orders.GET(":id", orderCtrl.GetOrder)
 		orders.PATCH(":id", orderCtrl.UpdateOrder)
 		orders.GET("", orderCtrl.ListOrders)
+		orders.PATCH(":id/items", orderCtrl.ModifyOrder)
+		orders.PUT(":id/items/:product_id/substitution", orderCtrl.SetSubstitutionPreference)
+		orders.POST(":id/substitutions/:substitution_id/approve", orderCtrl.ApproveSubstitution)
+		orders.POST(":id/substitutions/:substitution_id/reject", orderCtrl.RejectSubstitution)
+		orders.GET(":id/invoice", orderCtrl.GetInvoice)
+		orders.POST(":id/reorder", cartCtrl.Reorder)
+		orders.POST(":id/payments", paymentCtrl.CreatePayment)
+		orders.GET(":id/payments", paymentCtrl.ListOrderPayments)
+		orders.POST(":id/returns", returnCtrl.CreateReturn)
+	}
+
+	payments := router.Group("/payments")
+	{
+		payments.GET(":id", middlewares.AuthMiddleware(), paymentCtrl.GetPayment)
+		payments.POST("/callback/:provider", paymentCtrl.HandleCallback)
+	}
+
+	cart := router.Group("/cart")
+	cart.Use(middlewares.OptionalAuthMiddleware())
+	{
+		cart.GET("", cartCtrl.GetCart)
+		cart.POST("/items", cartCtrl.AddItem)
+		cart.PATCH("/items/:product_id", cartCtrl.UpdateItem)
+		cart.DELETE("/items/:product_id", cartCtrl.RemoveItem)
+		cart.POST("/merge", middlewares.AuthMiddleware(), cartCtrl.MergeCart)
+		cart.POST("/checkout", middlewares.AuthMiddleware(), cartCtrl.Checkout)
+	}
+
+	schedules := router.Group("/schedules")
+	schedules.Use(middlewares.AuthMiddleware())
+	{
+		schedules.POST("", scheduleCtrl.CreateSchedule)
+		schedules.GET("", scheduleCtrl.ListSchedules)
+		schedules.GET(":id", scheduleCtrl.GetSchedule)
+		schedules.POST(":id/pause", scheduleCtrl.PauseSchedule)
+		schedules.POST(":id/resume", scheduleCtrl.ResumeSchedule)
+		schedules.POST(":id/skip", scheduleCtrl.SkipNextRun)
+		schedules.DELETE(":id", scheduleCtrl.CancelSchedule)
+	}
+
+	returns := router.Group("/returns")
+	returns.Use(middlewares.AuthMiddleware())
+	{
+		returns.GET("", returnCtrl.ListMyReturns)
+		returns.GET(":id", returnCtrl.GetMyReturn)
+	}
+
+	admin := router.Group("/admin")
+	admin.Use(middlewares.AdminMiddleware(cfg.Admin.Token))
+	{
+		admin.POST("orders/:id/refunds", paymentCtrl.RefundOrder)
+		admin.POST("orders/:id/substitutions", orderCtrl.ProposeSubstitution)
+		admin.GET("returns", returnCtrl.ListReturns)
+		admin.GET("returns/:id", returnCtrl.GetReturn)
+		admin.POST("returns/:id/approve", returnCtrl.ApproveReturn)
+		admin.POST("returns/:id/reject", returnCtrl.RejectReturn)
+		admin.POST("returns/:id/receive", returnCtrl.ReceiveReturn)
+		admin.POST("returns/:id/refund", returnCtrl.RefundReturn)
+		admin.GET("reviews", reviewCtrl.ListReviews)
+		admin.POST("reviews/:id/approve", reviewCtrl.ApproveReview)
+		admin.POST("reviews/:id/reject", reviewCtrl.RejectReview)
+		admin.GET("emails/:template/preview", userCtrl.PreviewEmail)
+		admin.GET("mail-queue", userCtrl.ListQueuedEmails)
+		admin.POST("mail-queue/:id/resend", userCtrl.ResendEmail)
+		admin.POST("webhooks", userCtrl.CreateWebhook)
+		admin.GET("webhooks", userCtrl.ListWebhooks)
+		admin.DELETE("webhooks/:id", userCtrl.DeleteWebhook)
+		admin.GET("webhooks/:id/deliveries", userCtrl.ListWebhookDeliveries)
+		admin.POST("webhook-deliveries/:id/replay", userCtrl.ReplayWebhookDelivery)
+		admin.GET("dead-letters", deadLetterCtrl.ListDeadLetters)
+		admin.DELETE("dead-letters", deadLetterCtrl.PurgeDeadLetters)
+		admin.GET("dead-letters/:sequence", deadLetterCtrl.GetDeadLetter)
+		admin.DELETE("dead-letters/:sequence", deadLetterCtrl.PurgeDeadLetters)
+		admin.POST("dead-letters/:sequence/redrive", deadLetterCtrl.RedriveDeadLetter)
 	}
 
 	users := router.Group("/users")
@@ -52,5 +134,12 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 		users.PATCH("/:id/profile", userCtrl.UpdateUserProfile)
 	}
 
+	notifications := router.Group("/notifications")
+	notifications.Use(middlewares.AuthMiddleware())
+	{
+		notifications.GET("/preferences", userCtrl.GetNotificationPreferences)
+		notifications.PUT("/preferences", userCtrl.UpdateNotificationPreferences)
+	}
+
 	return router
 }
@@ -0,0 +1,22 @@
+module infra
+
+go 1.22.0
+
+toolchain go1.23.4
+
+require (
+	github.com/nats-io/nats.go v1.33.1
+	go.mongodb.org/mongo-driver v1.14.0
+	proto v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/klauspost/compress v1.17.7 // indirect
+	github.com/nats-io/nkeys v0.4.7 // indirect
+	github.com/nats-io/nuid v1.0.1 // indirect
+	golang.org/x/crypto v0.32.0 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+	google.golang.org/protobuf v1.36.6 // indirect
+)
+
+replace proto => ../proto
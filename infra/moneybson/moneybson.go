@@ -0,0 +1,68 @@
+// Package moneybson decodes money.Money from MongoDB, including the legacy
+// float prices in major units written before the money migration. It keeps
+// the BSON driver out of the proto module.
+package moneybson
+
+import (
+	"fmt"
+	"reflect"
+
+	"proto/money"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+)
+
+var moneyType = reflect.TypeOf(money.Money{})
+
+// Registry returns the default BSON registry with the money.Money decoder.
+// The services set it on their MongoDB client.
+func Registry() *bsoncodec.Registry {
+	registry := bson.NewRegistry()
+	registry.RegisterTypeDecoder(moneyType, bsoncodec.ValueDecoderFunc(decodeMoney))
+	return registry
+}
+
+// fields is money.Money without the custom decoder.
+type fields struct {
+	Amount   int64  `bson:"amount"`
+	Currency string `bson:"currency"`
+}
+
+// decodeMoney reads both the current {amount, currency} documents and legacy
+// float prices in major units.
+func decodeMoney(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || val.Type() != moneyType {
+		return bsoncodec.ValueDecoderError{Name: "decodeMoney", Types: []reflect.Type{moneyType}, Received: val}
+	}
+
+	t, data, err := bsonrw.Copier{}.CopyValueToBytes(vr)
+	if err != nil {
+		return err
+	}
+	raw := bson.RawValue{Type: t, Value: data}
+
+	var m money.Money
+	switch t {
+	case bson.TypeEmbeddedDocument:
+		var f fields
+		if err := raw.Unmarshal(&f); err != nil {
+			return err
+		}
+		m = money.New(f.Amount, f.Currency)
+	case bson.TypeDouble:
+		m = money.FromFloat(raw.Double(), money.DefaultCurrency)
+	case bson.TypeInt32:
+		m = money.FromFloat(float64(raw.Int32()), money.DefaultCurrency)
+	case bson.TypeInt64:
+		m = money.FromFloat(float64(raw.Int64()), money.DefaultCurrency)
+	case bson.TypeNull, bson.TypeUndefined:
+		m = money.Money{}
+	default:
+		return fmt.Errorf("money: cannot decode BSON %s", t)
+	}
+
+	val.Set(reflect.ValueOf(m))
+	return nil
+}
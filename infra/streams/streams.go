@@ -0,0 +1,54 @@
+// Package streams declares the JetStream streams of the events and sets them
+// up. It keeps the NATS client out of the proto module.
+package streams
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"proto/events"
+
+	"github.com/nats-io/nats.go"
+)
+
+// The JetStream streams of the events. They keep events on disk, so that a
+// service that was down receives what was published in the meantime. Every
+// service declares them from here, so the declarations cannot drift apart.
+var (
+	OrderStream = nats.StreamConfig{
+		Name:     "ORDERS",
+		Subjects: []string{"order.>", "return.>"},
+		Storage:  nats.FileStorage,
+		MaxAge:   7 * 24 * time.Hour,
+	}
+	StockStream = nats.StreamConfig{
+		Name:     "STOCK",
+		Subjects: []string{"stock.>"},
+		Storage:  nats.FileStorage,
+		MaxAge:   7 * 24 * time.Hour,
+	}
+	// DeadLetterStream keeps dead letters for a month, until they are
+	// redriven or purged.
+	DeadLetterStream = nats.StreamConfig{
+		Name:     "DEAD_LETTERS",
+		Subjects: []string{events.SubjectDeadLetter},
+		Storage:  nats.FileStorage,
+		MaxAge:   30 * 24 * time.Hour,
+	}
+)
+
+// EnsureStream creates the stream or brings an existing one up to date.
+func EnsureStream(js nats.JetStreamContext, stream nats.StreamConfig) error {
+	_, err := js.StreamInfo(stream.Name)
+	switch {
+	case errors.Is(err, nats.ErrStreamNotFound):
+		_, err = js.AddStream(&stream)
+	case err == nil:
+		_, err = js.UpdateStream(&stream)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set up stream %s: %w", stream.Name, err)
+	}
+	return nil
+}
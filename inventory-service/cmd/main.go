@@ -13,6 +13,7 @@ import (
 	"inventory-service/internal/infrastructure/cache"
 	"inventory-service/internal/infrastructure/database"
 	"inventory-service/internal/infrastructure/messaging"
+	"inventory-service/internal/infrastructure/orders"
 	"inventory-service/internal/infrastructure/product"
 	"inventory-service/internal/interfaces/routes"
 
@@ -40,11 +41,27 @@ func main() {
 	consumer := natsConsumer
 	log.Println("Using NATS consumer")
 
-	productClient, err := product.NewProductServiceClient(cfg, mongoDB)
+	var publisher messaging.EventPublisher
+	natsPublisher, err := messaging.NewNATSPublisher(cfg)
+	if err != nil {
+		log.Printf("Failed to set up NATS publisher, stock changes are not announced: %v", err)
+	} else {
+		publisher = natsPublisher
+	}
+
+	productClient, err := product.NewProductServiceClient(cfg, mongoDB, publisher)
 	if err != nil {
 		log.Fatalf("Failed to connect to Product Service: %v", err)
 	}
 
+	var purchaseVerifier orders.PurchaseVerifier
+	orderClient, err := orders.NewGRPCPurchaseVerifier(cfg)
+	if err != nil {
+		log.Printf("Failed to set up Order Service client, reviews are disabled: %v", err)
+	} else {
+		purchaseVerifier = orderClient
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -68,14 +85,20 @@ func main() {
 		}
 
 		consumer.Close()
+		if publisher != nil {
+			publisher.Close()
+		}
 		productClient.Close()
+		if purchaseVerifier != nil {
+			purchaseVerifier.Close()
+		}
 
 		cancel()
 	}()
 
 	grpcServer := gogrpc.NewServer()
 
-	routes.RegisterGRPCServices(grpcServer, mongoDB, consumer, productClient, redisClient)
+	routes.RegisterGRPCServices(grpcServer, mongoDB, consumer, publisher, productClient, redisClient, purchaseVerifier, natsConsumer.DeadLetters())
 
 	lis, err := net.Listen("tcp", ":"+cfg.Server.Port)
 	if err != nil {
@@ -20,6 +20,9 @@ func (uc *CategoryUseCase) CreateCategory(ctx context.Context, category *domain.
 	if category.Name == "" {
 		return nil, errors.New("category name is required")
 	}
+	if !category.TaxClass.IsValid() {
+		return nil, errors.New("invalid tax class")
+	}
 
 	return uc.repo.Create(ctx, category)
 }
@@ -39,6 +42,9 @@ func (uc *CategoryUseCase) UpdateCategory(ctx context.Context, category *domain.
 	if category.Name == "" {
 		return nil, errors.New("category name is required")
 	}
+	if !category.TaxClass.IsValid() {
+		return nil, errors.New("invalid tax class")
+	}
 
 	return uc.repo.Update(ctx, category)
 }
@@ -0,0 +1,67 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"inventory-service/internal/infrastructure/messaging"
+)
+
+type DeadLetterUseCase struct {
+	queue *messaging.DeadLetterQueue
+}
+
+func NewDeadLetterUseCase(queue *messaging.DeadLetterQueue) *DeadLetterUseCase {
+	return &DeadLetterUseCase{queue: queue}
+}
+
+func (uc *DeadLetterUseCase) ListDeadLetters(ctx context.Context, sourceSubject string, page, limit int) ([]*messaging.DeadLetter, int, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	return uc.queue.List(ctx, sourceSubject, page, limit)
+}
+
+func (uc *DeadLetterUseCase) GetDeadLetter(ctx context.Context, sequence uint64) (*messaging.DeadLetter, error) {
+	return uc.queue.Get(ctx, sequence)
+}
+
+// RedriveDeadLetter hands the event back to the consumer it failed in.
+func (uc *DeadLetterUseCase) RedriveDeadLetter(ctx context.Context, sequence uint64) (*messaging.DeadLetter, error) {
+	letter, err := uc.queue.Redrive(ctx, sequence)
+	if err != nil || letter == nil {
+		return nil, err
+	}
+
+	log.Printf("Redrove dead letter %d to %s for %s", sequence, letter.SourceSubject, letter.Consumer)
+	return letter, nil
+}
+
+// PurgeDeadLetters removes one dead letter when sequence is set, otherwise
+// those from sourceSubject, or all of them when it is empty.
+func (uc *DeadLetterUseCase) PurgeDeadLetters(ctx context.Context, sequence uint64, sourceSubject string) (int, error) {
+	if sequence != 0 {
+		deleted, err := uc.queue.Delete(ctx, sequence)
+		if err != nil {
+			return 0, err
+		}
+		if !deleted {
+			return 0, errors.New("dead letter not found")
+		}
+		log.Printf("Purged dead letter %d", sequence)
+		return 1, nil
+	}
+
+	purged, err := uc.queue.Purge(ctx, sourceSubject)
+	if err != nil {
+		return purged, err
+	}
+
+	log.Printf("Purged %d dead letters", purged)
+	return purged, nil
+}
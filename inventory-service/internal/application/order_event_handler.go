@@ -2,11 +2,10 @@ package application
 
 import (
 	"context"
-	"errors"
+	"inventory-service/internal/domain"
 	"inventory-service/internal/infrastructure/messaging"
 	grpc "inventory-service/internal/infrastructure/product"
 	"log"
-	"sync"
 )
 
 type OrderEventHandler struct {
@@ -21,49 +20,89 @@ func NewOrderEventHandler(productClient grpc.ProductServiceClient, metrics *Metr
 	}
 }
 
+// HandleOrderCreated takes the stock of all items of the order in one
+// transaction, or of none when one of them is short. Redelivered events are
+// ignored.
 func (h *OrderEventHandler) HandleOrderCreated(ctx context.Context, event *messaging.OrderCreatedEvent) error {
 	log.Printf("Processing order.created event for order ID: %s with %d items",
 		event.OrderID, len(event.Items))
 
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	var failedItems []messaging.StockUpdateResult
+	items := make([]domain.StockItem, len(event.Items))
+	for i, item := range event.Items {
+		items[i] = domain.StockItem{ProductID: item.ProductID, Quantity: item.Quantity}
+	}
 
-	for _, item := range event.Items {
-		wg.Add(1)
-		go func(item messaging.OrderItem) {
-			defer wg.Done()
-
-			err := h.productClient.DecreaseStock(ctx, item.ProductID, item.Quantity)
-
-			result := messaging.StockUpdateResult{
-				ProductID: item.ProductID,
-				Quantity:  item.Quantity,
-				Success:   err == nil,
-			}
-
-			if err != nil {
-				result.Error = err.Error()
-				log.Printf("Failed to update stock for product %s: %v", item.ProductID, err)
-
-				mu.Lock()
-				failedItems = append(failedItems, result)
-				mu.Unlock()
-
-				h.metrics.IncStockUpdateErrors()
-			} else {
-				log.Printf("Successfully updated stock for product %s by %d", item.ProductID, item.Quantity)
-			}
-		}(item)
+	if err := h.productClient.TakeOrderStock(ctx, event.OrderID, items); err != nil {
+		log.Printf("Failed to take stock for order %s: %v", event.OrderID, err)
+		h.metrics.IncStockUpdateErrors()
+		return err
 	}
 
-	wg.Wait()
+	log.Printf("Successfully processed order %s", event.OrderID)
+	return nil
+}
+
+// HandleOrderExpired returns the stock held by an order that was never paid.
+// Only what the order took is returned, once, whatever the event lists.
+func (h *OrderEventHandler) HandleOrderExpired(ctx context.Context, event *messaging.OrderExpiredEvent) error {
+	log.Printf("Processing order.expired event for order ID: %s with %d items",
+		event.OrderID, len(event.Items))
 
-	if len(failedItems) > 0 {
-		log.Printf("%d items failed stock update for order %s", len(failedItems), event.OrderID)
-		return errors.New("some items failed stock update")
+	if err := h.productClient.ReleaseOrderStock(ctx, event.OrderID); err != nil {
+		log.Printf("Failed to release stock of expired order %s: %v", event.OrderID, err)
+		h.metrics.IncStockUpdateErrors()
+		return err
 	}
 
-	log.Printf("Successfully processed order %s", event.OrderID)
+	log.Printf("Released stock of expired order %s", event.OrderID)
+	return nil
+}
+
+// HandleOrderModified takes stock for products added to an order and returns
+// it for products removed from it, once per event.
+func (h *OrderEventHandler) HandleOrderModified(ctx context.Context, eventID string, event *messaging.OrderModifiedEvent) error {
+	log.Printf("Processing order.modified event for order ID: %s with %d deltas",
+		event.OrderID, len(event.Deltas))
+
+	deltas := make([]domain.StockDelta, len(event.Deltas))
+	for i, delta := range event.Deltas {
+		deltas[i] = domain.StockDelta{ProductID: delta.ProductID, Delta: delta.Delta}
+	}
+
+	if err := h.productClient.ApplyOrderModification(ctx, eventID, event.OrderID, deltas); err != nil {
+		log.Printf("Failed to apply stock deltas of modified order %s: %v", event.OrderID, err)
+		h.metrics.IncStockUpdateErrors()
+		return err
+	}
+
+	log.Printf("Applied stock deltas of modified order %s", event.OrderID)
+	return nil
+}
+
+// HandleReturnReceived puts the sellable items of a returned parcel back on
+// sale, once per event. Damaged items are left out.
+func (h *OrderEventHandler) HandleReturnReceived(ctx context.Context, eventID string, event *messaging.ReturnReceivedEvent) error {
+	log.Printf("Processing return.received event for return ID: %s with %d items",
+		event.ReturnID, len(event.Items))
+
+	var items []domain.StockItem
+	for _, item := range event.Items {
+		if !item.Sellable {
+			log.Printf("Skipping unsellable product %s of return %s", item.ProductID, event.ReturnID)
+			continue
+		}
+		items = append(items, domain.StockItem{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	if err := h.productClient.RestockReturn(ctx, eventID, items); err != nil {
+		log.Printf("Failed to restock return %s: %v", event.ReturnID, err)
+		h.metrics.IncStockUpdateErrors()
+		return err
+	}
+
+	log.Printf("Restocked return %s", event.ReturnID)
 	return nil
 }
@@ -3,29 +3,45 @@ package application
 import (
 	"context"
 	"errors"
+	"log"
 
 	"inventory-service/internal/domain"
+	"inventory-service/internal/infrastructure/messaging"
 	"inventory-service/internal/infrastructure/persistence"
+	"proto/money"
 )
 
+// errProductCurrency is returned for prices outside the catalog currency;
+// orders and carts only sum amounts in one currency.
+var errProductCurrency = errors.New("product price must be in " + money.DefaultCurrency)
+
 type ProductUseCase struct {
-	repo persistence.ProductRepository
+	repo      persistence.ProductRepository
+	publisher messaging.EventPublisher
 }
 
-func NewProductUseCase(repo persistence.ProductRepository) *ProductUseCase {
-	return &ProductUseCase{repo: repo}
+// NewProductUseCase announces stock edits through publisher, which may be
+// nil.
+func NewProductUseCase(repo persistence.ProductRepository, publisher messaging.EventPublisher) *ProductUseCase {
+	return &ProductUseCase{repo: repo, publisher: publisher}
 }
 
 func (uc *ProductUseCase) CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
 	if product.Name == "" {
 		return nil, errors.New("product name is required")
 	}
-	if product.Price <= 0 {
+	if product.Price.Amount <= 0 {
 		return nil, errors.New("product price must be positive")
 	}
+	if !product.Price.SameCurrency(money.KZT(0)) {
+		return nil, errProductCurrency
+	}
 	if product.Stock < 0 {
 		return nil, errors.New("product stock cannot be negative")
 	}
+	if !product.TaxClass.IsValid() {
+		return nil, errors.New("invalid tax class")
+	}
 
 	return uc.repo.Create(ctx, product)
 }
@@ -45,8 +61,81 @@ func (uc *ProductUseCase) UpdateProduct(ctx context.Context, product *domain.Pro
 	if product.Name == "" {
 		return nil, errors.New("product name is required")
 	}
+	if !product.Price.SameCurrency(money.KZT(0)) {
+		return nil, errProductCurrency
+	}
+	if !product.TaxClass.IsValid() {
+		return nil, errors.New("invalid tax class")
+	}
+
+	existing, err := uc.repo.GetByID(ctx, product.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := uc.repo.Update(ctx, product)
+	if err != nil || updated == nil {
+		return updated, err
+	}
+
+	if existing != nil && existing.Stock != updated.Stock {
+		uc.publishStockChanged(updated.ID, existing.Stock, updated.Stock)
+	}
+
+	return updated, nil
+}
 
-	return uc.repo.Update(ctx, product)
+// DecreaseStock returns domain.ErrInsufficientStock when less than quantity
+// is left.
+func (uc *ProductUseCase) DecreaseStock(ctx context.Context, productID string, quantity int) (*domain.Product, error) {
+	if productID == "" {
+		return nil, errors.New("product ID is required")
+	}
+	if quantity <= 0 {
+		return nil, errors.New("quantity must be positive")
+	}
+
+	product, err := uc.repo.DecreaseStock(ctx, productID, quantity)
+	if err != nil || product == nil {
+		return product, err
+	}
+
+	uc.publishStockChanged(product.ID, product.Stock+quantity, product.Stock)
+	return product, nil
+}
+
+// DecreaseStockBatch takes the stock of all items or, when one of them fails,
+// of none.
+func (uc *ProductUseCase) DecreaseStockBatch(ctx context.Context, items []domain.StockItem) ([]*domain.Product, error) {
+	items, err := domain.MergeStockItems(items)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := uc.repo.DecreaseStockBatch(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, product := range products {
+		uc.publishStockChanged(product.ID, product.Stock+items[i].Quantity, product.Stock)
+	}
+	return products, nil
+}
+
+func (uc *ProductUseCase) publishStockChanged(productID string, previous, stock int) {
+	if uc.publisher == nil {
+		return
+	}
+
+	err := uc.publisher.PublishStockChanged(messaging.StockChangedEvent{
+		ProductID:     productID,
+		PreviousStock: previous,
+		Stock:         stock,
+	})
+	if err != nil {
+		log.Printf("Failed to publish stock change of product %s: %v", productID, err)
+	}
 }
 
 func (uc *ProductUseCase) DeleteProduct(ctx context.Context, id string) error {
@@ -0,0 +1,164 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"inventory-service/internal/domain"
+	"inventory-service/internal/infrastructure/messaging"
+	"inventory-service/internal/infrastructure/persistence"
+)
+
+// fakeProducts keeps the stock in memory. Like the MongoDB transaction, its
+// batch decrement changes every product or none.
+type fakeProducts struct {
+	persistence.ProductRepository
+	mu    sync.Mutex
+	stock map[string]int
+}
+
+func (r *fakeProducts) DecreaseStockBatch(ctx context.Context, items []domain.StockItem) ([]*domain.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if seen[item.ProductID] {
+			return nil, fmt.Errorf("product %s is repeated in the batch", item.ProductID)
+		}
+		seen[item.ProductID] = true
+
+		stock, ok := r.stock[item.ProductID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", domain.ErrProductNotFound, item.ProductID)
+		}
+		if stock < item.Quantity {
+			return nil, fmt.Errorf("%w for product %s", domain.ErrInsufficientStock, item.ProductID)
+		}
+	}
+
+	products := make([]*domain.Product, 0, len(items))
+	for _, item := range items {
+		r.stock[item.ProductID] -= item.Quantity
+		products = append(products, &domain.Product{ID: item.ProductID, Stock: r.stock[item.ProductID]})
+	}
+	return products, nil
+}
+
+func (r *fakeProducts) stockOf(productID string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stock[productID]
+}
+
+type recordingPublisher struct {
+	mu      sync.Mutex
+	changes []messaging.StockChangedEvent
+}
+
+func (p *recordingPublisher) PublishStockChanged(event messaging.StockChangedEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.changes = append(p.changes, event)
+	return nil
+}
+
+func (p *recordingPublisher) Close() {}
+
+func newBatchFixture() (*ProductUseCase, *fakeProducts, *recordingPublisher) {
+	repo := &fakeProducts{stock: map[string]int{"tea": 5, "milk": 10}}
+	publisher := &recordingPublisher{}
+	return NewProductUseCase(repo, publisher), repo, publisher
+}
+
+func TestDecreaseStockBatchTakesEveryItem(t *testing.T) {
+	uc, repo, publisher := newBatchFixture()
+
+	products, err := uc.DecreaseStockBatch(context.Background(), []domain.StockItem{
+		{ProductID: "tea", Quantity: 2},
+		{ProductID: "milk", Quantity: 3},
+		{ProductID: "tea", Quantity: 1},
+	})
+	if err != nil {
+		t.Fatalf("DecreaseStockBatch: %v", err)
+	}
+
+	if len(products) != 2 || repo.stockOf("tea") != 2 || repo.stockOf("milk") != 7 {
+		t.Fatalf("got %d products, tea %d, milk %d; want 2 products, tea 2, milk 7",
+			len(products), repo.stockOf("tea"), repo.stockOf("milk"))
+	}
+	want := []messaging.StockChangedEvent{
+		{ProductID: "tea", PreviousStock: 5, Stock: 2},
+		{ProductID: "milk", PreviousStock: 10, Stock: 7},
+	}
+	if len(publisher.changes) != len(want) {
+		t.Fatalf("published %d stock changes, want %d", len(publisher.changes), len(want))
+	}
+	for i, change := range publisher.changes {
+		if change != want[i] {
+			t.Errorf("stock change %d = %+v, want %+v", i, change, want[i])
+		}
+	}
+}
+
+func TestDecreaseStockBatchTakesNothingWhenAnItemFails(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []domain.StockItem
+		want  error
+	}{
+		{
+			name:  "short item",
+			items: []domain.StockItem{{ProductID: "milk", Quantity: 3}, {ProductID: "tea", Quantity: 6}},
+			want:  domain.ErrInsufficientStock,
+		},
+		{
+			// Each line fits the stock on its own, together they do not.
+			name:  "repeated product short in total",
+			items: []domain.StockItem{{ProductID: "tea", Quantity: 3}, {ProductID: "milk", Quantity: 3}, {ProductID: "tea", Quantity: 3}},
+			want:  domain.ErrInsufficientStock,
+		},
+		{
+			name:  "missing product",
+			items: []domain.StockItem{{ProductID: "milk", Quantity: 3}, {ProductID: "bread", Quantity: 1}},
+			want:  domain.ErrProductNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo, publisher := newBatchFixture()
+
+			_, err := uc.DecreaseStockBatch(context.Background(), tt.items)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("DecreaseStockBatch error = %v, want %v", err, tt.want)
+			}
+			if repo.stockOf("tea") != 5 || repo.stockOf("milk") != 10 {
+				t.Fatalf("stock changed to tea %d, milk %d", repo.stockOf("tea"), repo.stockOf("milk"))
+			}
+			if len(publisher.changes) != 0 {
+				t.Fatalf("published %d stock changes for a failed batch", len(publisher.changes))
+			}
+		})
+	}
+}
+
+func TestDecreaseStockBatchRejectsInvalidItems(t *testing.T) {
+	uc, repo, _ := newBatchFixture()
+
+	for _, items := range [][]domain.StockItem{
+		nil,
+		{{ProductID: "tea", Quantity: 1}, {ProductID: "", Quantity: 1}},
+		{{ProductID: "tea", Quantity: 1}, {ProductID: "milk", Quantity: 0}},
+	} {
+		if _, err := uc.DecreaseStockBatch(context.Background(), items); err == nil {
+			t.Errorf("DecreaseStockBatch(%+v) succeeded", items)
+		}
+	}
+	if repo.stockOf("tea") != 5 || repo.stockOf("milk") != 10 {
+		t.Fatalf("stock changed to tea %d, milk %d", repo.stockOf("tea"), repo.stockOf("milk"))
+	}
+}
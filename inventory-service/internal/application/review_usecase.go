@@ -0,0 +1,134 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"inventory-service/internal/domain"
+	"inventory-service/internal/infrastructure/orders"
+	"inventory-service/internal/infrastructure/persistence"
+)
+
+// Transactor runs fn in a transaction that commits when fn returns nil, like
+// database.MongoDBConnector.WithTransaction.
+type Transactor interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type ReviewUseCase struct {
+	db          Transactor
+	reviewRepo  persistence.ReviewRepository
+	productRepo persistence.ProductRepository
+	verifier    orders.PurchaseVerifier
+}
+
+func NewReviewUseCase(db Transactor, reviewRepo persistence.ReviewRepository, productRepo persistence.ProductRepository, verifier orders.PurchaseVerifier) *ReviewUseCase {
+	return &ReviewUseCase{
+		db:          db,
+		reviewRepo:  reviewRepo,
+		productRepo: productRepo,
+		verifier:    verifier,
+	}
+}
+
+// CreateReview accepts a review only from a customer who received the product.
+// It stays hidden until a moderator approves it.
+func (uc *ReviewUseCase) CreateReview(ctx context.Context, productID, userID string, rating int, text string) (*domain.Review, error) {
+	review, err := domain.NewReview(productID, userID, "", rating, text)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, fmt.Errorf("product not found: %s", productID)
+	}
+
+	if uc.verifier == nil {
+		return nil, errors.New("order service is unavailable, purchase cannot be verified")
+	}
+	orderID, err := uc.verifier.DeliveredOrderID(ctx, userID, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify purchase: %w", err)
+	}
+	if orderID == "" {
+		return nil, errors.New("only customers who received the product can review it")
+	}
+	review.OrderID = orderID
+
+	return uc.reviewRepo.Create(ctx, review)
+}
+
+func (uc *ReviewUseCase) GetReview(ctx context.Context, id string) (*domain.Review, error) {
+	if id == "" {
+		return nil, errors.New("review ID is required")
+	}
+
+	return uc.reviewRepo.GetByID(ctx, id)
+}
+
+// ListReviews returns approved reviews unless another status is asked for.
+// Moderators list the pending queue by leaving productID empty.
+func (uc *ReviewUseCase) ListReviews(ctx context.Context, productID string, status domain.ReviewStatus, sort domain.ReviewSort, page, limit int) ([]*domain.Review, int, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+	if sort == "" {
+		sort = domain.ReviewSortNewest
+	}
+	if !sort.IsValid() {
+		return nil, 0, fmt.Errorf("unknown sort order: %s", sort)
+	}
+	if status == "" {
+		status = domain.ReviewStatusApproved
+	}
+
+	filter := persistence.ReviewListFilter{ProductID: productID, Status: status}
+	return uc.reviewRepo.List(ctx, filter, sort, page, limit)
+}
+
+// ModerateReview approves or rejects a review and keeps the product rating in
+// line with the approved reviews. The status and the rating change in one
+// transaction, so a failure leaves neither changed.
+func (uc *ReviewUseCase) ModerateReview(ctx context.Context, id string, approve bool, note string) (*domain.Review, error) {
+	review, err := uc.GetReview(ctx, id)
+	if err != nil || review == nil {
+		return nil, err
+	}
+	if !approve && note == "" {
+		return nil, errors.New("a reason for the rejection is required")
+	}
+
+	from := review.Status
+	totalDelta, countDelta := review.Moderate(approve, note)
+
+	err = uc.db.WithTransaction(ctx, func(ctx context.Context) error {
+		ok, err := uc.reviewRepo.UpdateStatus(ctx, review, from)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("review was moderated concurrently, please retry")
+		}
+
+		if countDelta == 0 {
+			return nil
+		}
+		return uc.productRepo.AdjustRating(ctx, review.ProductID, totalDelta, countDelta)
+	})
+	if err != nil {
+		log.Printf("Failed to moderate review %s of product %s: %v", review.ID, review.ProductID, err)
+		return nil, err
+	}
+
+	log.Printf("Review %s of product %s is %s", review.ID, review.ProductID, review.Status)
+	return review, nil
+}
@@ -0,0 +1,239 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"inventory-service/internal/domain"
+	"inventory-service/internal/infrastructure/orders"
+	"inventory-service/internal/infrastructure/persistence"
+)
+
+// fakeReviews keeps reviews in memory and, like the unique index on product
+// and user, refuses a second review of a product by the same user.
+type fakeReviews struct {
+	persistence.ReviewRepository
+	mu      sync.Mutex
+	reviews map[string]domain.Review
+}
+
+func (r *fakeReviews) Create(ctx context.Context, review *domain.Review) (*domain.Review, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.reviews {
+		if existing.ProductID == review.ProductID && existing.UserID == review.UserID {
+			return nil, domain.ErrReviewExists
+		}
+	}
+	r.reviews[review.ID] = *review
+	return review, nil
+}
+
+func (r *fakeReviews) GetByID(ctx context.Context, id string) (*domain.Review, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if review, ok := r.reviews[id]; ok {
+		return &review, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeReviews) UpdateStatus(ctx context.Context, review *domain.Review, from domain.ReviewStatus) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.reviews[review.ID]
+	if !ok || stored.Status != from {
+		return false, nil
+	}
+	r.reviews[review.ID] = *review
+	return true, nil
+}
+
+// fakeRatings keeps the rating totals of products. AdjustRating fails while
+// failures is positive.
+type fakeRatings struct {
+	persistence.ProductRepository
+	mu       sync.Mutex
+	totals   map[string][2]int
+	failures int
+}
+
+func (r *fakeRatings) GetByID(ctx context.Context, id string) (*domain.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.totals[id]; !ok {
+		return nil, nil
+	}
+	return &domain.Product{ID: id}, nil
+}
+
+func (r *fakeRatings) AdjustRating(ctx context.Context, productID string, totalDelta, countDelta int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failures > 0 {
+		r.failures--
+		return errors.New("write conflict")
+	}
+	rating := r.totals[productID]
+	r.totals[productID] = [2]int{rating[0] + totalDelta, rating[1] + countDelta}
+	return nil
+}
+
+func (r *fakeRatings) rating(productID string) (total, count int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.totals[productID][0], r.totals[productID][1]
+}
+
+// fakeReviewTx restores the reviews and ratings when fn fails, like a MongoDB
+// transaction that aborts.
+type fakeReviewTx struct {
+	reviews *fakeReviews
+	ratings *fakeRatings
+}
+
+func (tx fakeReviewTx) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx.reviews.mu.Lock()
+	reviews := make(map[string]domain.Review, len(tx.reviews.reviews))
+	for id, review := range tx.reviews.reviews {
+		reviews[id] = review
+	}
+	tx.reviews.mu.Unlock()
+	tx.ratings.mu.Lock()
+	totals := make(map[string][2]int, len(tx.ratings.totals))
+	for id, rating := range tx.ratings.totals {
+		totals[id] = rating
+	}
+	tx.ratings.mu.Unlock()
+
+	err := fn(ctx)
+	if err != nil {
+		tx.reviews.mu.Lock()
+		tx.reviews.reviews = reviews
+		tx.reviews.mu.Unlock()
+		tx.ratings.mu.Lock()
+		tx.ratings.totals = totals
+		tx.ratings.mu.Unlock()
+	}
+	return err
+}
+
+// fakeVerifier knows one delivered order per user.
+type fakeVerifier struct {
+	orders.PurchaseVerifier
+	delivered map[string]string
+}
+
+func (v fakeVerifier) DeliveredOrderID(ctx context.Context, userID, productID string) (string, error) {
+	return v.delivered[userID], nil
+}
+
+type reviewFixture struct {
+	reviews *fakeReviews
+	ratings *fakeRatings
+	uc      *ReviewUseCase
+}
+
+// newReviewFixture returns a use case for the unrated product "tea", which
+// user-1 received in order-1.
+func newReviewFixture() *reviewFixture {
+	f := &reviewFixture{
+		reviews: &fakeReviews{reviews: make(map[string]domain.Review)},
+		ratings: &fakeRatings{totals: map[string][2]int{"tea": {}}},
+	}
+	verifier := fakeVerifier{delivered: map[string]string{"user-1": "order-1"}}
+	f.uc = NewReviewUseCase(fakeReviewTx{f.reviews, f.ratings}, f.reviews, f.ratings, verifier)
+	return f
+}
+
+func TestCreateReviewNeedsAVerifiedPurchase(t *testing.T) {
+	f := newReviewFixture()
+	ctx := context.Background()
+
+	if _, err := f.uc.CreateReview(ctx, "tea", "user-2", 5, "great"); err == nil || !strings.Contains(err.Error(), "received the product") {
+		t.Fatalf("review without a delivered order: err = %v, want a refusal", err)
+	}
+	if len(f.reviews.reviews) != 0 {
+		t.Fatalf("stored %d reviews without a purchase", len(f.reviews.reviews))
+	}
+
+	review, err := f.uc.CreateReview(ctx, "tea", "user-1", 5, "great")
+	if err != nil {
+		t.Fatalf("CreateReview: %v", err)
+	}
+	if review.OrderID != "order-1" || review.Status != domain.ReviewStatusPending {
+		t.Fatalf("review of order %q is %s, want pending for order-1", review.OrderID, review.Status)
+	}
+}
+
+func TestCreateReviewRefusesASecondReview(t *testing.T) {
+	f := newReviewFixture()
+	ctx := context.Background()
+
+	if _, err := f.uc.CreateReview(ctx, "tea", "user-1", 5, "great"); err != nil {
+		t.Fatalf("CreateReview: %v", err)
+	}
+	if _, err := f.uc.CreateReview(ctx, "tea", "user-1", 1, "changed my mind"); !errors.Is(err, domain.ErrReviewExists) {
+		t.Fatalf("second review: err = %v, want ErrReviewExists", err)
+	}
+	if len(f.reviews.reviews) != 1 {
+		t.Fatalf("stored %d reviews, want 1", len(f.reviews.reviews))
+	}
+}
+
+func TestModerateReviewKeepsTheRatingInLine(t *testing.T) {
+	f := newReviewFixture()
+	ctx := context.Background()
+	review, err := f.uc.CreateReview(ctx, "tea", "user-1", 4, "good")
+	if err != nil {
+		t.Fatalf("CreateReview: %v", err)
+	}
+
+	steps := []struct {
+		name      string
+		approve   bool
+		wantTotal int
+		wantCount int
+	}{
+		{"approve", true, 4, 1},
+		{"approved to rejected", false, 0, 0},
+		{"rejected again", false, 0, 0},
+		{"rejected to approved", true, 4, 1},
+		{"approved again", true, 4, 1},
+	}
+	for _, step := range steps {
+		if _, err := f.uc.ModerateReview(ctx, review.ID, step.approve, "checked"); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if total, count := f.ratings.rating("tea"); total != step.wantTotal || count != step.wantCount {
+			t.Fatalf("after %s rating = %d over %d reviews, want %d over %d", step.name, total, count, step.wantTotal, step.wantCount)
+		}
+	}
+}
+
+func TestModerateReviewKeepsTheStatusWhenTheRatingFails(t *testing.T) {
+	f := newReviewFixture()
+	ctx := context.Background()
+	review, err := f.uc.CreateReview(ctx, "tea", "user-1", 4, "good")
+	if err != nil {
+		t.Fatalf("CreateReview: %v", err)
+	}
+
+	f.ratings.failures = 1
+	if _, err := f.uc.ModerateReview(ctx, review.ID, true, ""); err == nil {
+		t.Fatal("moderation succeeded although the rating was not updated")
+	}
+	if stored, _ := f.reviews.GetByID(ctx, review.ID); stored.Status != domain.ReviewStatusPending {
+		t.Fatalf("review is %s after the failed moderation, want pending", stored.Status)
+	}
+
+	if _, err := f.uc.ModerateReview(ctx, review.ID, true, ""); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if total, count := f.ratings.rating("tea"); total != 4 || count != 1 {
+		t.Fatalf("rating after the retry = %d over %d reviews, want 4 over 1", total, count)
+	}
+}
@@ -10,9 +10,21 @@ type MongoDBConfig struct {
 	Timeout  int    `yaml:"timeout"`
 }
 
+// NATSConfig also tunes the JetStream consumers. AckWait and Backoff are in
+// seconds.
 type NATSConfig struct {
 	URL     string `yaml:"url"`
 	Cluster string `yaml:"cluster"`
+	// Durable prefixes the consumer names, so that a restarted service
+	// resumes where it stopped.
+	Durable    string `yaml:"durable"`
+	MaxDeliver int    `yaml:"max_deliver"`
+	AckWait    int    `yaml:"ack_wait"`
+	// Backoff are the delays before the redeliveries of a failed event. The
+	// last one repeats.
+	Backoff []int `yaml:"backoff"`
+	// Workers bounds how many events a replica handles at once.
+	Workers int `yaml:"workers"`
 }
 
 type RedisConfig struct {
@@ -22,11 +34,16 @@ type RedisConfig struct {
 	TTL      int    `yaml:"ttl"`
 }
 
+type ServicesConfig struct {
+	Order string `yaml:"order"`
+}
+
 type Config struct {
-	Server  ServerConfig  `yaml:"server"`
-	MongoDB MongoDBConfig `yaml:"mongodb"`
-	NATS    NATSConfig    `yaml:"nats"`
-	Redis   RedisConfig   `yaml:"redis"`
+	Server   ServerConfig   `yaml:"server"`
+	MongoDB  MongoDBConfig  `yaml:"mongodb"`
+	NATS     NATSConfig     `yaml:"nats"`
+	Redis    RedisConfig    `yaml:"redis"`
+	Services ServicesConfig `yaml:"services"`
 }
 
 func LoadConfig() *Config {
@@ -40,8 +57,13 @@ func LoadConfig() *Config {
 			Timeout:  10,
 		},
 		NATS: NATSConfig{
-			URL:     "nats://localhost:4222",
-			Cluster: "microservices",
+			URL:        "nats://localhost:4222",
+			Cluster:    "microservices",
+			Durable:    "inventory-service",
+			MaxDeliver: 5,
+			AckWait:    60,
+			Backoff:    []int{1, 5, 30, 120},
+			Workers:    8,
 		},
 		Redis: RedisConfig{
 			URI:      "localhost:6379",
@@ -49,5 +71,8 @@ func LoadConfig() *Config {
 			DB:       0,
 			TTL:      60,
 		},
+		Services: ServicesConfig{
+			Order: "localhost:50052",
+		},
 	}
 }
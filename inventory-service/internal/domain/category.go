@@ -10,16 +10,18 @@ type Category struct {
 	ID          string
 	Name        string
 	Description string
+	TaxClass    TaxClass
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
 
-func NewCategory(name, description string) *Category {
+func NewCategory(name, description string, taxClass TaxClass) *Category {
 	now := time.Now()
 	return &Category{
 		ID:          uuid.New().String(),
 		Name:        name,
 		Description: description,
+		TaxClass:    taxClass,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
@@ -0,0 +1,98 @@
+package domain
+
+import "time"
+
+type OrderStockStatus string
+
+const (
+	// OrderStockPending is the status of a new record while its items are
+	// taken, in the same transaction that stores it. Records written before
+	// stock was taken in one transaction may still have it; a redelivered
+	// event resumes them with the items not applied yet.
+	OrderStockPending OrderStockStatus = "pending"
+	OrderStockApplied OrderStockStatus = "applied"
+	// OrderStockReleased means the order expired and gave its stock back. A
+	// created event arriving after that takes nothing.
+	OrderStockReleased OrderStockStatus = "released"
+)
+
+type OrderStockItem struct {
+	ProductID string
+	Quantity  int
+	Applied   bool
+}
+
+// OrderStock records what stock an order took, so that its created event is
+// applied exactly once however often it is delivered.
+type OrderStock struct {
+	OrderID   string
+	Status    OrderStockStatus
+	Items     []OrderStockItem
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewOrderStock(orderID string, items []StockItem) *OrderStock {
+	now := time.Now()
+	record := &OrderStock{
+		OrderID:   orderID,
+		Status:    OrderStockPending,
+		Items:     make([]OrderStockItem, len(items)),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	for i, item := range items {
+		record.Items[i] = OrderStockItem{ProductID: item.ProductID, Quantity: item.Quantity}
+	}
+	return record
+}
+
+// StockDelta is how much more (positive) or less (negative) of a product an
+// order holds after it was modified.
+type StockDelta struct {
+	ProductID string
+	Delta     int
+}
+
+// Held returns how much of the product the order holds.
+func (s *OrderStock) Held(productID string) int {
+	for _, item := range s.Items {
+		if item.ProductID == productID && item.Applied {
+			return item.Quantity
+		}
+	}
+	return 0
+}
+
+// SetHeld records that the order holds quantity of the product, dropping the
+// product when quantity is zero.
+func (s *OrderStock) SetHeld(productID string, quantity int) {
+	items := s.Items[:0]
+	found := false
+	for _, item := range s.Items {
+		if item.ProductID == productID {
+			found = true
+			if quantity <= 0 {
+				continue
+			}
+			item.Quantity = quantity
+			item.Applied = true
+		}
+		items = append(items, item)
+	}
+	if !found && quantity > 0 {
+		items = append(items, OrderStockItem{ProductID: productID, Quantity: quantity, Applied: true})
+	}
+	s.Items = items
+	s.UpdatedAt = time.Now()
+}
+
+func (s *OrderStock) SetStatus(status OrderStockStatus) {
+	s.Status = status
+	s.UpdatedAt = time.Now()
+}
+
+func (s *OrderStock) MarkItem(i int, applied bool) {
+	s.Items[i].Applied = applied
+	s.UpdatedAt = time.Now()
+}
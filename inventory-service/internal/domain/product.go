@@ -1,23 +1,49 @@
 package domain
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
+	"proto/money"
+
 	"github.com/google/uuid"
 )
 
+var (
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrProductNotFound   = errors.New("product not found")
+)
+
+// StockItem is a quantity of a product taken from or put back in stock.
+type StockItem struct {
+	ProductID string
+	Quantity  int
+}
+
 type Product struct {
 	ID          string
 	Name        string
 	Description string
-	Price       float64
+	Price       money.Money
 	Stock       int
 	CategoryID  string
+	TaxClass    TaxClass
+	// RatingTotal and RatingCount sum up the approved reviews.
+	RatingTotal int
+	RatingCount int
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
 
-func NewProduct(name, description string, price float64, stock int, categoryID string) *Product {
+func (p *Product) RatingAverage() float64 {
+	if p.RatingCount == 0 {
+		return 0
+	}
+	return float64(p.RatingTotal) / float64(p.RatingCount)
+}
+
+func NewProduct(name, description string, price money.Money, stock int, categoryID string, taxClass TaxClass) *Product {
 	now := time.Now()
 	return &Product{
 		ID:          uuid.New().String(),
@@ -26,7 +52,36 @@ func NewProduct(name, description string, price float64, stock int, categoryID s
 		Price:       price,
 		Stock:       stock,
 		CategoryID:  categoryID,
+		TaxClass:    taxClass,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
 }
+
+// MergeStockItems sums up the quantities of repeated products, keeping the
+// order in which products first appear.
+func MergeStockItems(items []StockItem) ([]StockItem, error) {
+	if len(items) == 0 {
+		return nil, errors.New("at least one item is required")
+	}
+
+	index := make(map[string]int, len(items))
+	merged := make([]StockItem, 0, len(items))
+	for _, item := range items {
+		if item.ProductID == "" {
+			return nil, errors.New("product ID is required")
+		}
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("quantity for product %s must be positive", item.ProductID)
+		}
+
+		if i, ok := index[item.ProductID]; ok {
+			merged[i].Quantity += item.Quantity
+			continue
+		}
+		index[item.ProductID] = len(merged)
+		merged = append(merged, item)
+	}
+
+	return merged, nil
+}
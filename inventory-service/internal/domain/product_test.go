@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeStockItemsSumsRepeatedProductsInOrder(t *testing.T) {
+	merged, err := MergeStockItems([]StockItem{
+		{ProductID: "milk", Quantity: 2},
+		{ProductID: "tea", Quantity: 1},
+		{ProductID: "milk", Quantity: 3},
+	})
+	if err != nil {
+		t.Fatalf("MergeStockItems: %v", err)
+	}
+
+	want := []StockItem{{ProductID: "milk", Quantity: 5}, {ProductID: "tea", Quantity: 1}}
+	if !reflect.DeepEqual(merged, want) {
+		t.Fatalf("merged = %+v, want %+v", merged, want)
+	}
+}
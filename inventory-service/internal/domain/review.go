@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// MaxReviewTextLength is the longest review text accepted, in characters.
+const MaxReviewTextLength = 4000
+
+// ErrReviewExists is returned for a second review of a product by the same
+// user.
+var ErrReviewExists = errors.New("product already reviewed by this user")
+
+type ReviewStatus string
+
+const (
+	ReviewStatusPending  ReviewStatus = "pending"
+	ReviewStatusApproved ReviewStatus = "approved"
+	ReviewStatusRejected ReviewStatus = "rejected"
+)
+
+// ReviewSort orders review listings.
+type ReviewSort string
+
+const (
+	ReviewSortNewest  ReviewSort = "newest"
+	ReviewSortOldest  ReviewSort = "oldest"
+	ReviewSortHighest ReviewSort = "highest"
+	ReviewSortLowest  ReviewSort = "lowest"
+)
+
+func (s ReviewSort) IsValid() bool {
+	return s == ReviewSortNewest || s == ReviewSortOldest || s == ReviewSortHighest || s == ReviewSortLowest
+}
+
+// Review is a customer's rating of a product they received. OrderID is the
+// delivered order that proves the purchase. Only approved reviews are shown
+// and counted in the product rating.
+type Review struct {
+	ID             string
+	ProductID      string
+	UserID         string
+	OrderID        string
+	Rating         int
+	Text           string
+	Status         ReviewStatus
+	ModerationNote string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func NewReview(productID, userID, orderID string, rating int, text string) (*Review, error) {
+	if productID == "" || userID == "" {
+		return nil, errors.New("product ID and user ID are required")
+	}
+	if rating < 1 || rating > 5 {
+		return nil, errors.New("rating must be between 1 and 5")
+	}
+	if utf8.RuneCountInString(text) > MaxReviewTextLength {
+		return nil, errors.New("review text is too long")
+	}
+
+	now := time.Now()
+	return &Review{
+		ID:        uuid.New().String(),
+		ProductID: productID,
+		UserID:    userID,
+		OrderID:   orderID,
+		Rating:    rating,
+		Text:      text,
+		Status:    ReviewStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// Moderate approves or rejects the review and returns how the product rating
+// totals change as a result.
+func (r *Review) Moderate(approve bool, note string) (ratingDelta, countDelta int) {
+	wasApproved := r.Status == ReviewStatusApproved
+
+	r.Status = ReviewStatusRejected
+	if approve {
+		r.Status = ReviewStatusApproved
+	}
+	r.ModerationNote = note
+	r.UpdatedAt = time.Now()
+
+	switch {
+	case approve && !wasApproved:
+		return r.Rating, 1
+	case !approve && wasApproved:
+		return -r.Rating, -1
+	default:
+		return 0, 0
+	}
+}
@@ -0,0 +1,19 @@
+package domain
+
+type TaxClass string
+
+const (
+	TaxClassStandard TaxClass = "standard"
+	TaxClassExempt   TaxClass = "exempt"
+)
+
+// IsValid reports whether the class is known. An empty class is valid and
+// means the value is inherited (product from category, category from the
+// standard rate).
+func (c TaxClass) IsValid() bool {
+	switch c {
+	case "", TaxClassStandard, TaxClassExempt:
+		return true
+	}
+	return false
+}
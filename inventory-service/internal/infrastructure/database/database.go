@@ -2,23 +2,70 @@ package database
 
 import (
 	"time"
+
+	"proto/money"
 )
 
 type ProductDTO struct {
-	ID          string    `bson:"_id,omitempty"`
-	Name        string    `bson:"name"`
-	Description string    `bson:"description"`
-	Price       float64   `bson:"price"`
-	Stock       int       `bson:"stock"`
-	CategoryID  string    `bson:"category_id"`
-	CreatedAt   time.Time `bson:"created_at"`
-	UpdatedAt   time.Time `bson:"updated_at"`
+	ID          string      `bson:"_id,omitempty"`
+	Name        string      `bson:"name"`
+	Description string      `bson:"description"`
+	Price       money.Money `bson:"price"`
+	Stock       int         `bson:"stock"`
+	CategoryID  string      `bson:"category_id"`
+	TaxClass    string      `bson:"tax_class,omitempty"`
+	RatingTotal int         `bson:"rating_total"`
+	RatingCount int         `bson:"rating_count"`
+	CreatedAt   time.Time   `bson:"created_at"`
+	UpdatedAt   time.Time   `bson:"updated_at"`
 }
 
 type CategoryDTO struct {
 	ID          string    `bson:"_id,omitempty"`
 	Name        string    `bson:"name"`
 	Description string    `bson:"description"`
+	TaxClass    string    `bson:"tax_class,omitempty"`
 	CreatedAt   time.Time `bson:"created_at"`
 	UpdatedAt   time.Time `bson:"updated_at"`
 }
+
+type ReviewDTO struct {
+	ID             string    `bson:"_id,omitempty"`
+	ProductID      string    `bson:"product_id"`
+	UserID         string    `bson:"user_id"`
+	OrderID        string    `bson:"order_id"`
+	Rating         int       `bson:"rating"`
+	Text           string    `bson:"text"`
+	Status         string    `bson:"status"`
+	ModerationNote string    `bson:"moderation_note,omitempty"`
+	CreatedAt      time.Time `bson:"created_at"`
+	UpdatedAt      time.Time `bson:"updated_at"`
+}
+
+// AppliedOrderDTO marks on a product that an order took stock of it.
+type AppliedOrderDTO struct {
+	OrderID  string    `bson:"order_id"`
+	Quantity int       `bson:"quantity"`
+	At       time.Time `bson:"at"`
+}
+
+type ProcessedOrderItemDTO struct {
+	ProductID string `bson:"product_id"`
+	Quantity  int    `bson:"quantity"`
+	Applied   bool   `bson:"applied"`
+}
+
+type ProcessedOrderDTO struct {
+	OrderID   string                  `bson:"_id"`
+	Status    string                  `bson:"status"`
+	Items     []ProcessedOrderItemDTO `bson:"items"`
+	CreatedAt time.Time               `bson:"created_at"`
+	UpdatedAt time.Time               `bson:"updated_at"`
+}
+
+// ProcessedEventDTO records that the stock changes of an event were applied.
+type ProcessedEventDTO struct {
+	EventID     string    `bson:"_id"`
+	Type        string    `bson:"type"`
+	ProcessedAt time.Time `bson:"processed_at"`
+}
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"context"
+	"log"
+
+	"proto/money"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// migrateLegacyMoney rewrites float prices in major units into
+// {amount, currency} documents in tiyn. Until it has run, the float values
+// are still readable through the money decoder of infra/moneybson.
+func (m *MongoDBConnector) migrateLegacyMoney(ctx context.Context) error {
+	filter := bson.M{"price": bson.M{"$type": "double"}}
+	update := bson.A{
+		bson.M{"$set": bson.M{"price": legacyMoneyExpr("$price")}},
+	}
+
+	result, err := m.ProductCollection().UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.ModifiedCount > 0 {
+		log.Printf("Migrated %d products to tiyn prices", result.ModifiedCount)
+	}
+
+	return nil
+}
+
+// legacyMoneyExpr converts a float field in major units into a money
+// document, leaving values that are already documents untouched.
+func legacyMoneyExpr(field string) bson.M {
+	return bson.M{"$cond": bson.A{
+		bson.M{"$eq": bson.A{bson.M{"$type": field}, "double"}},
+		bson.M{
+			"amount":   bson.M{"$toLong": bson.M{"$round": bson.A{bson.M{"$multiply": bson.A{field, 100}}, 0}}},
+			"currency": money.DefaultCurrency,
+		},
+		field,
+	}}
+}
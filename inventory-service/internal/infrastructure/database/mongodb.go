@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"infra/moneybson"
 	"inventory-service/internal/config"
 	"log"
 	"time"
@@ -24,6 +25,35 @@ func (m *MongoDBConnector) CategoryCollection() *mongo.Collection {
 	return m.Database.Collection("categories")
 }
 
+func (m *MongoDBConnector) ReviewCollection() *mongo.Collection {
+	return m.Database.Collection("reviews")
+}
+
+func (m *MongoDBConnector) ProcessedOrderCollection() *mongo.Collection {
+	return m.Database.Collection("processed_orders")
+}
+
+func (m *MongoDBConnector) ProcessedEventCollection() *mongo.Collection {
+	return m.Database.Collection("processed_events")
+}
+
+// WithTransaction runs fn in a multi-document transaction and commits it when
+// fn returns nil. fn must do all its reads and writes with the context it is
+// given and may run more than once when the transaction is retried. MongoDB
+// only supports transactions on a replica set.
+func (m *MongoDBConnector) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := m.Client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
+
 func (m *MongoDBConnector) initIndexes(ctx context.Context) error {
 	productNameIndex := mongo.IndexModel{
 		Keys:    bson.M{"name": 1},
@@ -50,6 +80,28 @@ func (m *MongoDBConnector) initIndexes(ctx context.Context) error {
 	_, err = m.CategoryCollection().Indexes().CreateMany(ctx, []mongo.IndexModel{
 		categoryNameIndex,
 	})
+	if err != nil {
+		return err
+	}
+
+	_, err = m.ReviewCollection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "product_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "product_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Events are only redelivered while they are in a stream or the dead
+	// letter queue, which keeps them for at most 30 days.
+	_, err = m.ProcessedEventCollection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.M{"processed_at": 1},
+		Options: options.Index().SetExpireAfterSeconds(int32((60 * 24 * time.Hour).Seconds())),
+	})
 
 	return err
 }
@@ -58,7 +110,7 @@ func NewMongoDB(cfg *config.Config) (*MongoDBConnector, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.MongoDB.Timeout)*time.Second)
 	defer cancel()
 
-	clientOptions := options.Client().ApplyURI(cfg.MongoDB.URI)
+	clientOptions := options.Client().ApplyURI(cfg.MongoDB.URI).SetRegistry(moneybson.Registry())
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
 		return nil, err
@@ -84,6 +136,10 @@ func NewMongoDB(cfg *config.Config) (*MongoDBConnector, error) {
 		log.Println("MongoDB indexes created successfully")
 	}
 
+	if err := mongodb.migrateLegacyMoney(ctx); err != nil {
+		log.Printf("Warning: failed to migrate legacy prices: %v", err)
+	}
+
 	return mongodb, nil
 }
 
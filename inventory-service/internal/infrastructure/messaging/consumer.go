@@ -2,27 +2,63 @@ package messaging
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"infra/streams"
 	"inventory-service/internal/config"
+	"proto/events"
 
 	"github.com/nats-io/nats.go"
 )
 
 type MessageHandler func(context.Context, *OrderCreatedEvent) error
 
+type OrderExpiredHandler func(context.Context, *OrderExpiredEvent) error
+
+// OrderModifiedHandler and ReturnReceivedHandler also get the event ID, which
+// stays the same when the event is redelivered or redriven.
+type OrderModifiedHandler func(ctx context.Context, eventID string, event *OrderModifiedEvent) error
+
+type ReturnReceivedHandler func(ctx context.Context, eventID string, event *ReturnReceivedEvent) error
+
 type EventConsumer interface {
 	SubscribeToOrderCreated(handler MessageHandler) error
-	PublishToDLQ(subject string, event []byte) error
+	SubscribeToOrderExpired(handler OrderExpiredHandler) error
+	SubscribeToOrderModified(handler OrderModifiedHandler) error
+	SubscribeToReturnReceived(handler ReturnReceivedHandler) error
 	Close()
 }
 
+// NATSConsumer reads the order events from durable JetStream consumers. An
+// event is acknowledged once handled, and redelivered with a backoff when its
+// handler fails.
+//
+// Replicas share the consumers through queue groups, so every event is
+// handled by one of them. Within a replica the events are handled by a pool
+// of workers, in order for the same product. That order holds for the events
+// one replica received only: events of a product may go to different
+// replicas, and a redelivered event comes after the ones published later. The
+// stock handlers therefore do not rely on it: the stock ledger applies each
+// event once, retries a modification until the order took its stock and has a
+// created event that arrives after the expiry take nothing.
 type NATSConsumer struct {
-	conn         *nats.Conn
-	subscription *nats.Subscription
+	conn    *nats.Conn
+	js      nats.JetStreamContext
+	durable string
+	policy  DeliveryPolicy
+	pool    *WorkerPool
+	closing atomic.Bool
+
+	// failures remembers when events being retried first failed. It is lost
+	// on restart, the dead letter then gives the time of the first failure
+	// seen by this process.
+	mu       sync.Mutex
+	failures map[string]time.Time
 }
 
 func NewNATSConsumer(cfg *config.Config) (*NATSConsumer, error) {
@@ -37,26 +73,48 @@ func NewNATSConsumer(cfg *config.Config) (*NATSConsumer, error) {
 		return nil, err
 	}
 
+	js, err := nc.JetStream()
+	if err == nil {
+		err = streams.EnsureStream(js, streams.OrderStream)
+	}
+	if err == nil {
+		err = streams.EnsureStream(js, streams.DeadLetterStream)
+	}
+	if err != nil {
+		nc.Close()
+		return nil, err
+	}
+
 	log.Printf("[%s] Successfully connected to NATS [latency: %v]",
 		time.Now().Format(time.RFC3339Nano), time.Since(startTime))
 	return &NATSConsumer{
-		conn: nc,
+		conn:     nc,
+		js:       js,
+		durable:  cfg.NATS.Durable,
+		policy:   NewDeliveryPolicy(cfg.NATS),
+		pool:     NewWorkerPool(cfg.NATS.Workers),
+		failures: make(map[string]time.Time),
 	}, nil
 }
 
+// DeadLetters gives access to the events that failed on their last delivery.
+func (c *NATSConsumer) DeadLetters() *DeadLetterQueue {
+	return NewDeadLetterQueue(c.js)
+}
+
 func (c *NATSConsumer) SubscribeToOrderCreated(handler MessageHandler) error {
 	startTime := time.Now()
 	log.Printf("[%s] Subscribing to subject: %s",
 		startTime.Format(time.RFC3339Nano), SubjectOrderCreated)
 
-	sub, err := c.conn.Subscribe(SubjectOrderCreated, func(msg *nats.Msg) {
+	err := c.subscribe(SubjectOrderCreated, func(msg *nats.Msg) {
 		receiveTime := time.Now()
 		log.Printf("[%s] Received message from subject: %s (%.2f KB)",
 			receiveTime.Format(time.RFC3339Nano),
 			msg.Subject, float64(len(msg.Data))/1024.0)
 
 		var event OrderCreatedEvent
-		err := json.Unmarshal(msg.Data, &event)
+		_, err := decodeEvent(msg, &event)
 		if err != nil {
 			log.Printf("[%s] Error unmarshalling message: %v",
 				time.Now().Format(time.RFC3339Nano), err)
@@ -64,40 +122,42 @@ func (c *NATSConsumer) SubscribeToOrderCreated(handler MessageHandler) error {
 			fmt.Printf("EVENT_UNMARSHALLING_ERROR,subject=%s,timestamp=%d,error=%s\n",
 				msg.Subject, receiveTime.UnixNano(), err.Error())
 
-			c.PublishToDLQ(SubjectDeadLetter, msg.Data)
+			c.deadLetter(msg, err)
 			return
 		}
 
-		msgLatency := float64(receiveTime.UnixNano()-event.Timestamp) / 1e6
+		c.pool.Submit(event.ProductIDs(), func() {
+			msgLatency := float64(receiveTime.UnixNano()-event.Timestamp) / 1e6
 
-		log.Printf("[%s] Received order.created event for order ID: %s with %d items (message latency: %.2f ms)",
-			receiveTime.Format(time.RFC3339Nano), event.OrderID, len(event.Items), msgLatency)
+			log.Printf("[%s] Received order.created event for order ID: %s with %d items (message latency: %.2f ms)",
+				receiveTime.Format(time.RFC3339Nano), event.OrderID, len(event.Items), msgLatency)
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
 
-		fmt.Printf("EVENT_RECEIVED,order_id=%s,timestamp=%d,items=%d,latency_ms=%.2f\n",
-			event.OrderID, receiveTime.UnixNano(), len(event.Items), msgLatency)
+			fmt.Printf("EVENT_RECEIVED,order_id=%s,timestamp=%d,items=%d,latency_ms=%.2f\n",
+				event.OrderID, receiveTime.UnixNano(), len(event.Items), msgLatency)
 
-		procStartTime := time.Now()
-		err = handler(ctx, &event)
-		procDuration := time.Since(procStartTime)
+			procStartTime := time.Now()
+			err = handler(ctx, &event)
+			procDuration := time.Since(procStartTime)
 
-		if err != nil {
-			log.Printf("[%s] Error handling order created event: %v [processing_time: %v]",
-				time.Now().Format(time.RFC3339Nano), err, procDuration)
+			if err != nil {
+				log.Printf("[%s] Error handling order created event: %v [processing_time: %v]",
+					time.Now().Format(time.RFC3339Nano), err, procDuration)
 
-			fmt.Printf("EVENT_PROCESSING_ERROR,order_id=%s,timestamp=%d,error=%s,proc_time_ms=%.2f\n",
-				event.OrderID, time.Now().UnixNano(), err.Error(), float64(procDuration.Microseconds())/1000.0)
+				fmt.Printf("EVENT_PROCESSING_ERROR,order_id=%s,timestamp=%d,error=%s,proc_time_ms=%.2f\n",
+					event.OrderID, time.Now().UnixNano(), err.Error(), float64(procDuration.Microseconds())/1000.0)
+			} else {
+				log.Printf("[%s] Successfully processed order created event for order ID: %s [processing_time: %v]",
+					time.Now().Format(time.RFC3339Nano), event.OrderID, procDuration)
 
-			c.PublishToDLQ(SubjectDeadLetter, msg.Data)
-		} else {
-			log.Printf("[%s] Successfully processed order created event for order ID: %s [processing_time: %v]",
-				time.Now().Format(time.RFC3339Nano), event.OrderID, procDuration)
+				fmt.Printf("EVENT_PROCESSED,order_id=%s,timestamp=%d,proc_time_ms=%.2f\n",
+					event.OrderID, time.Now().UnixNano(), float64(procDuration.Microseconds())/1000.0)
+			}
 
-			fmt.Printf("EVENT_PROCESSED,order_id=%s,timestamp=%d,proc_time_ms=%.2f\n",
-				event.OrderID, time.Now().UnixNano(), float64(procDuration.Microseconds())/1000.0)
-		}
+			c.settle(msg, err)
+		})
 	})
 
 	if err != nil {
@@ -109,39 +169,264 @@ func (c *NATSConsumer) SubscribeToOrderCreated(handler MessageHandler) error {
 	log.Printf("[%s] Successfully subscribed to subject: %s [latency: %v]",
 		time.Now().Format(time.RFC3339Nano), SubjectOrderCreated, time.Since(startTime))
 
-	c.subscription = sub
 	return nil
 }
 
-func (c *NATSConsumer) PublishToDLQ(subject string, event []byte) error {
+func (c *NATSConsumer) SubscribeToOrderExpired(handler OrderExpiredHandler) error {
+	err := c.subscribe(SubjectOrderExpired, func(msg *nats.Msg) {
+		var event OrderExpiredEvent
+		if _, err := decodeEvent(msg, &event); err != nil {
+			log.Printf("[%s] Error unmarshalling %s message: %v",
+				time.Now().Format(time.RFC3339Nano), msg.Subject, err)
+			c.deadLetter(msg, err)
+			return
+		}
+
+		c.pool.Submit(event.ProductIDs(), func() {
+			log.Printf("[%s] Received order.expired event for order ID: %s with %d items",
+				time.Now().Format(time.RFC3339Nano), event.OrderID, len(event.Items))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			err := handler(ctx, &event)
+			if err != nil {
+				log.Printf("[%s] Error handling order expired event: %v",
+					time.Now().Format(time.RFC3339Nano), err)
+			}
+			c.settle(msg, err)
+		})
+	})
+	if err != nil {
+		log.Printf("[%s] Error subscribing to subject %s: %v",
+			time.Now().Format(time.RFC3339Nano), SubjectOrderExpired, err)
+		return err
+	}
+
+	return nil
+}
+
+func (c *NATSConsumer) SubscribeToOrderModified(handler OrderModifiedHandler) error {
+	err := c.subscribe(SubjectOrderModified, func(msg *nats.Msg) {
+		var event OrderModifiedEvent
+		eventID, err := decodeEvent(msg, &event)
+		if err != nil {
+			log.Printf("[%s] Error unmarshalling %s message: %v",
+				time.Now().Format(time.RFC3339Nano), msg.Subject, err)
+			c.deadLetter(msg, err)
+			return
+		}
+
+		c.pool.Submit(event.ProductIDs(), func() {
+			log.Printf("[%s] Received order.modified event for order ID: %s with %d deltas",
+				time.Now().Format(time.RFC3339Nano), event.OrderID, len(event.Deltas))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			err := handler(ctx, eventID, &event)
+			if err != nil {
+				log.Printf("[%s] Error handling order modified event: %v",
+					time.Now().Format(time.RFC3339Nano), err)
+			}
+			c.settle(msg, err)
+		})
+	})
+	if err != nil {
+		log.Printf("[%s] Error subscribing to subject %s: %v",
+			time.Now().Format(time.RFC3339Nano), SubjectOrderModified, err)
+		return err
+	}
+
+	return nil
+}
+
+func (c *NATSConsumer) SubscribeToReturnReceived(handler ReturnReceivedHandler) error {
+	err := c.subscribe(SubjectReturnReceived, func(msg *nats.Msg) {
+		var event ReturnReceivedEvent
+		eventID, err := decodeEvent(msg, &event)
+		if err != nil {
+			log.Printf("[%s] Error unmarshalling %s message: %v",
+				time.Now().Format(time.RFC3339Nano), msg.Subject, err)
+			c.deadLetter(msg, err)
+			return
+		}
+
+		c.pool.Submit(event.ProductIDs(), func() {
+			log.Printf("[%s] Received return.received event for return ID: %s with %d items",
+				time.Now().Format(time.RFC3339Nano), event.ReturnID, len(event.Items))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			err := handler(ctx, eventID, &event)
+			if err != nil {
+				log.Printf("[%s] Error handling return received event: %v",
+					time.Now().Format(time.RFC3339Nano), err)
+			}
+			c.settle(msg, err)
+		})
+	})
+	if err != nil {
+		log.Printf("[%s] Error subscribing to subject %s: %v",
+			time.Now().Format(time.RFC3339Nano), SubjectReturnReceived, err)
+		return err
+	}
+
+	return nil
+}
+
+// decodeEvent reads the event in msg, in protobuf or, from older publishers,
+// in JSON with or without envelope. An event of another
+// type or of a schema version this service does not know goes to the dead
+// letter queue like a malformed one, to be redriven after an upgrade.
+// It returns the event ID; bare events from older publishers have none and
+// are identified by their stream sequence instead.
+func decodeEvent(msg *nats.Msg, event events.Event) (string, error) {
+	envelope, err := events.Parse(msg.Subject, msg.Header, msg.Data)
+	if err != nil {
+		return "", err
+	}
+	if envelope.Type != msg.Subject {
+		return "", fmt.Errorf("unexpected event type %s on subject %s", envelope.Type, msg.Subject)
+	}
+	if err := envelope.Decode(event); err != nil {
+		return "", err
+	}
+
+	id := envelope.ID
+	if meta, err := msg.Metadata(); id == "" && err == nil {
+		id = fmt.Sprintf("%s:%d", meta.Stream, meta.Sequence.Stream)
+	}
+	return id, nil
+}
+
+// subscribe joins the queue group of the subject's durable consumer. The
+// handler runs on the subscription's goroutine and hands the event over to the
+// worker pool, so the events of a subject reach the pool in the order they
+// arrived.
+func (c *NATSConsumer) subscribe(subject string, handler nats.MsgHandler) error {
+	durable := durableName(c.durable, subject)
+	_, err := c.js.QueueSubscribe(subject, durable, func(msg *nats.Msg) {
+		if c.closing.Load() {
+			// Let another replica have it right away.
+			msg.Nak()
+			return
+		}
+		if target := msg.Header.Get(HeaderRedriveConsumer); target != "" && target != durable {
+			// Redriven for another consumer, this one handled it already.
+			msg.Ack()
+			return
+		}
+		handler(msg)
+	}, c.policy.subscribeOptions(durable)...)
+	return err
+}
+
+// settle acknowledges a handled event. A failed one is delivered again after a
+// backoff, and moved to the dead letter queue on its last delivery.
+func (c *NATSConsumer) settle(msg *nats.Msg, err error) {
+	if err == nil {
+		c.forgetFailure(msg)
+		if err := msg.Ack(); err != nil {
+			log.Printf("[%s] Error acknowledging %s message: %v",
+				time.Now().Format(time.RFC3339Nano), msg.Subject, err)
+		}
+		return
+	}
+
+	meta, metaErr := msg.Metadata()
+	if metaErr != nil || (c.policy.MaxDeliver > 0 && meta.NumDelivered >= uint64(c.policy.MaxDeliver)) {
+		c.deadLetter(msg, err)
+		return
+	}
+
+	c.mu.Lock()
+	if _, ok := c.failures[failureKey(meta)]; !ok {
+		c.failures[failureKey(meta)] = time.Now()
+	}
+	c.mu.Unlock()
+
+	delay := c.policy.Delay(meta.NumDelivered)
+	log.Printf("[%s] Redelivering %s message in %v (delivery %d of %d)",
+		time.Now().Format(time.RFC3339Nano), msg.Subject, delay, meta.NumDelivered, c.policy.MaxDeliver)
+	if err := msg.NakWithDelay(delay); err != nil {
+		log.Printf("[%s] Error rejecting %s message: %v",
+			time.Now().Format(time.RFC3339Nano), msg.Subject, err)
+	}
+}
+
+// deadLetter moves an event that cannot be handled to the dead letter queue
+// and stops its redelivery.
+func (c *NATSConsumer) deadLetter(msg *nats.Msg, cause error) {
+	consumer, attempts := "", 1
+	firstFailure := c.forgetFailure(msg)
+	if meta, err := msg.Metadata(); err == nil {
+		consumer, attempts = meta.Consumer, int(meta.NumDelivered)
+	}
+
+	if err := c.publishDeadLetter(newDeadLetterMsg(msg, consumer, attempts, firstFailure, cause)); err != nil {
+		// Without a dead letter the event is not terminated, so it comes
+		// back after the ack wait if it has deliveries left.
+		return
+	}
+	if err := msg.Term(); err != nil {
+		log.Printf("[%s] Error terminating %s message: %v",
+			time.Now().Format(time.RFC3339Nano), msg.Subject, err)
+	}
+}
+
+// forgetFailure returns when the event first failed, or now when it did not
+// fail before.
+func (c *NATSConsumer) forgetFailure(msg *nats.Msg) time.Time {
+	meta, err := msg.Metadata()
+	if err != nil {
+		return time.Now()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	firstFailure, ok := c.failures[failureKey(meta)]
+	if !ok {
+		return time.Now()
+	}
+	delete(c.failures, failureKey(meta))
+	return firstFailure
+}
+
+func failureKey(meta *nats.MsgMetadata) string {
+	return meta.Consumer + "/" + strconv.FormatUint(meta.Sequence.Stream, 10)
+}
+
+func (c *NATSConsumer) publishDeadLetter(msg *nats.Msg) error {
 	startTime := time.Now()
-	log.Printf("[%s] Publishing message to DLQ: %s (%.2f KB)",
-		startTime.Format(time.RFC3339Nano), subject, float64(len(event))/1024.0)
+	source := msg.Header.Get(HeaderDeadLetterSubject)
+	log.Printf("[%s] Publishing %s message to DLQ after %s attempts: %s",
+		startTime.Format(time.RFC3339Nano), source,
+		msg.Header.Get(HeaderDeadLetterAttempts), msg.Header.Get(HeaderDeadLetterError))
 
-	err := c.conn.Publish(subject, event)
+	_, err := c.js.PublishMsg(msg)
 	if err != nil {
 		log.Printf("[%s] Error publishing to DLQ: %v [latency: %v]",
 			time.Now().Format(time.RFC3339Nano), err, time.Since(startTime))
 		return err
 	}
 
-	log.Printf("[%s] Successfully published message to DLQ: %s [latency: %v]",
-		time.Now().Format(time.RFC3339Nano), subject, time.Since(startTime))
-
 	fmt.Printf("DLQ_PUBLISHED,subject=%s,timestamp=%d,size=%.2fKB,latency_ms=%.2f\n",
-		subject, startTime.UnixNano(), float64(len(event))/1024.0,
+		source, startTime.UnixNano(), float64(len(msg.Data))/1024.0,
 		float64(time.Since(startTime).Microseconds())/1000.0)
 
 	return nil
 }
 
+// Close waits for the events in flight. It leaves the durable consumers in
+// place: unsubscribing would delete them together with the events they hold
+// for this service.
 func (c *NATSConsumer) Close() {
 	closeTime := time.Now()
 
-	if c.subscription != nil {
-		c.subscription.Unsubscribe()
-		log.Printf("[%s] Unsubscribed from NATS", closeTime.Format(time.RFC3339Nano))
-	}
+	c.closing.Store(true)
+	c.pool.Wait()
 
 	if c.conn != nil {
 		c.conn.Close()
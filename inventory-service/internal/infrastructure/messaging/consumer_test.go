@@ -0,0 +1,385 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"inventory-service/internal/config"
+	"proto/events"
+
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+)
+
+func runJetStream(t *testing.T) *config.Config {
+	t.Helper()
+
+	opts := natsserver.DefaultTestOptions
+	opts.Port = -1
+	opts.JetStream = true
+	opts.StoreDir = t.TempDir()
+	srv := natsserver.RunServer(&opts)
+	t.Cleanup(srv.Shutdown)
+
+	cfg := config.LoadConfig()
+	cfg.NATS.URL = srv.ClientURL()
+	cfg.NATS.MaxDeliver = 3
+	return cfg
+}
+
+func newTestConsumer(t *testing.T, cfg *config.Config) *NATSConsumer {
+	t.Helper()
+
+	consumer, err := NewNATSConsumer(cfg)
+	if err != nil {
+		t.Fatalf("NewNATSConsumer: %v", err)
+	}
+	consumer.policy.Backoff = []time.Duration{10 * time.Millisecond}
+	t.Cleanup(consumer.Close)
+	return consumer
+}
+
+func publishOrderCreated(t *testing.T, cfg *config.Config, orderID string) {
+	t.Helper()
+
+	envelope, err := events.New("/order-service", events.TypeOrderCreated, orderID,
+		&OrderCreatedEvent{OrderID: orderID, Timestamp: time.Now().UnixNano()})
+	if err != nil {
+		t.Fatalf("envelope: %v", err)
+	}
+	publishEnvelope(t, cfg, envelope)
+}
+
+func publishEnvelope(t *testing.T, cfg *config.Config, envelope *events.Envelope) {
+	t.Helper()
+	publish(t, cfg, &nats.Msg{Subject: envelope.Type, Header: envelope.Header(), Data: envelope.Data})
+}
+
+func publishJSON(t *testing.T, cfg *config.Config, subject string, body any) {
+	t.Helper()
+	data, _ := json.Marshal(body)
+	publish(t, cfg, &nats.Msg{Subject: subject, Data: data})
+}
+
+func publish(t *testing.T, cfg *config.Config, msg *nats.Msg) {
+	t.Helper()
+
+	nc, err := nats.Connect(cfg.NATS.URL)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer nc.Close()
+	js, err := nc.JetStream()
+	if err != nil {
+		t.Fatalf("jetstream: %v", err)
+	}
+
+	if _, err := js.PublishMsg(msg); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+}
+
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a message")
+	}
+	var zero T
+	return zero
+}
+
+func TestConsumerReceivesEventsPublishedWhileDown(t *testing.T) {
+	cfg := runJetStream(t)
+
+	first := newTestConsumer(t, cfg)
+	if err := first.SubscribeToOrderCreated(func(context.Context, *OrderCreatedEvent) error { return nil }); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	first.Close()
+
+	publishOrderCreated(t, cfg, "order-1")
+
+	received := make(chan string, 1)
+	second := newTestConsumer(t, cfg)
+	err := second.SubscribeToOrderCreated(func(_ context.Context, event *OrderCreatedEvent) error {
+		received <- event.OrderID
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	if orderID := receive(t, received); orderID != "order-1" {
+		t.Fatalf("received order %q, want order-1", orderID)
+	}
+}
+
+func TestConsumerReadsJSONEvents(t *testing.T) {
+	cfg := runJetStream(t)
+	consumer := newTestConsumer(t, cfg)
+
+	received := make(chan string, 2)
+	err := consumer.SubscribeToOrderCreated(func(_ context.Context, event *OrderCreatedEvent) error {
+		received <- event.OrderID
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	publishJSON(t, cfg, SubjectOrderCreated, OrderCreatedEvent{OrderID: "order-1"})
+	publishJSON(t, cfg, SubjectOrderCreated, map[string]any{
+		"id":              "event-2",
+		"source":          "/order-service",
+		"specversion":     events.SpecVersion,
+		"type":            events.TypeOrderCreated,
+		"datacontenttype": events.ContentTypeJSON,
+		"schemaversion":   1,
+		"data":            OrderCreatedEvent{OrderID: "order-2"},
+	})
+
+	for _, want := range []string{"order-1", "order-2"} {
+		if orderID := receive(t, received); orderID != want {
+			t.Fatalf("received order %q, want %s", orderID, want)
+		}
+	}
+}
+
+func TestConsumerDeadLettersUnknownSchemaVersions(t *testing.T) {
+	cfg := runJetStream(t)
+	consumer := newTestConsumer(t, cfg)
+
+	dead := make(chan *nats.Msg, 1)
+	sub, err := consumer.conn.ChanSubscribe(SubjectDeadLetter, dead)
+	if err != nil {
+		t.Fatalf("subscribe to DLQ: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	var calls atomic.Int32
+	err = consumer.SubscribeToOrderCreated(func(context.Context, *OrderCreatedEvent) error {
+		calls.Add(1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	envelope, _ := events.New("/order-service", events.TypeOrderCreated, "order-1", &OrderCreatedEvent{OrderID: "order-1"})
+	envelope.SchemaVersion = 2
+	publishEnvelope(t, cfg, envelope)
+
+	msg := receive(t, dead)
+	if got := msg.Header.Get(HeaderDeadLetterError); got != "unsupported event schema version: order.created version 2" {
+		t.Fatalf("dead letter error %q", got)
+	}
+	if n := calls.Load(); n != 0 {
+		t.Fatalf("handler called %d times, want 0", n)
+	}
+}
+
+func TestReplicasShareEvents(t *testing.T) {
+	cfg := runJetStream(t)
+
+	var handled sync.Map
+	var duplicates, total atomic.Int32
+	done := make(chan struct{}, 20)
+	for i := 0; i < 2; i++ {
+		replica := newTestConsumer(t, cfg)
+		err := replica.SubscribeToOrderCreated(func(_ context.Context, event *OrderCreatedEvent) error {
+			if _, loaded := handled.LoadOrStore(event.OrderID, true); loaded {
+				duplicates.Add(1)
+			}
+			total.Add(1)
+			done <- struct{}{}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("subscribe: %v", err)
+		}
+	}
+
+	for i := 0; i < 20; i++ {
+		publishOrderCreated(t, cfg, fmt.Sprintf("order-%d", i))
+	}
+	for i := 0; i < 20; i++ {
+		receive(t, done)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	if n := total.Load(); n != 20 || duplicates.Load() != 0 {
+		t.Fatalf("handled %d events with %d duplicates, want 20 without duplicates", n, duplicates.Load())
+	}
+}
+
+func TestConsumerRedeliversFailedEvents(t *testing.T) {
+	cfg := runJetStream(t)
+	consumer := newTestConsumer(t, cfg)
+
+	var calls atomic.Int32
+	handled := make(chan int32, 1)
+	err := consumer.SubscribeToOrderCreated(func(context.Context, *OrderCreatedEvent) error {
+		n := calls.Add(1)
+		if n < 3 {
+			return errors.New("temporary failure")
+		}
+		handled <- n
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	publishOrderCreated(t, cfg, "order-1")
+
+	if n := receive(t, handled); n != 3 {
+		t.Fatalf("handled on call %d, want 3", n)
+	}
+}
+
+func TestConsumerDeadLettersAfterMaxDeliver(t *testing.T) {
+	cfg := runJetStream(t)
+	consumer := newTestConsumer(t, cfg)
+
+	dead := make(chan *nats.Msg, 1)
+	sub, err := consumer.conn.ChanSubscribe(SubjectDeadLetter, dead)
+	if err != nil {
+		t.Fatalf("subscribe to DLQ: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	var calls atomic.Int32
+	err = consumer.SubscribeToOrderCreated(func(context.Context, *OrderCreatedEvent) error {
+		calls.Add(1)
+		return errors.New("permanent failure")
+	})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	publishOrderCreated(t, cfg, "order-1")
+
+	msg := receive(t, dead)
+	var event OrderCreatedEvent
+	envelope, err := events.Parse(SubjectOrderCreated, msg.Header, msg.Data)
+	if err == nil {
+		err = envelope.Decode(&event)
+	}
+	if err != nil || event.OrderID != "order-1" {
+		t.Fatalf("dead letter is not the order event: %v", err)
+	}
+	if n := calls.Load(); n != int32(cfg.NATS.MaxDeliver) {
+		t.Fatalf("handler called %d times, want %d", n, cfg.NATS.MaxDeliver)
+	}
+
+	for header, want := range map[string]string{
+		HeaderDeadLetterSubject:  SubjectOrderCreated,
+		HeaderDeadLetterConsumer: "inventory-service-order-created",
+		HeaderDeadLetterError:    "permanent failure",
+		HeaderDeadLetterAttempts: "3",
+	} {
+		if got := msg.Header.Get(header); got != want {
+			t.Errorf("header %s = %q, want %q", header, got, want)
+		}
+	}
+	if _, err := time.Parse(time.RFC3339Nano, msg.Header.Get(HeaderDeadLetterFirstFailure)); err != nil {
+		t.Errorf("header %s: %v", HeaderDeadLetterFirstFailure, err)
+	}
+}
+
+func TestDeadLetterQueueRedrive(t *testing.T) {
+	cfg := runJetStream(t)
+	consumer := newTestConsumer(t, cfg)
+	queue := consumer.DeadLetters()
+	ctx := context.Background()
+
+	var healthy atomic.Bool
+	handled := make(chan string, 1)
+	err := consumer.SubscribeToOrderCreated(func(_ context.Context, event *OrderCreatedEvent) error {
+		if !healthy.Load() {
+			return errors.New("database is down")
+		}
+		handled <- event.OrderID
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	publishOrderCreated(t, cfg, "order-1")
+
+	var letters []*DeadLetter
+	deadline := time.Now().Add(5 * time.Second)
+	for len(letters) == 0 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+		letters, _, err = queue.List(ctx, SubjectOrderCreated, 1, 10)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+	}
+	if len(letters) != 1 {
+		t.Fatalf("got %d dead letters, want 1", len(letters))
+	}
+	if letters[0].Attempts != cfg.NATS.MaxDeliver || letters[0].Error != "database is down" {
+		t.Fatalf("unexpected dead letter %+v", letters[0])
+	}
+
+	healthy.Store(true)
+	if _, err := queue.Redrive(ctx, letters[0].Sequence); err != nil {
+		t.Fatalf("redrive: %v", err)
+	}
+	if orderID := receive(t, handled); orderID != "order-1" {
+		t.Fatalf("handled order %q, want order-1", orderID)
+	}
+
+	if letter, err := queue.Get(ctx, letters[0].Sequence); err != nil || letter != nil {
+		t.Fatalf("redriven dead letter is still queued: %v", err)
+	}
+}
+
+func TestDeadLetterQueuePurge(t *testing.T) {
+	cfg := runJetStream(t)
+	consumer := newTestConsumer(t, cfg)
+	queue := consumer.DeadLetters()
+	ctx := context.Background()
+
+	for _, subject := range []string{SubjectOrderCreated, SubjectOrderCreated, SubjectOrderExpired} {
+		msg := &nats.Msg{Subject: subject, Data: []byte("{}")}
+		if _, err := consumer.js.PublishMsg(newDeadLetterMsg(msg, "test", 1, time.Now(), errors.New("failed"))); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+
+	purged, err := queue.Purge(ctx, SubjectOrderCreated)
+	if err != nil || purged != 2 {
+		t.Fatalf("purged %d dead letters (%v), want 2", purged, err)
+	}
+	if _, total, err := queue.List(ctx, "", 1, 10); err != nil || total != 1 {
+		t.Fatalf("%d dead letters left (%v), want 1", total, err)
+	}
+
+	purged, err = queue.Purge(ctx, "")
+	if err != nil || purged != 1 {
+		t.Fatalf("purged %d dead letters (%v), want 1", purged, err)
+	}
+}
+
+func TestDeliveryPolicyDelay(t *testing.T) {
+	policy := NewDeliveryPolicy(config.NATSConfig{Backoff: []int{1, 5}})
+
+	for delivered, want := range map[uint64]time.Duration{1: time.Second, 2: 5 * time.Second, 7: 5 * time.Second} {
+		if got := policy.Delay(delivered); got != want {
+			t.Errorf("Delay(%d) = %v, want %v", delivered, got, want)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"infra/streams"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Headers of a dead letter. The body is the event as it was published.
+const (
+	HeaderDeadLetterSubject      = "Dlq-Source-Subject"
+	HeaderDeadLetterConsumer     = "Dlq-Consumer"
+	HeaderDeadLetterError        = "Dlq-Error"
+	HeaderDeadLetterAttempts     = "Dlq-Attempts"
+	HeaderDeadLetterFirstFailure = "Dlq-First-Failure"
+	// HeaderRedriveConsumer marks a redriven event. Only the named consumer
+	// handles it, the others already did.
+	HeaderRedriveConsumer = "Dlq-Redrive-Consumer"
+)
+
+// DeadLetter is an event that failed on its last delivery.
+type DeadLetter struct {
+	Sequence      uint64
+	SourceSubject string
+	Consumer      string
+	Error         string
+	Attempts      int
+	FirstFailure  time.Time
+	FailedAt      time.Time
+	Header        nats.Header
+	Data          []byte
+}
+
+func newDeadLetter(sequence uint64, failedAt time.Time, header nats.Header, data []byte) *DeadLetter {
+	attempts, _ := strconv.Atoi(header.Get(HeaderDeadLetterAttempts))
+	firstFailure, _ := time.Parse(time.RFC3339Nano, header.Get(HeaderDeadLetterFirstFailure))
+	return &DeadLetter{
+		Sequence:      sequence,
+		SourceSubject: header.Get(HeaderDeadLetterSubject),
+		Consumer:      header.Get(HeaderDeadLetterConsumer),
+		Error:         header.Get(HeaderDeadLetterError),
+		Attempts:      attempts,
+		FirstFailure:  firstFailure,
+		FailedAt:      failedAt,
+		Header:        header,
+		Data:          data,
+	}
+}
+
+// newDeadLetterMsg wraps a failed event. The headers of the event are kept so
+// that a redrive publishes it unchanged.
+func newDeadLetterMsg(msg *nats.Msg, consumer string, attempts int, firstFailure time.Time, cause error) *nats.Msg {
+	dead := nats.NewMsg(SubjectDeadLetter)
+	for key, values := range msg.Header {
+		dead.Header[key] = values
+	}
+	if previous := msg.Header.Get(HeaderDeadLetterFirstFailure); previous != "" {
+		// A redriven event keeps the time of its first failure.
+		firstFailure, _ = time.Parse(time.RFC3339Nano, previous)
+	}
+	dead.Header.Del(HeaderRedriveConsumer)
+	dead.Header.Set(HeaderDeadLetterSubject, msg.Subject)
+	dead.Header.Set(HeaderDeadLetterConsumer, consumer)
+	dead.Header.Set(HeaderDeadLetterError, cause.Error())
+	dead.Header.Set(HeaderDeadLetterAttempts, strconv.Itoa(attempts))
+	dead.Header.Set(HeaderDeadLetterFirstFailure, firstFailure.UTC().Format(time.RFC3339Nano))
+	dead.Data = msg.Data
+	return dead
+}
+
+// DeadLetterQueue lets admins inspect the dead letters and send them back to
+// their consumers.
+type DeadLetterQueue struct {
+	js nats.JetStreamContext
+}
+
+func NewDeadLetterQueue(js nats.JetStreamContext) *DeadLetterQueue {
+	return &DeadLetterQueue{js: js}
+}
+
+// List returns a page of the dead letters from sourceSubject, or from all
+// subjects when it is empty, oldest first.
+func (q *DeadLetterQueue) List(ctx context.Context, sourceSubject string, page, limit int) ([]*DeadLetter, int, error) {
+	var letters []*DeadLetter
+	total := 0
+	err := q.scan(ctx, func(letter *DeadLetter) {
+		if sourceSubject != "" && letter.SourceSubject != sourceSubject {
+			return
+		}
+		total++
+		if total > (page-1)*limit && len(letters) < limit {
+			letters = append(letters, letter)
+		}
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return letters, total, nil
+}
+
+func (q *DeadLetterQueue) Get(ctx context.Context, sequence uint64) (*DeadLetter, error) {
+	msg, err := q.js.GetMsg(streams.DeadLetterStream.Name, sequence, nats.Context(ctx))
+	if errors.Is(err, nats.ErrMsgNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newDeadLetter(msg.Sequence, msg.Time, msg.Header, msg.Data), nil
+}
+
+// Redrive publishes the event again for the consumer it failed in and removes
+// it from the queue.
+func (q *DeadLetterQueue) Redrive(ctx context.Context, sequence uint64) (*DeadLetter, error) {
+	letter, err := q.Get(ctx, sequence)
+	if err != nil || letter == nil {
+		return nil, err
+	}
+	if letter.SourceSubject == "" {
+		return nil, errors.New("dead letter has no source subject")
+	}
+
+	msg := nats.NewMsg(letter.SourceSubject)
+	for key, values := range letter.Header {
+		if strings.HasPrefix(key, "Dlq-") && key != HeaderDeadLetterFirstFailure {
+			continue
+		}
+		msg.Header[key] = values
+	}
+	if letter.Consumer != "" {
+		msg.Header.Set(HeaderRedriveConsumer, letter.Consumer)
+	}
+	msg.Data = letter.Data
+
+	if _, err := q.js.PublishMsg(msg, nats.Context(ctx)); err != nil {
+		return nil, err
+	}
+	if err := q.js.DeleteMsg(streams.DeadLetterStream.Name, sequence, nats.Context(ctx)); err != nil {
+		return nil, err
+	}
+	return letter, nil
+}
+
+// Delete removes one dead letter and reports whether it existed.
+func (q *DeadLetterQueue) Delete(ctx context.Context, sequence uint64) (bool, error) {
+	err := q.js.DeleteMsg(streams.DeadLetterStream.Name, sequence, nats.Context(ctx))
+	if errors.Is(err, nats.ErrMsgNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Purge removes the dead letters from sourceSubject, or all of them when it is
+// empty, and returns how many were removed.
+func (q *DeadLetterQueue) Purge(ctx context.Context, sourceSubject string) (int, error) {
+	if sourceSubject == "" {
+		info, err := q.js.StreamInfo(streams.DeadLetterStream.Name, nats.Context(ctx))
+		if err != nil {
+			return 0, err
+		}
+		if err := q.js.PurgeStream(streams.DeadLetterStream.Name, nats.Context(ctx)); err != nil {
+			return 0, err
+		}
+		return int(info.State.Msgs), nil
+	}
+
+	var sequences []uint64
+	err := q.scan(ctx, func(letter *DeadLetter) {
+		if letter.SourceSubject == sourceSubject {
+			sequences = append(sequences, letter.Sequence)
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, sequence := range sequences {
+		deleted, err := q.Delete(ctx, sequence)
+		if err != nil {
+			return purged, err
+		}
+		if deleted {
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// scan visits every dead letter in order through a short-lived ordered
+// consumer.
+func (q *DeadLetterQueue) scan(ctx context.Context, visit func(*DeadLetter)) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	info, err := q.js.StreamInfo(streams.DeadLetterStream.Name, nats.Context(ctx))
+	if err != nil {
+		return err
+	}
+	if info.State.Msgs == 0 {
+		return nil
+	}
+
+	sub, err := q.js.SubscribeSync(SubjectDeadLetter,
+		nats.BindStream(streams.DeadLetterStream.Name), nats.OrderedConsumer(), nats.DeliverAll())
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		msg, err := sub.NextMsgWithContext(ctx)
+		if err != nil {
+			return err
+		}
+		meta, err := msg.Metadata()
+		if err != nil {
+			return err
+		}
+
+		visit(newDeadLetter(meta.Sequence.Stream, meta.Timestamp, msg.Header, msg.Data))
+		if meta.NumPending == 0 || meta.Sequence.Stream >= info.State.LastSeq {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package messaging
+
+import (
+	"strings"
+	"time"
+
+	"inventory-service/internal/config"
+
+	"github.com/nats-io/nats.go"
+)
+
+// DeliveryPolicy decides how often and when a failed event is delivered
+// again.
+type DeliveryPolicy struct {
+	MaxDeliver int
+	AckWait    time.Duration
+	Backoff    []time.Duration
+}
+
+func NewDeliveryPolicy(cfg config.NATSConfig) DeliveryPolicy {
+	policy := DeliveryPolicy{
+		MaxDeliver: cfg.MaxDeliver,
+		AckWait:    time.Duration(cfg.AckWait) * time.Second,
+	}
+	for _, seconds := range cfg.Backoff {
+		policy.Backoff = append(policy.Backoff, time.Duration(seconds)*time.Second)
+	}
+	return policy
+}
+
+// Delay returns how long to wait before redelivering an event that failed on
+// its delivered-th delivery.
+func (p DeliveryPolicy) Delay(delivered uint64) time.Duration {
+	if len(p.Backoff) == 0 {
+		return 0
+	}
+	if delivered == 0 || delivered > uint64(len(p.Backoff)) {
+		return p.Backoff[len(p.Backoff)-1]
+	}
+	return p.Backoff[delivered-1]
+}
+
+// subscribeOptions describe a durable consumer that only starts with the
+// events published after it was first created and waits for every event to
+// be acknowledged.
+func (p DeliveryPolicy) subscribeOptions(durable string) []nats.SubOpt {
+	opts := []nats.SubOpt{
+		nats.Durable(durable),
+		nats.DeliverNew(),
+		nats.ManualAck(),
+		nats.AckExplicit(),
+	}
+	if p.MaxDeliver > 0 {
+		opts = append(opts, nats.MaxDeliver(p.MaxDeliver))
+	}
+	if p.AckWait > 0 {
+		opts = append(opts, nats.AckWait(p.AckWait))
+	}
+	return opts
+}
+
+// durableName derives a consumer name from a subject. Consumer names must not
+// contain dots.
+func durableName(prefix, subject string) string {
+	return prefix + "-" + strings.NewReplacer(".", "-", "*", "any", ">", "all").Replace(subject)
+}
@@ -1,27 +1,27 @@
 package messaging
 
-type OrderItem struct {
-	ProductID string  `json:"product_id"`
-	Quantity  int     `json:"quantity"`
-	Price     float64 `json:"price"`
-}
+import "proto/events"
 
-type OrderCreatedEvent struct {
-	OrderID   string      `json:"order_id"`
-	UserID    string      `json:"user_id"`
-	Items     []OrderItem `json:"items"`
-	Total     float64     `json:"total"`
-	Timestamp int64       `json:"timestamp"`
-}
-
-type StockUpdateResult struct {
-	ProductID string `json:"product_id"`
-	Success   bool   `json:"success"`
-	Quantity  int    `json:"quantity"`
-	Error     string `json:"error,omitempty"`
-}
+// The events are defined in proto/events, shared with their publishers.
+type (
+	OrderItem           = events.OrderItem
+	OrderCreatedEvent   = events.OrderCreated
+	OrderExpiredEvent   = events.OrderExpired
+	StockDelta          = events.StockDelta
+	OrderModifiedEvent  = events.OrderModified
+	ReturnedItem        = events.ReturnedItem
+	ReturnReceivedEvent = events.ReturnReceived
+	StockChangedEvent   = events.StockChanged
+)
 
 const (
-	SubjectOrderCreated = "order.created"
-	SubjectDeadLetter   = "dead.letter.queue"
+	SubjectOrderCreated   = events.TypeOrderCreated
+	SubjectOrderExpired   = events.TypeOrderExpired
+	SubjectOrderModified  = events.TypeOrderModified
+	SubjectReturnReceived = events.TypeReturnReceived
+	SubjectStockChanged   = events.TypeStockChanged
+	SubjectDeadLetter     = events.SubjectDeadLetter
 )
+
+// eventSource is the CloudEvents source of the events published here.
+const eventSource = "/inventory-service"
@@ -0,0 +1,73 @@
+package messaging
+
+import (
+	"log"
+	"time"
+
+	"infra/streams"
+	"inventory-service/internal/config"
+	"proto/events"
+
+	"github.com/nats-io/nats.go"
+)
+
+type EventPublisher interface {
+	PublishStockChanged(event StockChangedEvent) error
+	Close()
+}
+
+// NATSPublisher publishes to JetStream and waits until the stream stored the
+// event.
+type NATSPublisher struct {
+	conn *nats.Conn
+	js   nats.JetStreamContext
+}
+
+func NewNATSPublisher(cfg *config.Config) (*NATSPublisher, error) {
+	nc, err := nats.Connect(cfg.NATS.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := nc.JetStream()
+	if err == nil {
+		err = streams.EnsureStream(js, streams.StockStream)
+	}
+	if err != nil {
+		nc.Close()
+		return nil, err
+	}
+
+	log.Printf("[%s] NATS publisher connected to %s",
+		time.Now().Format(time.RFC3339Nano), cfg.NATS.URL)
+	return &NATSPublisher{conn: nc, js: js}, nil
+}
+
+func (p *NATSPublisher) PublishStockChanged(event StockChangedEvent) error {
+	if event.Timestamp == 0 {
+		event.Timestamp = time.Now().UnixNano()
+	}
+
+	envelope, err := events.New(eventSource, SubjectStockChanged, event.ProductID, &event)
+	if err != nil {
+		return err
+	}
+
+	msg := &nats.Msg{Subject: SubjectStockChanged, Header: envelope.Header(), Data: envelope.Data}
+	if _, err := p.js.PublishMsg(msg); err != nil {
+		log.Printf("[%s] Error publishing stock changed event for product %s: %v",
+			time.Now().Format(time.RFC3339Nano), event.ProductID, err)
+		return err
+	}
+
+	log.Printf("[%s] Published stock.changed event for product %s: %d -> %d",
+		time.Now().Format(time.RFC3339Nano), event.ProductID, event.PreviousStock, event.Stock)
+	return nil
+}
+
+func (p *NATSPublisher) Close() {
+	if p.conn != nil {
+		p.conn.Close()
+		log.Printf("[%s] NATS publisher connection closed", time.Now().Format(time.RFC3339Nano))
+	}
+}
@@ -0,0 +1,71 @@
+package messaging
+
+import "sync"
+
+// WorkerPool runs tasks on a bounded number of goroutines. Tasks that share a
+// key run one after the other in the order they were submitted, the others
+// run in parallel. The order only covers tasks submitted to this pool.
+type WorkerPool struct {
+	slots chan struct{}
+	wg    sync.WaitGroup
+
+	mu sync.Mutex
+	// tails holds, per key, a channel that is closed when the last task
+	// submitted with that key is done.
+	tails map[string]chan struct{}
+}
+
+func NewWorkerPool(workers int) *WorkerPool {
+	if workers <= 0 {
+		workers = 1
+	}
+	return &WorkerPool{
+		slots: make(chan struct{}, workers),
+		tails: make(map[string]chan struct{}),
+	}
+}
+
+// Submit blocks while all workers are busy. A task only waits for tasks
+// submitted before it, which already hold a worker, so it cannot deadlock.
+func (p *WorkerPool) Submit(keys []string, task func()) {
+	p.slots <- struct{}{}
+	p.wg.Add(1)
+
+	done := make(chan struct{})
+	var previous []chan struct{}
+	p.mu.Lock()
+	for _, key := range keys {
+		if tail, ok := p.tails[key]; ok && tail != done {
+			previous = append(previous, tail)
+		}
+		p.tails[key] = done
+	}
+	p.mu.Unlock()
+
+	go func() {
+		defer p.wg.Done()
+		defer func() { <-p.slots }()
+		defer p.release(keys, done)
+
+		for _, tail := range previous {
+			<-tail
+		}
+		task()
+	}()
+}
+
+func (p *WorkerPool) release(keys []string, done chan struct{}) {
+	p.mu.Lock()
+	for _, key := range keys {
+		if p.tails[key] == done {
+			delete(p.tails, key)
+		}
+	}
+	p.mu.Unlock()
+	close(done)
+}
+
+// Wait returns when every submitted task is done.
+func (p *WorkerPool) Wait() {
+	p.wg.Wait()
+}
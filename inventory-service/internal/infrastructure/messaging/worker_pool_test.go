@@ -0,0 +1,80 @@
+package messaging
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkerPoolKeepsOrderPerKey(t *testing.T) {
+	pool := NewWorkerPool(4)
+
+	var mu sync.Mutex
+	seen := map[string][]int{}
+	for i := 0; i < 50; i++ {
+		i := i
+		key := []string{"a", "b", "c"}[i%3]
+		pool.Submit([]string{key}, func() {
+			// Later tasks would overtake earlier ones without the ordering.
+			time.Sleep(time.Duration(50-i) * 100 * time.Microsecond)
+			mu.Lock()
+			seen[key] = append(seen[key], i)
+			mu.Unlock()
+		})
+	}
+	pool.Wait()
+
+	for key, order := range seen {
+		for j := 1; j < len(order); j++ {
+			if order[j] < order[j-1] {
+				t.Fatalf("tasks of key %s ran out of order: %v", key, order)
+			}
+		}
+	}
+}
+
+func TestWorkerPoolOrdersTasksWithSeveralKeys(t *testing.T) {
+	pool := NewWorkerPool(4)
+
+	var order []string
+	var mu sync.Mutex
+	record := func(name string) {
+		mu.Lock()
+		order = append(order, name)
+		mu.Unlock()
+	}
+
+	pool.Submit([]string{"a"}, func() { time.Sleep(20 * time.Millisecond); record("first") })
+	pool.Submit([]string{"b", "a"}, func() { record("second") })
+	pool.Submit([]string{"b"}, func() { record("third") })
+	pool.Wait()
+
+	if len(order) != 3 || order[0] != "first" || order[1] != "second" || order[2] != "third" {
+		t.Fatalf("tasks ran in order %v", order)
+	}
+}
+
+func TestWorkerPoolBoundsConcurrency(t *testing.T) {
+	pool := NewWorkerPool(3)
+
+	var running, peak atomic.Int32
+	for i := 0; i < 20; i++ {
+		pool.Submit([]string{string(rune('a' + i))}, func() {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+		})
+	}
+	pool.Wait()
+
+	if p := peak.Load(); p > 3 {
+		t.Fatalf("%d tasks ran at once, want at most 3", p)
+	}
+}
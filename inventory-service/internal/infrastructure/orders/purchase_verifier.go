@@ -0,0 +1,66 @@
+package orders
+
+import (
+	"context"
+	"log"
+
+	"inventory-service/internal/config"
+	orderpb "proto/order"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// deliveredStatus is the order status the order service uses once the
+// customer has received the goods.
+const deliveredStatus = "completed"
+
+type PurchaseVerifier interface {
+	// DeliveredOrderID returns a delivered order of the user that contains the
+	// product, or "" if there is none.
+	DeliveredOrderID(ctx context.Context, userID, productID string) (string, error)
+	Close() error
+}
+
+type GRPCPurchaseVerifier struct {
+	conn   *grpc.ClientConn
+	client orderpb.OrderServiceClient
+}
+
+func NewGRPCPurchaseVerifier(cfg *config.Config) (*GRPCPurchaseVerifier, error) {
+	conn, err := grpc.Dial(cfg.Services.Order, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Order service client configured for %s", cfg.Services.Order)
+
+	return &GRPCPurchaseVerifier{
+		conn:   conn,
+		client: orderpb.NewOrderServiceClient(conn),
+	}, nil
+}
+
+func (v *GRPCPurchaseVerifier) DeliveredOrderID(ctx context.Context, userID, productID string) (string, error) {
+	res, err := v.client.ListOrders(ctx, &orderpb.UserID{Id: userID})
+	if err != nil {
+		return "", err
+	}
+
+	for _, o := range res.GetOrders() {
+		if o.Status != deliveredStatus {
+			continue
+		}
+		for _, item := range o.Items {
+			if item.ProductId == productID {
+				return o.Id, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+func (v *GRPCPurchaseVerifier) Close() error {
+	return v.conn.Close()
+}
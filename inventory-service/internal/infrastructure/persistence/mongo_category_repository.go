@@ -26,6 +26,7 @@ func (r *mongoCategoryRepository) Create(ctx context.Context, category *domain.C
 		ID:          category.ID,
 		Name:        category.Name,
 		Description: category.Description,
+		TaxClass:    string(category.TaxClass),
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
@@ -58,6 +59,7 @@ func (r *mongoCategoryRepository) GetByID(ctx context.Context, id string) (*doma
 		ID:          categoryDTO.ID,
 		Name:        categoryDTO.Name,
 		Description: categoryDTO.Description,
+		TaxClass:    domain.TaxClass(categoryDTO.TaxClass),
 		CreatedAt:   categoryDTO.CreatedAt,
 		UpdatedAt:   categoryDTO.UpdatedAt,
 	}, nil
@@ -69,6 +71,7 @@ func (r *mongoCategoryRepository) Update(ctx context.Context, category *domain.C
 		"$set": bson.M{
 			"name":        category.Name,
 			"description": category.Description,
+			"tax_class":   string(category.TaxClass),
 			"updated_at":  time.Now(),
 		},
 	}
@@ -117,6 +120,7 @@ func (r *mongoCategoryRepository) List(ctx context.Context) ([]*domain.Category,
 			ID:          dto.ID,
 			Name:        dto.Name,
 			Description: dto.Description,
+			TaxClass:    domain.TaxClass(dto.TaxClass),
 			CreatedAt:   dto.CreatedAt,
 			UpdatedAt:   dto.UpdatedAt,
 		}
@@ -0,0 +1,34 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"inventory-service/internal/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoProcessedEventRepository struct {
+	db *database.MongoDBConnector
+}
+
+func NewMongoProcessedEventRepository(db *database.MongoDBConnector) *mongoProcessedEventRepository {
+	return &mongoProcessedEventRepository{db: db}
+}
+
+func (r *mongoProcessedEventRepository) Record(ctx context.Context, eventID, eventType string) (bool, error) {
+	_, err := r.db.ProcessedEventCollection().InsertOne(ctx, database.ProcessedEventDTO{
+		EventID:     eventID,
+		Type:        eventType,
+		ProcessedAt: time.Now(),
+	})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,102 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"inventory-service/internal/domain"
+	"inventory-service/internal/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoProcessedOrderRepository struct {
+	db *database.MongoDBConnector
+}
+
+func NewMongoProcessedOrderRepository(db *database.MongoDBConnector) *mongoProcessedOrderRepository {
+	return &mongoProcessedOrderRepository{db: db}
+}
+
+func (r *mongoProcessedOrderRepository) Begin(ctx context.Context, record *domain.OrderStock) (*domain.OrderStock, error) {
+	filter := bson.M{"_id": record.OrderID}
+	update := bson.M{"$setOnInsert": toProcessedOrderDTO(record)}
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After)
+
+	var dto database.ProcessedOrderDTO
+	if err := r.db.ProcessedOrderCollection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&dto); err != nil {
+		return nil, err
+	}
+
+	return toOrderStockDomain(dto), nil
+}
+
+func (r *mongoProcessedOrderRepository) GetByID(ctx context.Context, orderID string) (*domain.OrderStock, error) {
+	var dto database.ProcessedOrderDTO
+
+	err := r.db.ProcessedOrderCollection().FindOne(ctx, bson.M{"_id": orderID}).Decode(&dto)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toOrderStockDomain(dto), nil
+}
+
+func (r *mongoProcessedOrderRepository) Save(ctx context.Context, record *domain.OrderStock) error {
+	dto := toProcessedOrderDTO(record)
+	update := bson.M{
+		"$set": bson.M{
+			"status":     dto.Status,
+			"items":      dto.Items,
+			"updated_at": dto.UpdatedAt,
+		},
+	}
+
+	_, err := r.db.ProcessedOrderCollection().UpdateOne(ctx, bson.M{"_id": record.OrderID}, update)
+	return err
+}
+
+func toProcessedOrderDTO(record *domain.OrderStock) *database.ProcessedOrderDTO {
+	items := make([]database.ProcessedOrderItemDTO, len(record.Items))
+	for i, item := range record.Items {
+		items[i] = database.ProcessedOrderItemDTO{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Applied:   item.Applied,
+		}
+	}
+
+	return &database.ProcessedOrderDTO{
+		OrderID:   record.OrderID,
+		Status:    string(record.Status),
+		Items:     items,
+		CreatedAt: record.CreatedAt,
+		UpdatedAt: record.UpdatedAt,
+	}
+}
+
+func toOrderStockDomain(dto database.ProcessedOrderDTO) *domain.OrderStock {
+	items := make([]domain.OrderStockItem, len(dto.Items))
+	for i, item := range dto.Items {
+		items[i] = domain.OrderStockItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Applied:   item.Applied,
+		}
+	}
+
+	return &domain.OrderStock{
+		OrderID:   dto.OrderID,
+		Status:    domain.OrderStockStatus(dto.Status),
+		Items:     items,
+		CreatedAt: dto.CreatedAt,
+		UpdatedAt: dto.UpdatedAt,
+	}
+}
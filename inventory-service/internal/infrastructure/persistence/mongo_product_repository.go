@@ -3,6 +3,7 @@ package persistence
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"inventory-service/internal/domain"
@@ -13,6 +14,10 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// productProjection leaves out the orders remembered on a product, which only
+// the stock updates read.
+var productProjection = bson.M{"applied_orders": 0}
+
 type mongoProductRepository struct {
 	db *database.MongoDBConnector
 }
@@ -30,6 +35,7 @@ func (r *mongoProductRepository) Create(ctx context.Context, product *domain.Pro
 		Price:       product.Price,
 		Stock:       product.Stock,
 		CategoryID:  product.CategoryID,
+		TaxClass:    string(product.TaxClass),
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
@@ -49,7 +55,8 @@ func (r *mongoProductRepository) GetByID(ctx context.Context, id string) (*domai
 	var productDTO database.ProductDTO
 
 	filter := bson.M{"_id": id}
-	err := r.db.ProductCollection().FindOne(ctx, filter).Decode(&productDTO)
+	opts := options.FindOne().SetProjection(productProjection)
+	err := r.db.ProductCollection().FindOne(ctx, filter, opts).Decode(&productDTO)
 
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
@@ -58,16 +65,7 @@ func (r *mongoProductRepository) GetByID(ctx context.Context, id string) (*domai
 		return nil, err
 	}
 
-	return &domain.Product{
-		ID:          productDTO.ID,
-		Name:        productDTO.Name,
-		Description: productDTO.Description,
-		Price:       productDTO.Price,
-		Stock:       productDTO.Stock,
-		CategoryID:  productDTO.CategoryID,
-		CreatedAt:   productDTO.CreatedAt,
-		UpdatedAt:   productDTO.UpdatedAt,
-	}, nil
+	return toProductDomain(productDTO), nil
 }
 
 func (r *mongoProductRepository) Update(ctx context.Context, product *domain.Product) (*domain.Product, error) {
@@ -79,6 +77,7 @@ func (r *mongoProductRepository) Update(ctx context.Context, product *domain.Pro
 			"price":       product.Price,
 			"stock":       product.Stock,
 			"category_id": product.CategoryID,
+			"tax_class":   string(product.TaxClass),
 			"updated_at":  time.Now(),
 		},
 	}
@@ -95,6 +94,154 @@ func (r *mongoProductRepository) Update(ctx context.Context, product *domain.Pro
 	return product, nil
 }
 
+func (r *mongoProductRepository) DecreaseStock(ctx context.Context, productID string, quantity int) (*domain.Product, error) {
+	filter := bson.M{"_id": productID, "stock": bson.M{"$gte": quantity}}
+
+	product, err := r.incStock(ctx, filter, -quantity)
+	if err != nil || product != nil {
+		return product, err
+	}
+
+	// Nothing matched: either the product is missing or its stock is short.
+	existing, err := r.GetByID(ctx, productID)
+	if err != nil || existing == nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("%w for product %s: requested %d, available %d",
+		domain.ErrInsufficientStock, productID, quantity, existing.Stock)
+}
+
+func (r *mongoProductRepository) IncreaseStock(ctx context.Context, productID string, quantity int) (*domain.Product, error) {
+	return r.incStock(ctx, bson.M{"_id": productID}, quantity)
+}
+
+// DecreaseStockBatch takes every item in one transaction, so a failed item or
+// a crash midway leaves all stock untouched.
+func (r *mongoProductRepository) DecreaseStockBatch(ctx context.Context, items []domain.StockItem) ([]*domain.Product, error) {
+	var products []*domain.Product
+	err := r.db.WithTransaction(ctx, func(ctx context.Context) error {
+		products = make([]*domain.Product, 0, len(items))
+		for _, item := range items {
+			product, err := r.DecreaseStock(ctx, item.ProductID, item.Quantity)
+			if err == nil && product == nil {
+				err = fmt.Errorf("%w: %s", domain.ErrProductNotFound, item.ProductID)
+			}
+			if err != nil {
+				return err
+			}
+			products = append(products, product)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// maxAppliedOrders bounds the orders remembered on a product. Only recent
+// orders need the guard against redelivery: older ones are settled in the
+// processed_orders ledger.
+const maxAppliedOrders = 1000
+
+func (r *mongoProductRepository) TakeStockForOrder(ctx context.Context, orderID, productID string, quantity int) (*domain.Product, bool, error) {
+	filter := bson.M{
+		"_id":                     productID,
+		"stock":                   bson.M{"$gte": quantity},
+		"applied_orders.order_id": bson.M{"$ne": orderID},
+	}
+	update := bson.M{
+		"$inc": bson.M{"stock": -quantity},
+		"$set": bson.M{"updated_at": time.Now()},
+		"$push": bson.M{"applied_orders": bson.M{
+			"$each":  bson.A{database.AppliedOrderDTO{OrderID: orderID, Quantity: quantity, At: time.Now()}},
+			"$slice": -maxAppliedOrders,
+		}},
+	}
+
+	product, err := r.findOneAndUpdate(ctx, filter, update)
+	if err != nil || product != nil {
+		return product, product != nil, err
+	}
+
+	// Nothing matched: the order took its stock already, the product is
+	// missing or its stock is short.
+	applied, err := r.db.ProductCollection().CountDocuments(ctx, bson.M{"_id": productID, "applied_orders.order_id": orderID})
+	if err != nil {
+		return nil, false, err
+	}
+	existing, err := r.GetByID(ctx, productID)
+	if err != nil {
+		return nil, false, err
+	}
+	switch {
+	case existing == nil:
+		return nil, false, fmt.Errorf("%w: %s", domain.ErrProductNotFound, productID)
+	case applied > 0:
+		return existing, false, nil
+	default:
+		return nil, false, fmt.Errorf("%w for product %s: requested %d, available %d",
+			domain.ErrInsufficientStock, productID, quantity, existing.Stock)
+	}
+}
+
+func (r *mongoProductRepository) ReleaseStockForOrder(ctx context.Context, orderID, productID string, quantity int) (*domain.Product, error) {
+	filter := bson.M{"_id": productID, "applied_orders.order_id": orderID}
+	update := bson.M{
+		"$inc":  bson.M{"stock": quantity},
+		"$set":  bson.M{"updated_at": time.Now()},
+		"$pull": bson.M{"applied_orders": bson.M{"order_id": orderID}},
+	}
+
+	return r.findOneAndUpdate(ctx, filter, update)
+}
+
+// incStock adds delta to the stock of the product matching filter and returns
+// the product afterwards, or nil when nothing matched.
+func (r *mongoProductRepository) incStock(ctx context.Context, filter bson.M, delta int) (*domain.Product, error) {
+	update := bson.M{
+		"$inc": bson.M{"stock": delta},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+
+	return r.findOneAndUpdate(ctx, filter, update)
+}
+
+func (r *mongoProductRepository) findOneAndUpdate(ctx context.Context, filter, update bson.M) (*domain.Product, error) {
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(productProjection)
+
+	var productDTO database.ProductDTO
+	err := r.db.ProductCollection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&productDTO)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toProductDomain(productDTO), nil
+}
+
+func (r *mongoProductRepository) AdjustRating(ctx context.Context, productID string, totalDelta, countDelta int) error {
+	filter := bson.M{"_id": productID}
+	update := bson.M{
+		"$inc": bson.M{"rating_total": totalDelta, "rating_count": countDelta},
+	}
+
+	result, err := r.db.ProductCollection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("product not found")
+	}
+
+	return nil
+}
+
 func (r *mongoProductRepository) Delete(ctx context.Context, id string) error {
 	filter := bson.M{"_id": id}
 	result, err := r.db.ProductCollection().DeleteOne(ctx, filter)
@@ -125,7 +272,8 @@ func (r *mongoProductRepository) List(ctx context.Context, categoryID string, pa
 	findOptions := options.Find().
 		SetLimit(int64(limit)).
 		SetSkip(skip).
-		SetSort(bson.M{"name": 1})
+		SetSort(bson.M{"name": 1}).
+		SetProjection(productProjection)
 
 	cursor, err := r.db.ProductCollection().Find(ctx, filter, findOptions)
 	if err != nil {
@@ -140,17 +288,24 @@ func (r *mongoProductRepository) List(ctx context.Context, categoryID string, pa
 
 	products := make([]*domain.Product, len(productDTOs))
 	for i, dto := range productDTOs {
-		products[i] = &domain.Product{
-			ID:          dto.ID,
-			Name:        dto.Name,
-			Description: dto.Description,
-			Price:       dto.Price,
-			Stock:       dto.Stock,
-			CategoryID:  dto.CategoryID,
-			CreatedAt:   dto.CreatedAt,
-			UpdatedAt:   dto.UpdatedAt,
-		}
+		products[i] = toProductDomain(dto)
 	}
 
 	return products, int(count), nil
 }
+
+func toProductDomain(dto database.ProductDTO) *domain.Product {
+	return &domain.Product{
+		ID:          dto.ID,
+		Name:        dto.Name,
+		Description: dto.Description,
+		Price:       dto.Price,
+		Stock:       dto.Stock,
+		CategoryID:  dto.CategoryID,
+		TaxClass:    domain.TaxClass(dto.TaxClass),
+		RatingTotal: dto.RatingTotal,
+		RatingCount: dto.RatingCount,
+		CreatedAt:   dto.CreatedAt,
+		UpdatedAt:   dto.UpdatedAt,
+	}
+}
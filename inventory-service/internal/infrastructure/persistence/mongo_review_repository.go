@@ -0,0 +1,147 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"inventory-service/internal/domain"
+	"inventory-service/internal/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoReviewRepository struct {
+	db *database.MongoDBConnector
+}
+
+func NewMongoReviewRepository(db *database.MongoDBConnector) *mongoReviewRepository {
+	return &mongoReviewRepository{db: db}
+}
+
+func (r *mongoReviewRepository) Create(ctx context.Context, review *domain.Review) (*domain.Review, error) {
+	_, err := r.db.ReviewCollection().InsertOne(ctx, toReviewDTO(review))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, domain.ErrReviewExists
+		}
+		return nil, err
+	}
+
+	return review, nil
+}
+
+func (r *mongoReviewRepository) GetByID(ctx context.Context, id string) (*domain.Review, error) {
+	var dto database.ReviewDTO
+
+	err := r.db.ReviewCollection().FindOne(ctx, bson.M{"_id": id}).Decode(&dto)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toReviewDomain(dto), nil
+}
+
+func (r *mongoReviewRepository) List(ctx context.Context, filter ReviewListFilter, sort domain.ReviewSort, page, limit int) ([]*domain.Review, int, error) {
+	query := bson.M{}
+	if filter.ProductID != "" {
+		query["product_id"] = filter.ProductID
+	}
+	if filter.Status != "" {
+		query["status"] = string(filter.Status)
+	}
+
+	count, err := r.db.ReviewCollection().CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOptions := options.Find().
+		SetLimit(int64(limit)).
+		SetSkip(int64((page - 1) * limit)).
+		SetSort(reviewSortOrder(sort))
+
+	cursor, err := r.db.ReviewCollection().Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var dtos []database.ReviewDTO
+	if err := cursor.All(ctx, &dtos); err != nil {
+		return nil, 0, err
+	}
+
+	reviews := make([]*domain.Review, len(dtos))
+	for i, dto := range dtos {
+		reviews[i] = toReviewDomain(dto)
+	}
+
+	return reviews, int(count), nil
+}
+
+func (r *mongoReviewRepository) UpdateStatus(ctx context.Context, review *domain.Review, from domain.ReviewStatus) (bool, error) {
+	filter := bson.M{"_id": review.ID, "status": string(from)}
+	update := bson.M{
+		"$set": bson.M{
+			"status":          string(review.Status),
+			"moderation_note": review.ModerationNote,
+			"updated_at":      review.UpdatedAt,
+		},
+	}
+
+	result, err := r.db.ReviewCollection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+// reviewSortOrder breaks ties by creation time so pages stay stable.
+func reviewSortOrder(sort domain.ReviewSort) bson.D {
+	switch sort {
+	case domain.ReviewSortOldest:
+		return bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}
+	case domain.ReviewSortHighest:
+		return bson.D{{Key: "rating", Value: -1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}
+	case domain.ReviewSortLowest:
+		return bson.D{{Key: "rating", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}
+	default:
+		return bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: 1}}
+	}
+}
+
+func toReviewDTO(review *domain.Review) *database.ReviewDTO {
+	return &database.ReviewDTO{
+		ID:             review.ID,
+		ProductID:      review.ProductID,
+		UserID:         review.UserID,
+		OrderID:        review.OrderID,
+		Rating:         review.Rating,
+		Text:           review.Text,
+		Status:         string(review.Status),
+		ModerationNote: review.ModerationNote,
+		CreatedAt:      review.CreatedAt,
+		UpdatedAt:      review.UpdatedAt,
+	}
+}
+
+func toReviewDomain(dto database.ReviewDTO) *domain.Review {
+	return &domain.Review{
+		ID:             dto.ID,
+		ProductID:      dto.ProductID,
+		UserID:         dto.UserID,
+		OrderID:        dto.OrderID,
+		Rating:         dto.Rating,
+		Text:           dto.Text,
+		Status:         domain.ReviewStatus(dto.Status),
+		ModerationNote: dto.ModerationNote,
+		CreatedAt:      dto.CreatedAt,
+		UpdatedAt:      dto.UpdatedAt,
+	}
+}
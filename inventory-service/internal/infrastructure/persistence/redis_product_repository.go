@@ -61,6 +61,84 @@ func (r *redisProductRepository) Update(ctx context.Context, product *domain.Pro
 	return result, nil
 }
 
+func (r *redisProductRepository) AdjustRating(ctx context.Context, productID string, totalDelta, countDelta int) error {
+	if err := r.repo.AdjustRating(ctx, productID, totalDelta, countDelta); err != nil {
+		return err
+	}
+
+	if err := r.InvalidateCache(ctx, productID); err != nil {
+		log.Printf("Failed to invalidate product cache: %v", err)
+	}
+
+	if err := r.InvalidateListCache(ctx); err != nil {
+		log.Printf("Failed to invalidate list cache: %v", err)
+	}
+
+	return nil
+}
+
+func (r *redisProductRepository) DecreaseStock(ctx context.Context, productID string, quantity int) (*domain.Product, error) {
+	product, err := r.repo.DecreaseStock(ctx, productID, quantity)
+	if err != nil || product == nil {
+		return product, err
+	}
+
+	r.invalidateStock(ctx, productID)
+	return product, nil
+}
+
+func (r *redisProductRepository) IncreaseStock(ctx context.Context, productID string, quantity int) (*domain.Product, error) {
+	product, err := r.repo.IncreaseStock(ctx, productID, quantity)
+	if err != nil || product == nil {
+		return product, err
+	}
+
+	r.invalidateStock(ctx, productID)
+	return product, nil
+}
+
+func (r *redisProductRepository) DecreaseStockBatch(ctx context.Context, items []domain.StockItem) ([]*domain.Product, error) {
+	products, err := r.repo.DecreaseStockBatch(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		r.invalidateStock(ctx, item.ProductID)
+	}
+	return products, nil
+}
+
+func (r *redisProductRepository) TakeStockForOrder(ctx context.Context, orderID, productID string, quantity int) (*domain.Product, bool, error) {
+	product, taken, err := r.repo.TakeStockForOrder(ctx, orderID, productID, quantity)
+	if err != nil || !taken {
+		return product, taken, err
+	}
+
+	r.invalidateStock(ctx, productID)
+	return product, true, nil
+}
+
+func (r *redisProductRepository) ReleaseStockForOrder(ctx context.Context, orderID, productID string, quantity int) (*domain.Product, error) {
+	product, err := r.repo.ReleaseStockForOrder(ctx, orderID, productID, quantity)
+	if err != nil || product == nil {
+		return product, err
+	}
+
+	r.invalidateStock(ctx, productID)
+	return product, nil
+}
+
+func (r *redisProductRepository) invalidateStock(ctx context.Context, productID string) {
+	if err := r.InvalidateCache(ctx, productID); err != nil {
+		log.Printf("Failed to invalidate product cache: %v", err)
+	}
+
+	if err := r.InvalidateListCache(ctx); err != nil {
+		log.Printf("Failed to invalidate list cache: %v", err)
+	}
+}
+
 func (r *redisProductRepository) Delete(ctx context.Context, id string) error {
 
 	err := r.repo.Delete(ctx, id)
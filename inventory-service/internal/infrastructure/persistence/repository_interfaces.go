@@ -26,4 +26,55 @@ type ProductRepository interface {
 	Update(ctx context.Context, product *domain.Product) (*domain.Product, error)
 	Delete(ctx context.Context, id string) error
 	List(ctx context.Context, categoryID string, page, limit int) ([]*domain.Product, int, error)
+	// AdjustRating atomically changes the approved review totals.
+	AdjustRating(ctx context.Context, productID string, totalDelta, countDelta int) error
+	// DecreaseStock atomically takes quantity from the stock if that much is
+	// left and returns domain.ErrInsufficientStock otherwise. Like the other
+	// stock methods it returns nil when the product does not exist.
+	DecreaseStock(ctx context.Context, productID string, quantity int) (*domain.Product, error)
+	IncreaseStock(ctx context.Context, productID string, quantity int) (*domain.Product, error)
+	// DecreaseStockBatch takes the stock of every item or of none, in one
+	// transaction. Product IDs must be unique.
+	DecreaseStockBatch(ctx context.Context, items []domain.StockItem) ([]*domain.Product, error)
+	// TakeStockForOrder decreases the stock like DecreaseStock and records
+	// orderID on the product in the same update. It reports false, changing
+	// nothing, when the order already took its stock of the product.
+	TakeStockForOrder(ctx context.Context, orderID, productID string, quantity int) (*domain.Product, bool, error)
+	// ReleaseStockForOrder gives back what TakeStockForOrder took, so that a
+	// rejected order can be taken again. It returns nil when the order holds
+	// no stock of the product.
+	ReleaseStockForOrder(ctx context.Context, orderID, productID string, quantity int) (*domain.Product, error)
+}
+
+// ProcessedOrderRepository keeps the stock ledger of orders.
+type ProcessedOrderRepository interface {
+	// Begin stores record unless the order already has one, and returns the
+	// stored record.
+	Begin(ctx context.Context, record *domain.OrderStock) (*domain.OrderStock, error)
+	GetByID(ctx context.Context, orderID string) (*domain.OrderStock, error)
+	Save(ctx context.Context, record *domain.OrderStock) error
+}
+
+// ProcessedEventRepository remembers the events whose stock changes were
+// applied.
+type ProcessedEventRepository interface {
+	// Record stores the event and reports false, storing nothing, when it was
+	// recorded before. Run inside the transaction that applies the event, a
+	// duplicate also aborts that transaction.
+	Record(ctx context.Context, eventID, eventType string) (bool, error)
+}
+
+// ReviewListFilter selects reviews; empty fields match everything.
+type ReviewListFilter struct {
+	ProductID string
+	Status    domain.ReviewStatus
+}
+
+type ReviewRepository interface {
+	Create(ctx context.Context, review *domain.Review) (*domain.Review, error)
+	GetByID(ctx context.Context, id string) (*domain.Review, error)
+	List(ctx context.Context, filter ReviewListFilter, sort domain.ReviewSort, page, limit int) ([]*domain.Review, int, error)
+	// UpdateStatus stores a moderation decision only if the review is still in
+	// status from, so the product rating is adjusted exactly once.
+	UpdateStatus(ctx context.Context, review *domain.Review, from domain.ReviewStatus) (bool, error)
 }
@@ -2,78 +2,316 @@ package product
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"inventory-service/internal/config"
+	"inventory-service/internal/domain"
 	"inventory-service/internal/infrastructure/database"
+	"inventory-service/internal/infrastructure/messaging"
 	"inventory-service/internal/infrastructure/persistence"
 	"log"
-	"time"
 )
 
 type ProductServiceClient interface {
-	DecreaseStock(ctx context.Context, productID string, quantity int) error
+	// TakeOrderStock takes the stock of a new order all or nothing, in one
+	// transaction, and only once however often its event is delivered.
+	TakeOrderStock(ctx context.Context, orderID string, items []domain.StockItem) error
+	// ReleaseOrderStock gives back what TakeOrderStock took for an order, and
+	// only once.
+	ReleaseOrderStock(ctx context.Context, orderID string) error
+	// ApplyOrderModification applies the stock deltas of a modified order
+	// once per event ID.
+	ApplyOrderModification(ctx context.Context, eventID, orderID string, deltas []domain.StockDelta) error
+	// RestockReturn puts returned items back on sale once per event ID.
+	RestockReturn(ctx context.Context, eventID string, items []domain.StockItem) error
 	Close()
 }
 
+// transactor runs fn in a transaction that commits when fn returns nil, like
+// database.MongoDBConnector.WithTransaction.
+type transactor interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type ProductClient struct {
-	db          *database.MongoDBConnector
-	productRepo persistence.ProductRepository
+	db                 transactor
+	productRepo        persistence.ProductRepository
+	processedOrderRepo persistence.ProcessedOrderRepository
+	processedEventRepo persistence.ProcessedEventRepository
+	publisher          messaging.EventPublisher
 }
 
-func NewProductServiceClient(cfg *config.Config, existingDB *database.MongoDBConnector) (ProductServiceClient, error) {
+// NewProductServiceClient announces every stock change through publisher,
+// which may be nil.
+func NewProductServiceClient(cfg *config.Config, existingDB *database.MongoDBConnector, publisher messaging.EventPublisher) (ProductServiceClient, error) {
 	db := existingDB
 	productRepo := persistence.NewMongoProductRepository(db)
 
 	log.Println("Using product client with MongoDB")
 	return &ProductClient{
-		db:          db,
-		productRepo: productRepo,
+		db:                 db,
+		productRepo:        productRepo,
+		processedOrderRepo: persistence.NewMongoProcessedOrderRepository(db),
+		processedEventRepo: persistence.NewMongoProcessedEventRepository(db),
+		publisher:          publisher,
 	}, nil
 }
 
-func (c *ProductClient) DecreaseStock(ctx context.Context, productID string, quantity int) error {
-	startTime := time.Now()
-	log.Printf("[%s] Attempting to decrease stock for product %s by %d",
-		startTime.Format(time.RFC3339Nano), productID, quantity)
+// TakeOrderStock takes the stock of every item and records it in the order's
+// ledger in one transaction, so that other orders never see part of it taken
+// and a short item leaves all stock untouched. A redelivered event finds the
+// ledger of the first delivery and takes nothing.
+func (c *ProductClient) TakeOrderStock(ctx context.Context, orderID string, items []domain.StockItem) error {
+	items, err := domain.MergeStockItems(items)
+	if err != nil {
+		return err
+	}
+
+	var changes []stockChange
+	var status domain.OrderStockStatus
+	err = c.db.WithTransaction(ctx, func(ctx context.Context) error {
+		changes = nil
+
+		record, err := c.processedOrderRepo.Begin(ctx, domain.NewOrderStock(orderID, items))
+		if err != nil {
+			return err
+		}
+		status = record.Status
+		if status == domain.OrderStockApplied || status == domain.OrderStockReleased {
+			return nil
+		}
 
-	product, err := c.productRepo.GetByID(ctx, productID)
+		for i, item := range record.Items {
+			if item.Applied {
+				continue
+			}
+
+			product, taken, err := c.productRepo.TakeStockForOrder(ctx, orderID, item.ProductID, item.Quantity)
+			if err != nil {
+				return err
+			}
+			if taken {
+				changes = append(changes, stockChange{product: product, delta: -item.Quantity})
+			}
+			record.MarkItem(i, true)
+		}
+
+		record.SetStatus(domain.OrderStockApplied)
+		return c.processedOrderRepo.Save(ctx, record)
+	})
 	if err != nil {
-		log.Printf("[%s] Error getting product %s: %v [latency: %v]",
-			time.Now().Format(time.RFC3339Nano), productID, err, time.Since(startTime))
+		log.Printf("Failed to take stock for order %s, nothing was taken: %v", orderID, err)
 		return err
 	}
 
-	if product == nil {
-		err := fmt.Errorf("product not found: %s", productID)
-		log.Printf("[%s] %v [latency: %v]",
-			time.Now().Format(time.RFC3339Nano), err, time.Since(startTime))
+	switch status {
+	case domain.OrderStockApplied:
+		log.Printf("Stock of order %s was already taken, ignoring duplicate event", orderID)
+	case domain.OrderStockReleased:
+		log.Printf("Order %s was already released, ignoring late event", orderID)
+	default:
+		c.publishStockChanges(changes)
+		log.Printf("Took stock of %d products for order %s", len(items), orderID)
+	}
+	return nil
+}
+
+// ReleaseOrderStock releases only the items the ledger and the products say
+// the order took, in one transaction with the ledger. An order without a
+// record yet is recorded as released, so that its created event, should it
+// still arrive, takes nothing.
+func (c *ProductClient) ReleaseOrderStock(ctx context.Context, orderID string) error {
+	var changes []stockChange
+	err := c.db.WithTransaction(ctx, func(ctx context.Context) error {
+		changes = nil
+
+		released := domain.NewOrderStock(orderID, nil)
+		released.SetStatus(domain.OrderStockReleased)
+		record, err := c.processedOrderRepo.Begin(ctx, released)
+		if err != nil {
+			return err
+		}
+		if record.Status == domain.OrderStockReleased {
+			return nil
+		}
+
+		for i, item := range record.Items {
+			product, err := c.productRepo.ReleaseStockForOrder(ctx, orderID, item.ProductID, item.Quantity)
+			if err != nil {
+				return err
+			}
+			// Products added by a modification, or whose marker aged out,
+			// hold the order's stock without a marker.
+			if product == nil && item.Applied {
+				if product, err = c.productRepo.IncreaseStock(ctx, item.ProductID, item.Quantity); err != nil {
+					return err
+				}
+			}
+			if product != nil {
+				changes = append(changes, stockChange{product: product, delta: item.Quantity})
+			}
+			record.MarkItem(i, false)
+		}
+
+		record.SetStatus(domain.OrderStockReleased)
+		return c.processedOrderRepo.Save(ctx, record)
+	})
+	if err != nil {
 		return err
 	}
 
-	if product.Stock < quantity {
-		err := fmt.Errorf("insufficient stock for product %s: requested %d, available %d",
-			productID, quantity, product.Stock)
-		log.Printf("[%s] %v [latency: %v]",
-			time.Now().Format(time.RFC3339Nano), err, time.Since(startTime))
+	c.publishStockChanges(changes)
+	log.Printf("Released stock of order %s", orderID)
+	return nil
+}
+
+// ApplyOrderModification takes and returns stock by the deltas of a modified
+// order. The stock, the order's ledger and a record of the event change in
+// one transaction, so a redelivered event is applied once. The order's own
+// stock must be taken first; an order already released is left alone.
+func (c *ProductClient) ApplyOrderModification(ctx context.Context, eventID, orderID string, deltas []domain.StockDelta) error {
+	var changes []stockChange
+	err := c.db.WithTransaction(ctx, func(ctx context.Context) error {
+		changes = nil
+
+		if err := c.recordEvent(ctx, eventID, messaging.SubjectOrderModified); err != nil {
+			return err
+		}
+
+		record, err := c.processedOrderRepo.GetByID(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		switch {
+		case record == nil || record.Status == domain.OrderStockPending:
+			return fmt.Errorf("stock of order %s is not taken yet", orderID)
+		case record.Status == domain.OrderStockReleased:
+			log.Printf("Order %s was released, ignoring its modification", orderID)
+			return nil
+		}
+
+		for _, delta := range deltas {
+			held := record.Held(delta.ProductID)
+			var product *domain.Product
+			switch {
+			case delta.Delta > 0:
+				product, err = c.productRepo.DecreaseStock(ctx, delta.ProductID, delta.Delta)
+			case delta.Delta < 0 && held > 0:
+				delta.Delta = max(delta.Delta, -held)
+				product, err = c.productRepo.IncreaseStock(ctx, delta.ProductID, -delta.Delta)
+			default:
+				continue
+			}
+			if err == nil && product == nil {
+				err = fmt.Errorf("%w: %s", domain.ErrProductNotFound, delta.ProductID)
+			}
+			if err != nil {
+				return err
+			}
+
+			changes = append(changes, stockChange{product: product, delta: -delta.Delta})
+			record.SetHeld(delta.ProductID, held+delta.Delta)
+		}
+
+		return c.processedOrderRepo.Save(ctx, record)
+	})
+	if errors.Is(err, errEventApplied) {
+		log.Printf("Modification %s of order %s was already applied, ignoring duplicate event", eventID, orderID)
+		return nil
+	}
+	if err != nil {
 		return err
 	}
 
-	product.Stock -= quantity
-	updatedProduct, err := c.productRepo.Update(ctx, product)
+	c.publishStockChanges(changes)
+	log.Printf("Applied %d stock deltas of modified order %s", len(deltas), orderID)
+	return nil
+}
+
+// RestockReturn puts returned items back on sale, in one transaction with a
+// record of the event so a redelivered event restocks once. Items of deleted
+// products are skipped.
+func (c *ProductClient) RestockReturn(ctx context.Context, eventID string, items []domain.StockItem) error {
+	var changes []stockChange
+	err := c.db.WithTransaction(ctx, func(ctx context.Context) error {
+		changes = nil
+
+		if err := c.recordEvent(ctx, eventID, messaging.SubjectReturnReceived); err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			product, err := c.productRepo.IncreaseStock(ctx, item.ProductID, item.Quantity)
+			if err != nil {
+				return err
+			}
+			if product == nil {
+				log.Printf("Product %s no longer exists and is not restocked", item.ProductID)
+				continue
+			}
+			changes = append(changes, stockChange{product: product, delta: item.Quantity})
+		}
+		return nil
+	})
+	if errors.Is(err, errEventApplied) {
+		log.Printf("Return event %s was already applied, ignoring duplicate event", eventID)
+		return nil
+	}
 	if err != nil {
-		log.Printf("[%s] Error updating stock for product %s: %v [latency: %v]",
-			time.Now().Format(time.RFC3339Nano), productID, err, time.Since(startTime))
 		return err
 	}
 
-	endTime := time.Now()
-	log.Printf("[%s] Successfully decreased stock for product %s from %d to %d [latency: %v]",
-		endTime.Format(time.RFC3339Nano), updatedProduct.ID, updatedProduct.Stock+quantity,
-		updatedProduct.Stock, endTime.Sub(startTime))
+	c.publishStockChanges(changes)
+	return nil
+}
+
+// errEventApplied aborts the transaction of an event that was applied before.
+var errEventApplied = errors.New("event already applied")
+
+func (c *ProductClient) recordEvent(ctx context.Context, eventID, eventType string) error {
+	if eventID == "" {
+		return errors.New("event has no ID")
+	}
 
+	recorded, err := c.processedEventRepo.Record(ctx, eventID, eventType)
+	if err != nil {
+		return err
+	}
+	if !recorded {
+		return errEventApplied
+	}
 	return nil
 }
 
+// stockChange is a stock update made in a transaction, announced once the
+// transaction committed.
+type stockChange struct {
+	product *domain.Product
+	delta   int
+}
+
+func (c *ProductClient) publishStockChanges(changes []stockChange) {
+	for _, change := range changes {
+		c.publishStockChanged(change.product.ID, change.product.Stock-change.delta, change.product.Stock)
+	}
+}
+
+// publishStockChanged only logs failures: the stock is already updated.
+func (c *ProductClient) publishStockChanged(productID string, previous, stock int) {
+	if c.publisher == nil {
+		return
+	}
+
+	err := c.publisher.PublishStockChanged(messaging.StockChangedEvent{
+		ProductID:     productID,
+		PreviousStock: previous,
+		Stock:         stock,
+	})
+	if err != nil {
+		log.Printf("Failed to publish stock change of product %s: %v", productID, err)
+	}
+}
+
 func (c *ProductClient) Close() {
 	log.Println("Product client closed")
 }
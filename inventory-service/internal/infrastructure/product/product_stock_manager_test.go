@@ -0,0 +1,478 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"inventory-service/internal/domain"
+	"inventory-service/internal/infrastructure/messaging"
+	"inventory-service/internal/infrastructure/persistence"
+)
+
+// fakeStore keeps products, their order markers, the ledger and the processed
+// events in memory. A transaction restores all of it when it fails, like a
+// MongoDB transaction that aborts.
+type fakeStore struct {
+	mu        sync.Mutex
+	stock     map[string]int
+	markers   map[string]map[string]bool
+	ledger    map[string]*domain.OrderStock
+	events    map[string]bool
+	failSaves int
+}
+
+func newFakeStore(stock map[string]int) *fakeStore {
+	return &fakeStore{
+		stock:   stock,
+		markers: make(map[string]map[string]bool),
+		ledger:  make(map[string]*domain.OrderStock),
+		events:  make(map[string]bool),
+	}
+}
+
+type storeSnapshot struct {
+	stock   map[string]int
+	markers map[string]map[string]bool
+	ledger  map[string]*domain.OrderStock
+	events  map[string]bool
+}
+
+func (s *fakeStore) snapshot() storeSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap := storeSnapshot{
+		stock:   make(map[string]int, len(s.stock)),
+		markers: make(map[string]map[string]bool, len(s.markers)),
+		ledger:  make(map[string]*domain.OrderStock, len(s.ledger)),
+		events:  make(map[string]bool, len(s.events)),
+	}
+	for id, stock := range s.stock {
+		snap.stock[id] = stock
+	}
+	for id, orders := range s.markers {
+		snap.markers[id] = make(map[string]bool, len(orders))
+		for orderID := range orders {
+			snap.markers[id][orderID] = true
+		}
+	}
+	for id, record := range s.ledger {
+		snap.ledger[id] = cloneOrderStock(record)
+	}
+	for id := range s.events {
+		snap.events[id] = true
+	}
+	return snap
+}
+
+func (s *fakeStore) restore(snap storeSnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stock, s.markers, s.ledger, s.events = snap.stock, snap.markers, snap.ledger, snap.events
+}
+
+func (s *fakeStore) stockOf(productID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stock[productID]
+}
+
+func (s *fakeStore) restock(productID string, quantity int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stock[productID] += quantity
+}
+
+func (s *fakeStore) status(orderID string) domain.OrderStockStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if record, ok := s.ledger[orderID]; ok {
+		return record.Status
+	}
+	return ""
+}
+
+func cloneOrderStock(record *domain.OrderStock) *domain.OrderStock {
+	clone := *record
+	clone.Items = append([]domain.OrderStockItem(nil), record.Items...)
+	return &clone
+}
+
+type fakeTransactor struct {
+	mu    sync.Mutex
+	store *fakeStore
+}
+
+func (t *fakeTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	snap := t.store.snapshot()
+	if err := fn(ctx); err != nil {
+		t.store.restore(snap)
+		return err
+	}
+	return nil
+}
+
+type fakeProducts struct {
+	persistence.ProductRepository
+	store *fakeStore
+}
+
+func (r *fakeProducts) DecreaseStock(ctx context.Context, productID string, quantity int) (*domain.Product, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	stock, ok := r.store.stock[productID]
+	if !ok {
+		return nil, nil
+	}
+	if stock < quantity {
+		return nil, fmt.Errorf("%w for product %s", domain.ErrInsufficientStock, productID)
+	}
+	r.store.stock[productID] -= quantity
+	return &domain.Product{ID: productID, Stock: r.store.stock[productID]}, nil
+}
+
+func (r *fakeProducts) IncreaseStock(ctx context.Context, productID string, quantity int) (*domain.Product, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.stock[productID]; !ok {
+		return nil, nil
+	}
+	r.store.stock[productID] += quantity
+	return &domain.Product{ID: productID, Stock: r.store.stock[productID]}, nil
+}
+
+func (r *fakeProducts) TakeStockForOrder(ctx context.Context, orderID, productID string, quantity int) (*domain.Product, bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	stock, ok := r.store.stock[productID]
+	switch {
+	case !ok:
+		return nil, false, fmt.Errorf("%w: %s", domain.ErrProductNotFound, productID)
+	case r.store.markers[productID][orderID]:
+		return &domain.Product{ID: productID, Stock: stock}, false, nil
+	case stock < quantity:
+		return nil, false, fmt.Errorf("%w for product %s", domain.ErrInsufficientStock, productID)
+	}
+
+	r.store.stock[productID] -= quantity
+	if r.store.markers[productID] == nil {
+		r.store.markers[productID] = make(map[string]bool)
+	}
+	r.store.markers[productID][orderID] = true
+	return &domain.Product{ID: productID, Stock: r.store.stock[productID]}, true, nil
+}
+
+func (r *fakeProducts) ReleaseStockForOrder(ctx context.Context, orderID, productID string, quantity int) (*domain.Product, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if !r.store.markers[productID][orderID] {
+		return nil, nil
+	}
+	delete(r.store.markers[productID], orderID)
+	r.store.stock[productID] += quantity
+	return &domain.Product{ID: productID, Stock: r.store.stock[productID]}, nil
+}
+
+type fakeProcessedOrders struct {
+	store *fakeStore
+}
+
+func (r *fakeProcessedOrders) Begin(ctx context.Context, record *domain.OrderStock) (*domain.OrderStock, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if existing, ok := r.store.ledger[record.OrderID]; ok {
+		return cloneOrderStock(existing), nil
+	}
+	r.store.ledger[record.OrderID] = cloneOrderStock(record)
+	return cloneOrderStock(record), nil
+}
+
+func (r *fakeProcessedOrders) GetByID(ctx context.Context, orderID string) (*domain.OrderStock, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if record, ok := r.store.ledger[orderID]; ok {
+		return cloneOrderStock(record), nil
+	}
+	return nil, nil
+}
+
+func (r *fakeProcessedOrders) Save(ctx context.Context, record *domain.OrderStock) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if r.store.failSaves > 0 {
+		r.store.failSaves--
+		return errors.New("connection reset")
+	}
+	r.store.ledger[record.OrderID] = cloneOrderStock(record)
+	return nil
+}
+
+type fakeProcessedEvents struct {
+	store *fakeStore
+}
+
+func (r *fakeProcessedEvents) Record(ctx context.Context, eventID, eventType string) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if r.store.events[eventID] {
+		return false, nil
+	}
+	r.store.events[eventID] = true
+	return true, nil
+}
+
+type recordingPublisher struct {
+	mu      sync.Mutex
+	changes []messaging.StockChangedEvent
+}
+
+func (p *recordingPublisher) PublishStockChanged(event messaging.StockChangedEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.changes = append(p.changes, event)
+	return nil
+}
+
+func (p *recordingPublisher) Close() {}
+
+func (p *recordingPublisher) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.changes)
+}
+
+func newLedgerFixture() (*ProductClient, *fakeStore, *recordingPublisher) {
+	store := newFakeStore(map[string]int{"tea": 5, "milk": 10})
+	publisher := &recordingPublisher{}
+	client := &ProductClient{
+		db:                 &fakeTransactor{store: store},
+		productRepo:        &fakeProducts{store: store},
+		processedOrderRepo: &fakeProcessedOrders{store: store},
+		processedEventRepo: &fakeProcessedEvents{store: store},
+		publisher:          publisher,
+	}
+	return client, store, publisher
+}
+
+var orderItems = []domain.StockItem{{ProductID: "tea", Quantity: 2}, {ProductID: "milk", Quantity: 3}}
+
+func TestTakeOrderStockOnceForRedeliveredEvent(t *testing.T) {
+	ctx := context.Background()
+	client, store, publisher := newLedgerFixture()
+
+	for i := 0; i < 3; i++ {
+		if err := client.TakeOrderStock(ctx, "order-1", orderItems); err != nil {
+			t.Fatalf("delivery %d: %v", i+1, err)
+		}
+	}
+
+	if store.stockOf("tea") != 3 || store.stockOf("milk") != 7 {
+		t.Fatalf("stock is tea %d, milk %d; want tea 3, milk 7", store.stockOf("tea"), store.stockOf("milk"))
+	}
+	if store.status("order-1") != domain.OrderStockApplied {
+		t.Fatalf("ledger status = %q, want applied", store.status("order-1"))
+	}
+	if publisher.count() != 2 {
+		t.Fatalf("published %d stock changes, want 2", publisher.count())
+	}
+}
+
+func TestTakeOrderStockTakesNothingWhenTheLedgerUpdateFails(t *testing.T) {
+	ctx := context.Background()
+	client, store, publisher := newLedgerFixture()
+
+	store.failSaves = 1
+	if err := client.TakeOrderStock(ctx, "order-1", orderItems); err == nil {
+		t.Fatal("first delivery succeeded although the ledger update failed")
+	}
+	if store.stockOf("tea") != 5 || store.stockOf("milk") != 10 || store.status("order-1") != "" {
+		t.Fatalf("after the failure stock is tea %d, milk %d and the ledger %q; want it untouched",
+			store.stockOf("tea"), store.stockOf("milk"), store.status("order-1"))
+	}
+	if publisher.count() != 0 {
+		t.Fatalf("published %d stock changes of a rolled back order", publisher.count())
+	}
+
+	if err := client.TakeOrderStock(ctx, "order-1", orderItems); err != nil {
+		t.Fatalf("redelivery: %v", err)
+	}
+	if store.stockOf("tea") != 3 || store.stockOf("milk") != 7 {
+		t.Fatalf("after the redelivery stock is tea %d, milk %d; want tea 3, milk 7", store.stockOf("tea"), store.stockOf("milk"))
+	}
+	if store.status("order-1") != domain.OrderStockApplied {
+		t.Fatalf("ledger status = %q, want applied", store.status("order-1"))
+	}
+}
+
+func TestTakeOrderStockTakesNothingOfAShortOrder(t *testing.T) {
+	ctx := context.Background()
+	client, store, _ := newLedgerFixture()
+
+	items := []domain.StockItem{{ProductID: "milk", Quantity: 3}, {ProductID: "tea", Quantity: 6}}
+	if err := client.TakeOrderStock(ctx, "order-1", items); !errors.Is(err, domain.ErrInsufficientStock) {
+		t.Fatalf("TakeOrderStock error = %v, want insufficient stock", err)
+	}
+	if store.stockOf("tea") != 5 || store.stockOf("milk") != 10 || store.status("order-1") != "" {
+		t.Fatalf("short order left stock at tea %d, milk %d and the ledger %q",
+			store.stockOf("tea"), store.stockOf("milk"), store.status("order-1"))
+	}
+
+	store.restock("tea", 5)
+	if err := client.TakeOrderStock(ctx, "order-1", items); err != nil {
+		t.Fatalf("redelivery after restock: %v", err)
+	}
+	if store.stockOf("tea") != 4 || store.stockOf("milk") != 7 {
+		t.Fatalf("stock is tea %d, milk %d; want tea 4, milk 7", store.stockOf("tea"), store.stockOf("milk"))
+	}
+}
+
+func TestTakeOrderStockResumesAPendingRecord(t *testing.T) {
+	ctx := context.Background()
+	client, store, _ := newLedgerFixture()
+
+	// A record left pending with the tea taken, as written before the stock
+	// was taken in one transaction.
+	record := domain.NewOrderStock("order-1", orderItems)
+	record.MarkItem(0, true)
+	store.ledger["order-1"] = record
+	store.stock["tea"] = 3
+	store.markers["tea"] = map[string]bool{"order-1": true}
+
+	if err := client.TakeOrderStock(ctx, "order-1", orderItems); err != nil {
+		t.Fatalf("TakeOrderStock: %v", err)
+	}
+	if store.stockOf("tea") != 3 || store.stockOf("milk") != 7 {
+		t.Fatalf("stock is tea %d, milk %d; want tea 3, milk 7", store.stockOf("tea"), store.stockOf("milk"))
+	}
+	if store.status("order-1") != domain.OrderStockApplied {
+		t.Fatalf("ledger status = %q, want applied", store.status("order-1"))
+	}
+}
+
+func TestReleaseOrderStockOnce(t *testing.T) {
+	ctx := context.Background()
+	client, store, _ := newLedgerFixture()
+
+	if err := client.TakeOrderStock(ctx, "order-1", orderItems); err != nil {
+		t.Fatalf("TakeOrderStock: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := client.ReleaseOrderStock(ctx, "order-1"); err != nil {
+			t.Fatalf("release %d: %v", i+1, err)
+		}
+	}
+	if store.stockOf("tea") != 5 || store.stockOf("milk") != 10 {
+		t.Fatalf("stock is tea %d, milk %d; want it all back once", store.stockOf("tea"), store.stockOf("milk"))
+	}
+
+	// A created event replayed after the release takes nothing.
+	if err := client.TakeOrderStock(ctx, "order-1", orderItems); err != nil {
+		t.Fatalf("late created event: %v", err)
+	}
+	if store.stockOf("tea") != 5 || store.stockOf("milk") != 10 {
+		t.Fatalf("late created event took stock: tea %d, milk %d", store.stockOf("tea"), store.stockOf("milk"))
+	}
+}
+
+func TestReleaseBeforeCreatedEventKeepsTheStock(t *testing.T) {
+	ctx := context.Background()
+	client, store, _ := newLedgerFixture()
+
+	if err := client.ReleaseOrderStock(ctx, "order-1"); err != nil {
+		t.Fatalf("ReleaseOrderStock: %v", err)
+	}
+	if err := client.TakeOrderStock(ctx, "order-1", orderItems); err != nil {
+		t.Fatalf("TakeOrderStock: %v", err)
+	}
+	if store.stockOf("tea") != 5 || store.stockOf("milk") != 10 {
+		t.Fatalf("stock is tea %d, milk %d; want it untouched", store.stockOf("tea"), store.stockOf("milk"))
+	}
+}
+
+func TestOrderModificationAppliesOncePerEvent(t *testing.T) {
+	ctx := context.Background()
+	client, store, _ := newLedgerFixture()
+
+	if err := client.TakeOrderStock(ctx, "order-1", orderItems); err != nil {
+		t.Fatalf("TakeOrderStock: %v", err)
+	}
+	deltas := []domain.StockDelta{{ProductID: "tea", Delta: -1}, {ProductID: "milk", Delta: 2}}
+	for i := 0; i < 2; i++ {
+		if err := client.ApplyOrderModification(ctx, "event-1", "order-1", deltas); err != nil {
+			t.Fatalf("delivery %d: %v", i+1, err)
+		}
+	}
+	if store.stockOf("tea") != 4 || store.stockOf("milk") != 5 {
+		t.Fatalf("stock is tea %d, milk %d; want tea 4, milk 5", store.stockOf("tea"), store.stockOf("milk"))
+	}
+
+	// The release gives back what the order holds after the modification.
+	if err := client.ReleaseOrderStock(ctx, "order-1"); err != nil {
+		t.Fatalf("ReleaseOrderStock: %v", err)
+	}
+	if store.stockOf("tea") != 5 || store.stockOf("milk") != 10 {
+		t.Fatalf("after the release stock is tea %d, milk %d; want tea 5, milk 10", store.stockOf("tea"), store.stockOf("milk"))
+	}
+}
+
+func TestFailedOrderModificationChangesNothing(t *testing.T) {
+	ctx := context.Background()
+	client, store, publisher := newLedgerFixture()
+
+	if err := client.TakeOrderStock(ctx, "order-1", orderItems); err != nil {
+		t.Fatalf("TakeOrderStock: %v", err)
+	}
+	published := publisher.count()
+
+	deltas := []domain.StockDelta{{ProductID: "milk", Delta: 2}, {ProductID: "tea", Delta: 4}}
+	if err := client.ApplyOrderModification(ctx, "event-1", "order-1", deltas); !errors.Is(err, domain.ErrInsufficientStock) {
+		t.Fatalf("ApplyOrderModification error = %v, want insufficient stock", err)
+	}
+	if store.stockOf("tea") != 3 || store.stockOf("milk") != 7 {
+		t.Fatalf("stock is tea %d, milk %d; want tea 3, milk 7", store.stockOf("tea"), store.stockOf("milk"))
+	}
+	if publisher.count() != published {
+		t.Fatal("published stock changes of a rolled back modification")
+	}
+
+	// The event was not recorded, so its redelivery applies it.
+	store.restock("tea", 1)
+	if err := client.ApplyOrderModification(ctx, "event-1", "order-1", deltas); err != nil {
+		t.Fatalf("redelivery: %v", err)
+	}
+	if store.stockOf("tea") != 0 || store.stockOf("milk") != 5 {
+		t.Fatalf("stock is tea %d, milk %d; want tea 0, milk 5", store.stockOf("tea"), store.stockOf("milk"))
+	}
+}
+
+func TestModificationWaitsForTheCreatedEvent(t *testing.T) {
+	ctx := context.Background()
+	client, store, _ := newLedgerFixture()
+
+	deltas := []domain.StockDelta{{ProductID: "milk", Delta: 2}}
+	if err := client.ApplyOrderModification(ctx, "event-1", "order-1", deltas); err == nil {
+		t.Fatal("modification was applied before the order took its stock")
+	}
+	if store.stockOf("milk") != 10 {
+		t.Fatalf("milk stock = %d, want 10", store.stockOf("milk"))
+	}
+}
+
+func TestRestockReturnOncePerEvent(t *testing.T) {
+	ctx := context.Background()
+	client, store, _ := newLedgerFixture()
+
+	items := []domain.StockItem{{ProductID: "tea", Quantity: 1}, {ProductID: "bread", Quantity: 2}}
+	for i := 0; i < 2; i++ {
+		if err := client.RestockReturn(ctx, "return-1", items); err != nil {
+			t.Fatalf("delivery %d: %v", i+1, err)
+		}
+	}
+	if store.stockOf("tea") != 6 {
+		t.Fatalf("tea stock = %d, want 6", store.stockOf("tea"))
+	}
+}
@@ -23,6 +23,7 @@ func (h *CategoryHandler) CreateCategory(ctx context.Context, req *inventory.Cat
 	category := domain.NewCategory(
 		req.Category.Name,
 		req.Category.Description,
+		domain.TaxClass(req.Category.TaxClass),
 	)
 
 	created, err := h.categoryUseCase.CreateCategory(ctx, category)
@@ -35,6 +36,7 @@ func (h *CategoryHandler) CreateCategory(ctx context.Context, req *inventory.Cat
 			Id:          created.ID,
 			Name:        created.Name,
 			Description: created.Description,
+			TaxClass:    string(created.TaxClass),
 		},
 	}, nil
 }
@@ -53,6 +55,7 @@ func (h *CategoryHandler) GetCategory(ctx context.Context, req *inventory.Catego
 			Id:          category.ID,
 			Name:        category.Name,
 			Description: category.Description,
+			TaxClass:    string(category.TaxClass),
 		},
 	}, nil
 }
@@ -62,6 +65,7 @@ func (h *CategoryHandler) UpdateCategory(ctx context.Context, req *inventory.Cat
 		ID:          req.Category.Id,
 		Name:        req.Category.Name,
 		Description: req.Category.Description,
+		TaxClass:    domain.TaxClass(req.Category.TaxClass),
 	}
 
 	updated, err := h.categoryUseCase.UpdateCategory(ctx, category)
@@ -77,6 +81,7 @@ func (h *CategoryHandler) UpdateCategory(ctx context.Context, req *inventory.Cat
 			Id:          updated.ID,
 			Name:        updated.Name,
 			Description: updated.Description,
+			TaxClass:    string(updated.TaxClass),
 		},
 	}, nil
 }
@@ -102,6 +107,7 @@ func (h *CategoryHandler) ListCategories(ctx context.Context, req *inventory.Emp
 			Id:          c.ID,
 			Name:        c.Name,
 			Description: c.Description,
+			TaxClass:    string(c.TaxClass),
 		})
 	}
 
@@ -2,22 +2,30 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"inventory-service/internal/application"
 	"inventory-service/internal/domain"
+	"inventory-service/internal/infrastructure/messaging"
 	"proto/inventory"
+	"proto/money"
 )
 
 type InventoryHandler struct {
 	inventory.UnimplementedInventoryServiceServer
-	productUseCase  *application.ProductUseCase
-	categoryUseCase *application.CategoryUseCase
+	productUseCase    *application.ProductUseCase
+	categoryUseCase   *application.CategoryUseCase
+	reviewUseCase     *application.ReviewUseCase
+	deadLetterUseCase *application.DeadLetterUseCase
 }
 
-func NewInventoryHandler(productUseCase *application.ProductUseCase, categoryUseCase *application.CategoryUseCase) *InventoryHandler {
+func NewInventoryHandler(productUseCase *application.ProductUseCase, categoryUseCase *application.CategoryUseCase, reviewUseCase *application.ReviewUseCase, deadLetterUseCase *application.DeadLetterUseCase) *InventoryHandler {
 	return &InventoryHandler{
-		productUseCase:  productUseCase,
-		categoryUseCase: categoryUseCase,
+		productUseCase:    productUseCase,
+		categoryUseCase:   categoryUseCase,
+		reviewUseCase:     reviewUseCase,
+		deadLetterUseCase: deadLetterUseCase,
 	}
 }
 
@@ -25,9 +33,10 @@ func (h *InventoryHandler) CreateProduct(ctx context.Context, req *inventory.Pro
 	product := domain.NewProduct(
 		req.Product.Name,
 		req.Product.Description,
-		float64(req.Product.Price),
+		money.FromProto(req.Product.Price),
 		int(req.Product.Stock),
 		req.Product.CategoryId,
+		domain.TaxClass(req.Product.TaxClass),
 	)
 
 	created, err := h.productUseCase.CreateProduct(ctx, product)
@@ -36,14 +45,7 @@ func (h *InventoryHandler) CreateProduct(ctx context.Context, req *inventory.Pro
 	}
 
 	return &inventory.ProductResponse{
-		Product: &inventory.Product{
-			Id:          created.ID,
-			Name:        created.Name,
-			Description: created.Description,
-			Price:       float32(created.Price),
-			Stock:       int32(created.Stock),
-			CategoryId:  created.CategoryID,
-		},
+		Product: convertToProtoProduct(created),
 	}, nil
 }
 
@@ -57,14 +59,7 @@ func (h *InventoryHandler) GetProduct(ctx context.Context, req *inventory.Produc
 	}
 
 	return &inventory.ProductResponse{
-		Product: &inventory.Product{
-			Id:          product.ID,
-			Name:        product.Name,
-			Description: product.Description,
-			Price:       float32(product.Price),
-			Stock:       int32(product.Stock),
-			CategoryId:  product.CategoryID,
-		},
+		Product: convertToProtoProduct(product),
 	}, nil
 }
 
@@ -73,9 +68,10 @@ func (h *InventoryHandler) UpdateProduct(ctx context.Context, req *inventory.Pro
 		ID:          req.Product.Id,
 		Name:        req.Product.Name,
 		Description: req.Product.Description,
-		Price:       float64(req.Product.Price),
+		Price:       money.FromProto(req.Product.Price),
 		Stock:       int(req.Product.Stock),
 		CategoryID:  req.Product.CategoryId,
+		TaxClass:    domain.TaxClass(req.Product.TaxClass),
 	}
 
 	updated, err := h.productUseCase.UpdateProduct(ctx, product)
@@ -87,14 +83,7 @@ func (h *InventoryHandler) UpdateProduct(ctx context.Context, req *inventory.Pro
 	}
 
 	return &inventory.ProductResponse{
-		Product: &inventory.Product{
-			Id:          updated.ID,
-			Name:        updated.Name,
-			Description: updated.Description,
-			Price:       float32(updated.Price),
-			Stock:       int32(updated.Stock),
-			CategoryId:  updated.CategoryID,
-		},
+		Product: convertToProtoProduct(updated),
 	}, nil
 }
 
@@ -115,14 +104,7 @@ func (h *InventoryHandler) ListProducts(ctx context.Context, req *inventory.Prod
 
 	var protoProducts []*inventory.Product
 	for _, p := range products {
-		protoProducts = append(protoProducts, &inventory.Product{
-			Id:          p.ID,
-			Name:        p.Name,
-			Description: p.Description,
-			Price:       float32(p.Price),
-			Stock:       int32(p.Stock),
-			CategoryId:  p.CategoryID,
-		})
+		protoProducts = append(protoProducts, convertToProtoProduct(p))
 	}
 
 	return &inventory.ProductListResponse{
@@ -135,6 +117,7 @@ func (h *InventoryHandler) CreateCategory(ctx context.Context, req *inventory.Ca
 	category := domain.NewCategory(
 		req.Category.Name,
 		req.Category.Description,
+		domain.TaxClass(req.Category.TaxClass),
 	)
 
 	created, err := h.categoryUseCase.CreateCategory(ctx, category)
@@ -147,6 +130,7 @@ func (h *InventoryHandler) CreateCategory(ctx context.Context, req *inventory.Ca
 			Id:          created.ID,
 			Name:        created.Name,
 			Description: created.Description,
+			TaxClass:    string(created.TaxClass),
 		},
 	}, nil
 }
@@ -165,6 +149,7 @@ func (h *InventoryHandler) GetCategory(ctx context.Context, req *inventory.Categ
 			Id:          category.ID,
 			Name:        category.Name,
 			Description: category.Description,
+			TaxClass:    string(category.TaxClass),
 		},
 	}, nil
 }
@@ -174,6 +159,7 @@ func (h *InventoryHandler) UpdateCategory(ctx context.Context, req *inventory.Ca
 		ID:          req.Category.Id,
 		Name:        req.Category.Name,
 		Description: req.Category.Description,
+		TaxClass:    domain.TaxClass(req.Category.TaxClass),
 	}
 
 	updated, err := h.categoryUseCase.UpdateCategory(ctx, category)
@@ -189,6 +175,7 @@ func (h *InventoryHandler) UpdateCategory(ctx context.Context, req *inventory.Ca
 			Id:          updated.ID,
 			Name:        updated.Name,
 			Description: updated.Description,
+			TaxClass:    string(updated.TaxClass),
 		},
 	}, nil
 }
@@ -214,6 +201,7 @@ func (h *InventoryHandler) ListCategories(ctx context.Context, req *inventory.Em
 			Id:          c.ID,
 			Name:        c.Name,
 			Description: c.Description,
+			TaxClass:    string(c.TaxClass),
 		})
 	}
 
@@ -221,3 +209,181 @@ func (h *InventoryHandler) ListCategories(ctx context.Context, req *inventory.Em
 		Categories: protoCategories,
 	}, nil
 }
+
+func (h *InventoryHandler) CreateReview(ctx context.Context, req *inventory.CreateReviewRequest) (*inventory.ReviewResponse, error) {
+	review, err := h.reviewUseCase.CreateReview(ctx, req.ProductId, req.UserId, int(req.Rating), req.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inventory.ReviewResponse{Review: convertToProtoReview(review)}, nil
+}
+
+func (h *InventoryHandler) ListReviews(ctx context.Context, req *inventory.ListReviewsRequest) (*inventory.ListReviewsResponse, error) {
+	reviews, total, err := h.reviewUseCase.ListReviews(ctx, req.ProductId,
+		domain.ReviewStatus(req.Status), domain.ReviewSort(req.Sort), int(req.Page), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	var protoReviews []*inventory.Review
+	for _, r := range reviews {
+		protoReviews = append(protoReviews, convertToProtoReview(r))
+	}
+
+	return &inventory.ListReviewsResponse{
+		Reviews: protoReviews,
+		Total:   int32(total),
+	}, nil
+}
+
+func (h *InventoryHandler) ModerateReview(ctx context.Context, req *inventory.ModerateReviewRequest) (*inventory.ReviewResponse, error) {
+	review, err := h.reviewUseCase.ModerateReview(ctx, req.Id, req.Approve, req.Note)
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		return nil, nil
+	}
+
+	return &inventory.ReviewResponse{Review: convertToProtoReview(review)}, nil
+}
+
+func (h *InventoryHandler) DecreaseStock(ctx context.Context, req *inventory.DecreaseStockRequest) (*inventory.DecreaseStockResponse, error) {
+	product, err := h.productUseCase.DecreaseStock(ctx, req.ProductId, int(req.Quantity))
+	if errors.Is(err, domain.ErrInsufficientStock) {
+		return &inventory.DecreaseStockResponse{Success: false, Message: err.Error()}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return &inventory.DecreaseStockResponse{Success: false, Message: "product not found"}, nil
+	}
+
+	return &inventory.DecreaseStockResponse{
+		Success: true,
+		Message: "stock decreased",
+		Stock:   int32(product.Stock),
+	}, nil
+}
+
+func (h *InventoryHandler) DecreaseStockBatch(ctx context.Context, req *inventory.DecreaseStockBatchRequest) (*inventory.DecreaseStockBatchResponse, error) {
+	items := make([]domain.StockItem, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = domain.StockItem{ProductID: item.ProductId, Quantity: int(item.Quantity)}
+	}
+
+	products, err := h.productUseCase.DecreaseStockBatch(ctx, items)
+	if errors.Is(err, domain.ErrInsufficientStock) || errors.Is(err, domain.ErrProductNotFound) {
+		return &inventory.DecreaseStockBatchResponse{Success: false, Message: err.Error()}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := &inventory.DecreaseStockBatchResponse{Success: true, Message: "stock decreased"}
+	for _, product := range products {
+		response.Stock = append(response.Stock, &inventory.StockLevel{
+			ProductId: product.ID,
+			Stock:     int32(product.Stock),
+		})
+	}
+
+	return response, nil
+}
+
+func (h *InventoryHandler) ListDeadLetters(ctx context.Context, req *inventory.ListDeadLettersRequest) (*inventory.ListDeadLettersResponse, error) {
+	letters, total, err := h.deadLetterUseCase.ListDeadLetters(ctx, req.SourceSubject, int(req.Page), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	var protoLetters []*inventory.DeadLetter
+	for _, letter := range letters {
+		protoLetters = append(protoLetters, convertToProtoDeadLetter(letter))
+	}
+
+	return &inventory.ListDeadLettersResponse{
+		DeadLetters: protoLetters,
+		Total:       int32(total),
+	}, nil
+}
+
+func (h *InventoryHandler) GetDeadLetter(ctx context.Context, req *inventory.DeadLetterID) (*inventory.DeadLetterResponse, error) {
+	letter, err := h.deadLetterUseCase.GetDeadLetter(ctx, req.Sequence)
+	if err != nil {
+		return nil, err
+	}
+	if letter == nil {
+		return &inventory.DeadLetterResponse{}, nil
+	}
+
+	return &inventory.DeadLetterResponse{DeadLetter: convertToProtoDeadLetter(letter)}, nil
+}
+
+func (h *InventoryHandler) RedriveDeadLetter(ctx context.Context, req *inventory.DeadLetterID) (*inventory.DeadLetterResponse, error) {
+	letter, err := h.deadLetterUseCase.RedriveDeadLetter(ctx, req.Sequence)
+	if err != nil {
+		return nil, err
+	}
+	if letter == nil {
+		return &inventory.DeadLetterResponse{}, nil
+	}
+
+	return &inventory.DeadLetterResponse{DeadLetter: convertToProtoDeadLetter(letter)}, nil
+}
+
+func (h *InventoryHandler) PurgeDeadLetters(ctx context.Context, req *inventory.PurgeDeadLettersRequest) (*inventory.PurgeDeadLettersResponse, error) {
+	purged, err := h.deadLetterUseCase.PurgeDeadLetters(ctx, req.Sequence, req.SourceSubject)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inventory.PurgeDeadLettersResponse{Purged: int32(purged)}, nil
+}
+
+func convertToProtoProduct(p *domain.Product) *inventory.Product {
+	return &inventory.Product{
+		Id:            p.ID,
+		Name:          p.Name,
+		Description:   p.Description,
+		Price:         p.Price.ToProto(),
+		Stock:         int32(p.Stock),
+		CategoryId:    p.CategoryID,
+		TaxClass:      string(p.TaxClass),
+		RatingAverage: p.RatingAverage(),
+		RatingCount:   int32(p.RatingCount),
+	}
+}
+
+func convertToProtoReview(r *domain.Review) *inventory.Review {
+	return &inventory.Review{
+		Id:             r.ID,
+		ProductId:      r.ProductID,
+		UserId:         r.UserID,
+		OrderId:        r.OrderID,
+		Rating:         int32(r.Rating),
+		Text:           r.Text,
+		Status:         string(r.Status),
+		ModerationNote: r.ModerationNote,
+		CreatedAt:      r.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      r.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func convertToProtoDeadLetter(l *messaging.DeadLetter) *inventory.DeadLetter {
+	letter := &inventory.DeadLetter{
+		Sequence:      l.Sequence,
+		SourceSubject: l.SourceSubject,
+		Consumer:      l.Consumer,
+		Error:         l.Error,
+		Attempts:      int32(l.Attempts),
+		FailedAt:      l.FailedAt.Format(time.RFC3339),
+		Data:          l.Data,
+	}
+	if !l.FirstFailure.IsZero() {
+		letter.FirstFailureAt = l.FirstFailure.Format(time.RFC3339)
+	}
+	return letter
+}
@@ -6,6 +6,7 @@ import (
 	"inventory-service/internal/application"
 	"inventory-service/internal/domain"
 	"proto/inventory"
+	"proto/money"
 )
 
 type ProductHandler struct {
@@ -23,9 +24,10 @@ func (h *ProductHandler) CreateProduct(ctx context.Context, req *inventory.Produ
 	product := domain.NewProduct(
 		req.Product.Name,
 		req.Product.Description,
-		float64(req.Product.Price),
+		money.FromProto(req.Product.Price),
 		int(req.Product.Stock),
 		req.Product.CategoryId,
+		domain.TaxClass(req.Product.TaxClass),
 	)
 
 	created, err := h.productUseCase.CreateProduct(ctx, product)
@@ -38,9 +40,10 @@ func (h *ProductHandler) CreateProduct(ctx context.Context, req *inventory.Produ
 			Id:          created.ID,
 			Name:        created.Name,
 			Description: created.Description,
-			Price:       float32(created.Price),
+			Price:       created.Price.ToProto(),
 			Stock:       int32(created.Stock),
 			CategoryId:  created.CategoryID,
+			TaxClass:    string(created.TaxClass),
 		},
 	}, nil
 }
@@ -59,9 +62,10 @@ func (h *ProductHandler) GetProduct(ctx context.Context, req *inventory.ProductI
 			Id:          product.ID,
 			Name:        product.Name,
 			Description: product.Description,
-			Price:       float32(product.Price),
+			Price:       product.Price.ToProto(),
 			Stock:       int32(product.Stock),
 			CategoryId:  product.CategoryID,
+			TaxClass:    string(product.TaxClass),
 		},
 	}, nil
 }
@@ -71,9 +75,10 @@ func (h *ProductHandler) UpdateProduct(ctx context.Context, req *inventory.Produ
 		ID:          req.Product.Id,
 		Name:        req.Product.Name,
 		Description: req.Product.Description,
-		Price:       float64(req.Product.Price),
+		Price:       money.FromProto(req.Product.Price),
 		Stock:       int(req.Product.Stock),
 		CategoryID:  req.Product.CategoryId,
+		TaxClass:    domain.TaxClass(req.Product.TaxClass),
 	}
 
 	updated, err := h.productUseCase.UpdateProduct(ctx, product)
@@ -89,9 +94,10 @@ func (h *ProductHandler) UpdateProduct(ctx context.Context, req *inventory.Produ
 			Id:          updated.ID,
 			Name:        updated.Name,
 			Description: updated.Description,
-			Price:       float32(updated.Price),
+			Price:       updated.Price.ToProto(),
 			Stock:       int32(updated.Stock),
 			CategoryId:  updated.CategoryID,
+			TaxClass:    string(updated.TaxClass),
 		},
 	}, nil
 }
@@ -117,9 +123,10 @@ func (h *ProductHandler) ListProducts(ctx context.Context, req *inventory.Produc
 			Id:          p.ID,
 			Name:        p.Name,
 			Description: p.Description,
-			Price:       float32(p.Price),
+			Price:       p.Price.ToProto(),
 			Stock:       int32(p.Stock),
 			CategoryId:  p.CategoryID,
+			TaxClass:    string(p.TaxClass),
 		})
 	}
 
@@ -6,6 +6,7 @@ import (
 	"inventory-service/internal/infrastructure/cache"
 	"inventory-service/internal/infrastructure/database"
 	"inventory-service/internal/infrastructure/messaging"
+	"inventory-service/internal/infrastructure/orders"
 	"inventory-service/internal/infrastructure/persistence"
 	"inventory-service/internal/infrastructure/product"
 	"inventory-service/internal/interfaces/handlers"
@@ -19,11 +20,15 @@ func RegisterGRPCServices(
 	grpcServer *gogrpc.Server,
 	db *database.MongoDBConnector,
 	consumer messaging.EventConsumer,
+	publisher messaging.EventPublisher,
 	productClient product.ProductServiceClient,
 	redisClient *cache.RedisClient,
+	purchaseVerifier orders.PurchaseVerifier,
+	deadLetters *messaging.DeadLetterQueue,
 ) {
 	mongoProductRepo := persistence.NewMongoProductRepository(db)
 	categoryRepo := persistence.NewMongoCategoryRepository(db)
+	reviewRepo := persistence.NewMongoReviewRepository(db)
 
 	var productRepo persistence.ProductRepository
 	if redisClient != nil {
@@ -34,13 +39,15 @@ func RegisterGRPCServices(
 		log.Println("Using MongoDB product repository without caching")
 	}
 
-	productUseCase := application.NewProductUseCase(productRepo)
+	productUseCase := application.NewProductUseCase(productRepo, publisher)
 	categoryUseCase := application.NewCategoryUseCase(categoryRepo)
+	reviewUseCase := application.NewReviewUseCase(db, reviewRepo, productRepo, purchaseVerifier)
+	deadLetterUseCase := application.NewDeadLetterUseCase(deadLetters)
 	metrics := application.NewMetrics()
 
 	orderEventHandler := application.NewOrderEventHandler(productClient, metrics)
 
-	inventoryHandler := handlers.NewInventoryHandler(productUseCase, categoryUseCase)
+	inventoryHandler := handlers.NewInventoryHandler(productUseCase, categoryUseCase, reviewUseCase, deadLetterUseCase)
 
 	inventory.RegisterInventoryServiceServer(grpcServer, inventoryHandler)
 
@@ -54,4 +61,34 @@ func RegisterGRPCServices(
 	}
 
 	log.Println("Successfully subscribed to order.created events")
+
+	err = consumer.SubscribeToOrderExpired(func(ctx context.Context, event *messaging.OrderExpiredEvent) error {
+		metrics.IncEventsProcessed()
+		return orderEventHandler.HandleOrderExpired(ctx, event)
+	})
+	if err != nil {
+		log.Fatalf("Failed to subscribe to order.expired events: %v", err)
+	}
+
+	log.Println("Successfully subscribed to order.expired events")
+
+	err = consumer.SubscribeToOrderModified(func(ctx context.Context, eventID string, event *messaging.OrderModifiedEvent) error {
+		metrics.IncEventsProcessed()
+		return orderEventHandler.HandleOrderModified(ctx, eventID, event)
+	})
+	if err != nil {
+		log.Fatalf("Failed to subscribe to order.modified events: %v", err)
+	}
+
+	log.Println("Successfully subscribed to order.modified events")
+
+	err = consumer.SubscribeToReturnReceived(func(ctx context.Context, eventID string, event *messaging.ReturnReceivedEvent) error {
+		metrics.IncEventsProcessed()
+		return orderEventHandler.HandleReturnReceived(ctx, eventID, event)
+	})
+	if err != nil {
+		log.Fatalf("Failed to subscribe to return.received events: %v", err)
+	}
+
+	log.Println("Successfully subscribed to return.received events")
 }
@@ -19,6 +19,9 @@ import (
 
 func main() {
 	cfg := config.LoadConfig()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
 
 	mongoDB, err := database.NewMongoDB(cfg)
 	if err != nil {
@@ -51,6 +54,10 @@ func main() {
 		<-sigCh
 		log.Println("Shutting down gracefully...")
 
+		for _, worker := range services.Workers {
+			worker.Stop()
+		}
+
 		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer shutdownCancel()
 
@@ -66,6 +73,12 @@ func main() {
 			}
 		}
 
+		if services.ProductCatalog != nil {
+			if err := services.ProductCatalog.Close(); err != nil {
+				log.Printf("Error during inventory client disconnect: %v", err)
+			}
+		}
+
 		grpcServer.GracefulStop()
 		cancel()
 	}()
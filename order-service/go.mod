@@ -3,11 +3,13 @@ module order-service
 go 1.23.4
 
 require (
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/google/uuid v1.6.0
 	github.com/nats-io/nats.go v1.33.1
 	github.com/redis/go-redis/v9 v9.5.1
 	go.mongodb.org/mongo-driver v1.14.0
 	google.golang.org/grpc v1.71.1
+	infra v0.0.0-00010101000000-000000000000
 	proto v0.0.0-00010101000000-000000000000
 )
 
@@ -32,4 +34,7 @@ require (
 	google.golang.org/protobuf v1.36.6 // indirect
 )
 
-replace proto => ../proto
+replace (
+	infra => ../infra
+	proto => ../proto
+)
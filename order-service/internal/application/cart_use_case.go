@@ -0,0 +1,379 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/inventory"
+	"order-service/internal/infrastructure/persistence"
+	"proto/money"
+)
+
+type CartUseCase struct {
+	cartRepo     persistence.CartRepository
+	orderUseCase *OrderUseCase
+	catalog      inventory.ProductCatalog
+}
+
+func NewCartUseCase(cartRepo persistence.CartRepository, orderUseCase *OrderUseCase, catalog inventory.ProductCatalog) *CartUseCase {
+	return &CartUseCase{
+		cartRepo:     cartRepo,
+		orderUseCase: orderUseCase,
+		catalog:      catalog,
+	}
+}
+
+func (uc *CartUseCase) GetCart(ctx context.Context, owner domain.CartOwner) (*domain.PricedCart, error) {
+	if err := uc.ready(owner); err != nil {
+		return nil, err
+	}
+
+	cart, err := uc.cartRepo.Get(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.reprice(ctx, cart)
+}
+
+func (uc *CartUseCase) AddItem(ctx context.Context, owner domain.CartOwner, productID string, quantity int) (*domain.PricedCart, error) {
+	if err := uc.ready(owner); err != nil {
+		return nil, err
+	}
+	if productID == "" {
+		return nil, errors.New("product ID is required")
+	}
+	if quantity <= 0 {
+		return nil, errors.New("quantity must be positive")
+	}
+
+	product, err := uc.catalog.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := uc.cartRepo.Update(ctx, owner, func(cart *domain.Cart) error {
+		cart.AddItem(product.ID, quantity, product.Price)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.reprice(ctx, cart)
+}
+
+func (uc *CartUseCase) UpdateItem(ctx context.Context, owner domain.CartOwner, productID string, quantity int) (*domain.PricedCart, error) {
+	if err := uc.ready(owner); err != nil {
+		return nil, err
+	}
+	if quantity < 0 {
+		return nil, errors.New("quantity cannot be negative")
+	}
+
+	cart, err := uc.cartRepo.Update(ctx, owner, func(cart *domain.Cart) error {
+		if !cart.SetQuantity(productID, quantity) {
+			return fmt.Errorf("product %s is not in the cart", productID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.reprice(ctx, cart)
+}
+
+func (uc *CartUseCase) RemoveItem(ctx context.Context, owner domain.CartOwner, productID string) (*domain.PricedCart, error) {
+	if err := uc.ready(owner); err != nil {
+		return nil, err
+	}
+
+	cart, err := uc.cartRepo.Update(ctx, owner, func(cart *domain.Cart) error {
+		cart.RemoveItem(productID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.reprice(ctx, cart)
+}
+
+// MergeCart moves the anonymous session cart into the user's cart on login.
+func (uc *CartUseCase) MergeCart(ctx context.Context, userID, sessionID string) (*domain.PricedCart, error) {
+	if userID == "" || sessionID == "" {
+		return nil, errors.New("user ID and session ID are required")
+	}
+	owner := domain.CartOwner{UserID: userID}
+	if err := uc.ready(owner); err != nil {
+		return nil, err
+	}
+
+	cart, err := uc.cartRepo.Merge(ctx, domain.CartOwner{SessionID: sessionID}, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Merged session cart %s into cart of user %s", sessionID, userID)
+
+	return uc.reprice(ctx, cart)
+}
+
+// Checkout turns the user's cart into an order at current inventory prices.
+// The cart is taken atomically so a double submit cannot create two orders,
+// and it is put back if anything blocks the order, without undoing what the
+// customer added to the cart meanwhile.
+func (uc *CartUseCase) Checkout(ctx context.Context, userID, address string) (*domain.Order, error) {
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+	owner := domain.CartOwner{UserID: userID}
+	if err := uc.ready(owner); err != nil {
+		return nil, err
+	}
+
+	cart, err := uc.cartRepo.Take(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if cart.IsEmpty() {
+		return nil, errors.New("cart is empty")
+	}
+
+	order, err := uc.checkout(ctx, cart, address)
+	if err != nil {
+		_, restoreErr := uc.cartRepo.Update(ctx, owner, func(current *domain.Cart) error {
+			current.Restore(cart)
+			return nil
+		})
+		if restoreErr != nil {
+			log.Printf("Failed to restore cart of user %s after checkout error: %v", userID, restoreErr)
+		}
+		return nil, err
+	}
+
+	log.Printf("Checked out cart of user %s as order %s", userID, order.ID)
+
+	return order, nil
+}
+
+func (uc *CartUseCase) checkout(ctx context.Context, cart *domain.Cart, address string) (*domain.Order, error) {
+	priced := uc.price(ctx, cart)
+	if priced.HasIssues {
+		var blocked []string
+		for _, line := range priced.Lines {
+			if line.OutOfStock || line.Unavailable {
+				blocked = append(blocked, line.ProductID)
+			}
+		}
+		return nil, fmt.Errorf("cart has unavailable items: %s", strings.Join(blocked, ", "))
+	}
+
+	items := make([]domain.OrderItem, len(priced.Lines))
+	for i, line := range priced.Lines {
+		items[i] = domain.OrderItem{
+			ProductID: line.ProductID,
+			Quantity:  line.Quantity,
+			Price:     line.UnitPrice,
+		}
+	}
+
+	return uc.orderUseCase.CreateOrder(ctx, cart.Owner.UserID, items, address)
+}
+
+// reprice prices the cart and remembers the prices the customer has now seen,
+// so a price change is flagged once.
+func (uc *CartUseCase) reprice(ctx context.Context, cart *domain.Cart) (*domain.PricedCart, error) {
+	priced := uc.price(ctx, cart)
+
+	changed := make(map[string]money.Money)
+	for _, line := range priced.Lines {
+		if line.PriceChanged {
+			changed[line.ProductID] = line.UnitPrice
+		}
+	}
+	if len(changed) > 0 {
+		_, err := uc.cartRepo.Update(ctx, cart.Owner, func(stored *domain.Cart) error {
+			for i := range stored.Items {
+				if price, ok := changed[stored.Items[i].ProductID]; ok {
+					stored.Items[i].Price = price
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("Failed to store new cart prices: %v", err)
+		}
+	}
+
+	return priced, nil
+}
+
+func (uc *CartUseCase) price(ctx context.Context, cart *domain.Cart) *domain.PricedCart {
+	currency := money.DefaultCurrency
+	if len(cart.Items) > 0 && cart.Items[0].Price.Currency != "" {
+		currency = cart.Items[0].Price.Currency
+	}
+
+	priced := &domain.PricedCart{
+		Owner:     cart.Owner,
+		Lines:     make([]domain.CartLine, len(cart.Items)),
+		Total:     money.New(0, currency),
+		UpdatedAt: cart.UpdatedAt,
+		ExpiresAt: cart.UpdatedAt.Add(uc.cartRepo.TTL()),
+	}
+
+	for i, item := range cart.Items {
+		line := domain.CartLine{
+			ProductID: item.ProductID,
+			Name:      item.ProductID,
+			Quantity:  item.Quantity,
+			UnitPrice: item.Price,
+		}
+
+		product, err := uc.catalog.GetProduct(ctx, item.ProductID)
+		if err != nil {
+			log.Printf("Failed to price cart item %s: %v", item.ProductID, err)
+			line.Unavailable = true
+		} else {
+			line.Name = product.Name
+			line.UnitPrice = product.Price
+			line.AvailableStock = product.Stock
+			line.OutOfStock = product.Stock < item.Quantity
+			if product.Price != item.Price {
+				line.PriceChanged = true
+				line.PreviousPrice = item.Price
+			}
+		}
+
+		line.LineTotal = line.UnitPrice.Mul(line.Quantity)
+		if !line.Unavailable {
+			total, err := priced.Total.Add(line.LineTotal)
+			if err != nil {
+				log.Printf("Failed to price cart item %s: %v", item.ProductID, err)
+				line.Unavailable = true
+			} else {
+				priced.Total = total
+			}
+		}
+		priced.HasIssues = priced.HasIssues || line.OutOfStock || line.Unavailable
+		priced.Lines[i] = line
+	}
+
+	return priced
+}
+
+func (uc *CartUseCase) ready(owner domain.CartOwner) error {
+	if uc.cartRepo == nil {
+		return errors.New("cart storage is unavailable")
+	}
+	if uc.catalog == nil {
+		return errors.New("product catalog is unavailable")
+	}
+	return owner.Validate()
+}
+
+// Reorder copies a past order at current prices into the user's cart, or into
+// a new pending order. Products that are gone or out of stock are dropped,
+// quantities are capped at the stock left, and every difference is reported.
+func (uc *CartUseCase) Reorder(ctx context.Context, orderID, userID string, createOrder bool) (*domain.ReorderResult, error) {
+	if orderID == "" {
+		return nil, errors.New("order ID is required")
+	}
+	if uc.catalog == nil {
+		return nil, errors.New("product catalog is unavailable")
+	}
+
+	pastOrder, err := uc.orderUseCase.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if pastOrder == nil || (userID != "" && pastOrder.UserID != userID) {
+		return nil, errors.New("order not found")
+	}
+
+	result := &domain.ReorderResult{}
+	var items []domain.OrderItem
+	for _, item := range pastOrder.Items {
+		change := domain.ReorderChange{
+			ProductID:        item.ProductID,
+			Name:             item.ProductID,
+			PreviousQuantity: item.Quantity,
+			PreviousPrice:    item.Price,
+		}
+
+		product, err := uc.catalog.GetProduct(ctx, item.ProductID)
+		if err != nil {
+			log.Printf("Product %s of order %s is unavailable for reorder: %v", item.ProductID, orderID, err)
+			change.Kind = domain.ReorderUnavailable
+			result.Changes = append(result.Changes, change)
+			continue
+		}
+		change.Name = product.Name
+		change.CurrentPrice = product.Price
+
+		if product.Stock <= 0 {
+			change.Kind = domain.ReorderOutOfStock
+			result.Changes = append(result.Changes, change)
+			continue
+		}
+
+		quantity := min(item.Quantity, product.Stock)
+		switch {
+		case quantity < item.Quantity:
+			change.Kind = domain.ReorderQuantityReduced
+		case product.Price != item.Price:
+			change.Kind = domain.ReorderPriceChanged
+		}
+		if change.Kind != "" {
+			change.Quantity = quantity
+			result.Changes = append(result.Changes, change)
+		}
+
+		items = append(items, domain.OrderItem{
+			ProductID: product.ID,
+			Quantity:  quantity,
+			Price:     product.Price,
+		})
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("none of the products in order %s are available", orderID)
+	}
+
+	if createOrder {
+		result.Order, err = uc.orderUseCase.CreateOrder(ctx, pastOrder.UserID, items, pastOrder.Address)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Reordered order %s as order %s", orderID, result.Order.ID)
+		return result, nil
+	}
+
+	owner := domain.CartOwner{UserID: pastOrder.UserID}
+	if err := uc.ready(owner); err != nil {
+		return nil, err
+	}
+	cart, err := uc.cartRepo.Update(ctx, owner, func(cart *domain.Cart) error {
+		for _, item := range items {
+			cart.AddItem(item.ProductID, item.Quantity, item.Price)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Cart, err = uc.reprice(ctx, cart)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Reordered order %s into the cart of user %s", orderID, pastOrder.UserID)
+
+	return result, nil
+}
@@ -0,0 +1,235 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/inventory"
+	"proto/money"
+)
+
+func newCartFixture() (*CartUseCase, *fakeCarts, *fakeCatalog) {
+	catalog := &fakeCatalog{products: map[string]*inventory.ProductInfo{
+		"tea":  {ID: "tea", Name: "Tea", Price: money.KZT(100000), Stock: 10, TaxClass: domain.TaxClassStandard},
+		"milk": {ID: "milk", Name: "Milk", Price: money.KZT(50000), Stock: 3, TaxClass: domain.TaxClassExempt},
+	}}
+	carts := newFakeCarts()
+	return NewCartUseCase(carts, nil, catalog), carts, catalog
+}
+
+func TestMergeCartSumsQuantitiesAndDropsSessionCart(t *testing.T) {
+	ctx := context.Background()
+	uc, carts, _ := newCartFixture()
+	session := domain.CartOwner{SessionID: "session-1"}
+	user := domain.CartOwner{UserID: "user-1"}
+
+	for _, add := range []struct {
+		owner     domain.CartOwner
+		productID string
+		quantity  int
+	}{
+		{user, "tea", 1},
+		{session, "tea", 2},
+		{session, "milk", 1},
+	} {
+		if _, err := uc.AddItem(ctx, add.owner, add.productID, add.quantity); err != nil {
+			t.Fatalf("AddItem %s: %v", add.productID, err)
+		}
+	}
+
+	priced, err := uc.MergeCart(ctx, user.UserID, session.SessionID)
+	if err != nil {
+		t.Fatalf("MergeCart: %v", err)
+	}
+
+	quantities := make(map[string]int)
+	for _, line := range priced.Lines {
+		quantities[line.ProductID] = line.Quantity
+	}
+	if quantities["tea"] != 3 || quantities["milk"] != 1 || len(quantities) != 2 {
+		t.Fatalf("merged quantities = %v, want 3 tea and 1 milk", quantities)
+	}
+	if priced.Total != money.KZT(350000) {
+		t.Fatalf("total = %v, want 3500.00 KZT", priced.Total)
+	}
+	if left, _ := carts.Get(ctx, session); !left.IsEmpty() {
+		t.Fatalf("session cart still holds %d items", len(left.Items))
+	}
+
+	again, err := uc.MergeCart(ctx, user.UserID, session.SessionID)
+	if err != nil {
+		t.Fatalf("MergeCart again: %v", err)
+	}
+	if again.Total != priced.Total {
+		t.Fatalf("merging twice changed the total to %v", again.Total)
+	}
+}
+
+func TestGetCartRepricesAgainstInventory(t *testing.T) {
+	ctx := context.Background()
+	uc, _, catalog := newCartFixture()
+	user := domain.CartOwner{UserID: "user-1"}
+
+	if _, err := uc.AddItem(ctx, user, "tea", 2); err != nil {
+		t.Fatalf("AddItem: %v", err)
+	}
+	if _, err := uc.AddItem(ctx, user, "milk", 2); err != nil {
+		t.Fatalf("AddItem: %v", err)
+	}
+
+	catalog.products["tea"].Price = money.KZT(120000)
+	catalog.products["milk"].Stock = 1
+
+	priced, err := uc.GetCart(ctx, user)
+	if err != nil {
+		t.Fatalf("GetCart: %v", err)
+	}
+	tea, milk := priced.Lines[0], priced.Lines[1]
+	if !tea.PriceChanged || tea.PreviousPrice != money.KZT(100000) || tea.LineTotal != money.KZT(240000) {
+		t.Fatalf("tea line = %+v, want repriced from 1000.00 to 1200.00 KZT", tea)
+	}
+	if !milk.OutOfStock || milk.AvailableStock != 1 || !priced.HasIssues {
+		t.Fatalf("milk line = %+v, want out of stock with 1 left", milk)
+	}
+	if priced.Total != money.KZT(340000) {
+		t.Fatalf("total = %v, want 3400.00 KZT", priced.Total)
+	}
+
+	priced, err = uc.GetCart(ctx, user)
+	if err != nil {
+		t.Fatalf("GetCart again: %v", err)
+	}
+	if priced.Lines[0].PriceChanged {
+		t.Fatal("price change reported twice")
+	}
+
+	delete(catalog.products, "tea")
+	priced, err = uc.GetCart(ctx, user)
+	if err != nil {
+		t.Fatalf("GetCart: %v", err)
+	}
+	if !priced.Lines[0].Unavailable || priced.Total != money.KZT(100000) {
+		t.Fatalf("removed product line = %+v, total %v; want it unavailable and left out of the total", priced.Lines[0], priced.Total)
+	}
+}
+
+// hookCatalog runs beforeLookup once, at the first product lookup.
+type hookCatalog struct {
+	*fakeCatalog
+	beforeLookup func()
+}
+
+func (c *hookCatalog) GetProduct(ctx context.Context, productID string) (*inventory.ProductInfo, error) {
+	if hook := c.beforeLookup; hook != nil {
+		c.beforeLookup = nil
+		hook()
+	}
+	return c.fakeCatalog.GetProduct(ctx, productID)
+}
+
+func TestFailedCheckoutRestoresCartWithoutUndoingNewItems(t *testing.T) {
+	ctx := context.Background()
+	uc, carts, catalog := newCartFixture()
+	user := domain.CartOwner{UserID: "user-1"}
+
+	for _, productID := range []string{"tea", "milk"} {
+		if _, err := uc.AddItem(ctx, user, productID, 2); err != nil {
+			t.Fatalf("AddItem %s: %v", productID, err)
+		}
+	}
+	// Milk sells out, so checkout is blocked. While it runs, the customer
+	// adds tea again in another tab.
+	catalog.products["milk"].Stock = 0
+	uc.catalog = &hookCatalog{fakeCatalog: catalog, beforeLookup: func() {
+		carts.Update(ctx, user, func(cart *domain.Cart) error {
+			cart.AddItem("tea", 1, money.KZT(100000))
+			return nil
+		})
+	}}
+
+	if _, err := uc.Checkout(ctx, user.UserID, "Astana"); err == nil {
+		t.Fatal("Checkout succeeded with milk out of stock")
+	}
+
+	cart, _ := carts.Get(ctx, user)
+	quantities := make(map[string]int)
+	for _, item := range cart.Items {
+		quantities[item.ProductID] = item.Quantity
+	}
+	if quantities["tea"] != 1 || quantities["milk"] != 2 || len(quantities) != 2 {
+		t.Fatalf("cart after failed checkout = %v, want the new 1 tea and the restored 2 milk", quantities)
+	}
+}
+
+func TestReorderReportsOneChangePerLine(t *testing.T) {
+	ctx := context.Background()
+	pastOrder, err := domain.NewOrder("user-1", []domain.OrderItem{
+		{ProductID: "tea", Quantity: 2, Price: money.KZT(100000), TaxClass: domain.TaxClassStandard},
+		{ProductID: "milk", Quantity: 2, Price: money.KZT(50000), TaxClass: domain.TaxClassExempt},
+		{ProductID: "bread", Quantity: 1, Price: money.KZT(30000), TaxClass: domain.TaxClassStandard},
+		{ProductID: "eggs", Quantity: 3, Price: money.KZT(20000), TaxClass: domain.TaxClassStandard},
+		{ProductID: "sugar", Quantity: 1, Price: money.KZT(10000), TaxClass: domain.TaxClassStandard},
+	}, domain.OrderStatusCompleted)
+	if err != nil {
+		t.Fatalf("NewOrder: %v", err)
+	}
+	catalog := &fakeCatalog{products: map[string]*inventory.ProductInfo{
+		"tea":   {ID: "tea", Name: "Tea", Price: money.KZT(120000), Stock: 10},
+		"milk":  {ID: "milk", Name: "Milk", Price: money.KZT(50000), Stock: 1},
+		"bread": {ID: "bread", Name: "Bread", Price: money.KZT(30000), Stock: 0},
+		"eggs":  {ID: "eggs", Name: "Eggs", Price: money.KZT(25000), Stock: 2},
+		"sugar": {ID: "sugar", Name: "Sugar", Price: money.KZT(10000), Stock: 5},
+	}}
+	carts := newFakeCarts()
+	orderUseCase := NewOrderUseCase(newFakeOrders(pastOrder), fakePublisher{}, nil, catalog)
+	uc := NewCartUseCase(carts, orderUseCase, catalog)
+
+	result, err := uc.Reorder(ctx, pastOrder.ID, "user-1", false)
+	if err != nil {
+		t.Fatalf("Reorder: %v", err)
+	}
+
+	want := map[string]domain.ReorderChange{
+		"tea": {Kind: domain.ReorderPriceChanged, PreviousQuantity: 2, Quantity: 2,
+			PreviousPrice: money.KZT(100000), CurrentPrice: money.KZT(120000)},
+		"milk": {Kind: domain.ReorderQuantityReduced, PreviousQuantity: 2, Quantity: 1,
+			PreviousPrice: money.KZT(50000), CurrentPrice: money.KZT(50000)},
+		"bread": {Kind: domain.ReorderOutOfStock, PreviousQuantity: 1,
+			PreviousPrice: money.KZT(30000), CurrentPrice: money.KZT(30000)},
+		"eggs": {Kind: domain.ReorderQuantityReduced, PreviousQuantity: 3, Quantity: 2,
+			PreviousPrice: money.KZT(20000), CurrentPrice: money.KZT(25000)},
+	}
+	if len(result.Changes) != len(want) {
+		t.Fatalf("got %d changes %+v, want one for each of %d changed lines", len(result.Changes), result.Changes, len(want))
+	}
+	for _, change := range result.Changes {
+		expected, ok := want[change.ProductID]
+		if !ok {
+			t.Errorf("unexpected change %+v", change)
+			continue
+		}
+		expected.ProductID, expected.Name = change.ProductID, change.Name
+		if change != expected {
+			t.Errorf("change of %s = %+v, want %+v", change.ProductID, change, expected)
+		}
+		delete(want, change.ProductID)
+	}
+
+	quantities := make(map[string]int)
+	for _, line := range result.Cart.Lines {
+		quantities[line.ProductID] = line.Quantity
+	}
+	wantQuantities := map[string]int{"tea": 2, "milk": 1, "eggs": 2, "sugar": 1}
+	if len(quantities) != len(wantQuantities) {
+		t.Fatalf("cart = %v, want %v", quantities, wantQuantities)
+	}
+	for productID, quantity := range wantQuantities {
+		if quantities[productID] != quantity {
+			t.Fatalf("cart = %v, want %v", quantities, wantQuantities)
+		}
+	}
+	if result.Cart.Total != money.KZT(2*120000+50000+2*25000+10000) {
+		t.Fatalf("cart total = %v, want the current prices", result.Cart.Total)
+	}
+}
@@ -0,0 +1,108 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"order-service/internal/domain"
+)
+
+const (
+	expiryTTL   = 30 * time.Minute
+	expiryLease = time.Minute
+)
+
+func newStaleOrder(t *testing.T, status domain.OrderStatus, age time.Duration) *domain.Order {
+	t.Helper()
+	order := newPendingOrder(t)
+	order.Status = status
+	order.CreatedAt = time.Now().Add(-age)
+	return order
+}
+
+func TestExpirePendingOrdersCancelsOnlyStaleUnpaidOrders(t *testing.T) {
+	ctx := context.Background()
+	stale := newStaleOrder(t, domain.OrderStatusPending, time.Hour)
+	fresh := newStaleOrder(t, domain.OrderStatusPending, time.Minute)
+	paid := newStaleOrder(t, domain.OrderStatusPaid, time.Hour)
+	orders := newFakeOrders(stale, fresh, paid)
+	publisher := &recordingPublisher{}
+	uc := NewOrderUseCase(orders, publisher, nil, nil)
+
+	now := time.Now()
+	expired, err := uc.ExpirePendingOrders(ctx, now, expiryTTL, expiryLease, noOpenPayments)
+	if err != nil || expired != 1 {
+		t.Fatalf("ExpirePendingOrders = %d, %v; want 1 order expired", expired, err)
+	}
+
+	if got := orders.get(stale.ID); got.Status != domain.OrderStatusCancelled || got.ExpiredAt.IsZero() {
+		t.Fatalf("stale order is %s, want cancelled with an expiry time", got.Status)
+	}
+	if got := orders.get(fresh.ID).Status; got != domain.OrderStatusPending {
+		t.Fatalf("fresh order is %s, want pending", got)
+	}
+	if got := orders.get(paid.ID).Status; got != domain.OrderStatusPaid {
+		t.Fatalf("paid order is %s, want paid", got)
+	}
+	if len(publisher.expired) != 1 || publisher.expired[0].OrderID != stale.ID || len(publisher.expired[0].Items) != 2 {
+		t.Fatalf("published %+v, want order.expired with the stale order's items", publisher.expired)
+	}
+
+	if expired, _ := uc.ExpirePendingOrders(ctx, now.Add(2*expiryLease), expiryTTL, expiryLease, noOpenPayments); expired != 0 {
+		t.Fatalf("second run expired %d orders, want none", expired)
+	}
+	if len(publisher.expired) != 1 {
+		t.Fatalf("order.expired published %d times, want once", len(publisher.expired))
+	}
+}
+
+func TestExpiredOrderEventIsRetriedAfterTheLease(t *testing.T) {
+	ctx := context.Background()
+	stale := newStaleOrder(t, domain.OrderStatusPending, time.Hour)
+	orders := newFakeOrders(stale)
+	publisher := &recordingPublisher{failures: 1}
+	uc := NewOrderUseCase(orders, publisher, nil, nil)
+
+	now := time.Now()
+	uc.ExpirePendingOrders(ctx, now, expiryTTL, expiryLease, noOpenPayments)
+	if got := orders.get(stale.ID).Status; got != domain.OrderStatusCancelled {
+		t.Fatalf("order is %s, want cancelled", got)
+	}
+	if len(publisher.expired) != 0 {
+		t.Fatal("order.expired published while NATS was unavailable")
+	}
+
+	uc.ExpirePendingOrders(ctx, now.Add(expiryLease/2), expiryTTL, expiryLease, noOpenPayments)
+	if len(publisher.expired) != 0 {
+		t.Fatal("order.expired retried before the lease ran out")
+	}
+
+	uc.ExpirePendingOrders(ctx, now.Add(2*expiryLease), expiryTTL, expiryLease, noOpenPayments)
+	if len(publisher.expired) != 1 {
+		t.Fatalf("order.expired published %d times after the lease, want once", len(publisher.expired))
+	}
+}
+
+func TestOrderBeingPaidDoesNotExpire(t *testing.T) {
+	ctx := context.Background()
+	stale := newStaleOrder(t, domain.OrderStatusPending, time.Hour)
+	orders := newFakeOrders(stale)
+	publisher := &recordingPublisher{}
+	uc := NewOrderUseCase(orders, publisher, nil, nil)
+	paying := paymentCancellerFunc(func(context.Context, string) error {
+		return errors.New("payment was completed in the meantime")
+	})
+
+	expired, err := uc.ExpirePendingOrders(ctx, time.Now(), expiryTTL, expiryLease, paying)
+	if err != nil || expired != 0 {
+		t.Fatalf("ExpirePendingOrders = %d, %v; want nothing expired", expired, err)
+	}
+	if got := orders.get(stale.ID).Status; got != domain.OrderStatusPending {
+		t.Fatalf("order is %s, want pending", got)
+	}
+	if len(publisher.expired) != 0 {
+		t.Fatal("order.expired published for an order being paid")
+	}
+}
@@ -0,0 +1,595 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/inventory"
+	"order-service/internal/infrastructure/messaging"
+	"order-service/internal/infrastructure/persistence"
+	"proto/money"
+)
+
+type fakeCatalog struct {
+	products map[string]*inventory.ProductInfo
+	err      error
+}
+
+func (c *fakeCatalog) GetProduct(_ context.Context, productID string) (*inventory.ProductInfo, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	product, ok := c.products[productID]
+	if !ok {
+		return nil, fmt.Errorf("product not found: %s", productID)
+	}
+	return product, nil
+}
+
+func (c *fakeCatalog) Close() error { return nil }
+
+// fakeOrders keeps orders in memory. It hands out copies, so a use case only
+// sees what it stored through the repository.
+type fakeOrders struct {
+	persistence.OrderRepository
+	mu     sync.Mutex
+	orders map[string]*domain.Order
+	// unpublished and expiryLeases track ClaimExpired like the order
+	// document's expiry fields, createdUnpublished and createdLeases track
+	// ClaimUnpublishedCreated.
+	unpublished        map[string]bool
+	expiryLeases       map[string]time.Time
+	createdUnpublished map[string]bool
+	createdLeases      map[string]time.Time
+}
+
+func newFakeOrders(orders ...*domain.Order) *fakeOrders {
+	r := &fakeOrders{
+		orders:             make(map[string]*domain.Order),
+		unpublished:        make(map[string]bool),
+		expiryLeases:       make(map[string]time.Time),
+		createdUnpublished: make(map[string]bool),
+		createdLeases:      make(map[string]time.Time),
+	}
+	for _, order := range orders {
+		r.orders[order.ID] = cloneOrder(order)
+	}
+	return r
+}
+
+func cloneOrder(order *domain.Order) *domain.Order {
+	clone := *order
+	clone.Items = append([]domain.OrderItem(nil), order.Items...)
+	clone.Refunds = append([]domain.Refund(nil), order.Refunds...)
+	clone.Receipts = append([]domain.OrderReceipt(nil), order.Receipts...)
+	clone.Substitutions = append([]domain.Substitution(nil), order.Substitutions...)
+	return &clone
+}
+
+func (r *fakeOrders) get(id string) *domain.Order {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if order, ok := r.orders[id]; ok {
+		return cloneOrder(order)
+	}
+	return nil
+}
+
+func (r *fakeOrders) Create(ctx context.Context, order *domain.Order) (*domain.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if order.IdempotencyKey != "" {
+		for _, stored := range r.orders {
+			if stored.IdempotencyKey == order.IdempotencyKey {
+				return cloneOrder(stored), nil
+			}
+		}
+	}
+	r.orders[order.ID] = cloneOrder(order)
+	r.createdUnpublished[order.ID] = true
+	return order, nil
+}
+
+func (r *fakeOrders) GetByIdempotencyKey(ctx context.Context, key string) (*domain.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, order := range r.orders {
+		if order.IdempotencyKey == key {
+			return cloneOrder(order), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeOrders) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.orders)
+}
+
+func (r *fakeOrders) GetByID(ctx context.Context, id string) (*domain.Order, error) {
+	return r.get(id), nil
+}
+
+func (r *fakeOrders) UpdateStatus(ctx context.Context, order *domain.Order, from domain.OrderStatus) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.orders[order.ID]
+	if !ok || stored.Status != from {
+		return false, nil
+	}
+	stored.Status = order.Status
+	stored.UpdatedAt = order.UpdatedAt
+	return true, nil
+}
+
+func (r *fakeOrders) AddRefund(ctx context.Context, orderID string, refund domain.Refund, lastUpdatedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.orders[orderID]
+	if !ok || !stored.UpdatedAt.Equal(lastUpdatedAt) {
+		return false, nil
+	}
+	if stored.Status != domain.OrderStatusPaid && stored.Status != domain.OrderStatusCompleted {
+		return false, nil
+	}
+	if stored.FindRefund(refund.ID) != nil {
+		return false, nil
+	}
+	stored.Refunds = append(stored.Refunds, refund)
+	stored.UpdatedAt = time.Now()
+	return true, nil
+}
+
+func (r *fakeOrders) CompleteRefund(ctx context.Context, orderID, refundID, providerRefundID string, fullyRefunded bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.orders[orderID]
+	if !ok || stored.FindRefund(refundID) == nil {
+		return errors.New("refund not found")
+	}
+	stored.CompleteRefund(refundID, providerRefundID)
+	if fullyRefunded {
+		stored.Status = domain.OrderStatusRefunded
+	}
+	return nil
+}
+
+func (r *fakeOrders) RemoveRefund(ctx context.Context, orderID, refundID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stored, ok := r.orders[orderID]; ok {
+		stored.RemoveRefund(refundID)
+	}
+	return nil
+}
+
+func (r *fakeOrders) AddReceipt(ctx context.Context, orderID string, receipt domain.OrderReceipt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.orders[orderID]
+	if !ok {
+		return errors.New("order not found")
+	}
+	stored.Receipts = append(stored.Receipts, receipt)
+	return nil
+}
+
+func (r *fakeOrders) ClaimExpired(ctx context.Context, createdBefore, now, leaseUntil time.Time) (*domain.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, order := range r.orders {
+		stale := order.Status == domain.OrderStatusPending && order.CreatedAt.Before(createdBefore)
+		if (!stale && !r.unpublished[id]) || r.expiryLeases[id].After(now) {
+			continue
+		}
+		r.expiryLeases[id] = leaseUntil
+		return cloneOrder(order), nil
+	}
+	return nil, nil
+}
+
+func (r *fakeOrders) Expire(ctx context.Context, orderID string, expiredAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.orders[orderID]
+	if !ok || stored.Status != domain.OrderStatusPending {
+		return false, nil
+	}
+	stored.Status = domain.OrderStatusCancelled
+	stored.ExpiredAt = expiredAt
+	r.unpublished[orderID] = true
+	return true, nil
+}
+
+func (r *fakeOrders) ReleaseExpiry(ctx context.Context, orderID string, published bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.expiryLeases, orderID)
+	if published {
+		delete(r.unpublished, orderID)
+	}
+	return nil
+}
+
+func (r *fakeOrders) ClaimUnpublishedCreated(ctx context.Context, createdBefore, now, leaseUntil time.Time) (*domain.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, order := range r.orders {
+		if !r.createdUnpublished[id] || !order.CreatedAt.Before(createdBefore) || r.createdLeases[id].After(now) {
+			continue
+		}
+		r.createdLeases[id] = leaseUntil
+		return cloneOrder(order), nil
+	}
+	return nil, nil
+}
+
+func (r *fakeOrders) ReleaseCreated(ctx context.Context, orderID string, published bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.createdLeases, orderID)
+	if published {
+		delete(r.createdUnpublished, orderID)
+	}
+	return nil
+}
+
+func (r *fakeOrders) UpdateItems(ctx context.Context, order *domain.Order) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.orders[order.ID]
+	if !ok || !stored.Status.IsModifiable() {
+		return false, nil
+	}
+	updated := cloneOrder(order)
+	updated.Status = stored.Status
+	r.orders[order.ID] = updated
+	return true, nil
+}
+
+func (r *fakeOrders) UpdateLines(ctx context.Context, order *domain.Order, lastUpdatedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.orders[order.ID]
+	if !ok || !stored.UpdatedAt.Equal(lastUpdatedAt) {
+		return false, nil
+	}
+	updated := cloneOrder(stored)
+	updated.Items = append([]domain.OrderItem(nil), order.Items...)
+	updated.NetTotal, updated.TaxTotal, updated.Total = order.NetTotal, order.TaxTotal, order.Total
+	updated.TaxBreakdown = order.TaxBreakdown
+	updated.Substitutions = append([]domain.Substitution(nil), order.Substitutions...)
+	updated.UpdatedAt = time.Now()
+	r.orders[order.ID] = updated
+	return true, nil
+}
+
+func (r *fakeOrders) ListWithExpiredSubstitutions(ctx context.Context, now time.Time) ([]*domain.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var orders []*domain.Order
+	for _, order := range r.orders {
+		for _, s := range order.Substitutions {
+			if s.Status == domain.SubstitutionProposed && !now.Before(s.ExpiresAt) {
+				orders = append(orders, cloneOrder(order))
+				break
+			}
+		}
+	}
+	return orders, nil
+}
+
+func (r *fakeOrders) ListWithUnsettledSubstitutions(ctx context.Context) ([]*domain.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var orders []*domain.Order
+	for _, order := range r.orders {
+		for _, s := range order.Substitutions {
+			if s.Unsettled() {
+				orders = append(orders, cloneOrder(order))
+				break
+			}
+		}
+	}
+	return orders, nil
+}
+
+func (r *fakeOrders) SettleSubstitution(ctx context.Context, orderID, substitutionID string, refunded, published bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.orders[orderID]
+	if !ok {
+		return nil
+	}
+	for i := range stored.Substitutions {
+		if s := &stored.Substitutions[i]; s.ID == substitutionID {
+			s.RefundOwed = s.RefundOwed && !refunded
+			s.Unpublished = s.Unpublished && !published
+		}
+	}
+	return nil
+}
+
+// fakePayments keeps payment intents in memory.
+type fakePayments struct {
+	mu      sync.Mutex
+	intents map[string]*domain.PaymentIntent
+}
+
+func newFakePayments() *fakePayments {
+	return &fakePayments{intents: make(map[string]*domain.PaymentIntent)}
+}
+
+func (r *fakePayments) Create(ctx context.Context, intent *domain.PaymentIntent) (*domain.PaymentIntent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *intent
+	r.intents[intent.ID] = &stored
+	return intent, nil
+}
+
+func (r *fakePayments) GetByID(ctx context.Context, id string) (*domain.PaymentIntent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if intent, ok := r.intents[id]; ok {
+		clone := *intent
+		return &clone, nil
+	}
+	return nil, nil
+}
+
+func (r *fakePayments) GetByProviderPaymentID(ctx context.Context, provider, providerPaymentID string) (*domain.PaymentIntent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, intent := range r.intents {
+		if intent.Provider == provider && intent.ProviderPaymentID == providerPaymentID {
+			clone := *intent
+			return &clone, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakePayments) Update(ctx context.Context, intent *domain.PaymentIntent) (*domain.PaymentIntent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *intent
+	r.intents[intent.ID] = &stored
+	return intent, nil
+}
+
+func (r *fakePayments) UpdateStatus(ctx context.Context, intent *domain.PaymentIntent, from domain.PaymentStatus) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.intents[intent.ID]
+	if !ok || stored.Status != from {
+		return false, nil
+	}
+	stored.Status = intent.Status
+	stored.UpdatedAt = intent.UpdatedAt
+	return true, nil
+}
+
+func (r *fakePayments) ListByOrderID(ctx context.Context, orderID string) ([]*domain.PaymentIntent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var intents []*domain.PaymentIntent
+	for _, intent := range r.intents {
+		if intent.OrderID == orderID {
+			clone := *intent
+			intents = append(intents, &clone)
+		}
+	}
+	return intents, nil
+}
+
+// fakePublisher accepts every event. The use cases publish most events from
+// their own goroutines, so tests do not rely on them.
+type fakePublisher struct {
+	messaging.EventPublisher
+}
+
+func (fakePublisher) PublishOrderCreated(messaging.OrderCreatedEvent) error             { return nil }
+func (fakePublisher) PublishOrderRefunded(messaging.OrderRefundedEvent) error           { return nil }
+func (fakePublisher) PublishOrderExpired(messaging.OrderExpiredEvent) error             { return nil }
+func (fakePublisher) PublishOrderModified(messaging.OrderModifiedEvent) error           { return nil }
+func (fakePublisher) PublishOrderStatusChanged(messaging.OrderStatusChangedEvent) error { return nil }
+func (fakePublisher) PublishScheduledOrderCreated(messaging.ScheduledOrderCreatedEvent) error {
+	return nil
+}
+
+// recordingPublisher keeps the order.expired, order.created and
+// substitution.proposed events, which are published synchronously. It fails
+// the first failures order.expired, the first createdFailures order.created
+// and the first proposedFailures substitution.proposed events.
+type recordingPublisher struct {
+	fakePublisher
+	mu               sync.Mutex
+	failures         int
+	createdFailures  int
+	proposedFailures int
+	expired          []messaging.OrderExpiredEvent
+	created          []messaging.OrderCreatedEvent
+	proposed         []messaging.SubstitutionProposedEvent
+}
+
+func (p *recordingPublisher) PublishSubstitutionProposed(event messaging.SubstitutionProposedEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.proposedFailures > 0 {
+		p.proposedFailures--
+		return errors.New("NATS unavailable")
+	}
+	p.proposed = append(p.proposed, event)
+	return nil
+}
+
+func (p *recordingPublisher) PublishOrderCreated(event messaging.OrderCreatedEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.createdFailures > 0 {
+		p.createdFailures--
+		return errors.New("NATS unavailable")
+	}
+	p.created = append(p.created, event)
+	return nil
+}
+
+func (p *recordingPublisher) PublishOrderExpired(event messaging.OrderExpiredEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("NATS unavailable")
+	}
+	p.expired = append(p.expired, event)
+	return nil
+}
+
+// paymentCancellerFunc adapts a function to PaymentCanceller.
+type paymentCancellerFunc func(ctx context.Context, orderID string) error
+
+func (f paymentCancellerFunc) CancelOpenPayments(ctx context.Context, orderID string) error {
+	return f(ctx, orderID)
+}
+
+var noOpenPayments = paymentCancellerFunc(func(context.Context, string) error { return nil })
+
+// newPendingOrder returns a pending order of two standard-rated lines,
+// 1 000.00 and 2 × 500.00 KZT.
+func newPendingOrder(t *testing.T) *domain.Order {
+	t.Helper()
+	order, err := domain.NewOrder("user-1", []domain.OrderItem{
+		{ProductID: "tea", Quantity: 1, Price: money.KZT(100000), TaxClass: domain.TaxClassStandard},
+		{ProductID: "milk", Quantity: 2, Price: money.KZT(50000), TaxClass: domain.TaxClassStandard},
+	}, domain.OrderStatusPending)
+	if err != nil {
+		t.Fatalf("NewOrder: %v", err)
+	}
+	return order
+}
+
+// fakePendingReceipts keeps pending receipts in memory.
+type fakePendingReceipts struct {
+	mu       sync.Mutex
+	receipts map[string]*domain.PendingReceipt
+	leases   map[string]time.Time
+}
+
+func newFakePendingReceipts() *fakePendingReceipts {
+	return &fakePendingReceipts{
+		receipts: make(map[string]*domain.PendingReceipt),
+		leases:   make(map[string]time.Time),
+	}
+}
+
+func (r *fakePendingReceipts) Create(ctx context.Context, receipt *domain.PendingReceipt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *receipt
+	r.receipts[receipt.ID] = &stored
+	return nil
+}
+
+func (r *fakePendingReceipts) ClaimDue(ctx context.Context, now, leaseUntil time.Time) (*domain.PendingReceipt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, receipt := range r.receipts {
+		if receipt.NextAttemptAt.After(now) || r.leases[id].After(now) {
+			continue
+		}
+		r.leases[id] = leaseUntil
+		claimed := *receipt
+		return &claimed, nil
+	}
+	return nil, nil
+}
+
+func (r *fakePendingReceipts) RecordFailure(ctx context.Context, receipt *domain.PendingReceipt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *receipt
+	r.receipts[receipt.ID] = &stored
+	delete(r.leases, receipt.ID)
+	return nil
+}
+
+func (r *fakePendingReceipts) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.receipts, id)
+	delete(r.leases, id)
+	return nil
+}
+
+func (r *fakePendingReceipts) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.receipts)
+}
+
+// fakeCarts keeps carts in memory, by owner.
+type fakeCarts struct {
+	mu    sync.Mutex
+	carts map[domain.CartOwner]*domain.Cart
+}
+
+func newFakeCarts() *fakeCarts {
+	return &fakeCarts{carts: make(map[domain.CartOwner]*domain.Cart)}
+}
+
+func cloneCart(cart *domain.Cart) *domain.Cart {
+	clone := *cart
+	clone.Items = append([]domain.CartItem(nil), cart.Items...)
+	return &clone
+}
+
+func (r *fakeCarts) load(owner domain.CartOwner) *domain.Cart {
+	if cart, ok := r.carts[owner]; ok {
+		return cloneCart(cart)
+	}
+	return domain.NewCart(owner)
+}
+
+func (r *fakeCarts) Get(ctx context.Context, owner domain.CartOwner) (*domain.Cart, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.load(owner), nil
+}
+
+func (r *fakeCarts) Update(ctx context.Context, owner domain.CartOwner, fn func(cart *domain.Cart) error) (*domain.Cart, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cart := r.load(owner)
+	if err := fn(cart); err != nil {
+		return nil, err
+	}
+	r.carts[owner] = cloneCart(cart)
+	return cart, nil
+}
+
+func (r *fakeCarts) Merge(ctx context.Context, from, into domain.CartOwner) (*domain.Cart, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	target := r.load(into)
+	target.Merge(r.load(from))
+	r.carts[into] = cloneCart(target)
+	delete(r.carts, from)
+	return target, nil
+}
+
+func (r *fakeCarts) Take(ctx context.Context, owner domain.CartOwner) (*domain.Cart, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cart := r.load(owner)
+	delete(r.carts, owner)
+	return cart, nil
+}
+
+func (r *fakeCarts) TTL() time.Duration { return 24 * time.Hour }
@@ -0,0 +1,52 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"order-service/internal/infrastructure/inventory"
+	"order-service/internal/infrastructure/invoice"
+)
+
+type InvoiceUseCase struct {
+	orderUseCase *OrderUseCase
+	catalog      inventory.ProductCatalog
+	seller       invoice.Company
+}
+
+func NewInvoiceUseCase(orderUseCase *OrderUseCase, catalog inventory.ProductCatalog, seller invoice.Company) *InvoiceUseCase {
+	return &InvoiceUseCase{
+		orderUseCase: orderUseCase,
+		catalog:      catalog,
+		seller:       seller,
+	}
+}
+
+func (uc *InvoiceUseCase) GetInvoice(ctx context.Context, orderID, format string, locale invoice.Locale, buyer invoice.Buyer) (*invoice.File, error) {
+	if orderID == "" {
+		return nil, errors.New("order ID is required")
+	}
+
+	order, err := uc.orderUseCase.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, nil
+	}
+
+	names := make(map[string]string)
+	if uc.catalog != nil {
+		for _, item := range order.Items {
+			product, err := uc.catalog.GetProduct(ctx, item.ProductID)
+			if err != nil {
+				log.Printf("Failed to resolve name of product %s for invoice: %v", item.ProductID, err)
+				continue
+			}
+			names[item.ProductID] = product.Name
+		}
+	}
+
+	return invoice.Render(invoice.NewDocument(order, names, uc.seller, buyer, locale), format)
+}
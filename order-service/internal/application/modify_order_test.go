@@ -0,0 +1,96 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/inventory"
+	"proto/money"
+)
+
+func newModifyFixture(t *testing.T, status domain.OrderStatus) (*OrderUseCase, *fakeOrders, *fakeCatalog, *domain.Order) {
+	t.Helper()
+	order := newPendingOrder(t)
+	order.Status = status
+	orders := newFakeOrders(order)
+	catalog := &fakeCatalog{products: map[string]*inventory.ProductInfo{
+		"tea":   {ID: "tea", Price: money.KZT(100000), Stock: 5, TaxClass: domain.TaxClassStandard},
+		"milk":  {ID: "milk", Price: money.KZT(50000), Stock: 5, TaxClass: domain.TaxClassStandard},
+		"bread": {ID: "bread", Price: money.KZT(30000), Stock: 1, TaxClass: domain.TaxClassExempt},
+	}}
+	return NewOrderUseCase(orders, fakePublisher{}, nil, catalog), orders, catalog, order
+}
+
+func TestModifyOrderReturnsStockDeltas(t *testing.T) {
+	ctx := context.Background()
+	uc, orders, _, order := newModifyFixture(t, domain.OrderStatusPending)
+	cancelled := 0
+	payments := paymentCancellerFunc(func(context.Context, string) error {
+		cancelled++
+		return nil
+	})
+
+	modified, deltas, err := uc.ModifyOrder(ctx, order.ID, []domain.OrderLineChange{
+		{ProductID: "tea", Quantity: 3},
+		{ProductID: "milk", Quantity: 0},
+		{ProductID: "bread", Quantity: 1},
+	}, payments)
+	if err != nil {
+		t.Fatalf("ModifyOrder: %v", err)
+	}
+
+	want := []domain.QuantityDelta{{ProductID: "tea", Delta: 2}, {ProductID: "milk", Delta: -2}, {ProductID: "bread", Delta: 1}}
+	if !reflect.DeepEqual(deltas, want) {
+		t.Fatalf("deltas = %v, want %v", deltas, want)
+	}
+	if modified.Total != money.KZT(330000) {
+		t.Fatalf("total = %v, want 3300.00 KZT", modified.Total)
+	}
+	if bread := modified.Items[len(modified.Items)-1]; bread.Price != money.KZT(30000) || bread.TaxRate != 0 {
+		t.Fatalf("added line = %v at %d, want the catalog price and exempt rate", bread.Price, bread.TaxRate)
+	}
+	if stored := orders.get(order.ID); stored.Total != modified.Total || len(stored.Items) != 2 {
+		t.Fatalf("stored order has %d lines totalling %v", len(stored.Items), stored.Total)
+	}
+	if cancelled != 1 {
+		t.Fatalf("open payments cancelled %d times, want once", cancelled)
+	}
+
+	_, deltas, err = uc.ModifyOrder(ctx, order.ID, []domain.OrderLineChange{{ProductID: "tea", Quantity: 3}}, payments)
+	if err != nil {
+		t.Fatalf("ModifyOrder without changes: %v", err)
+	}
+	if len(deltas) != 0 || cancelled != 1 {
+		t.Fatalf("unchanged order gave deltas %v and cancelled payments %d times", deltas, cancelled)
+	}
+}
+
+func TestModifyOrderRefusesWhatItCannotApply(t *testing.T) {
+	ctx := context.Background()
+
+	uc, orders, _, order := newModifyFixture(t, domain.OrderStatusPending)
+	if _, _, err := uc.ModifyOrder(ctx, order.ID, []domain.OrderLineChange{{ProductID: "bread", Quantity: 2}}, noOpenPayments); err == nil {
+		t.Fatal("added more bread than is in stock")
+	}
+	if _, _, err := uc.ModifyOrder(ctx, order.ID, []domain.OrderLineChange{{ProductID: "tea", Quantity: 0}, {ProductID: "milk", Quantity: 0}}, noOpenPayments); err == nil {
+		t.Fatal("removed every line")
+	}
+
+	paying := paymentCancellerFunc(func(context.Context, string) error {
+		return errors.New("payment was completed in the meantime")
+	})
+	if _, _, err := uc.ModifyOrder(ctx, order.ID, []domain.OrderLineChange{{ProductID: "tea", Quantity: 2}}, paying); err == nil {
+		t.Fatal("modified an order while it is being paid")
+	}
+	if stored := orders.get(order.ID); stored.Total != order.Total {
+		t.Fatalf("refused modifications changed the total to %v", stored.Total)
+	}
+
+	uc, _, _, order = newModifyFixture(t, domain.OrderStatusPaid)
+	if _, _, err := uc.ModifyOrder(ctx, order.ID, []domain.OrderLineChange{{ProductID: "tea", Quantity: 2}}, noOpenPayments); err == nil {
+		t.Fatal("modified a paid order")
+	}
+}
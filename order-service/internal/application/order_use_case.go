@@ -2,58 +2,130 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"order-service/internal/domain"
 	"order-service/internal/infrastructure/database"
+	"order-service/internal/infrastructure/inventory"
 	"order-service/internal/infrastructure/messaging"
 	"order-service/internal/infrastructure/persistence"
+	"proto/money"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// PaymentCanceller voids the payments of an order that were not completed.
+type PaymentCanceller interface {
+	CancelOpenPayments(ctx context.Context, orderID string) error
+}
+
 type OrderUseCase struct {
 	orderRepo      persistence.OrderRepository
 	eventPublisher messaging.EventPublisher
 	cache          *database.RedisCache
+	catalog        inventory.ProductCatalog
 }
 
-func NewOrderUseCase(orderRepo persistence.OrderRepository, eventPublisher messaging.EventPublisher, cache *database.RedisCache) *OrderUseCase {
+func NewOrderUseCase(orderRepo persistence.OrderRepository, eventPublisher messaging.EventPublisher, cache *database.RedisCache, catalog inventory.ProductCatalog) *OrderUseCase {
 	return &OrderUseCase{
 		orderRepo:      orderRepo,
 		eventPublisher: eventPublisher,
 		cache:          cache,
+		catalog:        catalog,
 	}
 }
 
-func (uc *OrderUseCase) CreateOrder(ctx context.Context, userID string, items []domain.OrderItem) (*domain.Order, error) {
+func (uc *OrderUseCase) CreateOrder(ctx context.Context, userID string, items []domain.OrderItem, address string) (*domain.Order, error) {
+	return uc.createOrder(ctx, "", userID, items, address)
+}
 
-	order := domain.NewOrder(userID, items, domain.OrderStatusPending)
+// CreateOrderOnce places an order under an idempotency key. If an earlier
+// attempt already placed an order with the key, that order is returned and
+// nothing new is placed.
+func (uc *OrderUseCase) CreateOrderOnce(ctx context.Context, key, userID string, items []domain.OrderItem, address string) (*domain.Order, error) {
+	existing, err := uc.orderRepo.GetByIdempotencyKey(ctx, key)
+	if err != nil || existing != nil {
+		return existing, err
+	}
 
-	savedOrder, err := uc.orderRepo.Create(ctx, order)
+	return uc.createOrder(ctx, key, userID, items, address)
+}
+
+func (uc *OrderUseCase) createOrder(ctx context.Context, key, userID string, items []domain.OrderItem, address string) (*domain.Order, error) {
+	if err := uc.resolveFromCatalog(ctx, items); err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if !item.Price.SameCurrency(items[0].Price) {
+			return nil, errors.New("all order items must be priced in the same currency")
+		}
+	}
+
+	order, err := domain.NewOrder(userID, items, domain.OrderStatusPending)
 	if err != nil {
 		return nil, err
 	}
+	order.Address = address
+	order.IdempotencyKey = key
 
-	if uc.cache != nil {
-		cacheKey := fmt.Sprintf("user_orders:%s", userID)
-		if err := uc.cache.Delete(ctx, cacheKey); err != nil {
-			log.Printf("Failed to invalidate cache for user %s: %v", userID, err)
-		} else {
-			log.Printf("Cache invalidated for user %s", userID)
-		}
+	savedOrder, err := uc.orderRepo.Create(ctx, order)
+	if err != nil {
+		return nil, err
 	}
+	if savedOrder.ID != order.ID {
+		// A concurrent attempt placed the order first.
+		return savedOrder, nil
+	}
+
+	uc.invalidateUserOrders(ctx, userID)
 
-	go uc.publishOrderCreatedEvent(savedOrder)
+	// The order is placed either way: a failed order.created event is
+	// published again by RepublishCreatedEvents, so the customer does not
+	// retry and place it twice.
+	if err := uc.publishOrderCreatedEvent(savedOrder); err != nil {
+		log.Printf("Failed to publish order.created event for order %s, will retry: %v", savedOrder.ID, err)
+	} else if err := uc.orderRepo.ReleaseCreated(ctx, savedOrder.ID, true); err != nil {
+		log.Printf("Failed to mark order.created event of order %s published: %v", savedOrder.ID, err)
+	}
 
 	return savedOrder, nil
 }
 
+// resolveFromCatalog takes the price and the effective VAT class of every
+// item from the inventory catalog. A price the caller already set must match
+// the catalog, so an order is never placed at a price the customer did not
+// see or one they made up. Without a catalog, or while it is unreachable, it
+// fails: a guessed price or class would put the wrong amounts into the order
+// and its receipts.
+func (uc *OrderUseCase) resolveFromCatalog(ctx context.Context, items []domain.OrderItem) error {
+	if uc.catalog == nil {
+		return fmt.Errorf("%w: prices and tax classes cannot be resolved", inventory.ErrCatalogUnavailable)
+	}
+
+	for i := range items {
+		product, err := uc.catalog.GetProduct(ctx, items[i].ProductID)
+		if err != nil {
+			return fmt.Errorf("failed to resolve product %s: %w", items[i].ProductID, err)
+		}
+		if !items[i].Price.IsZero() && items[i].Price != product.Price {
+			return fmt.Errorf("price of product %s is %s, not %s; please review the order", items[i].ProductID, product.Price, items[i].Price)
+		}
+		items[i].Price = product.Price
+		items[i].TaxClass = product.TaxClass
+	}
+
+	return nil
+}
+
 func (uc *OrderUseCase) GetOrderByID(ctx context.Context, id string) (*domain.Order, error) {
 	return uc.orderRepo.GetByID(ctx, id)
 }
 
+// UpdateOrderStatus moves an order along its lifecycle. Transitions the
+// lifecycle does not allow fail with domain.ErrStatusTransition, and the
+// status is only stored if nobody changed it since it was read.
 func (uc *OrderUseCase) UpdateOrderStatus(ctx context.Context, id string, status domain.OrderStatus) (*domain.Order, error) {
 	order, err := uc.orderRepo.GetByID(ctx, id)
 	if err != nil {
@@ -64,22 +136,288 @@ func (uc *OrderUseCase) UpdateOrderStatus(ctx context.Context, id string, status
 		return nil, nil
 	}
 
+	previous := order.Status
+	if !previous.CanBecome(status) {
+		return nil, fmt.Errorf("%w: order %s is %s and cannot become %s", domain.ErrStatusTransition, order.ID, previous, status)
+	}
+	if previous == status {
+		return order, nil
+	}
+
 	order.UpdateStatus(status)
-	updatedOrder, err := uc.orderRepo.Update(ctx, order)
+	ok, err := uc.orderRepo.UpdateStatus(ctx, order, previous)
 	if err != nil {
 		return nil, err
 	}
+	if !ok {
+		return nil, fmt.Errorf("order %s was changed concurrently, please retry", order.ID)
+	}
 
-	if uc.cache != nil {
-		cacheKey := fmt.Sprintf("user_orders:%s", order.UserID)
-		if err := uc.cache.Delete(ctx, cacheKey); err != nil {
-			log.Printf("Failed to invalidate cache for user %s: %v", order.UserID, err)
-		} else {
-			log.Printf("Cache invalidated for user %s", order.UserID)
+	uc.invalidateUserOrders(ctx, order.UserID)
+
+	if _, ok := messaging.StatusSubject(string(status)); ok {
+		go uc.publishOrderStatusChangedEvent(order, previous)
+	}
+
+	return order, nil
+}
+
+// ModifyOrder changes the lines of an order that has not been paid yet and
+// publishes order.modified with the per-product stock deltas. Products new to
+// the order are priced from the inventory catalog.
+//
+// A payment started for the old total is cancelled before the new lines are
+// stored; while the customer is completing one, the order cannot change.
+func (uc *OrderUseCase) ModifyOrder(ctx context.Context, orderID string, changes []domain.OrderLineChange, payments PaymentCanceller) (*domain.Order, []domain.QuantityDelta, error) {
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if order == nil {
+		return nil, nil, nil
+	}
+
+	current := make(map[string]bool, len(order.Items))
+	for _, item := range order.Items {
+		current[item.ProductID] = true
+	}
+	for i := range changes {
+		if current[changes[i].ProductID] || changes[i].Quantity <= 0 {
+			continue
 		}
+		if uc.catalog == nil {
+			return nil, nil, errors.New("inventory is unavailable, new products cannot be added")
+		}
+		product, err := uc.catalog.GetProduct(ctx, changes[i].ProductID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("product %s is not available: %w", changes[i].ProductID, err)
+		}
+		changes[i].Price = product.Price
+		changes[i].TaxClass = product.TaxClass
+	}
+
+	previousTotal := order.Total
+	deltas, err := order.Modify(changes)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := uc.checkStock(ctx, deltas); err != nil {
+		return nil, nil, err
+	}
+	if order.Total != previousTotal {
+		if err := payments.CancelOpenPayments(ctx, order.ID); err != nil {
+			return nil, nil, fmt.Errorf("order %s is being paid and cannot be modified: %w", order.ID, err)
+		}
+	}
+
+	ok, err := uc.orderRepo.UpdateItems(ctx, order)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, fmt.Errorf("order %s can no longer be modified", order.ID)
+	}
+
+	uc.invalidateUserOrders(ctx, order.UserID)
+
+	if len(deltas) > 0 {
+		go uc.publishOrderModifiedEvent(order, deltas)
 	}
 
-	return updatedOrder, nil
+	return order, deltas, nil
+}
+
+// checkStock makes sure every product the order now holds more of is in stock.
+func (uc *OrderUseCase) checkStock(ctx context.Context, deltas []domain.QuantityDelta) error {
+	if uc.catalog == nil {
+		return nil
+	}
+
+	for _, delta := range deltas {
+		if delta.Delta <= 0 {
+			continue
+		}
+		product, err := uc.catalog.GetProduct(ctx, delta.ProductID)
+		if err != nil {
+			return fmt.Errorf("failed to check stock of product %s: %w", delta.ProductID, err)
+		}
+		if product.Stock < delta.Delta {
+			return fmt.Errorf("insufficient stock for product %s: requested %d more, available %d",
+				delta.ProductID, delta.Delta, product.Stock)
+		}
+	}
+
+	return nil
+}
+
+// ReserveRefund records a pending refund on the order before any money moves.
+// It fails when the order changed since it was read, so a concurrent refund
+// has to be validated again against the new refunded total.
+func (uc *OrderUseCase) ReserveRefund(ctx context.Context, order *domain.Order, refund *domain.Refund) error {
+	ok, err := uc.orderRepo.AddRefund(ctx, order.ID, *refund, order.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("order %s was changed concurrently, please retry", order.ID)
+	}
+
+	order.AddRefund(*refund)
+	uc.invalidateUserOrders(ctx, order.UserID)
+
+	return nil
+}
+
+// ReleaseRefund drops a reserved refund the provider did not pay out.
+func (uc *OrderUseCase) ReleaseRefund(ctx context.Context, order *domain.Order, refund *domain.Refund) error {
+	if err := uc.orderRepo.RemoveRefund(ctx, order.ID, refund.ID); err != nil {
+		return err
+	}
+
+	order.RemoveRefund(refund.ID)
+	uc.invalidateUserOrders(ctx, order.UserID)
+
+	return nil
+}
+
+// CompleteRefund marks a reserved refund paid out, marks the order refunded
+// once nothing captured is left and publishes order.refunded.
+func (uc *OrderUseCase) CompleteRefund(ctx context.Context, order *domain.Order, refund *domain.Refund, captured money.Money) (*domain.Order, error) {
+	refundedTotal := order.RefundedTotal()
+	overCaptured, err := refundedTotal.Sub(captured)
+	if err != nil {
+		return nil, err
+	}
+	fullyRefunded := !overCaptured.IsNegative()
+
+	if err := uc.orderRepo.CompleteRefund(ctx, order.ID, refund.ID, refund.ProviderRefundID, fullyRefunded); err != nil {
+		return nil, err
+	}
+
+	order.CompleteRefund(refund.ID, refund.ProviderRefundID)
+	refund.Status = domain.RefundStatusCompleted
+	if fullyRefunded {
+		order.UpdateStatus(domain.OrderStatusRefunded)
+	}
+
+	uc.invalidateUserOrders(ctx, order.UserID)
+
+	go uc.publishOrderRefundedEvent(order, refund, refundedTotal, fullyRefunded)
+
+	return order, nil
+}
+
+func (uc *OrderUseCase) AttachReceipt(ctx context.Context, order *domain.Order, receipt domain.OrderReceipt) error {
+	if err := uc.orderRepo.AddReceipt(ctx, order.ID, receipt); err != nil {
+		return err
+	}
+	order.Receipts = append(order.Receipts, receipt)
+
+	uc.invalidateUserOrders(ctx, order.UserID)
+
+	return nil
+}
+
+// ExpirePendingOrders cancels orders left pending for longer than ttl and
+// publishes order.expired for each so inventory releases their stock. An order
+// whose event could not be published stays leased and is retried once the
+// lease runs out. The open payments of an order are cancelled first; an order
+// whose payment cannot be cancelled, because the customer is paying it right
+// now, is skipped and tried again once its lease runs out.
+func (uc *OrderUseCase) ExpirePendingOrders(ctx context.Context, now time.Time, ttl, lease time.Duration, payments PaymentCanceller) (int, error) {
+	expired := 0
+	for {
+		order, err := uc.orderRepo.ClaimExpired(ctx, now.Add(-ttl), now, now.Add(lease))
+		if err != nil {
+			return expired, err
+		}
+		if order == nil {
+			return expired, nil
+		}
+
+		if uc.expire(ctx, order, now, payments) {
+			expired++
+		}
+	}
+}
+
+func (uc *OrderUseCase) expire(ctx context.Context, order *domain.Order, now time.Time, payments PaymentCanceller) bool {
+	if order.Status == domain.OrderStatusPending {
+		if err := payments.CancelOpenPayments(ctx, order.ID); err != nil {
+			// The order stays leased, so this run does not claim it again.
+			log.Printf("Order %s has a payment in progress and does not expire yet: %v", order.ID, err)
+			return false
+		}
+
+		ok, err := uc.orderRepo.Expire(ctx, order.ID, now)
+		if err != nil {
+			log.Printf("Failed to expire order %s: %v", order.ID, err)
+			return false
+		}
+		if !ok {
+			log.Printf("Order %s left pending status before it could expire", order.ID)
+			if err := uc.orderRepo.ReleaseExpiry(ctx, order.ID, false); err != nil {
+				log.Printf("Failed to release expiry lease of order %s: %v", order.ID, err)
+			}
+			return false
+		}
+
+		order.UpdateStatus(domain.OrderStatusCancelled)
+		order.ExpiredAt = now
+		uc.invalidateUserOrders(ctx, order.UserID)
+		log.Printf("Order %s expired after staying unpaid since %s", order.ID, order.CreatedAt.Format(time.RFC3339))
+	}
+
+	if err := uc.publishOrderExpiredEvent(order); err != nil {
+		log.Printf("Failed to publish order.expired event for order %s, will retry: %v", order.ID, err)
+		return true
+	}
+
+	if err := uc.orderRepo.ReleaseExpiry(ctx, order.ID, true); err != nil {
+		log.Printf("Failed to release expiry lease of order %s: %v", order.ID, err)
+	}
+
+	return true
+}
+
+// RepublishCreatedEvents publishes the order.created events that failed when
+// their orders were placed. Orders younger than grace are left to the request
+// that placed them. Inventory takes the stock of an order once, so an event
+// published twice does no harm.
+func (uc *OrderUseCase) RepublishCreatedEvents(ctx context.Context, now time.Time, grace, lease time.Duration) (int, error) {
+	published := 0
+	for {
+		order, err := uc.orderRepo.ClaimUnpublishedCreated(ctx, now.Add(-grace), now, now.Add(lease))
+		if err != nil {
+			return published, err
+		}
+		if order == nil {
+			return published, nil
+		}
+
+		if err := uc.publishOrderCreatedEvent(order); err != nil {
+			// The order stays leased, so this run does not claim it again.
+			log.Printf("Failed to publish order.created event for order %s, will retry: %v", order.ID, err)
+			continue
+		}
+		if err := uc.orderRepo.ReleaseCreated(ctx, order.ID, true); err != nil {
+			log.Printf("Failed to mark order.created event of order %s published: %v", order.ID, err)
+		}
+		published++
+	}
+}
+
+func (uc *OrderUseCase) invalidateUserOrders(ctx context.Context, userID string) {
+	if uc.cache == nil {
+		return
+	}
+
+	cacheKey := fmt.Sprintf("user_orders:%s", userID)
+	if err := uc.cache.Delete(ctx, cacheKey); err != nil {
+		log.Printf("Failed to invalidate cache for user %s: %v", userID, err)
+	} else {
+		log.Printf("Cache invalidated for user %s", userID)
+	}
 }
 
 func (uc *OrderUseCase) ListOrdersByUserID(ctx context.Context, userID string) ([]*domain.Order, error) {
@@ -112,7 +450,7 @@ func (uc *OrderUseCase) ListOrdersByUserID(ctx context.Context, userID string) (
 	return dbOrders, nil
 }
 
-func (uc *OrderUseCase) publishOrderCreatedEvent(order *domain.Order) {
+func (uc *OrderUseCase) publishOrderCreatedEvent(order *domain.Order) error {
 
 	items := make([]messaging.OrderItem, len(order.Items))
 	for i, item := range order.Items {
@@ -131,7 +469,89 @@ func (uc *OrderUseCase) publishOrderCreatedEvent(order *domain.Order) {
 		Timestamp: time.Now().UnixNano(),
 	}
 
-	err := uc.eventPublisher.PublishOrderCreated(event)
-	if err != nil {
+	return uc.eventPublisher.PublishOrderCreated(event)
+}
+
+func (uc *OrderUseCase) publishOrderRefundedEvent(order *domain.Order, refund *domain.Refund, refundedTotal money.Money, fullyRefunded bool) {
+	items := make([]messaging.RefundedItem, len(refund.Lines))
+	for i, line := range refund.Lines {
+		items[i] = messaging.RefundedItem{
+			ProductID: line.ProductID,
+			Quantity:  line.Quantity,
+			Amount:    line.Amount,
+		}
+	}
+
+	event := messaging.OrderRefundedEvent{
+		OrderID:       order.ID,
+		UserID:        order.UserID,
+		RefundID:      refund.ID,
+		Amount:        refund.Amount,
+		RefundedTotal: refundedTotal,
+		FullyRefunded: fullyRefunded,
+		Items:         items,
+		Reason:        refund.Reason,
+		Timestamp:     time.Now().UnixNano(),
+	}
+
+	if err := uc.eventPublisher.PublishOrderRefunded(event); err != nil {
+		log.Printf("Failed to publish order.refunded event for order %s: %v", order.ID, err)
+	}
+}
+
+func (uc *OrderUseCase) publishOrderExpiredEvent(order *domain.Order) error {
+	items := make([]messaging.OrderItem, len(order.Items))
+	for i, item := range order.Items {
+		items[i] = messaging.OrderItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Price:     item.Price,
+		}
+	}
+
+	return uc.eventPublisher.PublishOrderExpired(messaging.OrderExpiredEvent{
+		OrderID:   order.ID,
+		UserID:    order.UserID,
+		Items:     items,
+		Total:     order.Total,
+		ExpiredAt: order.ExpiredAt.UnixNano(),
+		Timestamp: time.Now().UnixNano(),
+	})
+}
+
+func (uc *OrderUseCase) publishOrderStatusChangedEvent(order *domain.Order, previous domain.OrderStatus) {
+	event := messaging.OrderStatusChangedEvent{
+		OrderID:        order.ID,
+		UserID:         order.UserID,
+		Status:         string(order.Status),
+		PreviousStatus: string(previous),
+		Total:          order.Total,
+		Timestamp:      time.Now().UnixNano(),
+	}
+
+	if err := uc.eventPublisher.PublishOrderStatusChanged(event); err != nil {
+		log.Printf("Failed to publish status event for order %s: %v", order.ID, err)
+	}
+}
+
+func (uc *OrderUseCase) publishOrderModifiedEvent(order *domain.Order, deltas []domain.QuantityDelta) {
+	stockDeltas := make([]messaging.StockDelta, len(deltas))
+	for i, delta := range deltas {
+		stockDeltas[i] = messaging.StockDelta{
+			ProductID: delta.ProductID,
+			Delta:     delta.Delta,
+		}
+	}
+
+	event := messaging.OrderModifiedEvent{
+		OrderID:   order.ID,
+		UserID:    order.UserID,
+		Deltas:    stockDeltas,
+		Total:     order.Total,
+		Timestamp: time.Now().UnixNano(),
+	}
+
+	if err := uc.eventPublisher.PublishOrderModified(event); err != nil {
+		log.Printf("Failed to publish order.modified event for order %s: %v", order.ID, err)
 	}
 }
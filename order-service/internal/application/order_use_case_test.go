@@ -0,0 +1,139 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/inventory"
+	"proto/money"
+)
+
+func TestResolveFromCatalogTakesPriceAndTaxClass(t *testing.T) {
+	uc := &OrderUseCase{catalog: &fakeCatalog{products: map[string]*inventory.ProductInfo{
+		"milk": {ID: "milk", Price: money.KZT(45000), TaxClass: domain.TaxClassExempt},
+	}}}
+
+	items := []domain.OrderItem{{ProductID: "milk"}}
+	if err := uc.resolveFromCatalog(context.Background(), items); err != nil {
+		t.Fatalf("resolveFromCatalog: %v", err)
+	}
+	if items[0].TaxClass != domain.TaxClassExempt || items[0].Price != money.KZT(45000) {
+		t.Fatalf("item = %s at %v, want exempt at 450.00 KZT", items[0].TaxClass, items[0].Price)
+	}
+
+	if err := uc.resolveFromCatalog(context.Background(), []domain.OrderItem{{ProductID: "unknown"}}); err == nil {
+		t.Fatal("unknown product resolved")
+	}
+}
+
+func TestResolveFromCatalogFailsWhileInventoryIsUnreachable(t *testing.T) {
+	for _, uc := range []*OrderUseCase{
+		{catalog: &fakeCatalog{err: fmt.Errorf("%w: connection refused", inventory.ErrCatalogUnavailable)}},
+		{},
+	} {
+		items := []domain.OrderItem{{ProductID: "milk"}}
+		if err := uc.resolveFromCatalog(context.Background(), items); !errors.Is(err, inventory.ErrCatalogUnavailable) {
+			t.Fatalf("resolveFromCatalog error = %v, want catalog unavailable", err)
+		}
+		if items[0].TaxClass != "" {
+			t.Fatalf("tax class = %s, want it left unresolved", items[0].TaxClass)
+		}
+	}
+}
+
+func newCreateOrderFixture() (*OrderUseCase, *fakeOrders, *recordingPublisher) {
+	orders := newFakeOrders()
+	publisher := &recordingPublisher{}
+	catalog := &fakeCatalog{products: map[string]*inventory.ProductInfo{
+		"tea": {ID: "tea", Price: money.KZT(100000), TaxClass: domain.TaxClassStandard},
+	}}
+	return NewOrderUseCase(orders, publisher, nil, catalog), orders, publisher
+}
+
+var teaLine = []domain.OrderItem{{ProductID: "tea", Quantity: 2, Price: money.KZT(100000)}}
+
+func TestCreateOrderChargesTheCatalogPrice(t *testing.T) {
+	ctx := context.Background()
+	uc, orders, _ := newCreateOrderFixture()
+
+	order, err := uc.CreateOrder(ctx, "user-1", []domain.OrderItem{{ProductID: "tea", Quantity: 2}}, "Astana")
+	if err != nil {
+		t.Fatalf("CreateOrder without a price: %v", err)
+	}
+	if order.Items[0].Price != money.KZT(100000) || order.Total != money.KZT(200000) {
+		t.Fatalf("order of 2 teas at %v totals %v, want 2000.00 KZT at the catalog price", order.Items[0].Price, order.Total)
+	}
+
+	cheap := []domain.OrderItem{{ProductID: "tea", Quantity: 2, Price: money.KZT(1)}}
+	if _, err := uc.CreateOrder(ctx, "user-1", cheap, "Astana"); err == nil {
+		t.Fatal("an order at a price the client made up was placed")
+	}
+	if orders.count() != 1 {
+		t.Fatalf("%d orders stored, want only the one at the catalog price", orders.count())
+	}
+}
+
+func TestCreateOrderPublishesOrderCreatedBeforeReturning(t *testing.T) {
+	ctx := context.Background()
+	uc, orders, publisher := newCreateOrderFixture()
+
+	order, err := uc.CreateOrder(ctx, "user-1", teaLine, "Astana")
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+	if len(publisher.created) != 1 || publisher.created[0].OrderID != order.ID {
+		t.Fatalf("published %+v, want order.created for %s", publisher.created, order.ID)
+	}
+
+	republished, err := uc.RepublishCreatedEvents(ctx, time.Now().Add(time.Hour), time.Minute, time.Minute)
+	if err != nil || republished != 0 {
+		t.Fatalf("RepublishCreatedEvents = %d, %v; want nothing left to publish", republished, err)
+	}
+	if orders.createdUnpublished[order.ID] {
+		t.Fatal("order still marked unpublished")
+	}
+}
+
+func TestCreateOrderLeavesAFailedOrderCreatedForTheWorker(t *testing.T) {
+	ctx := context.Background()
+	uc, _, publisher := newCreateOrderFixture()
+	publisher.createdFailures = 2
+
+	order, err := uc.CreateOrder(ctx, "user-1", teaLine, "Astana")
+	if err != nil {
+		t.Fatalf("CreateOrder failed with the order placed: %v", err)
+	}
+	if len(publisher.created) != 0 {
+		t.Fatalf("published %d events, want none", len(publisher.created))
+	}
+
+	// Within the grace period the order is left to the request placing it.
+	now := time.Now()
+	if republished, _ := uc.RepublishCreatedEvents(ctx, now, time.Minute, time.Minute); republished != 0 {
+		t.Fatalf("republished %d events of a fresh order", republished)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if republished, err := uc.RepublishCreatedEvents(ctx, now, time.Minute, time.Minute); err != nil || republished != 0 {
+		t.Fatalf("RepublishCreatedEvents = %d, %v; want the publish to fail again", republished, err)
+	}
+	// The failed order stays leased until the lease runs out.
+	if republished, _ := uc.RepublishCreatedEvents(ctx, now, time.Minute, time.Minute); republished != 0 {
+		t.Fatalf("republished %d events of a leased order", republished)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if republished, err := uc.RepublishCreatedEvents(ctx, now, time.Minute, time.Minute); err != nil || republished != 1 {
+		t.Fatalf("RepublishCreatedEvents = %d, %v; want 1 event published", republished, err)
+	}
+	if len(publisher.created) != 1 || publisher.created[0].OrderID != order.ID || len(publisher.created[0].Items) != 1 {
+		t.Fatalf("published %+v, want order.created for %s", publisher.created, order.ID)
+	}
+	if republished, _ := uc.RepublishCreatedEvents(ctx, now.Add(time.Hour), time.Minute, time.Minute); republished != 0 {
+		t.Fatalf("order.created published again after it succeeded")
+	}
+}
@@ -0,0 +1,462 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/payment"
+	"order-service/internal/infrastructure/persistence"
+	"proto/money"
+)
+
+type PaymentUseCase struct {
+	paymentRepo    persistence.PaymentRepository
+	orderUseCase   *OrderUseCase
+	receiptUseCase *ReceiptUseCase
+	provider       payment.PaymentProvider
+}
+
+func NewPaymentUseCase(paymentRepo persistence.PaymentRepository, orderUseCase *OrderUseCase, receiptUseCase *ReceiptUseCase, provider payment.PaymentProvider) *PaymentUseCase {
+	return &PaymentUseCase{
+		paymentRepo:    paymentRepo,
+		orderUseCase:   orderUseCase,
+		receiptUseCase: receiptUseCase,
+		provider:       provider,
+	}
+}
+
+// CreatePayment starts collecting the total of a pending order with the
+// given method and returns the intent with what the customer needs to pay.
+// An open intent for the same method and amount is returned again; any other
+// open intent is cancelled first, so the order is never charged twice.
+func (uc *PaymentUseCase) CreatePayment(ctx context.Context, orderID string, method domain.PaymentMethod) (*domain.PaymentIntent, error) {
+	if orderID == "" {
+		return nil, errors.New("order ID is required")
+	}
+	if !method.IsValid() {
+		return nil, errors.New("payment method must be card or qr")
+	}
+
+	order, err := uc.orderUseCase.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, errors.New("order not found")
+	}
+	if !order.Status.IsPayable() {
+		return nil, fmt.Errorf("order %s cannot be paid in status %s", order.ID, order.Status)
+	}
+
+	open, err := uc.openIntents(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, intent := range open {
+		if intent.Method == method && intent.Amount == order.Total && intent.Provider == uc.provider.Name() {
+			return intent, nil
+		}
+	}
+	if err := uc.cancelIntents(ctx, open); err != nil {
+		return nil, err
+	}
+
+	intent := domain.NewPaymentIntent(order.ID, order.Total, method, uc.provider.Name())
+
+	var session *payment.CheckoutSession
+	switch method {
+	case domain.PaymentMethodCard:
+		session, err = uc.provider.CreateCardPayment(ctx, intent)
+	case domain.PaymentMethodQR:
+		session, err = uc.provider.CreateQRPayment(ctx, intent)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("payment provider %s: %w", uc.provider.Name(), err)
+	}
+
+	intent.ProviderPaymentID = session.ProviderPaymentID
+	intent.RedirectURL = session.RedirectURL
+	intent.QRPayload = session.QRPayload
+
+	created, err := uc.paymentRepo.Create(ctx, intent)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Created %s payment %s for order %s (%s)", method, intent.ID, order.ID, intent.Amount)
+
+	return created, nil
+}
+
+// CancelOpenPayments voids every intent of the order the customer has not
+// completed yet. It fails if one of them succeeded in the meantime.
+func (uc *PaymentUseCase) CancelOpenPayments(ctx context.Context, orderID string) error {
+	open, err := uc.openIntents(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	return uc.cancelIntents(ctx, open)
+}
+
+func (uc *PaymentUseCase) openIntents(ctx context.Context, orderID string) ([]*domain.PaymentIntent, error) {
+	intents, err := uc.paymentRepo.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var open []*domain.PaymentIntent
+	for _, intent := range intents {
+		if !intent.Status.IsFinal() {
+			open = append(open, intent)
+		}
+	}
+	return open, nil
+}
+
+func (uc *PaymentUseCase) cancelIntents(ctx context.Context, intents []*domain.PaymentIntent) error {
+	for _, intent := range intents {
+		if intent.Provider != uc.provider.Name() {
+			return fmt.Errorf("payment %s was made with provider %s, which is not configured", intent.ID, intent.Provider)
+		}
+		if err := uc.provider.CancelPayment(ctx, intent); err != nil {
+			return fmt.Errorf("payment provider %s: %w", uc.provider.Name(), err)
+		}
+
+		intent.UpdateStatus(domain.PaymentStatusCancelled)
+		ok, err := uc.paymentRepo.UpdateStatus(ctx, intent, domain.PaymentStatusPending)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("payment %s was completed in the meantime", intent.ID)
+		}
+
+		log.Printf("Cancelled payment %s for order %s", intent.ID, intent.OrderID)
+	}
+	return nil
+}
+
+func (uc *PaymentUseCase) GetPayment(ctx context.Context, id string) (*domain.PaymentIntent, error) {
+	if id == "" {
+		return nil, errors.New("payment ID is required")
+	}
+
+	return uc.paymentRepo.GetByID(ctx, id)
+}
+
+func (uc *PaymentUseCase) ListOrderPayments(ctx context.Context, orderID string) ([]*domain.PaymentIntent, error) {
+	if orderID == "" {
+		return nil, errors.New("order ID is required")
+	}
+
+	return uc.paymentRepo.ListByOrderID(ctx, orderID)
+}
+
+// HandleCallback applies a provider webhook. The signature is verified first;
+// callbacks for intents that already reached a final status are ignored, so
+// providers can safely redeliver them. A successful payment marks the order
+// as paid before the intent is settled, so a failure in between leaves the
+// intent pending and the provider's retry completes it. Only the callback
+// that settles the intent issues the sale receipt. A payment captured for an
+// order that can no longer be paid, because it expired, was cancelled or now
+// has another total, is refunded instead.
+func (uc *PaymentUseCase) HandleCallback(ctx context.Context, providerName string, payload []byte, signature string) (*domain.PaymentIntent, error) {
+	if providerName != uc.provider.Name() {
+		return nil, fmt.Errorf("unknown payment provider: %s", providerName)
+	}
+
+	event, err := uc.provider.ParseCallback(payload, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	intent, err := uc.paymentRepo.GetByProviderPaymentID(ctx, providerName, event.ProviderPaymentID)
+	if err != nil {
+		return nil, err
+	}
+	if intent == nil {
+		return nil, fmt.Errorf("payment not found: %s", event.ProviderPaymentID)
+	}
+
+	if intent.Status.IsFinal() {
+		log.Printf("Ignoring %s callback for payment %s already %s", event.Status, intent.ID, intent.Status)
+		return intent, nil
+	}
+
+	switch event.Status {
+	case domain.PaymentStatusPending:
+		return intent, nil
+	case domain.PaymentStatusSucceeded:
+		if event.Amount != intent.Amount {
+			return nil, fmt.Errorf("payment %s captured %s, expected %s", intent.ID, event.Amount, intent.Amount)
+		}
+	case domain.PaymentStatusFailed, domain.PaymentStatusCancelled:
+	default:
+		return nil, fmt.Errorf("unknown payment status: %s", event.Status)
+	}
+
+	var paidOrder *domain.Order
+	if event.Status == domain.PaymentStatusSucceeded {
+		order, err := uc.orderUseCase.GetOrderByID(ctx, intent.OrderID)
+		if err != nil {
+			return nil, err
+		}
+		if order == nil {
+			return nil, fmt.Errorf("order not found: %s", intent.OrderID)
+		}
+		if order.Total != intent.Amount {
+			return uc.refundLateCapture(ctx, intent, fmt.Errorf("order %s now totals %s", order.ID, order.Total))
+		}
+
+		paidOrder, err = uc.orderUseCase.UpdateOrderStatus(ctx, intent.OrderID, domain.OrderStatusPaid)
+		if errors.Is(err, domain.ErrStatusTransition) {
+			return uc.refundLateCapture(ctx, intent, err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("payment %s succeeded but order %s was not updated: %w", intent.ID, intent.OrderID, err)
+		}
+	}
+
+	intent.UpdateStatus(event.Status)
+	ok, err := uc.paymentRepo.UpdateStatus(ctx, intent, domain.PaymentStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		log.Printf("Payment %s was settled by a concurrent callback", intent.ID)
+		return uc.paymentRepo.GetByID(ctx, intent.ID)
+	}
+
+	log.Printf("Payment %s for order %s is %s", intent.ID, intent.OrderID, intent.Status)
+
+	if paidOrder != nil && uc.receiptUseCase != nil {
+		if _, err := uc.receiptUseCase.IssueSaleReceipt(ctx, paidOrder, intent.Method); err != nil {
+			log.Printf("Sale receipt for order %s is pending and will be retried: %v", paidOrder.ID, err)
+		}
+	}
+
+	return intent, nil
+}
+
+// refundLateCapture returns a payment the provider captured after its order
+// stopped accepting payments. The refund takes the intent's ID, so a
+// redelivered callback repeats the same refund at the provider rather than
+// paying out twice, and the intent stays pending until the refund went
+// through.
+func (uc *PaymentUseCase) refundLateCapture(ctx context.Context, intent *domain.PaymentIntent, reason error) (*domain.PaymentIntent, error) {
+	captured := *intent
+	captured.UpdateStatus(domain.PaymentStatusSucceeded)
+
+	refund := domain.NewRefund(intent.ID, intent.Amount, "order is no longer payable", nil)
+	refund.ID = intent.ID
+	if _, err := uc.provider.Refund(ctx, &captured, refund); err != nil {
+		return nil, fmt.Errorf("payment %s was captured but could not be refunded (%v): %w", intent.ID, reason, err)
+	}
+
+	intent.UpdateStatus(domain.PaymentStatusRefunded)
+	ok, err := uc.paymentRepo.UpdateStatus(ctx, intent, domain.PaymentStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		log.Printf("Payment %s was settled by a concurrent callback", intent.ID)
+		return uc.paymentRepo.GetByID(ctx, intent.ID)
+	}
+
+	log.Printf("Refunded payment %s captured too late: %v", intent.ID, reason)
+	return intent, nil
+}
+
+// Refund returns money for an order that was paid. With lines, the refund is
+// the VAT-inclusive price of the returned units; with only an amount, that
+// amount is refunded as-is; with neither, everything still captured is
+// refunded. The refund is validated against what was captured and refunded
+// before and reserved on the order, which fails if another refund got there
+// first; only then is it sent to the provider and marked completed.
+func (uc *PaymentUseCase) Refund(ctx context.Context, orderID string, lines []domain.RefundLine, amount money.Money, reason string) (*domain.Order, *domain.Refund, error) {
+	return uc.refund(ctx, "", orderID, lines, amount, reason)
+}
+
+// RefundOnce refunds like Refund, under refundID. If the order already has a
+// refund with that ID, nothing new is refunded: a completed one is returned,
+// a pending one is still being issued by another call and fails.
+func (uc *PaymentUseCase) RefundOnce(ctx context.Context, refundID, orderID string, lines []domain.RefundLine, amount money.Money, reason string) (*domain.Order, *domain.Refund, error) {
+	if refundID == "" {
+		return nil, nil, errors.New("refund ID is required")
+	}
+	return uc.refund(ctx, refundID, orderID, lines, amount, reason)
+}
+
+func (uc *PaymentUseCase) refund(ctx context.Context, refundID, orderID string, lines []domain.RefundLine, amount money.Money, reason string) (*domain.Order, *domain.Refund, error) {
+	if orderID == "" {
+		return nil, nil, errors.New("order ID is required")
+	}
+	if len(lines) > 0 && !amount.IsZero() {
+		return nil, nil, errors.New("refund either lines or an amount, not both")
+	}
+
+	order, err := uc.orderUseCase.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if order == nil {
+		return nil, nil, errors.New("order not found")
+	}
+	if existing := order.FindRefund(refundID); refundID != "" && existing != nil {
+		if existing.Status == domain.RefundStatusPending {
+			return nil, nil, fmt.Errorf("refund %s of order %s is still being issued", refundID, order.ID)
+		}
+		return order, existing, nil
+	}
+	switch order.Status {
+	case domain.OrderStatusPaid, domain.OrderStatusCompleted:
+	case domain.OrderStatusRefunded:
+		return nil, nil, fmt.Errorf("order %s is already fully refunded", order.ID)
+	default:
+		return nil, nil, fmt.Errorf("order %s cannot be refunded in status %s", order.ID, order.Status)
+	}
+
+	intents, err := uc.paymentRepo.ListByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	captured := money.New(0, order.Total.Currency)
+	var captures []*domain.PaymentIntent
+	for _, intent := range intents {
+		if intent.Status == domain.PaymentStatusSucceeded {
+			if captured, err = captured.Add(intent.Amount); err != nil {
+				return nil, nil, err
+			}
+			captures = append(captures, intent)
+		}
+	}
+	if len(captures) == 0 {
+		return nil, nil, fmt.Errorf("order %s has no captured payment", order.ID)
+	}
+	remaining, err := captured.Sub(order.RefundedTotal())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch {
+	case len(lines) > 0:
+		lines, amount, err = priceRefundLines(order, lines)
+		if err != nil {
+			return nil, nil, err
+		}
+	case amount.IsZero():
+		amount = remaining
+	default:
+		if amount.Currency == "" {
+			amount.Currency = order.Total.Currency
+		}
+		if !amount.SameCurrency(order.Total) {
+			return nil, nil, fmt.Errorf("refund must be in %s", order.Total.Currency)
+		}
+	}
+
+	if amount.IsNegative() || amount.IsZero() {
+		return nil, nil, errors.New("refund amount must be positive")
+	}
+	if !covers(remaining, amount) {
+		return nil, nil, fmt.Errorf("refund of %s exceeds the %s still captured", amount, remaining)
+	}
+
+	var intent *domain.PaymentIntent
+	for _, capture := range captures {
+		left, err := capture.Amount.Sub(order.RefundedAmountForPayment(capture.ID))
+		if err != nil {
+			return nil, nil, err
+		}
+		if covers(left, amount) {
+			intent = capture
+			break
+		}
+	}
+	if intent == nil {
+		return nil, nil, fmt.Errorf("refund of %s exceeds what is left on any single payment", amount)
+	}
+	if intent.Provider != uc.provider.Name() {
+		return nil, nil, fmt.Errorf("payment %s was made with provider %s, which is not configured", intent.ID, intent.Provider)
+	}
+
+	refund := domain.NewRefund(intent.ID, amount, reason, lines)
+	if refundID != "" {
+		refund.ID = refundID
+	}
+	if err := uc.orderUseCase.ReserveRefund(ctx, order, refund); err != nil {
+		return nil, nil, err
+	}
+
+	refund.ProviderRefundID, err = uc.provider.Refund(ctx, intent, refund)
+	if err != nil {
+		if releaseErr := uc.orderUseCase.ReleaseRefund(ctx, order, refund); releaseErr != nil {
+			log.Printf("Failed to release refund %s of order %s: %v", refund.ID, order.ID, releaseErr)
+		}
+		return nil, nil, fmt.Errorf("payment provider %s: %w", uc.provider.Name(), err)
+	}
+
+	log.Printf("Refunded %s of payment %s for order %s", amount, intent.ID, order.ID)
+
+	updatedOrder, err := uc.orderUseCase.CompleteRefund(ctx, order, refund, captured)
+	if err != nil {
+		return nil, nil, fmt.Errorf("refund %s was issued but not recorded on order %s: %w", refund.ProviderRefundID, order.ID, err)
+	}
+
+	if uc.receiptUseCase != nil {
+		if _, err := uc.receiptUseCase.IssueReturnReceipt(ctx, updatedOrder, refund, intent.Method); err != nil {
+			log.Printf("Return receipt for refund %s is pending and will be retried: %v", refund.ID, err)
+		}
+	}
+
+	return updatedOrder, refund, nil
+}
+
+// covers reports whether available is at least amount in the same currency.
+func covers(available, amount money.Money) bool {
+	after, err := available.Sub(amount)
+	return err == nil && !after.IsNegative()
+}
+
+// priceRefundLines checks that every returned quantity is still refundable and
+// prices it at the line's VAT-inclusive unit price.
+func priceRefundLines(order *domain.Order, lines []domain.RefundLine) ([]domain.RefundLine, money.Money, error) {
+	total := money.New(0, order.Total.Currency)
+	requested := make(map[string]int)
+	priced := make([]domain.RefundLine, 0, len(lines))
+
+	for _, line := range lines {
+		if line.Quantity <= 0 {
+			return nil, money.Money{}, fmt.Errorf("refund quantity for product %s must be positive", line.ProductID)
+		}
+
+		var item *domain.OrderItem
+		for i := range order.Items {
+			if order.Items[i].ProductID == line.ProductID {
+				item = &order.Items[i]
+				break
+			}
+		}
+		if item == nil {
+			return nil, money.Money{}, fmt.Errorf("product %s is not part of order %s", line.ProductID, order.ID)
+		}
+
+		requested[line.ProductID] += line.Quantity
+		refundable := item.Quantity - order.RefundedQuantity(line.ProductID)
+		if requested[line.ProductID] > refundable {
+			return nil, money.Money{}, fmt.Errorf("only %d of product %s can still be refunded", refundable, line.ProductID)
+		}
+
+		line.Amount = item.Price.Mul(line.Quantity)
+		var err error
+		if total, err = total.Add(line.Amount); err != nil {
+			return nil, money.Money{}, err
+		}
+		priced = append(priced, line)
+	}
+
+	return priced, total, nil
+}
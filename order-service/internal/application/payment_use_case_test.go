@@ -0,0 +1,149 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/payment"
+)
+
+type paymentFixture struct {
+	orders   *fakeOrders
+	payments *fakePayments
+	provider *payment.FakeProvider
+	uc       *PaymentUseCase
+}
+
+func newPaymentFixture(orders ...*domain.Order) *paymentFixture {
+	f := &paymentFixture{
+		orders:   newFakeOrders(orders...),
+		payments: newFakePayments(),
+		provider: payment.NewFakeProvider("test-secret", "https://pay.test"),
+	}
+	orderUseCase := NewOrderUseCase(f.orders, fakePublisher{}, nil, nil)
+	f.uc = NewPaymentUseCase(f.payments, orderUseCase, nil, f.provider)
+	return f
+}
+
+func TestFakeProviderSignsCallbacks(t *testing.T) {
+	provider := payment.NewFakeProvider("test-secret", "https://pay.test")
+	intent := domain.NewPaymentIntent("order-1", newPendingOrder(t).Total, domain.PaymentMethodCard, payment.FakeProviderName)
+
+	payload, signature, err := provider.BuildCallback(intent, domain.PaymentStatusSucceeded)
+	if err != nil {
+		t.Fatalf("BuildCallback: %v", err)
+	}
+
+	event, err := provider.ParseCallback(payload, signature)
+	if err != nil {
+		t.Fatalf("ParseCallback: %v", err)
+	}
+	if event.Status != domain.PaymentStatusSucceeded || event.Amount != intent.Amount {
+		t.Fatalf("event = %+v, want succeeded for %s", event, intent.Amount)
+	}
+
+	other := payment.NewFakeProvider("other-secret", "https://pay.test")
+	if _, err := other.ParseCallback(payload, signature); !errors.Is(err, payment.ErrInvalidSignature) {
+		t.Fatalf("callback signed with another secret: err = %v, want ErrInvalidSignature", err)
+	}
+	if _, err := provider.ParseCallback(append(payload, ' '), signature); !errors.Is(err, payment.ErrInvalidSignature) {
+		t.Fatalf("tampered callback: err = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestCreatePaymentAcceptsOrdersThatMayBecomePaid(t *testing.T) {
+	for _, tt := range []struct {
+		status  domain.OrderStatus
+		payable bool
+	}{
+		{domain.OrderStatusPending, true},
+		{domain.OrderStatusConfirmed, true},
+		{domain.OrderStatusPaid, false},
+		{domain.OrderStatusDispatched, false},
+		{domain.OrderStatusCancelled, false},
+		{domain.OrderStatusRefunded, false},
+	} {
+		t.Run(string(tt.status), func(t *testing.T) {
+			order := newPendingOrder(t)
+			order.Status = tt.status
+			f := newPaymentFixture(order)
+
+			_, err := f.uc.CreatePayment(context.Background(), order.ID, domain.PaymentMethodCard)
+			if tt.payable && err != nil {
+				t.Fatalf("CreatePayment: %v", err)
+			}
+			if !tt.payable && err == nil {
+				t.Fatal("CreatePayment succeeded")
+			}
+		})
+	}
+}
+
+func TestCallbackMarksOrderPaidOnce(t *testing.T) {
+	ctx := context.Background()
+	order := newPendingOrder(t)
+	f := newPaymentFixture(order)
+
+	intent, err := f.uc.CreatePayment(ctx, order.ID, domain.PaymentMethodCard)
+	if err != nil {
+		t.Fatalf("CreatePayment: %v", err)
+	}
+	again, err := f.uc.CreatePayment(ctx, order.ID, domain.PaymentMethodCard)
+	if err != nil {
+		t.Fatalf("CreatePayment again: %v", err)
+	}
+	if again.ID != intent.ID {
+		t.Fatalf("second CreatePayment opened intent %s, want %s again", again.ID, intent.ID)
+	}
+
+	payload, signature, err := f.provider.BuildCallback(intent, domain.PaymentStatusSucceeded)
+	if err != nil {
+		t.Fatalf("BuildCallback: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		settled, err := f.uc.HandleCallback(ctx, payment.FakeProviderName, payload, signature)
+		if err != nil {
+			t.Fatalf("HandleCallback %d: %v", i+1, err)
+		}
+		if settled.Status != domain.PaymentStatusSucceeded {
+			t.Fatalf("HandleCallback %d: intent is %s, want succeeded", i+1, settled.Status)
+		}
+	}
+
+	if got := f.orders.get(order.ID).Status; got != domain.OrderStatusPaid {
+		t.Fatalf("order is %s, want paid", got)
+	}
+
+	if _, err := f.uc.HandleCallback(ctx, payment.FakeProviderName, payload, "bad"); !errors.Is(err, payment.ErrInvalidSignature) {
+		t.Fatalf("unsigned callback: err = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestCallbackRefundsCaptureOfExpiredOrder(t *testing.T) {
+	ctx := context.Background()
+	order := newPendingOrder(t)
+	f := newPaymentFixture(order)
+
+	intent, err := f.uc.CreatePayment(ctx, order.ID, domain.PaymentMethodQR)
+	if err != nil {
+		t.Fatalf("CreatePayment: %v", err)
+	}
+
+	expired := f.orders.get(order.ID)
+	expired.UpdateStatus(domain.OrderStatusCancelled)
+	f.orders.UpdateStatus(ctx, expired, domain.OrderStatusPending)
+
+	payload, signature, _ := f.provider.BuildCallback(intent, domain.PaymentStatusSucceeded)
+	settled, err := f.uc.HandleCallback(ctx, payment.FakeProviderName, payload, signature)
+	if err != nil {
+		t.Fatalf("HandleCallback: %v", err)
+	}
+	if settled.Status != domain.PaymentStatusRefunded {
+		t.Fatalf("intent is %s, want refunded", settled.Status)
+	}
+	if got := f.orders.get(order.ID).Status; got != domain.OrderStatusCancelled {
+		t.Fatalf("order is %s, want it to stay cancelled", got)
+	}
+}
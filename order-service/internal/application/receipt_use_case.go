@@ -0,0 +1,177 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/fiscal"
+	"order-service/internal/infrastructure/inventory"
+	"order-service/internal/infrastructure/persistence"
+)
+
+type ReceiptUseCase struct {
+	orderUseCase *OrderUseCase
+	pendingRepo  persistence.PendingReceiptRepository
+	ofd          fiscal.OFDProvider
+	catalog      inventory.ProductCatalog
+	cashier      domain.Cashier
+}
+
+func NewReceiptUseCase(orderUseCase *OrderUseCase, pendingRepo persistence.PendingReceiptRepository, ofd fiscal.OFDProvider, catalog inventory.ProductCatalog, cashier domain.Cashier) *ReceiptUseCase {
+	return &ReceiptUseCase{
+		orderUseCase: orderUseCase,
+		pendingRepo:  pendingRepo,
+		ofd:          ofd,
+		catalog:      catalog,
+		cashier:      cashier,
+	}
+}
+
+// IssueSaleReceipt registers the sale receipt of a paid order. A receipt the
+// OFD does not take now is kept and retried by RetryPending, so an error
+// only means the receipt is late.
+func (uc *ReceiptUseCase) IssueSaleReceipt(ctx context.Context, order *domain.Order, method domain.PaymentMethod) (*domain.FiscalReceipt, error) {
+	receipt, err := domain.NewSaleReceipt(order, method, uc.cashier)
+	if err != nil {
+		return nil, err
+	}
+	return uc.issue(ctx, order, receipt, method)
+}
+
+// IssueReturnReceipt registers the return receipt of a refund, retried like
+// a sale receipt.
+func (uc *ReceiptUseCase) IssueReturnReceipt(ctx context.Context, order *domain.Order, refund *domain.Refund, method domain.PaymentMethod) (*domain.FiscalReceipt, error) {
+	receipt, err := domain.NewReturnReceipt(order, refund, method, uc.cashier)
+	if err != nil {
+		return nil, err
+	}
+	return uc.issue(ctx, order, receipt, method)
+}
+
+func (uc *ReceiptUseCase) issue(ctx context.Context, order *domain.Order, receipt *domain.FiscalReceipt, method domain.PaymentMethod) (*domain.FiscalReceipt, error) {
+	pending := domain.NewPendingReceipt(receipt, method)
+	if err := uc.pendingRepo.Create(ctx, pending); err != nil {
+		return nil, fmt.Errorf("failed to store %s receipt for order %s: %w", receipt.Type, order.ID, err)
+	}
+
+	if err := uc.submit(ctx, order, receipt); err != nil {
+		uc.recordFailure(ctx, pending, err)
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// RetryPending submits the receipts whose next attempt is due.
+func (uc *ReceiptUseCase) RetryPending(ctx context.Context, now time.Time, lease time.Duration) (int, error) {
+	issued := 0
+	for {
+		pending, err := uc.pendingRepo.ClaimDue(ctx, now, now.Add(lease))
+		if err != nil {
+			return issued, err
+		}
+		if pending == nil {
+			return issued, nil
+		}
+
+		if err := uc.retry(ctx, pending); err != nil {
+			log.Printf("Retry %d of %s receipt %s for order %s failed: %v", pending.Attempts+1, pending.Type, pending.ID, pending.OrderID, err)
+			uc.recordFailure(ctx, pending, err)
+			continue
+		}
+		issued++
+	}
+}
+
+// retry rebuilds the receipt from the order under its original ID and issue
+// time, so the OFD recognizes a receipt it registered before.
+func (uc *ReceiptUseCase) retry(ctx context.Context, pending *domain.PendingReceipt) error {
+	order, err := uc.orderUseCase.GetOrderByID(ctx, pending.OrderID)
+	if err != nil {
+		return err
+	}
+	if order == nil {
+		return fmt.Errorf("order %s not found", pending.OrderID)
+	}
+
+	var receipt *domain.FiscalReceipt
+	switch pending.Type {
+	case domain.ReceiptTypeSale:
+		receipt, err = domain.NewSaleReceipt(order, pending.Method, uc.cashier)
+	case domain.ReceiptTypeSaleReturn:
+		refund := order.FindRefund(pending.RefundID)
+		if refund == nil {
+			return fmt.Errorf("refund %s not found on order %s", pending.RefundID, order.ID)
+		}
+		receipt, err = domain.NewReturnReceipt(order, refund, pending.Method, uc.cashier)
+	default:
+		return fmt.Errorf("unknown receipt type %s", pending.Type)
+	}
+	if err != nil {
+		return err
+	}
+	receipt.ID = pending.ID
+	receipt.IssuedAt = pending.CreatedAt
+
+	return uc.submit(ctx, order, receipt)
+}
+
+// submit registers the receipt, stores it on the order unless an earlier
+// attempt already did, and drops it from the pending receipts.
+func (uc *ReceiptUseCase) submit(ctx context.Context, order *domain.Order, receipt *domain.FiscalReceipt) error {
+	uc.resolveLineNames(ctx, receipt)
+
+	registration, err := uc.ofd.SubmitReceipt(ctx, receipt)
+	if err != nil {
+		return fmt.Errorf("OFD %s rejected %s receipt for order %s: %w", uc.ofd.Name(), receipt.Type, order.ID, err)
+	}
+	receipt.FiscalNumber = registration.FiscalNumber
+	receipt.QRURL = registration.QRURL
+
+	if !order.HasReceipt(receipt.ID) {
+		if err := uc.orderUseCase.AttachReceipt(ctx, order, receipt.Summary()); err != nil {
+			return fmt.Errorf("receipt %s was registered but not stored on order %s: %w", receipt.FiscalNumber, order.ID, err)
+		}
+	}
+
+	if err := uc.pendingRepo.Delete(ctx, receipt.ID); err != nil {
+		// The next retry finds the receipt registered and stored.
+		log.Printf("Failed to clear pending receipt %s: %v", receipt.ID, err)
+	}
+
+	log.Printf("Issued %s receipt %s for order %s (%s)", receipt.Type, receipt.FiscalNumber, order.ID, receipt.Total)
+
+	return nil
+}
+
+func (uc *ReceiptUseCase) recordFailure(ctx context.Context, pending *domain.PendingReceipt, err error) {
+	pending.Fail(err, time.Now())
+	if err := uc.pendingRepo.RecordFailure(ctx, pending); err != nil {
+		log.Printf("Failed to record failed attempt of receipt %s: %v", pending.ID, err)
+	}
+}
+
+// resolveLineNames fills in product names from the catalog. Lines keep the
+// product ID as their name when the catalog is unavailable.
+func (uc *ReceiptUseCase) resolveLineNames(ctx context.Context, receipt *domain.FiscalReceipt) {
+	for i := range receipt.Lines {
+		line := &receipt.Lines[i]
+		if line.ProductID == "" {
+			line.Name = "Возврат"
+			continue
+		}
+		line.Name = line.ProductID
+		if uc.catalog == nil {
+			continue
+		}
+		product, err := uc.catalog.GetProduct(ctx, line.ProductID)
+		if err != nil {
+			log.Printf("Failed to resolve name of product %s for receipt: %v", line.ProductID, err)
+			continue
+		}
+		line.Name = product.Name
+	}
+}
@@ -0,0 +1,64 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/fiscal"
+)
+
+// flakyOFD refuses the first failures receipts, then registers them with the
+// fake OFD.
+type flakyOFD struct {
+	*fiscal.FakeOFD
+	failures int
+}
+
+func (o *flakyOFD) SubmitReceipt(ctx context.Context, receipt *domain.FiscalReceipt) (*fiscal.Registration, error) {
+	if o.failures > 0 {
+		o.failures--
+		return nil, errors.New("OFD unavailable")
+	}
+	return o.FakeOFD.SubmitReceipt(ctx, receipt)
+}
+
+func TestFailedReceiptIsRetriedUnderItsID(t *testing.T) {
+	ctx := context.Background()
+	order := newPendingOrder(t)
+	order.Status = domain.OrderStatusPaid
+	orders := newFakeOrders(order)
+	pending := newFakePendingReceipts()
+	ofd := &flakyOFD{FakeOFD: fiscal.NewFakeOFD("https://ofd.test"), failures: 1}
+	uc := NewReceiptUseCase(NewOrderUseCase(orders, fakePublisher{}, nil, nil), pending, ofd, nil, domain.Cashier{})
+
+	if _, err := uc.IssueSaleReceipt(ctx, orders.get(order.ID), domain.PaymentMethodCard); err == nil {
+		t.Fatal("IssueSaleReceipt succeeded while the OFD is unavailable")
+	}
+	if pending.count() != 1 {
+		t.Fatalf("%d pending receipts, want 1", pending.count())
+	}
+
+	now := time.Now()
+	if issued, err := uc.RetryPending(ctx, now, time.Minute); err != nil || issued != 0 {
+		t.Fatalf("RetryPending before the backoff = %d, %v; want nothing retried", issued, err)
+	}
+
+	issued, err := uc.RetryPending(ctx, now.Add(2*time.Minute), time.Minute)
+	if err != nil || issued != 1 {
+		t.Fatalf("RetryPending = %d, %v; want 1 receipt issued", issued, err)
+	}
+	if pending.count() != 0 {
+		t.Fatalf("%d pending receipts left, want none", pending.count())
+	}
+
+	receipts := orders.get(order.ID).Receipts
+	if len(receipts) != 1 || receipts[0].Total != order.Total || receipts[0].FiscalNumber == "" {
+		t.Fatalf("order receipts = %+v, want one registered sale receipt for %s", receipts, order.Total)
+	}
+	if submitted := ofd.Receipts(); len(submitted) != 1 || submitted[0].ID != receipts[0].ID {
+		t.Fatalf("OFD registered %d receipts, want the pending one once", len(submitted))
+	}
+}
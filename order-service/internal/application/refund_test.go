@@ -0,0 +1,84 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/payment"
+	"proto/money"
+)
+
+// newPaidFixture returns a paid order of 2 000.00 KZT captured by one card
+// payment.
+func newPaidFixture(t *testing.T) (*paymentFixture, *domain.Order) {
+	t.Helper()
+	order := newPendingOrder(t)
+	order.Status = domain.OrderStatusPaid
+	f := newPaymentFixture(order)
+
+	intent := domain.NewPaymentIntent(order.ID, order.Total, domain.PaymentMethodCard, payment.FakeProviderName)
+	intent.Status = domain.PaymentStatusSucceeded
+	f.payments.Create(context.Background(), intent)
+
+	return f, order
+}
+
+func TestRefundLinesAtMostTheOrderedQuantity(t *testing.T) {
+	ctx := context.Background()
+	f, order := newPaidFixture(t)
+
+	_, refund, err := f.uc.Refund(ctx, order.ID, []domain.RefundLine{{ProductID: "milk", Quantity: 1}}, money.Money{}, "spilled")
+	if err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+	if refund.Amount != money.KZT(50000) || refund.Status != domain.RefundStatusCompleted {
+		t.Fatalf("refund = %s %s, want completed 500.00 KZT", refund.Status, refund.Amount)
+	}
+
+	if _, _, err := f.uc.Refund(ctx, order.ID, []domain.RefundLine{{ProductID: "milk", Quantity: 2}}, money.Money{}, ""); err == nil {
+		t.Fatal("refunded 3 of 2 ordered units")
+	}
+	if _, _, err := f.uc.Refund(ctx, order.ID, []domain.RefundLine{{ProductID: "bread", Quantity: 1}}, money.Money{}, ""); err == nil {
+		t.Fatal("refunded a product that is not on the order")
+	}
+	if _, _, err := f.uc.Refund(ctx, order.ID, []domain.RefundLine{{ProductID: "tea", Quantity: 1}}, money.KZT(100), ""); err == nil {
+		t.Fatal("refunded lines and an amount at once")
+	}
+}
+
+func TestRefundAmountsAtMostWhatIsCaptured(t *testing.T) {
+	ctx := context.Background()
+	f, order := newPaidFixture(t)
+
+	if _, _, err := f.uc.Refund(ctx, order.ID, nil, money.KZT(200001), ""); err == nil {
+		t.Fatal("refunded more than was captured")
+	}
+	if _, _, err := f.uc.Refund(ctx, order.ID, nil, money.New(100, "USD"), ""); err == nil {
+		t.Fatal("refunded in another currency")
+	}
+	if _, _, err := f.uc.Refund(ctx, order.ID, nil, money.KZT(-100), ""); err == nil {
+		t.Fatal("refunded a negative amount")
+	}
+
+	if _, _, err := f.uc.Refund(ctx, order.ID, nil, money.KZT(150000), "late"); err != nil {
+		t.Fatalf("partial Refund: %v", err)
+	}
+	if _, _, err := f.uc.Refund(ctx, order.ID, nil, money.KZT(50001), ""); err == nil {
+		t.Fatal("refunded more than is left after a partial refund")
+	}
+
+	updated, refund, err := f.uc.Refund(ctx, order.ID, nil, money.Money{}, "cancelled")
+	if err != nil {
+		t.Fatalf("Refund of the rest: %v", err)
+	}
+	if refund.Amount != money.KZT(50000) {
+		t.Fatalf("refund of the rest = %s, want 500.00 KZT", refund.Amount)
+	}
+	if updated.Status != domain.OrderStatusRefunded || f.orders.get(order.ID).Status != domain.OrderStatusRefunded {
+		t.Fatalf("order is %s, want refunded", f.orders.get(order.ID).Status)
+	}
+	if _, _, err := f.uc.Refund(ctx, order.ID, nil, money.Money{}, ""); err == nil {
+		t.Fatal("refunded a fully refunded order")
+	}
+}
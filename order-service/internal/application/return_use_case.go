@@ -0,0 +1,186 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/messaging"
+	"order-service/internal/infrastructure/persistence"
+	"proto/money"
+)
+
+type ReturnUseCase struct {
+	returnRepo     persistence.ReturnRepository
+	orderUseCase   *OrderUseCase
+	paymentUseCase *PaymentUseCase
+	eventPublisher messaging.EventPublisher
+}
+
+func NewReturnUseCase(returnRepo persistence.ReturnRepository, orderUseCase *OrderUseCase, paymentUseCase *PaymentUseCase, eventPublisher messaging.EventPublisher) *ReturnUseCase {
+	return &ReturnUseCase{
+		returnRepo:     returnRepo,
+		orderUseCase:   orderUseCase,
+		paymentUseCase: paymentUseCase,
+		eventPublisher: eventPublisher,
+	}
+}
+
+func (uc *ReturnUseCase) CreateReturn(ctx context.Context, orderID, userID string, lines []domain.ReturnLine, reason string, photos []string) (*domain.Return, error) {
+	order, err := uc.orderUseCase.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil || (userID != "" && order.UserID != userID) {
+		return nil, errors.New("order not found")
+	}
+
+	others, err := uc.returnRepo.List(ctx, persistence.ReturnListFilter{OrderID: order.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := domain.NewReturn(order, lines, reason, photos, others)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.returnRepo.Create(ctx, ret)
+}
+
+// GetReturn returns nil when the return does not exist or, if userID is set,
+// belongs to another user.
+func (uc *ReturnUseCase) GetReturn(ctx context.Context, id, userID string) (*domain.Return, error) {
+	ret, err := uc.returnRepo.GetByID(ctx, id)
+	if err != nil || ret == nil {
+		return nil, err
+	}
+	if userID != "" && ret.UserID != userID {
+		return nil, nil
+	}
+	return ret, nil
+}
+
+func (uc *ReturnUseCase) ListReturns(ctx context.Context, filter persistence.ReturnListFilter) ([]*domain.Return, error) {
+	return uc.returnRepo.List(ctx, filter)
+}
+
+func (uc *ReturnUseCase) ApproveReturn(ctx context.Context, id, note string) (*domain.Return, error) {
+	return uc.transition(ctx, id, func(ret *domain.Return) error {
+		return ret.Approve(note)
+	})
+}
+
+func (uc *ReturnUseCase) RejectReturn(ctx context.Context, id, note string) (*domain.Return, error) {
+	return uc.transition(ctx, id, func(ret *domain.Return) error {
+		return ret.Reject(note)
+	})
+}
+
+// ReceiveReturn checks the parcel in and publishes return.received so the
+// sellable items are restocked.
+func (uc *ReturnUseCase) ReceiveReturn(ctx context.Context, id string, unsellable []string, note string) (*domain.Return, error) {
+	ret, err := uc.transition(ctx, id, func(ret *domain.Return) error {
+		return ret.Receive(unsellable, note)
+	})
+	if err != nil || ret == nil {
+		return ret, err
+	}
+
+	go uc.publishReturnReceived(ret)
+
+	return ret, nil
+}
+
+// RefundReturn refunds the returned lines to the customer's payment. The
+// return moves to refunding first, so that concurrent calls do not both
+// refund it, and the refund is issued under the ID it got then. A call that
+// failed midway can be repeated: it finishes the same refund.
+func (uc *ReturnUseCase) RefundReturn(ctx context.Context, id string) (*domain.Return, error) {
+	ret, err := uc.returnRepo.GetByID(ctx, id)
+	if err != nil || ret == nil {
+		return nil, err
+	}
+	if ret.Status != domain.ReturnStatusRefunding {
+		if ret, err = uc.transition(ctx, id, (*domain.Return).StartRefund); err != nil || ret == nil {
+			return nil, err
+		}
+	}
+
+	lines := make([]domain.RefundLine, len(ret.Lines))
+	for i, line := range ret.Lines {
+		lines[i] = domain.RefundLine{
+			ProductID: line.ProductID,
+			Quantity:  line.Quantity,
+		}
+	}
+
+	_, refund, err := uc.paymentUseCase.RefundOnce(ctx, ret.RefundID, ret.OrderID, lines, money.Money{}, "return "+ret.ID+": "+ret.Reason)
+	if err != nil {
+		return nil, fmt.Errorf("return %s is still to be refunded: %w", ret.ID, err)
+	}
+
+	if err := ret.MarkRefunded(); err != nil {
+		return nil, err
+	}
+	ok, err := uc.returnRepo.Update(ctx, ret, domain.ReturnStatusRefunding)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// A concurrent call finished the same refund.
+		return uc.returnRepo.GetByID(ctx, ret.ID)
+	}
+
+	log.Printf("Return %s for order %s is refunded by refund %s", ret.ID, ret.OrderID, refund.ID)
+	return ret, nil
+}
+
+func (uc *ReturnUseCase) transition(ctx context.Context, id string, fn func(ret *domain.Return) error) (*domain.Return, error) {
+	ret, err := uc.returnRepo.GetByID(ctx, id)
+	if err != nil || ret == nil {
+		return nil, err
+	}
+
+	from := ret.Status
+	if err := fn(ret); err != nil {
+		return nil, err
+	}
+
+	ok, err := uc.returnRepo.Update(ctx, ret, from)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("return %s was changed concurrently, please retry", ret.ID)
+	}
+
+	log.Printf("Return %s for order %s is %s", ret.ID, ret.OrderID, ret.Status)
+	return ret, nil
+}
+
+func (uc *ReturnUseCase) publishReturnReceived(ret *domain.Return) {
+	items := make([]messaging.ReturnedItem, len(ret.Lines))
+	for i, line := range ret.Lines {
+		items[i] = messaging.ReturnedItem{
+			ProductID: line.ProductID,
+			Quantity:  line.Quantity,
+			Sellable:  line.Sellable,
+		}
+	}
+
+	event := messaging.ReturnReceivedEvent{
+		ReturnID:  ret.ID,
+		OrderID:   ret.OrderID,
+		UserID:    ret.UserID,
+		Items:     items,
+		Timestamp: time.Now().UnixNano(),
+	}
+
+	if err := uc.eventPublisher.PublishReturnReceived(event); err != nil {
+		log.Printf("Failed to publish return.received event for return %s: %v", ret.ID, err)
+	}
+}
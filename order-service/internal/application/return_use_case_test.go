@@ -0,0 +1,180 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/payment"
+	"order-service/internal/infrastructure/persistence"
+	"proto/money"
+)
+
+type fakeReturns struct {
+	mu      sync.Mutex
+	returns map[string]*domain.Return
+}
+
+func newFakeReturns() *fakeReturns {
+	return &fakeReturns{returns: make(map[string]*domain.Return)}
+}
+
+func cloneReturn(ret *domain.Return) *domain.Return {
+	clone := *ret
+	clone.Lines = append([]domain.ReturnLine(nil), ret.Lines...)
+	return &clone
+}
+
+func (r *fakeReturns) Create(ctx context.Context, ret *domain.Return) (*domain.Return, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.returns[ret.ID] = cloneReturn(ret)
+	return ret, nil
+}
+
+func (r *fakeReturns) GetByID(ctx context.Context, id string) (*domain.Return, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if ret, ok := r.returns[id]; ok {
+		return cloneReturn(ret), nil
+	}
+	return nil, nil
+}
+
+func (r *fakeReturns) List(ctx context.Context, filter persistence.ReturnListFilter) ([]*domain.Return, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var list []*domain.Return
+	for _, ret := range r.returns {
+		if filter.OrderID == "" || ret.OrderID == filter.OrderID {
+			list = append(list, cloneReturn(ret))
+		}
+	}
+	return list, nil
+}
+
+func (r *fakeReturns) Update(ctx context.Context, ret *domain.Return, from domain.ReturnStatus) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.returns[ret.ID]
+	if !ok || stored.Status != from {
+		return false, nil
+	}
+	r.returns[ret.ID] = cloneReturn(ret)
+	return true, nil
+}
+
+// newReturnFixture returns a received return of both milk units of a
+// delivered order captured by one card payment.
+func newReturnFixture(t *testing.T) (*ReturnUseCase, *paymentFixture, *fakeReturns, *domain.Return) {
+	t.Helper()
+	ctx := context.Background()
+	order := newPendingOrder(t)
+	order.Status = domain.OrderStatusCompleted
+	f := newPaymentFixture(order)
+
+	intent := domain.NewPaymentIntent(order.ID, order.Total, domain.PaymentMethodCard, payment.FakeProviderName)
+	intent.Status = domain.PaymentStatusSucceeded
+	f.payments.Create(ctx, intent)
+
+	returns := newFakeReturns()
+	uc := NewReturnUseCase(returns, f.uc.orderUseCase, f.uc, fakePublisher{})
+	ret, err := uc.CreateReturn(ctx, order.ID, order.UserID, []domain.ReturnLine{{ProductID: "milk", Quantity: 2}}, "spoiled", nil)
+	if err != nil {
+		t.Fatalf("CreateReturn: %v", err)
+	}
+	if _, err := uc.ApproveReturn(ctx, ret.ID, ""); err != nil {
+		t.Fatalf("ApproveReturn: %v", err)
+	}
+	if ret, err = returns.GetByID(ctx, ret.ID); err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if err := ret.Receive(nil, ""); err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+	returns.Update(ctx, ret, domain.ReturnStatusApproved)
+	return uc, f, returns, ret
+}
+
+func TestConcurrentRefundsOfAReturnRefundOnce(t *testing.T) {
+	ctx := context.Background()
+	uc, f, returns, ret := newReturnFixture(t)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := uc.RefundReturn(ctx, ret.ID); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	order := f.orders.get(ret.OrderID)
+	if len(order.Refunds) != 1 || order.RefundedQuantity("milk") != 2 {
+		t.Fatalf("order has %d refunds for %d milk, want one for 2", len(order.Refunds), order.RefundedQuantity("milk"))
+	}
+	if succeeded == 0 {
+		t.Fatal("no call refunded the return")
+	}
+	stored, _ := returns.GetByID(ctx, ret.ID)
+	if stored.Status != domain.ReturnStatusRefunded || stored.RefundID != order.Refunds[0].ID {
+		t.Fatalf("return is %s with refund %q, want refunded with %q", stored.Status, stored.RefundID, order.Refunds[0].ID)
+	}
+
+	if _, err := uc.RefundReturn(ctx, ret.ID); err == nil {
+		t.Fatal("refunded a refunded return")
+	}
+}
+
+func TestRefundReturnFinishesAnInterruptedRefund(t *testing.T) {
+	ctx := context.Background()
+	uc, f, returns, ret := newReturnFixture(t)
+
+	// The return moved to refunding and its refund was paid out, but the call
+	// stopped before the return was updated.
+	if err := ret.StartRefund(); err != nil {
+		t.Fatalf("StartRefund: %v", err)
+	}
+	returns.Update(ctx, ret, domain.ReturnStatusReceived)
+	if _, _, err := f.uc.RefundOnce(ctx, ret.RefundID, ret.OrderID, []domain.RefundLine{{ProductID: "milk", Quantity: 2}}, money.Money{}, "return"); err != nil {
+		t.Fatalf("RefundOnce: %v", err)
+	}
+
+	refunded, err := uc.RefundReturn(ctx, ret.ID)
+	if err != nil {
+		t.Fatalf("RefundReturn: %v", err)
+	}
+	if refunded.Status != domain.ReturnStatusRefunded {
+		t.Fatalf("return is %s, want refunded", refunded.Status)
+	}
+	if refunds := f.orders.get(ret.OrderID).Refunds; len(refunds) != 1 {
+		t.Fatalf("order has %d refunds, want 1", len(refunds))
+	}
+}
+
+func TestRefundReturnIssuesTheRefundOfAnInterruptedCall(t *testing.T) {
+	ctx := context.Background()
+	uc, f, returns, ret := newReturnFixture(t)
+
+	// The call stopped after the return moved to refunding.
+	if err := ret.StartRefund(); err != nil {
+		t.Fatalf("StartRefund: %v", err)
+	}
+	returns.Update(ctx, ret, domain.ReturnStatusReceived)
+
+	if _, err := uc.RefundReturn(ctx, ret.ID); err != nil {
+		t.Fatalf("RefundReturn: %v", err)
+	}
+	refunds := f.orders.get(ret.OrderID).Refunds
+	if len(refunds) != 1 || refunds[0].ID != ret.RefundID || refunds[0].Status != domain.RefundStatusCompleted {
+		t.Fatalf("refunds = %+v, want one completed refund %s", refunds, ret.RefundID)
+	}
+}
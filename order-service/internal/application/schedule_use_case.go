@@ -0,0 +1,233 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/inventory"
+	"order-service/internal/infrastructure/messaging"
+	"order-service/internal/infrastructure/persistence"
+)
+
+type ScheduleUseCase struct {
+	scheduleRepo   persistence.ScheduleRepository
+	orderUseCase   *OrderUseCase
+	catalog        inventory.ProductCatalog
+	eventPublisher messaging.EventPublisher
+}
+
+func NewScheduleUseCase(scheduleRepo persistence.ScheduleRepository, orderUseCase *OrderUseCase, catalog inventory.ProductCatalog, eventPublisher messaging.EventPublisher) *ScheduleUseCase {
+	return &ScheduleUseCase{
+		scheduleRepo:   scheduleRepo,
+		orderUseCase:   orderUseCase,
+		catalog:        catalog,
+		eventPublisher: eventPublisher,
+	}
+}
+
+func (uc *ScheduleUseCase) CreateSchedule(ctx context.Context, userID string, items []domain.ScheduleItem, address string, cadence domain.Cadence, startAt time.Time) (*domain.Schedule, error) {
+	schedule, err := domain.NewSchedule(userID, items, address, cadence, startAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.scheduleRepo.Create(ctx, schedule)
+}
+
+func (uc *ScheduleUseCase) GetSchedule(ctx context.Context, id, userID string) (*domain.Schedule, error) {
+	schedule, err := uc.scheduleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil || (userID != "" && schedule.UserID != userID) {
+		return nil, nil
+	}
+
+	return schedule, nil
+}
+
+func (uc *ScheduleUseCase) ListSchedules(ctx context.Context, userID string) ([]*domain.Schedule, error) {
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+
+	return uc.scheduleRepo.ListByUserID(ctx, userID)
+}
+
+func (uc *ScheduleUseCase) PauseSchedule(ctx context.Context, id, userID string) (*domain.Schedule, error) {
+	return uc.modify(ctx, id, userID, func(s *domain.Schedule) error { return s.Pause() })
+}
+
+func (uc *ScheduleUseCase) ResumeSchedule(ctx context.Context, id, userID string) (*domain.Schedule, error) {
+	return uc.modify(ctx, id, userID, func(s *domain.Schedule) error { return s.Resume(time.Now()) })
+}
+
+func (uc *ScheduleUseCase) SkipNext(ctx context.Context, id, userID string) (*domain.Schedule, error) {
+	return uc.modify(ctx, id, userID, func(s *domain.Schedule) error { return s.SkipNext() })
+}
+
+func (uc *ScheduleUseCase) CancelSchedule(ctx context.Context, id, userID string) (*domain.Schedule, error) {
+	return uc.modify(ctx, id, userID, func(s *domain.Schedule) error { return s.Cancel() })
+}
+
+func (uc *ScheduleUseCase) modify(ctx context.Context, id, userID string, fn func(s *domain.Schedule) error) (*domain.Schedule, error) {
+	schedule, err := uc.GetSchedule(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, errors.New("schedule not found")
+	}
+
+	lastUpdatedAt := schedule.UpdatedAt
+	if err := fn(schedule); err != nil {
+		return nil, err
+	}
+
+	ok, err := uc.scheduleRepo.Update(ctx, schedule, lastUpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("schedule %s was changed concurrently, please retry", schedule.ID)
+	}
+
+	return schedule, nil
+}
+
+// RunDue places the orders of every schedule that is due and returns how many
+// schedules were processed.
+func (uc *ScheduleUseCase) RunDue(ctx context.Context, now time.Time, lease time.Duration) (int, error) {
+	processed := 0
+	for {
+		schedule, err := uc.scheduleRepo.ClaimDue(ctx, now, now.Add(lease))
+		if err != nil {
+			return processed, err
+		}
+		if schedule == nil {
+			return processed, nil
+		}
+
+		uc.run(ctx, schedule, now)
+		processed++
+	}
+}
+
+// run places one scheduled order. Products that are gone or out of stock are
+// skipped; if nothing can be ordered the run is recorded as failed and the
+// schedule moves on. Other errors keep the run due and it is retried, see
+// fail. The order is placed under the run's key, so a retry after a crash
+// finds the order instead of placing a second one.
+func (uc *ScheduleUseCase) run(ctx context.Context, schedule *domain.Schedule, now time.Time) {
+	runAt := schedule.NextRunAt
+	schedule.LastRunAt = now
+
+	items, skipped, err := uc.priceItems(ctx, schedule)
+	if err == nil && len(items) == 0 {
+		uc.advance(schedule, now)
+		schedule.Failures = 0
+		schedule.LastError = "none of the scheduled products are available"
+		log.Printf("Schedule %s skipped: %s", schedule.ID, schedule.LastError)
+		if err := uc.scheduleRepo.CompleteRun(ctx, schedule, runAt); err != nil {
+			log.Printf("Failed to record run of schedule %s: %v", schedule.ID, err)
+		}
+		return
+	}
+
+	var order *domain.Order
+	if err == nil {
+		order, err = uc.orderUseCase.CreateOrderOnce(ctx, schedule.RunKey(), schedule.UserID, items, schedule.Address)
+	}
+	if err != nil {
+		uc.fail(ctx, schedule, runAt, now, err)
+		return
+	}
+
+	uc.advance(schedule, now)
+	schedule.LastOrderID = order.ID
+	schedule.LastError = ""
+	schedule.Failures = 0
+	if err := uc.scheduleRepo.CompleteRun(ctx, schedule, runAt); err != nil {
+		log.Printf("Failed to record run of schedule %s: %v", schedule.ID, err)
+	}
+
+	log.Printf("Schedule %s placed order %s, next run at %s", schedule.ID, order.ID, schedule.NextRunAt.Format(time.RFC3339))
+	go uc.publishScheduledOrderCreated(schedule, order, skipped)
+}
+
+// fail records a failed attempt. The run is retried after a delay that grows
+// with every failure, and given up after domain.MaxRunAttempts attempts, when
+// the schedule moves on to its next run.
+func (uc *ScheduleUseCase) fail(ctx context.Context, schedule *domain.Schedule, runAt, now time.Time, err error) {
+	schedule.Failures++
+	schedule.LastError = err.Error()
+
+	if schedule.Failures >= domain.MaxRunAttempts {
+		log.Printf("Scheduled order for schedule %s failed %d times, skipping this run: %v", schedule.ID, schedule.Failures, err)
+		uc.advance(schedule, now)
+		schedule.Failures = 0
+		if err := uc.scheduleRepo.CompleteRun(ctx, schedule, runAt); err != nil {
+			log.Printf("Failed to record run of schedule %s: %v", schedule.ID, err)
+		}
+		return
+	}
+
+	retryAt := now.Add(schedule.RetryDelay())
+	log.Printf("Scheduled order for schedule %s failed, will retry at %s: %v", schedule.ID, retryAt.Format(time.RFC3339), err)
+	if err := uc.scheduleRepo.RecordFailure(ctx, schedule, runAt, retryAt); err != nil {
+		log.Printf("Failed to record run of schedule %s: %v", schedule.ID, err)
+	}
+}
+
+func (uc *ScheduleUseCase) priceItems(ctx context.Context, schedule *domain.Schedule) ([]domain.OrderItem, []string, error) {
+	if uc.catalog == nil {
+		return nil, nil, errors.New("product catalog is unavailable")
+	}
+
+	var items []domain.OrderItem
+	var skipped []string
+	for _, item := range schedule.Items {
+		product, err := uc.catalog.GetProduct(ctx, item.ProductID)
+		if err != nil || product.Stock < item.Quantity {
+			skipped = append(skipped, item.ProductID)
+			continue
+		}
+		items = append(items, domain.OrderItem{
+			ProductID: product.ID,
+			Quantity:  item.Quantity,
+			Price:     product.Price,
+		})
+	}
+
+	return items, skipped, nil
+}
+
+// advance moves the schedule to its next run after now, so a worker that was
+// down for weeks does not place a burst of missed orders.
+func (uc *ScheduleUseCase) advance(schedule *domain.Schedule, now time.Time) {
+	schedule.NextRunAt = schedule.NextAfter(schedule.NextRunAt)
+	for !schedule.NextRunAt.After(now) {
+		schedule.NextRunAt = schedule.NextAfter(schedule.NextRunAt)
+	}
+}
+
+func (uc *ScheduleUseCase) publishScheduledOrderCreated(schedule *domain.Schedule, order *domain.Order, skipped []string) {
+	event := messaging.ScheduledOrderCreatedEvent{
+		ScheduleID:      schedule.ID,
+		OrderID:         order.ID,
+		UserID:          schedule.UserID,
+		Address:         schedule.Address,
+		Total:           order.Total,
+		SkippedProducts: skipped,
+		NextRunAt:       schedule.NextRunAt.Unix(),
+		Timestamp:       time.Now().UnixNano(),
+	}
+
+	if err := uc.eventPublisher.PublishScheduledOrderCreated(event); err != nil {
+		log.Printf("Failed to publish order.scheduled event for order %s: %v", order.ID, err)
+	}
+}
@@ -0,0 +1,287 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/inventory"
+	"order-service/internal/infrastructure/persistence"
+	"proto/money"
+)
+
+// fakeSchedules keeps schedules in memory and checks the same conditions as
+// the MongoDB repository.
+type fakeSchedules struct {
+	persistence.ScheduleRepository
+	mu        sync.Mutex
+	schedules map[string]*domain.Schedule
+	leases    map[string]time.Time
+}
+
+func newFakeSchedules(schedules ...*domain.Schedule) *fakeSchedules {
+	r := &fakeSchedules{
+		schedules: make(map[string]*domain.Schedule),
+		leases:    make(map[string]time.Time),
+	}
+	for _, schedule := range schedules {
+		clone := *schedule
+		r.schedules[schedule.ID] = &clone
+	}
+	return r
+}
+
+func (r *fakeSchedules) get(id string) *domain.Schedule {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clone := *r.schedules[id]
+	return &clone
+}
+
+func (r *fakeSchedules) GetByID(ctx context.Context, id string) (*domain.Schedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if schedule, ok := r.schedules[id]; ok {
+		clone := *schedule
+		return &clone, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeSchedules) Update(ctx context.Context, schedule *domain.Schedule, lastUpdatedAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.schedules[schedule.ID]
+	if !ok || !stored.UpdatedAt.Equal(lastUpdatedAt) {
+		return false, nil
+	}
+	stored.Status = schedule.Status
+	stored.NextRunAt = schedule.NextRunAt
+	stored.UpdatedAt = schedule.UpdatedAt
+	return true, nil
+}
+
+func (r *fakeSchedules) ClaimDue(ctx context.Context, now, leaseUntil time.Time) (*domain.Schedule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, schedule := range r.schedules {
+		if schedule.Status != domain.ScheduleStatusActive || schedule.NextRunAt.After(now) || r.leases[id].After(now) {
+			continue
+		}
+		r.leases[id] = leaseUntil
+		clone := *schedule
+		return &clone, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeSchedules) CompleteRun(ctx context.Context, schedule *domain.Schedule, runAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := r.schedules[schedule.ID]
+	r.recordOutcome(stored, schedule)
+	stored.LastOrderID = schedule.LastOrderID
+	stored.Failures = 0
+	if stored.NextRunAt.Equal(runAt) {
+		stored.NextRunAt = schedule.NextRunAt
+		stored.Failures = schedule.Failures
+	}
+	delete(r.leases, schedule.ID)
+	return nil
+}
+
+func (r *fakeSchedules) RecordFailure(ctx context.Context, schedule *domain.Schedule, runAt, retryAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := r.schedules[schedule.ID]
+	r.recordOutcome(stored, schedule)
+	if !stored.NextRunAt.Equal(runAt) {
+		stored.Failures = 0
+		delete(r.leases, schedule.ID)
+		return nil
+	}
+	stored.Failures = schedule.Failures
+	r.leases[schedule.ID] = retryAt
+	return nil
+}
+
+func (r *fakeSchedules) recordOutcome(stored, schedule *domain.Schedule) {
+	stored.LastRunAt = schedule.LastRunAt
+	stored.LastError = schedule.LastError
+	stored.UpdatedAt = time.Now()
+}
+
+// beforeLookupCatalog runs beforeLookup at the first product lookup.
+type beforeLookupCatalog struct {
+	*fakeCatalog
+	once         sync.Once
+	beforeLookup func()
+}
+
+func (c *beforeLookupCatalog) GetProduct(ctx context.Context, productID string) (*inventory.ProductInfo, error) {
+	c.once.Do(c.beforeLookup)
+	return c.fakeCatalog.GetProduct(ctx, productID)
+}
+
+type scheduleFixture struct {
+	schedule     *domain.Schedule
+	schedules    *fakeSchedules
+	orders       *fakeOrders
+	orderCatalog *fakeCatalog
+	uc           *ScheduleUseCase
+}
+
+// newScheduleFixture returns a weekly water schedule that is due now.
+func newScheduleFixture(t *testing.T) *scheduleFixture {
+	t.Helper()
+	schedule, err := domain.NewSchedule("user-1", []domain.ScheduleItem{{ProductID: "water", Quantity: 2}}, "Astana", domain.CadenceWeekly, time.Time{})
+	if err != nil {
+		t.Fatalf("NewSchedule: %v", err)
+	}
+
+	products := map[string]*inventory.ProductInfo{
+		"water": {ID: "water", Name: "Water", Price: money.KZT(90000), Stock: 100, TaxClass: domain.TaxClassStandard},
+	}
+	f := &scheduleFixture{
+		schedule:     schedule,
+		schedules:    newFakeSchedules(schedule),
+		orders:       newFakeOrders(),
+		orderCatalog: &fakeCatalog{products: products},
+	}
+	orderUseCase := NewOrderUseCase(f.orders, fakePublisher{}, nil, f.orderCatalog)
+	f.uc = NewScheduleUseCase(f.schedules, orderUseCase, &fakeCatalog{products: products}, fakePublisher{})
+	return f
+}
+
+func (f *scheduleFixture) runDue(t *testing.T, now time.Time) int {
+	t.Helper()
+	processed, err := f.uc.RunDue(context.Background(), now, time.Minute)
+	if err != nil {
+		t.Fatalf("RunDue: %v", err)
+	}
+	return processed
+}
+
+func TestScheduledRunPlacesOneOrderAndMovesOn(t *testing.T) {
+	f := newScheduleFixture(t)
+	runAt := f.schedule.NextRunAt
+	now := runAt.Add(time.Second)
+
+	if processed := f.runDue(t, now); processed != 1 {
+		t.Fatalf("processed %d schedules, want 1", processed)
+	}
+	stored := f.schedules.get(f.schedule.ID)
+	if f.orders.count() != 1 || stored.LastOrderID == "" {
+		t.Fatalf("placed %d orders, last order %q; want 1", f.orders.count(), stored.LastOrderID)
+	}
+	if want := runAt.AddDate(0, 0, 7); !stored.NextRunAt.Equal(want) {
+		t.Fatalf("next run at %s, want %s", stored.NextRunAt, want)
+	}
+
+	if processed := f.runDue(t, now); processed != 0 {
+		t.Fatalf("processed %d schedules again before the next run", processed)
+	}
+}
+
+func TestScheduledRunRetriedAfterACrashFindsItsOrder(t *testing.T) {
+	f := newScheduleFixture(t)
+	runAt := f.schedule.NextRunAt
+	now := runAt.Add(time.Second)
+	f.runDue(t, now)
+	placed := f.schedules.get(f.schedule.ID).LastOrderID
+
+	// The worker crashed before it recorded the run: the schedule is due
+	// again once the lease runs out.
+	f.schedules.mu.Lock()
+	f.schedules.schedules[f.schedule.ID].NextRunAt = runAt
+	f.schedules.schedules[f.schedule.ID].LastOrderID = ""
+	f.schedules.mu.Unlock()
+
+	f.runDue(t, now.Add(2*time.Minute))
+	if f.orders.count() != 1 {
+		t.Fatalf("placed %d orders for one run, want 1", f.orders.count())
+	}
+	if got := f.schedules.get(f.schedule.ID).LastOrderID; got != placed {
+		t.Fatalf("retried run recorded order %s, want %s", got, placed)
+	}
+}
+
+func TestFailedScheduledRunIsRetriedWithBackoffThenGivenUp(t *testing.T) {
+	f := newScheduleFixture(t)
+	f.orderCatalog.err = fmt.Errorf("%w: connection refused", inventory.ErrCatalogUnavailable)
+	runAt := f.schedule.NextRunAt
+	now := runAt.Add(time.Second)
+
+	for attempt := 1; attempt < domain.MaxRunAttempts; attempt++ {
+		if processed := f.runDue(t, now); processed != 1 {
+			t.Fatalf("attempt %d: processed %d schedules, want 1", attempt, processed)
+		}
+		stored := f.schedules.get(f.schedule.ID)
+		if stored.Failures != attempt || stored.LastError == "" || !stored.NextRunAt.Equal(runAt) {
+			t.Fatalf("attempt %d: failures %d, error %q, next run %s; want the run kept for a retry",
+				attempt, stored.Failures, stored.LastError, stored.NextRunAt)
+		}
+
+		delay := stored.RetryDelay()
+		if processed := f.runDue(t, now.Add(delay/2)); processed != 0 {
+			t.Fatalf("attempt %d: retried before the %s backoff", attempt, delay)
+		}
+		now = now.Add(delay)
+	}
+
+	f.runDue(t, now)
+	stored := f.schedules.get(f.schedule.ID)
+	if stored.Failures != 0 || stored.LastError == "" || !stored.NextRunAt.After(now) {
+		t.Fatalf("after %d attempts: failures %d, error %q, next run %s; want the run given up",
+			domain.MaxRunAttempts, stored.Failures, stored.LastError, stored.NextRunAt)
+	}
+	if f.orders.count() != 0 {
+		t.Fatalf("placed %d orders", f.orders.count())
+	}
+}
+
+func TestFailedScheduledRunSucceedsOnRetry(t *testing.T) {
+	f := newScheduleFixture(t)
+	f.orderCatalog.err = fmt.Errorf("%w: connection refused", inventory.ErrCatalogUnavailable)
+	runAt := f.schedule.NextRunAt
+	now := runAt.Add(time.Second)
+	f.runDue(t, now)
+
+	f.orderCatalog.err = nil
+	f.runDue(t, now.Add(time.Minute))
+	stored := f.schedules.get(f.schedule.ID)
+	if f.orders.count() != 1 || stored.Failures != 0 || stored.LastError != "" {
+		t.Fatalf("placed %d orders, failures %d, error %q; want the retry to succeed", f.orders.count(), stored.Failures, stored.LastError)
+	}
+	if want := runAt.AddDate(0, 0, 7); !stored.NextRunAt.Equal(want) {
+		t.Fatalf("next run at %s, want %s", stored.NextRunAt, want)
+	}
+}
+
+func TestSkipDuringARunIsKept(t *testing.T) {
+	f := newScheduleFixture(t)
+	runAt := f.schedule.NextRunAt
+	ctx := context.Background()
+
+	// While the run is placing its order, the customer skips the next two
+	// runs.
+	f.uc.catalog = &beforeLookupCatalog{fakeCatalog: f.uc.catalog.(*fakeCatalog), beforeLookup: func() {
+		for i := 0; i < 2; i++ {
+			if _, err := f.uc.SkipNext(ctx, f.schedule.ID, "user-1"); err != nil {
+				t.Errorf("SkipNext: %v", err)
+			}
+		}
+	}}
+	f.runDue(t, runAt.Add(time.Second))
+
+	stored := f.schedules.get(f.schedule.ID)
+	if want := runAt.AddDate(0, 0, 14); !stored.NextRunAt.Equal(want) {
+		t.Fatalf("next run at %s, want the skipped-to %s", stored.NextRunAt, want)
+	}
+	if f.orders.count() != 1 || stored.LastOrderID == "" {
+		t.Fatalf("placed %d orders, last order %q; want the run recorded", f.orders.count(), stored.LastOrderID)
+	}
+}
@@ -0,0 +1,270 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/inventory"
+	"order-service/internal/infrastructure/messaging"
+	"order-service/internal/infrastructure/persistence"
+	"proto/money"
+)
+
+// PaymentRefunder refunds part of a paid order at most once per refund ID.
+type PaymentRefunder interface {
+	RefundOnce(ctx context.Context, refundID, orderID string, lines []domain.RefundLine, amount money.Money, reason string) (*domain.Order, *domain.Refund, error)
+}
+
+type SubstitutionUseCase struct {
+	orderRepo      persistence.OrderRepository
+	orderUseCase   *OrderUseCase
+	catalog        inventory.ProductCatalog
+	eventPublisher messaging.EventPublisher
+	payments       PaymentRefunder
+	timeout        time.Duration
+}
+
+func NewSubstitutionUseCase(orderRepo persistence.OrderRepository, orderUseCase *OrderUseCase, catalog inventory.ProductCatalog, eventPublisher messaging.EventPublisher, payments PaymentRefunder, timeout time.Duration) *SubstitutionUseCase {
+	return &SubstitutionUseCase{
+		orderRepo:      orderRepo,
+		orderUseCase:   orderUseCase,
+		catalog:        catalog,
+		eventPublisher: eventPublisher,
+		payments:       payments,
+		timeout:        timeout,
+	}
+}
+
+func (uc *SubstitutionUseCase) SetPreference(ctx context.Context, orderID, userID, productID, preference, substituteProductID string) (*domain.Order, error) {
+	pref, err := domain.ParseSubstitutionPreference(preference)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := uc.getOrder(ctx, orderID, userID)
+	if err != nil || order == nil {
+		return nil, err
+	}
+	lastUpdatedAt := order.UpdatedAt
+
+	if err := order.SetSubstitutionPreference(productID, pref, substituteProductID); err != nil {
+		return nil, err
+	}
+	if err := uc.save(ctx, order, lastUpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// Propose records what the picker did about a missing product. An empty
+// substituteProductID drops the line. On a paid order the difference is
+// refunded once the line is resolved; a refund or event that fails is retried
+// by ExpireProposals.
+func (uc *SubstitutionUseCase) Propose(ctx context.Context, orderID, productID, substituteProductID string, quantity int) (*domain.Order, *domain.Substitution, error) {
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil || order == nil {
+		return nil, nil, err
+	}
+	lastUpdatedAt := order.UpdatedAt
+
+	var substitute *inventory.ProductInfo
+	if substituteProductID != "" {
+		if uc.catalog == nil {
+			return nil, nil, errors.New("inventory is unavailable, substitutes cannot be priced")
+		}
+		substitute, err = uc.catalog.GetProduct(ctx, substituteProductID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("substitute %s is not available: %w", substituteProductID, err)
+		}
+		if substitute.Stock < quantity {
+			return nil, nil, fmt.Errorf("insufficient stock for substitute %s: requested %d, available %d",
+				substituteProductID, quantity, substitute.Stock)
+		}
+	} else {
+		substitute = &inventory.ProductInfo{}
+	}
+
+	now := time.Now()
+	s, err := order.ProposeSubstitution(productID, substituteProductID, quantity,
+		substitute.Price, substitute.TaxClass, now, now.Add(uc.timeout))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := uc.save(ctx, order, lastUpdatedAt); err != nil {
+		return nil, nil, err
+	}
+
+	log.Printf("Substitution %s for product %s in order %s is %s", s.ID, productID, order.ID, s.Status)
+	uc.settle(ctx, order, s)
+	uc.publishResolved(order, s)
+
+	return order, s, nil
+}
+
+// Decide records the customer's answer to a proposed substitute.
+func (uc *SubstitutionUseCase) Decide(ctx context.Context, orderID, userID, substitutionID string, approve bool) (*domain.Order, *domain.Substitution, error) {
+	order, err := uc.getOrder(ctx, orderID, userID)
+	if err != nil || order == nil {
+		return nil, nil, err
+	}
+	lastUpdatedAt := order.UpdatedAt
+
+	s, err := order.DecideSubstitution(substitutionID, approve, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := uc.save(ctx, order, lastUpdatedAt); err != nil {
+		return nil, nil, err
+	}
+
+	log.Printf("Substitution %s in order %s is %s", s.ID, order.ID, s.Status)
+	uc.settle(ctx, order, s)
+	uc.publishResolved(order, s)
+
+	return order, s, nil
+}
+
+// ExpireProposals drops the lines of proposals the customers did not answer
+// in time. An order changed concurrently by another instance is skipped and
+// picked up on the next run. Refunds and substitution.proposed events that
+// failed earlier are retried afterwards.
+func (uc *SubstitutionUseCase) ExpireProposals(ctx context.Context, now time.Time) (int, error) {
+	orders, err := uc.orderRepo.ListWithExpiredSubstitutions(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, order := range orders {
+		lastUpdatedAt := order.UpdatedAt
+		substitutions, err := order.ExpireSubstitutions(now)
+		if err != nil {
+			log.Printf("Failed to expire substitutions of order %s: %v", order.ID, err)
+			continue
+		}
+		if len(substitutions) == 0 {
+			continue
+		}
+		if err := uc.save(ctx, order, lastUpdatedAt); err != nil {
+			log.Printf("Failed to expire substitutions of order %s: %v", order.ID, err)
+			continue
+		}
+		expired += len(substitutions)
+		for i := range substitutions {
+			uc.settle(ctx, order, &substitutions[i])
+		}
+	}
+
+	uc.settleOwed(ctx)
+	return expired, nil
+}
+
+// settleOwed retries the refunds and events still owed for substitutions.
+func (uc *SubstitutionUseCase) settleOwed(ctx context.Context) {
+	orders, err := uc.orderRepo.ListWithUnsettledSubstitutions(ctx)
+	if err != nil {
+		log.Printf("Failed to list orders with unsettled substitutions: %v", err)
+		return
+	}
+
+	for _, order := range orders {
+		for i := range order.Substitutions {
+			if order.Substitutions[i].Unsettled() {
+				uc.settle(ctx, order, &order.Substitutions[i])
+			}
+		}
+	}
+}
+
+// settle refunds the difference a substitution knocked off a paid order and
+// announces a proposed substitute. What fails stays owed and is retried by
+// settleOwed; the refund is idempotent under the substitution ID.
+func (uc *SubstitutionUseCase) settle(ctx context.Context, order *domain.Order, s *domain.Substitution) {
+	if !s.Unsettled() {
+		return
+	}
+
+	refunded := false
+	if s.RefundOwed {
+		_, _, err := uc.payments.RefundOnce(ctx, s.ID, order.ID, nil, s.RefundAmount,
+			fmt.Sprintf("substitution %s for product %s", s.ID, s.ProductID))
+		if err != nil {
+			log.Printf("Failed to refund substitution %s of order %s, will retry: %v", s.ID, order.ID, err)
+		} else {
+			refunded = true
+		}
+	}
+
+	published := false
+	if s.Unpublished {
+		if s.Status != domain.SubstitutionProposed {
+			published = true
+		} else if err := uc.publishSubstitutionProposed(order, s); err != nil {
+			log.Printf("Failed to publish %s event for order %s, will retry: %v", messaging.SubjectSubstitutionProposed, order.ID, err)
+		} else {
+			published = true
+		}
+	}
+
+	if err := uc.orderRepo.SettleSubstitution(ctx, order.ID, s.ID, refunded, published); err != nil {
+		log.Printf("Failed to settle substitution %s of order %s: %v", s.ID, order.ID, err)
+	}
+}
+
+func (uc *SubstitutionUseCase) getOrder(ctx context.Context, orderID, userID string) (*domain.Order, error) {
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil || order == nil {
+		return nil, err
+	}
+	if userID != "" && order.UserID != userID {
+		return nil, nil
+	}
+	return order, nil
+}
+
+func (uc *SubstitutionUseCase) save(ctx context.Context, order *domain.Order, lastUpdatedAt time.Time) error {
+	ok, err := uc.orderRepo.UpdateLines(ctx, order, lastUpdatedAt)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("order was changed concurrently, please retry")
+	}
+
+	uc.orderUseCase.invalidateUserOrders(ctx, order.UserID)
+	return nil
+}
+
+// publishResolved takes stock for an approved substitute. Stock of the missing
+// product is not returned since the picker could not find it.
+func (uc *SubstitutionUseCase) publishResolved(order *domain.Order, s *domain.Substitution) {
+	if s.Status != domain.SubstitutionApproved {
+		return
+	}
+
+	go uc.orderUseCase.publishOrderModifiedEvent(order, []domain.QuantityDelta{{
+		ProductID: s.SubstituteProductID,
+		Delta:     s.SubstituteQuantity,
+	}})
+}
+
+func (uc *SubstitutionUseCase) publishSubstitutionProposed(order *domain.Order, s *domain.Substitution) error {
+	event := messaging.SubstitutionProposedEvent{
+		OrderID:             order.ID,
+		UserID:              order.UserID,
+		SubstitutionID:      s.ID,
+		ProductID:           s.ProductID,
+		SubstituteProductID: s.SubstituteProductID,
+		Quantity:            s.SubstituteQuantity,
+		Price:               s.Price,
+		ExpiresAt:           s.ExpiresAt.Unix(),
+		Timestamp:           time.Now().UnixNano(),
+	}
+
+	return uc.eventPublisher.PublishSubstitutionProposed(event)
+}
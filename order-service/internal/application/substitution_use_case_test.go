@@ -0,0 +1,146 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/inventory"
+	"proto/money"
+)
+
+// flakyRefunder fails the first failures refunds and passes the rest on.
+type flakyRefunder struct {
+	PaymentRefunder
+	failures int
+}
+
+func (r *flakyRefunder) RefundOnce(ctx context.Context, refundID, orderID string, lines []domain.RefundLine, amount money.Money, reason string) (*domain.Order, *domain.Refund, error) {
+	if r.failures > 0 {
+		r.failures--
+		return nil, nil, errors.New("provider unavailable")
+	}
+	return r.PaymentRefunder.RefundOnce(ctx, refundID, orderID, lines, amount, reason)
+}
+
+type substitutionFixture struct {
+	*paymentFixture
+	order     *domain.Order
+	publisher *recordingPublisher
+	refunder  *flakyRefunder
+	uc        *SubstitutionUseCase
+}
+
+// newSubstitutionFixture returns the paid order of newPaidFixture with
+// oat milk at 450.00 KZT in stock as a substitute for its milk.
+func newSubstitutionFixture(t *testing.T) *substitutionFixture {
+	t.Helper()
+	payments, order := newPaidFixture(t)
+	f := &substitutionFixture{
+		paymentFixture: payments,
+		order:          order,
+		publisher:      &recordingPublisher{},
+		refunder:       &flakyRefunder{PaymentRefunder: payments.uc},
+	}
+	catalog := &fakeCatalog{products: map[string]*inventory.ProductInfo{
+		"oat-milk": {ID: "oat-milk", Price: money.KZT(45000), Stock: 10, TaxClass: domain.TaxClassStandard},
+	}}
+	orderUseCase := NewOrderUseCase(f.orders, f.publisher, nil, nil)
+	f.uc = NewSubstitutionUseCase(f.orders, orderUseCase, catalog, f.publisher, f.refunder, time.Hour)
+	return f
+}
+
+func (f *substitutionFixture) substitution(t *testing.T, id string) domain.Substitution {
+	t.Helper()
+	for _, s := range f.orders.get(f.order.ID).Substitutions {
+		if s.ID == id {
+			return s
+		}
+	}
+	t.Fatalf("substitution %s not stored", id)
+	return domain.Substitution{}
+}
+
+func TestApprovedSubstituteRefundsTheDifferenceOfAPaidOrder(t *testing.T) {
+	ctx := context.Background()
+	f := newSubstitutionFixture(t)
+
+	_, proposed, err := f.uc.Propose(ctx, f.order.ID, "milk", "oat-milk", 2)
+	if err != nil {
+		t.Fatalf("Propose: %v", err)
+	}
+	if len(f.publisher.proposed) != 1 || f.substitution(t, proposed.ID).Unpublished {
+		t.Fatalf("published %d substitution.proposed events, want the one stored as published", len(f.publisher.proposed))
+	}
+
+	order, s, err := f.uc.Decide(ctx, f.order.ID, f.order.UserID, proposed.ID, true)
+	if err != nil {
+		t.Fatalf("Decide: %v", err)
+	}
+	if order.Total != money.KZT(190000) {
+		t.Fatalf("total = %v, want 1900.00 KZT", order.Total)
+	}
+
+	refund := f.orders.get(f.order.ID).FindRefund(s.ID)
+	if refund == nil || refund.Amount != money.KZT(10000) || refund.Status != domain.RefundStatusCompleted {
+		t.Fatalf("refund = %+v, want 100.00 KZT completed under the substitution ID", refund)
+	}
+	if f.substitution(t, s.ID).RefundOwed {
+		t.Fatal("the refund is still owed after it completed")
+	}
+}
+
+func TestPricierSubstituteForAPaidOrderIsRefused(t *testing.T) {
+	f := newSubstitutionFixture(t)
+
+	if _, _, err := f.uc.Propose(context.Background(), f.order.ID, "milk", "oat-milk", 3); err == nil {
+		t.Fatal("3 × 450.00 KZT was accepted for 2 × 500.00 KZT already paid")
+	}
+	if stored := f.orders.get(f.order.ID); len(stored.Substitutions) != 0 || len(stored.Refunds) != 0 {
+		t.Fatalf("a refused substitute changed the order: %+v", stored)
+	}
+}
+
+func TestFailedSubstitutionRefundAndEventAreRetried(t *testing.T) {
+	ctx := context.Background()
+	f := newSubstitutionFixture(t)
+	f.publisher.proposedFailures = 1
+	f.refunder.failures = 1
+
+	_, proposed, err := f.uc.Propose(ctx, f.order.ID, "milk", "oat-milk", 2)
+	if err != nil {
+		t.Fatalf("Propose with NATS down: %v", err)
+	}
+	if len(f.publisher.proposed) != 0 || !f.substitution(t, proposed.ID).Unpublished {
+		t.Fatal("a failed substitution.proposed event is not kept for a retry")
+	}
+
+	if _, err := f.uc.ExpireProposals(ctx, time.Now()); err != nil {
+		t.Fatalf("ExpireProposals: %v", err)
+	}
+	if len(f.publisher.proposed) != 1 || f.substitution(t, proposed.ID).Unpublished {
+		t.Fatalf("published %d substitution.proposed events on retry, want 1", len(f.publisher.proposed))
+	}
+
+	if _, _, err := f.uc.Decide(ctx, f.order.ID, f.order.UserID, proposed.ID, false); err != nil {
+		t.Fatalf("Decide with the provider down: %v", err)
+	}
+	if s := f.substitution(t, proposed.ID); !s.RefundOwed || s.RefundAmount != money.KZT(100000) {
+		t.Fatalf("refund owed %v for %v, want 1000.00 KZT kept for a retry", s.RefundOwed, s.RefundAmount)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := f.uc.ExpireProposals(ctx, time.Now()); err != nil {
+			t.Fatalf("ExpireProposals: %v", err)
+		}
+	}
+	stored := f.orders.get(f.order.ID)
+	if len(stored.Refunds) != 1 || stored.Refunds[0].Amount != money.KZT(100000) || stored.Refunds[0].Status != domain.RefundStatusCompleted {
+		t.Fatalf("refunds after retries = %+v, want one of 1000.00 KZT", stored.Refunds)
+	}
+	if f.substitution(t, proposed.ID).Unsettled() {
+		t.Fatal("the substitution is still unsettled after the retry")
+	}
+}
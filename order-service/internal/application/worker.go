@@ -0,0 +1,105 @@
+package application
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// PeriodicWorker runs a background job on a fixed interval until stopped.
+// The job returns how many items it processed.
+type PeriodicWorker struct {
+	name     string
+	interval time.Duration
+	job      func(ctx context.Context, now time.Time) (int, error)
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+func NewPeriodicWorker(name string, interval time.Duration, job func(ctx context.Context, now time.Time) (int, error)) *PeriodicWorker {
+	return &PeriodicWorker{
+		name:     name,
+		interval: interval,
+		job:      job,
+	}
+}
+
+// NewScheduleWorker places the orders of due schedules.
+func NewScheduleWorker(scheduleUseCase *ScheduleUseCase, interval, lease time.Duration) *PeriodicWorker {
+	return NewPeriodicWorker("Schedule", interval, func(ctx context.Context, now time.Time) (int, error) {
+		return scheduleUseCase.RunDue(ctx, now, lease)
+	})
+}
+
+// NewExpiryWorker cancels orders that stayed unpaid for longer than ttl,
+// together with their open payments.
+func NewExpiryWorker(orderUseCase *OrderUseCase, payments PaymentCanceller, ttl, interval, lease time.Duration) *PeriodicWorker {
+	return NewPeriodicWorker("Expiry", interval, func(ctx context.Context, now time.Time) (int, error) {
+		return orderUseCase.ExpirePendingOrders(ctx, now, ttl, lease, payments)
+	})
+}
+
+// NewOrderCreatedWorker publishes the order.created events that failed when
+// their orders were placed.
+func NewOrderCreatedWorker(orderUseCase *OrderUseCase, interval, lease time.Duration) *PeriodicWorker {
+	return NewPeriodicWorker("OrderCreated", interval, func(ctx context.Context, now time.Time) (int, error) {
+		return orderUseCase.RepublishCreatedEvents(ctx, now, interval, lease)
+	})
+}
+
+// NewReceiptWorker resubmits fiscal receipts the OFD has not registered yet.
+func NewReceiptWorker(receiptUseCase *ReceiptUseCase, interval, lease time.Duration) *PeriodicWorker {
+	return NewPeriodicWorker("Receipt", interval, func(ctx context.Context, now time.Time) (int, error) {
+		return receiptUseCase.RetryPending(ctx, now, lease)
+	})
+}
+
+// NewSubstitutionWorker drops lines whose substitutes were not answered in
+// time and retries the refunds and events still owed for substitutions.
+func NewSubstitutionWorker(substitutionUseCase *SubstitutionUseCase, interval time.Duration) *PeriodicWorker {
+	return NewPeriodicWorker("Substitution", interval, substitutionUseCase.ExpireProposals)
+}
+
+func (w *PeriodicWorker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		log.Printf("%s worker started, running every %v", w.name, w.interval)
+		for {
+			w.tick(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (w *PeriodicWorker) tick(ctx context.Context) {
+	processed, err := w.job(ctx, time.Now())
+	if err != nil {
+		log.Printf("%s worker error: %v", w.name, err)
+	}
+	if processed > 0 {
+		log.Printf("%s worker processed %d items", w.name, processed)
+	}
+}
+
+// Stop waits for the current run to finish.
+func (w *PeriodicWorker) Stop() {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+	w.wg.Wait()
+	log.Printf("%s worker stopped", w.name)
+}
@@ -1,5 +1,10 @@
 package config
 
+import (
+	"errors"
+	"os"
+)
+
 type ServerConfig struct {
 	Port string `yaml:"port"`
 }
@@ -13,6 +18,12 @@ type MongoDBConfig struct {
 type NATSConfig struct {
 	URL     string `yaml:"url"`
 	Cluster string `yaml:"cluster"`
+	// RetryInterval between attempts to publish order.created events that
+	// failed, in seconds.
+	RetryInterval int `yaml:"retry_interval"`
+	// RetryLease is how long a claimed order stays locked to one worker, in
+	// seconds.
+	RetryLease int `yaml:"retry_lease"`
 }
 
 type RedisConfig struct {
@@ -23,15 +34,108 @@ type RedisConfig struct {
 	TTL      int    `yaml:"ttl"`
 }
 
+type ServicesConfig struct {
+	Inventory string `yaml:"inventory"`
+}
+
+type CartConfig struct {
+	// TTL is how long an untouched cart is kept, in seconds.
+	TTL int `yaml:"ttl"`
+}
+
+type SchedulerConfig struct {
+	// Interval between checks for due schedules, in seconds.
+	Interval int `yaml:"interval"`
+	// Lease is how long a claimed schedule stays locked to one worker, in
+	// seconds.
+	Lease int `yaml:"lease"`
+}
+
+type ExpiryConfig struct {
+	// TTL is how long an order may stay pending before it is cancelled, in
+	// seconds.
+	TTL int `yaml:"ttl"`
+	// Interval between checks for expired orders, in seconds.
+	Interval int `yaml:"interval"`
+	// Lease is how long a claimed order stays locked to one worker, in
+	// seconds.
+	Lease int `yaml:"lease"`
+}
+
+type SubstitutionConfig struct {
+	// Timeout is how long the customer has to answer a proposed substitute,
+	// in seconds. Unanswered proposals are treated as rejected.
+	Timeout int `yaml:"timeout"`
+	// Interval between checks for unanswered proposals, in seconds.
+	Interval int `yaml:"interval"`
+}
+
+// PaymentConfig selects the payment provider. WebhookSecret signs the
+// provider's callbacks; outside development it must come from
+// PAYMENT_WEBHOOK_SECRET.
+type PaymentConfig struct {
+	Provider      string `yaml:"provider"`
+	WebhookSecret string `yaml:"webhook_secret"`
+	BaseURL       string `yaml:"base_url"`
+}
+
+// FiscalConfig describes the OFD connection and the registered cash register
+// receipts are issued from.
+type FiscalConfig struct {
+	Provider              string `yaml:"provider"`
+	QRBaseURL             string `yaml:"qr_base_url"`
+	KKMRegistrationNumber string `yaml:"kkm_registration_number"`
+	CashierName           string `yaml:"cashier_name"`
+	SellerBIN             string `yaml:"seller_bin"`
+	SellerName            string `yaml:"seller_name"`
+	// RetryInterval between checks for receipts the OFD has not registered
+	// yet, in seconds.
+	RetryInterval int `yaml:"retry_interval"`
+	// RetryLease is how long a claimed receipt stays locked to one worker, in
+	// seconds.
+	RetryLease int `yaml:"retry_lease"`
+}
+
+// InvoiceConfig holds the company details printed on invoices.
+type InvoiceConfig struct {
+	CompanyName string `yaml:"company_name"`
+	CompanyBIN  string `yaml:"company_bin"`
+	Address     string `yaml:"address"`
+	BankName    string `yaml:"bank_name"`
+	IBAN        string `yaml:"iban"`
+	BIK         string `yaml:"bik"`
+}
+
 type Config struct {
-	Server  ServerConfig  `yaml:"server"`
-	MongoDB MongoDBConfig `yaml:"mongodb"`
-	NATS    NATSConfig    `yaml:"nats"`
-	Redis   RedisConfig   `yaml:"redis"`
+	// DevMode is set with APP_ENV=development and allows well-known local
+	// secrets.
+	DevMode      bool               `yaml:"dev_mode"`
+	Server       ServerConfig       `yaml:"server"`
+	MongoDB      MongoDBConfig      `yaml:"mongodb"`
+	NATS         NATSConfig         `yaml:"nats"`
+	Redis        RedisConfig        `yaml:"redis"`
+	Services     ServicesConfig     `yaml:"services"`
+	Cart         CartConfig         `yaml:"cart"`
+	Scheduler    SchedulerConfig    `yaml:"scheduler"`
+	Expiry       ExpiryConfig       `yaml:"expiry"`
+	Substitution SubstitutionConfig `yaml:"substitution"`
+	Payment      PaymentConfig      `yaml:"payment"`
+	Fiscal       FiscalConfig       `yaml:"fiscal"`
+	Invoice      InvoiceConfig      `yaml:"invoice"`
 }
 
+// devWebhookSecret signs fake provider callbacks in development only.
+const devWebhookSecret = "local-webhook-secret"
+
 func LoadConfig() *Config {
+	devMode := os.Getenv("APP_ENV") == "development"
+	webhookSecret := os.Getenv("PAYMENT_WEBHOOK_SECRET")
+	if webhookSecret == "" && devMode {
+		webhookSecret = devWebhookSecret
+	}
+
 	return &Config{
+		DevMode: devMode,
 		Server: ServerConfig{
 			Port: "50052",
 		},
@@ -41,8 +145,10 @@ func LoadConfig() *Config {
 			Timeout:  10,
 		},
 		NATS: NATSConfig{
-			URL:     "nats://localhost:4222",
-			Cluster: "microservices",
+			URL:           "nats://localhost:4222",
+			Cluster:       "microservices",
+			RetryInterval: 30,
+			RetryLease:    60,
 		},
 		Redis: RedisConfig{
 			Host:     "localhost",
@@ -51,5 +157,52 @@ func LoadConfig() *Config {
 			DB:       0,
 			TTL:      300,
 		},
+		Services: ServicesConfig{
+			Inventory: "localhost:50051",
+		},
+		Cart: CartConfig{
+			TTL: 7 * 24 * 60 * 60,
+		},
+		Scheduler: SchedulerConfig{
+			Interval: 60,
+			Lease:    300,
+		},
+		Expiry: ExpiryConfig{
+			TTL:      30 * 60,
+			Interval: 60,
+			Lease:    120,
+		},
+		Substitution: SubstitutionConfig{
+			Timeout:  10 * 60,
+			Interval: 30,
+		},
+		Payment: PaymentConfig{
+			Provider:      "fake",
+			WebhookSecret: webhookSecret,
+			BaseURL:       "http://localhost:8080/fake-pay",
+		},
+		Fiscal: FiscalConfig{
+			Provider:              "fake",
+			QRBaseURL:             "http://consumer.oofd.kz",
+			KKMRegistrationNumber: "010100000000",
+			CashierName:           "Online store",
+			SellerBIN:             "000000000000",
+			SellerName:            "KazakhDelivery",
+			RetryInterval:         60,
+			RetryLease:            120,
+		},
+		Invoice: InvoiceConfig{
+			CompanyName: "KazakhDelivery",
+			CompanyBIN:  "000000000000",
+			Address:     "Astana, Kazakhstan",
+		},
+	}
+}
+
+// Validate rejects configurations that are only safe for local runs.
+func (c *Config) Validate() error {
+	if c.Payment.WebhookSecret == "" {
+		return errors.New("PAYMENT_WEBHOOK_SECRET is required unless APP_ENV=development")
 	}
+	return nil
 }
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"proto/money"
+)
+
+// CartOwner identifies a cart: a signed-in user or an anonymous session.
+type CartOwner struct {
+	UserID    string
+	SessionID string
+}
+
+func (o CartOwner) Validate() error {
+	if o.UserID == "" && o.SessionID == "" {
+		return errors.New("user ID or session ID is required")
+	}
+	return nil
+}
+
+func (o CartOwner) IsAnonymous() bool {
+	return o.UserID == ""
+}
+
+type CartItem struct {
+	ProductID string
+	Quantity  int
+	// Price is the unit price the customer last saw.
+	Price   money.Money
+	AddedAt time.Time
+}
+
+type Cart struct {
+	Owner     CartOwner
+	Items     []CartItem
+	UpdatedAt time.Time
+}
+
+func NewCart(owner CartOwner) *Cart {
+	return &Cart{
+		Owner:     owner,
+		UpdatedAt: time.Now(),
+	}
+}
+
+func (c *Cart) IsEmpty() bool {
+	return len(c.Items) == 0
+}
+
+// AddItem adds units of a product, increasing the quantity when the product
+// is already in the cart.
+func (c *Cart) AddItem(productID string, quantity int, price money.Money) {
+	defer c.touch()
+
+	for i := range c.Items {
+		if c.Items[i].ProductID == productID {
+			c.Items[i].Quantity += quantity
+			c.Items[i].Price = price
+			return
+		}
+	}
+	c.Items = append(c.Items, CartItem{
+		ProductID: productID,
+		Quantity:  quantity,
+		Price:     price,
+		AddedAt:   time.Now(),
+	})
+}
+
+// SetQuantity changes the quantity of a product; zero removes it. It reports
+// whether the product was in the cart.
+func (c *Cart) SetQuantity(productID string, quantity int) bool {
+	if quantity == 0 {
+		return c.RemoveItem(productID)
+	}
+
+	for i := range c.Items {
+		if c.Items[i].ProductID == productID {
+			c.Items[i].Quantity = quantity
+			c.touch()
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Cart) RemoveItem(productID string) bool {
+	for i := range c.Items {
+		if c.Items[i].ProductID == productID {
+			c.Items = append(c.Items[:i], c.Items[i+1:]...)
+			c.touch()
+			return true
+		}
+	}
+	return false
+}
+
+// Merge adds the items of another cart, summing quantities of products that
+// are in both.
+func (c *Cart) Merge(other *Cart) {
+	for _, item := range other.Items {
+		merged := false
+		for i := range c.Items {
+			if c.Items[i].ProductID == item.ProductID {
+				c.Items[i].Quantity += item.Quantity
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			c.Items = append(c.Items, item)
+		}
+	}
+	c.touch()
+}
+
+// Restore puts back the items of a cart taken for a checkout that failed.
+// Products the customer added again in the meantime keep their new quantity.
+func (c *Cart) Restore(taken *Cart) {
+	for _, item := range taken.Items {
+		present := false
+		for i := range c.Items {
+			if c.Items[i].ProductID == item.ProductID {
+				present = true
+				break
+			}
+		}
+		if !present {
+			c.Items = append(c.Items, item)
+		}
+	}
+	c.touch()
+}
+
+func (c *Cart) touch() {
+	c.UpdatedAt = time.Now()
+}
+
+// CartLine is a cart item re-priced against the current inventory.
+type CartLine struct {
+	ProductID      string
+	Name           string
+	Quantity       int
+	UnitPrice      money.Money
+	LineTotal      money.Money
+	AvailableStock int
+	OutOfStock     bool
+	Unavailable    bool
+	PriceChanged   bool
+	PreviousPrice  money.Money
+}
+
+type PricedCart struct {
+	Owner     CartOwner
+	Lines     []CartLine
+	Total     money.Money
+	HasIssues bool
+	UpdatedAt time.Time
+	ExpiresAt time.Time
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+
+	"proto/money"
+)
+
+// OrderLineChange sets the quantity of a product in an order. A zero quantity
+// removes the line; a product not yet in the order is added with Price and
+// TaxClass.
+type OrderLineChange struct {
+	ProductID string
+	Quantity  int
+	Price     money.Money
+	TaxClass  TaxClass
+}
+
+// QuantityDelta is how much more (positive) or less (negative) of a product an
+// order holds after a modification.
+type QuantityDelta struct {
+	ProductID string
+	Delta     int
+}
+
+// IsModifiable reports whether the order lines may still change: only while
+// the order is pending or confirmed, so not once it is paid, dispatched,
+// cancelled or expired.
+func (s OrderStatus) IsModifiable() bool {
+	return s == OrderStatusPending || s == OrderStatusConfirmed
+}
+
+// Modify applies the changes, recomputes the totals and returns the quantity
+// deltas per product in the order the changes were given.
+func (o *Order) Modify(changes []OrderLineChange) ([]QuantityDelta, error) {
+	if !o.Status.IsModifiable() {
+		return nil, fmt.Errorf("order %s cannot be modified in status %s", o.ID, o.Status)
+	}
+	if len(changes) == 0 {
+		return nil, errors.New("no changes given")
+	}
+
+	seen := make(map[string]bool, len(changes))
+	items := append([]OrderItem(nil), o.Items...)
+	var deltas []QuantityDelta
+	for _, change := range changes {
+		if change.ProductID == "" {
+			return nil, errors.New("product id is required")
+		}
+		if change.Quantity < 0 {
+			return nil, fmt.Errorf("invalid quantity for product %s", change.ProductID)
+		}
+		if seen[change.ProductID] {
+			return nil, fmt.Errorf("product %s is changed more than once", change.ProductID)
+		}
+		seen[change.ProductID] = true
+
+		idx := -1
+		for i := range items {
+			if items[i].ProductID == change.ProductID {
+				idx = i
+				break
+			}
+		}
+
+		var delta int
+		switch {
+		case idx < 0 && change.Quantity == 0:
+			return nil, fmt.Errorf("product %s is not in the order", change.ProductID)
+		case idx < 0:
+			if len(items) > 0 && !change.Price.SameCurrency(items[0].Price) {
+				return nil, errors.New("all order items must be priced in the same currency")
+			}
+			items = append(items, OrderItem{
+				ProductID: change.ProductID,
+				Quantity:  change.Quantity,
+				Price:     change.Price,
+				TaxClass:  change.TaxClass,
+			})
+			delta = change.Quantity
+		case change.Quantity == 0:
+			delta = -items[idx].Quantity
+			items = append(items[:idx], items[idx+1:]...)
+		default:
+			delta = change.Quantity - items[idx].Quantity
+			items[idx].Quantity = change.Quantity
+		}
+
+		if delta != 0 {
+			deltas = append(deltas, QuantityDelta{ProductID: change.ProductID, Delta: delta})
+		}
+	}
+
+	if len(items) == 0 {
+		return nil, errors.New("an order must keep at least one item; cancel it instead")
+	}
+
+	o.Items = items
+	if err := o.CalculateTotals(); err != nil {
+		return nil, err
+	}
+	o.UpdateStatus(o.Status)
+
+	return deltas, nil
+}
@@ -1,54 +1,178 @@
 package domain
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
+	"proto/money"
+
 	"github.com/google/uuid"
 )
 
 type OrderStatus string
 
+// A dispatched order is on its way with a courier; a completed one was
+// delivered to the customer.
 const (
-	OrderStatusPending   OrderStatus = "pending"
-	OrderStatusCompleted OrderStatus = "completed"
-	OrderStatusCancelled OrderStatus = "cancelled"
+	OrderStatusPending    OrderStatus = "pending"
+	OrderStatusConfirmed  OrderStatus = "confirmed"
+	OrderStatusPaid       OrderStatus = "paid"
+	OrderStatusDispatched OrderStatus = "dispatched"
+	OrderStatusCompleted  OrderStatus = "completed"
+	OrderStatusCancelled  OrderStatus = "cancelled"
+	OrderStatusRefunded   OrderStatus = "refunded"
 )
 
+// ErrStatusTransition is returned for a status change the order lifecycle
+// does not allow.
+var ErrStatusTransition = errors.New("order status cannot change")
+
+// orderTransitions lists where an order may go from each status. Cancelled
+// and refunded orders are final.
+//
+// UpdateOrder used to store any status. Since the lifecycle is enforced it
+// refuses moves that skip payment, such as pending or confirmed to dispatched
+// or completed, moves back, such as paid to pending, and any move out of
+// cancelled or refunded; an order that must skip ahead is paid first.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:    {OrderStatusConfirmed, OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusConfirmed:  {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:       {OrderStatusDispatched, OrderStatusCompleted, OrderStatusRefunded},
+	OrderStatusDispatched: {OrderStatusCompleted},
+	OrderStatusCompleted:  {OrderStatusRefunded},
+}
+
+// CanBecome reports whether an order may move from s to next. Keeping the
+// same status is allowed, so repeated updates are harmless.
+func (s OrderStatus) CanBecome(next OrderStatus) bool {
+	if s == next {
+		return true
+	}
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPayable reports whether a payment may be started for an order in status
+// s, that is whether it may still become paid.
+func (s OrderStatus) IsPayable() bool {
+	return s != OrderStatusPaid && s.CanBecome(OrderStatusPaid)
+}
+
 type OrderItem struct {
-	ProductID string
-	Quantity  int
-	Price     float64
+	ProductID   string
+	Quantity    int
+	Price       money.Money
+	TaxClass    TaxClass
+	TaxRate     int
+	NetAmount   money.Money
+	TaxAmount   money.Money
+	GrossAmount money.Money
+	// Substitution is empty until the customer picks a preference.
+	Substitution        SubstitutionPreference
+	SubstituteProductID string
 }
 
 type Order struct {
-	ID        string
-	UserID    string
-	Items     []OrderItem
-	Total     float64
-	Status    OrderStatus
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID            string
+	UserID        string
+	Items         []OrderItem
+	Address       string
+	NetTotal      money.Money
+	TaxTotal      money.Money
+	Total         money.Money
+	TaxBreakdown  []TaxLine
+	Refunds       []Refund
+	Receipts      []OrderReceipt
+	Substitutions []Substitution
+	Status        OrderStatus
+	ExpiredAt     time.Time
+	// IdempotencyKey is set on orders placed by the system, such as
+	// scheduled runs, so that a retried run finds its order instead of
+	// placing another one.
+	IdempotencyKey string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
-func NewOrder(userID string, items []OrderItem, status OrderStatus) *Order {
+func NewOrder(userID string, items []OrderItem, status OrderStatus) (*Order, error) {
 	if status == "" {
 		status = OrderStatusPending
 	}
 
-	var total float64
-	for _, item := range items {
-		total += item.Price * float64(item.Quantity)
-	}
-
-	return &Order{
+	order := &Order{
 		ID:        uuid.New().String(),
 		UserID:    userID,
 		Items:     items,
-		Total:     total,
 		Status:    status,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
+	if err := order.CalculateTotals(); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// CalculateTotals recomputes the net, tax and gross amounts of every line and
+// of the whole order from the VAT-inclusive unit prices and tax classes. All
+// lines must be priced in the same currency.
+func (o *Order) CalculateTotals() error {
+	currency := money.DefaultCurrency
+	if len(o.Items) > 0 && o.Items[0].Price.Currency != "" {
+		currency = o.Items[0].Price.Currency
+	}
+	for _, item := range o.Items {
+		if !item.Price.SameCurrency(money.New(0, currency)) {
+			return fmt.Errorf("product %s is priced in %s, not %s: %w",
+				item.ProductID, item.Price.Currency, currency, money.ErrCurrencyMismatch)
+		}
+	}
+
+	o.NetTotal = money.New(0, currency)
+	o.TaxTotal = money.New(0, currency)
+	o.Total = money.New(0, currency)
+	o.TaxBreakdown = nil
+
+	// Every amount below derives from a price in the order's currency, so the
+	// minor units can be summed directly.
+	byRate := make(map[int]int)
+	for i := range o.Items {
+		item := &o.Items[i]
+		if item.TaxClass == "" {
+			item.TaxClass = TaxClassStandard
+		}
+		item.TaxRate = item.TaxClass.Rate()
+		item.GrossAmount = item.Price.Mul(item.Quantity)
+		item.NetAmount, item.TaxAmount = splitGross(item.GrossAmount, item.TaxRate)
+
+		o.NetTotal.Amount += item.NetAmount.Amount
+		o.TaxTotal.Amount += item.TaxAmount.Amount
+		o.Total.Amount += item.GrossAmount.Amount
+
+		idx, ok := byRate[item.TaxRate]
+		if !ok {
+			idx = len(o.TaxBreakdown)
+			byRate[item.TaxRate] = idx
+			o.TaxBreakdown = append(o.TaxBreakdown, TaxLine{
+				TaxRate:     item.TaxRate,
+				NetAmount:   money.New(0, currency),
+				TaxAmount:   money.New(0, currency),
+				GrossAmount: money.New(0, currency),
+			})
+		}
+		line := &o.TaxBreakdown[idx]
+		line.NetAmount.Amount += item.NetAmount.Amount
+		line.TaxAmount.Amount += item.TaxAmount.Amount
+		line.GrossAmount.Amount += item.GrossAmount.Amount
+	}
+
+	return nil
 }
 
 func (o *Order) UpdateStatus(newStatus OrderStatus) {
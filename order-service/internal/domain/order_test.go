@@ -0,0 +1,38 @@
+package domain
+
+import "testing"
+
+func TestOrderStatusTransitions(t *testing.T) {
+	allowed := []struct{ from, to OrderStatus }{
+		{OrderStatusPending, OrderStatusPaid},
+		{OrderStatusPending, OrderStatusCancelled},
+		{OrderStatusConfirmed, OrderStatusPaid},
+		{OrderStatusPaid, OrderStatusDispatched},
+		{OrderStatusPaid, OrderStatusRefunded},
+		{OrderStatusDispatched, OrderStatusCompleted},
+		{OrderStatusCompleted, OrderStatusRefunded},
+		{OrderStatusPaid, OrderStatusPaid},
+	}
+	for _, tt := range allowed {
+		if !tt.from.CanBecome(tt.to) {
+			t.Errorf("%s cannot become %s", tt.from, tt.to)
+		}
+	}
+
+	forbidden := []struct{ from, to OrderStatus }{
+		{OrderStatusCancelled, OrderStatusPaid},
+		{OrderStatusPaid, OrderStatusPending},
+		{OrderStatusPaid, OrderStatusCancelled},
+		{OrderStatusRefunded, OrderStatusPaid},
+		{OrderStatusDispatched, OrderStatusCancelled},
+		// Accepted by UpdateOrder before the lifecycle was enforced.
+		{OrderStatusPending, OrderStatusCompleted},
+		{OrderStatusPending, OrderStatusDispatched},
+		{OrderStatusConfirmed, OrderStatusCompleted},
+	}
+	for _, tt := range forbidden {
+		if tt.from.CanBecome(tt.to) {
+			t.Errorf("%s can become %s", tt.from, tt.to)
+		}
+	}
+}
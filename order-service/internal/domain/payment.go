@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"time"
+
+	"proto/money"
+
+	"github.com/google/uuid"
+)
+
+type PaymentMethod string
+
+const (
+	PaymentMethodCard PaymentMethod = "card"
+	PaymentMethodQR   PaymentMethod = "qr"
+)
+
+func (m PaymentMethod) IsValid() bool {
+	return m == PaymentMethodCard || m == PaymentMethodQR
+}
+
+type PaymentStatus string
+
+const (
+	PaymentStatusPending   PaymentStatus = "pending"
+	PaymentStatusSucceeded PaymentStatus = "succeeded"
+	PaymentStatusFailed    PaymentStatus = "failed"
+	PaymentStatusCancelled PaymentStatus = "cancelled"
+	// PaymentStatusRefunded is a payment captured for an order that could no
+	// longer be paid, such as an expired one, and returned right away.
+	PaymentStatusRefunded PaymentStatus = "refunded"
+)
+
+// IsFinal reports whether the provider can no longer change the status.
+func (s PaymentStatus) IsFinal() bool {
+	return s == PaymentStatusSucceeded || s == PaymentStatusFailed || s == PaymentStatusCancelled ||
+		s == PaymentStatusRefunded
+}
+
+// PaymentIntent is one attempt to collect the order total from the customer.
+type PaymentIntent struct {
+	ID                string
+	OrderID           string
+	Amount            money.Money
+	Method            PaymentMethod
+	Status            PaymentStatus
+	Provider          string
+	ProviderPaymentID string
+	RedirectURL       string
+	QRPayload         string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func NewPaymentIntent(orderID string, amount money.Money, method PaymentMethod, provider string) *PaymentIntent {
+	now := time.Now()
+	return &PaymentIntent{
+		ID:        uuid.New().String(),
+		OrderID:   orderID,
+		Amount:    amount,
+		Method:    method,
+		Status:    PaymentStatusPending,
+		Provider:  provider,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func (p *PaymentIntent) UpdateStatus(status PaymentStatus) {
+	p.Status = status
+	p.UpdatedAt = time.Now()
+}
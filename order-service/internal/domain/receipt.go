@@ -0,0 +1,242 @@
+package domain
+
+import (
+	"time"
+
+	"proto/money"
+
+	"github.com/google/uuid"
+)
+
+type ReceiptType string
+
+const (
+	ReceiptTypeSale       ReceiptType = "sale"
+	ReceiptTypeSaleReturn ReceiptType = "sale_return"
+)
+
+// ReceiptPaymentType is how the customer paid, in OFD terms. Kaspi-style QR
+// payments are reported as mobile payments.
+type ReceiptPaymentType string
+
+const (
+	ReceiptPaymentTypeCard   ReceiptPaymentType = "card"
+	ReceiptPaymentTypeMobile ReceiptPaymentType = "mobile"
+)
+
+func ReceiptPaymentTypeFor(method PaymentMethod) ReceiptPaymentType {
+	if method == PaymentMethodQR {
+		return ReceiptPaymentTypeMobile
+	}
+	return ReceiptPaymentTypeCard
+}
+
+// Cashier identifies the seller and the registered cash register (KKM) the
+// receipt is issued from.
+type Cashier struct {
+	Name                  string
+	KKMRegistrationNumber string
+	SellerBIN             string
+	SellerName            string
+}
+
+type ReceiptLine struct {
+	ProductID string
+	Name      string
+	Quantity  int
+	UnitPrice money.Money
+	Amount    money.Money
+	TaxRate   int
+	TaxAmount money.Money
+}
+
+// FiscalReceipt is the fiscal document submitted to the Operator of Fiscal
+// Data for a sale or a refund.
+type FiscalReceipt struct {
+	ID           string
+	OrderID      string
+	RefundID     string
+	Type         ReceiptType
+	PaymentType  ReceiptPaymentType
+	Lines        []ReceiptLine
+	Total        money.Money
+	TaxTotal     money.Money
+	Cashier      Cashier
+	FiscalNumber string
+	QRURL        string
+	IssuedAt     time.Time
+}
+
+// OrderReceipt is what the order keeps of a registered fiscal receipt.
+type OrderReceipt struct {
+	ID           string
+	Type         ReceiptType
+	RefundID     string
+	FiscalNumber string
+	QRURL        string
+	Total        money.Money
+	IssuedAt     time.Time
+}
+
+// PendingReceipt is a receipt the OFD has not registered yet. It is stored
+// before the first submission and removed once the receipt is on the order,
+// so that receipts survive OFD outages and restarts. Its ID is the fiscal
+// receipt's, by which the OFD recognizes a resubmission.
+type PendingReceipt struct {
+	ID            string
+	OrderID       string
+	Type          ReceiptType
+	RefundID      string
+	Method        PaymentMethod
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+}
+
+func NewPendingReceipt(receipt *FiscalReceipt, method PaymentMethod) *PendingReceipt {
+	return &PendingReceipt{
+		ID:            receipt.ID,
+		OrderID:       receipt.OrderID,
+		Type:          receipt.Type,
+		RefundID:      receipt.RefundID,
+		Method:        method,
+		NextAttemptAt: receipt.IssuedAt,
+		CreatedAt:     receipt.IssuedAt,
+	}
+}
+
+// maxReceiptRetryDelay caps the backoff: receipts are required by law, so
+// they are retried until the OFD takes them.
+const maxReceiptRetryDelay = time.Hour
+
+// Fail records a failed submission and schedules the next one a minute
+// later, doubling with every further failure up to an hour.
+func (p *PendingReceipt) Fail(err error, now time.Time) {
+	p.Attempts++
+	p.LastError = err.Error()
+
+	delay := maxReceiptRetryDelay
+	if p.Attempts <= 6 {
+		delay = time.Minute << (p.Attempts - 1)
+	}
+	p.NextAttemptAt = now.Add(delay)
+}
+
+func NewSaleReceipt(order *Order, method PaymentMethod, cashier Cashier) (*FiscalReceipt, error) {
+	lines := make([]ReceiptLine, len(order.Items))
+	for i, item := range order.Items {
+		lines[i] = ReceiptLine{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UnitPrice: item.Price,
+			Amount:    item.GrossAmount,
+			TaxRate:   item.TaxRate,
+			TaxAmount: item.TaxAmount,
+		}
+	}
+
+	return newFiscalReceipt(order, "", ReceiptTypeSale, method, cashier, lines)
+}
+
+// NewReturnReceipt builds the return receipt for a refund. Refunded lines are
+// taxed at the rate of the order line they come from; a plain amount is spread
+// over the order's VAT rates in proportion to their gross totals.
+func NewReturnReceipt(order *Order, refund *Refund, method PaymentMethod, cashier Cashier) (*FiscalReceipt, error) {
+	var lines []ReceiptLine
+	if len(refund.Lines) > 0 {
+		for _, line := range refund.Lines {
+			rate := VATRateKZ
+			unitPrice := line.Amount
+			for _, item := range order.Items {
+				if item.ProductID == line.ProductID {
+					rate = item.TaxRate
+					unitPrice = item.Price
+					break
+				}
+			}
+			_, tax := splitGross(line.Amount, rate)
+			lines = append(lines, ReceiptLine{
+				ProductID: line.ProductID,
+				Quantity:  line.Quantity,
+				UnitPrice: unitPrice,
+				Amount:    line.Amount,
+				TaxRate:   rate,
+				TaxAmount: tax,
+			})
+		}
+	} else {
+		lines = allocateRefund(order, refund.Amount)
+	}
+
+	return newFiscalReceipt(order, refund.ID, ReceiptTypeSaleReturn, method, cashier, lines)
+}
+
+func allocateRefund(order *Order, amount money.Money) []ReceiptLine {
+	if len(order.TaxBreakdown) == 0 || order.Total.IsZero() {
+		_, tax := splitGross(amount, VATRateKZ)
+		return []ReceiptLine{{Quantity: 1, UnitPrice: amount, Amount: amount, TaxRate: VATRateKZ, TaxAmount: tax}}
+	}
+
+	lines := make([]ReceiptLine, len(order.TaxBreakdown))
+	// Every share is a part of amount, so the minor units add up directly.
+	var allocated int64
+	for i, taxLine := range order.TaxBreakdown {
+		share := amount.MulRatio(taxLine.GrossAmount.Amount, order.Total.Amount)
+		if i == len(order.TaxBreakdown)-1 {
+			share = money.New(amount.Amount-allocated, amount.Currency)
+		}
+		allocated += share.Amount
+
+		_, tax := splitGross(share, taxLine.TaxRate)
+		lines[i] = ReceiptLine{Quantity: 1, UnitPrice: share, Amount: share, TaxRate: taxLine.TaxRate, TaxAmount: tax}
+	}
+	return lines
+}
+
+func newFiscalReceipt(order *Order, refundID string, receiptType ReceiptType, method PaymentMethod, cashier Cashier, lines []ReceiptLine) (*FiscalReceipt, error) {
+	receipt := &FiscalReceipt{
+		ID:          uuid.New().String(),
+		OrderID:     order.ID,
+		RefundID:    refundID,
+		Type:        receiptType,
+		PaymentType: ReceiptPaymentTypeFor(method),
+		Lines:       lines,
+		Total:       money.New(0, order.Total.Currency),
+		TaxTotal:    money.New(0, order.Total.Currency),
+		Cashier:     cashier,
+		IssuedAt:    time.Now(),
+	}
+	var err error
+	for _, line := range lines {
+		if receipt.Total, err = receipt.Total.Add(line.Amount); err != nil {
+			return nil, err
+		}
+		if receipt.TaxTotal, err = receipt.TaxTotal.Add(line.TaxAmount); err != nil {
+			return nil, err
+		}
+	}
+	return receipt, nil
+}
+
+// HasReceipt reports whether the order already keeps the receipt.
+func (o *Order) HasReceipt(receiptID string) bool {
+	for _, receipt := range o.Receipts {
+		if receipt.ID == receiptID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *FiscalReceipt) Summary() OrderReceipt {
+	return OrderReceipt{
+		ID:           r.ID,
+		Type:         r.Type,
+		RefundID:     r.RefundID,
+		FiscalNumber: r.FiscalNumber,
+		QRURL:        r.QRURL,
+		Total:        r.Total,
+		IssuedAt:     r.IssuedAt,
+	}
+}
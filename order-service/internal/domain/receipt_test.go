@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"proto/money"
+)
+
+// newReceiptOrder returns a paid order of 2 × 560.00 KZT at 12% VAT and
+// 450.00 KZT exempt from VAT.
+func newReceiptOrder(t *testing.T) *Order {
+	t.Helper()
+	order, err := NewOrder("user-1", []OrderItem{
+		{ProductID: "bread", Quantity: 2, Price: money.KZT(56000), TaxClass: TaxClassStandard},
+		{ProductID: "milk", Quantity: 1, Price: money.KZT(45000), TaxClass: TaxClassExempt},
+	}, OrderStatusPaid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return order
+}
+
+func TestSaleReceiptMatchesOrderTotals(t *testing.T) {
+	order := newReceiptOrder(t)
+
+	receipt, err := NewSaleReceipt(order, PaymentMethodQR, Cashier{Name: "Online"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if receipt.Type != ReceiptTypeSale || receipt.PaymentType != ReceiptPaymentTypeMobile {
+		t.Fatalf("receipt is %s paid by %s, want sale paid by mobile", receipt.Type, receipt.PaymentType)
+	}
+	if receipt.Total != order.Total || receipt.TaxTotal != order.TaxTotal {
+		t.Fatalf("receipt totals %v (VAT %v), want %v (VAT %v)", receipt.Total, receipt.TaxTotal, order.Total, order.TaxTotal)
+	}
+	if len(receipt.Lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(receipt.Lines))
+	}
+	bread := receipt.Lines[0]
+	if bread.Amount != money.KZT(112000) || bread.TaxRate != VATRateKZ || bread.TaxAmount != money.KZT(12000) {
+		t.Fatalf("bread line = %v at %d, VAT %v", bread.Amount, bread.TaxRate, bread.TaxAmount)
+	}
+	if milk := receipt.Lines[1]; milk.TaxRate != 0 || !milk.TaxAmount.IsZero() {
+		t.Fatalf("exempt line taxed at %d: %v", milk.TaxRate, milk.TaxAmount)
+	}
+}
+
+func TestReturnReceiptOfLinesUsesTheirVATRate(t *testing.T) {
+	order := newReceiptOrder(t)
+	refund := NewRefund("payment-1", money.KZT(56000), "", []RefundLine{
+		{ProductID: "bread", Quantity: 1, Amount: money.KZT(56000)},
+	})
+
+	receipt, err := NewReturnReceipt(order, refund, PaymentMethodCard, Cashier{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if receipt.Type != ReceiptTypeSaleReturn || receipt.RefundID != refund.ID {
+		t.Fatalf("receipt is %s for refund %q, want sale return for %q", receipt.Type, receipt.RefundID, refund.ID)
+	}
+	if receipt.Total != money.KZT(56000) || receipt.TaxTotal != money.KZT(6000) {
+		t.Fatalf("receipt totals %v (VAT %v), want 560.00 KZT (VAT 60.00 KZT)", receipt.Total, receipt.TaxTotal)
+	}
+	if line := receipt.Lines[0]; line.UnitPrice != money.KZT(56000) || line.TaxRate != VATRateKZ {
+		t.Fatalf("line = %v at %d, want 560.00 KZT at 12%%", line.UnitPrice, line.TaxRate)
+	}
+}
+
+func TestReturnReceiptSpreadsAmountOverVATRates(t *testing.T) {
+	order := newReceiptOrder(t)
+	refund := NewRefund("payment-1", money.KZT(78500), "", nil)
+
+	receipt, err := NewReturnReceipt(order, refund, PaymentMethodCard, Cashier{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if receipt.Total != refund.Amount {
+		t.Fatalf("receipt total = %v, want %v", receipt.Total, refund.Amount)
+	}
+	if receipt.TaxTotal != money.KZT(6000) {
+		t.Fatalf("receipt VAT = %v, want 60.00 KZT", receipt.TaxTotal)
+	}
+	for _, line := range receipt.Lines {
+		if line.TaxRate == VATRateKZ && line.Amount != money.KZT(56000) {
+			t.Errorf("standard share = %v, want 560.00 KZT", line.Amount)
+		}
+		if line.TaxRate == 0 && line.Amount != money.KZT(22500) {
+			t.Errorf("exempt share = %v, want 225.00 KZT", line.Amount)
+		}
+	}
+}
+
+func TestPendingReceiptBacksOffUpToAnHour(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	pending := &PendingReceipt{}
+
+	for attempt, want := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute} {
+		pending.Fail(errors.New("OFD unavailable"), now)
+		if got := pending.NextAttemptAt.Sub(now); got != want {
+			t.Fatalf("attempt %d: retry in %v, want %v", attempt+1, got, want)
+		}
+	}
+	for i := 0; i < 20; i++ {
+		pending.Fail(errors.New("OFD unavailable"), now)
+	}
+	if got := pending.NextAttemptAt.Sub(now); got != time.Hour {
+		t.Fatalf("after %d attempts: retry in %v, want 1h", pending.Attempts, got)
+	}
+	if pending.LastError != "OFD unavailable" {
+		t.Fatalf("last error = %q", pending.LastError)
+	}
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "proto/money"
+
+type ReorderChangeKind string
+
+const (
+	ReorderPriceChanged    ReorderChangeKind = "price_changed"
+	ReorderQuantityReduced ReorderChangeKind = "quantity_reduced"
+	ReorderOutOfStock      ReorderChangeKind = "out_of_stock"
+	ReorderUnavailable     ReorderChangeKind = "unavailable"
+)
+
+// ReorderChange describes how a line of a past order differs when it is
+// bought again. Each line has at most one change, and Kind names its most
+// important difference: a line both reduced and repriced is
+// ReorderQuantityReduced, with the new price in CurrentPrice.
+type ReorderChange struct {
+	ProductID        string
+	Name             string
+	Kind             ReorderChangeKind
+	PreviousQuantity int
+	Quantity         int
+	PreviousPrice    money.Money
+	CurrentPrice     money.Money
+}
+
+// ReorderResult holds either the cart or the order the past order was copied
+// into, with the list of changes.
+type ReorderResult struct {
+	Cart    *PricedCart
+	Order   *Order
+	Changes []ReorderChange
+}
@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReturnStatus string
+
+const (
+	ReturnStatusRequested ReturnStatus = "requested"
+	ReturnStatusApproved  ReturnStatus = "approved"
+	ReturnStatusReceived  ReturnStatus = "received"
+	// ReturnStatusRefunding is set before the refund is issued, so that only
+	// one call issues it. RefundID is known from then on.
+	ReturnStatusRefunding ReturnStatus = "refunding"
+	ReturnStatusRefunded  ReturnStatus = "refunded"
+	ReturnStatusRejected  ReturnStatus = "rejected"
+)
+
+// MaxReturnPhotos limits how many photo links a customer can attach.
+const MaxReturnPhotos = 10
+
+// ReturnLine is a product sent back. Sellable is decided when the parcel is
+// received and tells whether the items go back on sale.
+type ReturnLine struct {
+	ProductID string
+	Quantity  int
+	Sellable  bool
+}
+
+// Return is a customer's request to send back products of a delivered order.
+type Return struct {
+	ID        string
+	OrderID   string
+	UserID    string
+	Lines     []ReturnLine
+	Reason    string
+	Photos    []string
+	Status    ReturnStatus
+	AdminNote string
+	RefundID  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewReturn validates the requested lines against the delivered order. Items
+// already refunded or claimed by another open return cannot be returned again.
+func NewReturn(order *Order, lines []ReturnLine, reason string, photos []string, others []*Return) (*Return, error) {
+	if order.Status != OrderStatusCompleted {
+		return nil, fmt.Errorf("order %s is not delivered", order.ID)
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("return needs at least one line")
+	}
+	if reason == "" {
+		return nil, errors.New("return reason is required")
+	}
+	if len(photos) > MaxReturnPhotos {
+		return nil, fmt.Errorf("at most %d photos can be attached", MaxReturnPhotos)
+	}
+	for _, photo := range photos {
+		u, err := url.Parse(photo)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid photo URL: %s", photo)
+		}
+	}
+
+	seen := make(map[string]bool, len(lines))
+	for i := range lines {
+		line := &lines[i]
+		if seen[line.ProductID] {
+			return nil, fmt.Errorf("product %s is listed more than once", line.ProductID)
+		}
+		seen[line.ProductID] = true
+
+		idx := order.itemIndex(line.ProductID)
+		if idx < 0 {
+			return nil, fmt.Errorf("product %s is not in the order", line.ProductID)
+		}
+		if line.Quantity <= 0 {
+			return nil, fmt.Errorf("invalid quantity for product %s", line.ProductID)
+		}
+
+		claimed := order.RefundedQuantity(line.ProductID)
+		for _, other := range others {
+			if other.Status != ReturnStatusRejected && other.Status != ReturnStatusRefunded {
+				claimed += other.Quantity(line.ProductID)
+			}
+		}
+		if available := order.Items[idx].Quantity - claimed; line.Quantity > available {
+			return nil, fmt.Errorf("only %d of product %s can be returned", available, line.ProductID)
+		}
+		line.Sellable = false
+	}
+
+	now := time.Now()
+	return &Return{
+		ID:        uuid.New().String(),
+		OrderID:   order.ID,
+		UserID:    order.UserID,
+		Lines:     lines,
+		Reason:    reason,
+		Photos:    photos,
+		Status:    ReturnStatusRequested,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+func (r *Return) Quantity(productID string) int {
+	for _, line := range r.Lines {
+		if line.ProductID == productID {
+			return line.Quantity
+		}
+	}
+	return 0
+}
+
+func (r *Return) Approve(note string) error {
+	if r.Status != ReturnStatusRequested {
+		return fmt.Errorf("return %s cannot be approved in status %s", r.ID, r.Status)
+	}
+	r.setStatus(ReturnStatusApproved, note)
+	return nil
+}
+
+func (r *Return) Reject(note string) error {
+	if r.Status != ReturnStatusRequested && r.Status != ReturnStatusApproved {
+		return fmt.Errorf("return %s cannot be rejected in status %s", r.ID, r.Status)
+	}
+	if note == "" {
+		return errors.New("a reason for the rejection is required")
+	}
+	r.setStatus(ReturnStatusRejected, note)
+	return nil
+}
+
+// Receive records the parcel as received. Lines listed in unsellable are
+// damaged and will not be restocked.
+func (r *Return) Receive(unsellable []string, note string) error {
+	if r.Status != ReturnStatusApproved {
+		return fmt.Errorf("return %s cannot be received in status %s", r.ID, r.Status)
+	}
+
+	damaged := make(map[string]bool, len(unsellable))
+	for _, productID := range unsellable {
+		if r.Quantity(productID) == 0 {
+			return fmt.Errorf("product %s is not in the return", productID)
+		}
+		damaged[productID] = true
+	}
+	for i := range r.Lines {
+		r.Lines[i].Sellable = !damaged[r.Lines[i].ProductID]
+	}
+
+	r.setStatus(ReturnStatusReceived, note)
+	return nil
+}
+
+// StartRefund picks the ID of the refund for the returned items.
+func (r *Return) StartRefund() error {
+	if r.Status != ReturnStatusReceived {
+		return fmt.Errorf("return %s cannot be refunded in status %s", r.ID, r.Status)
+	}
+	r.RefundID = uuid.New().String()
+	r.setStatus(ReturnStatusRefunding, "")
+	return nil
+}
+
+func (r *Return) MarkRefunded() error {
+	if r.Status != ReturnStatusRefunding {
+		return fmt.Errorf("return %s cannot be refunded in status %s", r.ID, r.Status)
+	}
+	r.setStatus(ReturnStatusRefunded, "")
+	return nil
+}
+
+func (r *Return) setStatus(status ReturnStatus, note string) {
+	r.Status = status
+	if note != "" {
+		r.AdminNote = note
+	}
+	r.UpdatedAt = time.Now()
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"testing"
+
+	"proto/money"
+)
+
+func newDeliveredOrder(t *testing.T) *Order {
+	t.Helper()
+	order, err := NewOrder("u1", []OrderItem{
+		{ProductID: "tea", Quantity: 1, Price: money.KZT(100000)},
+		{ProductID: "milk", Quantity: 2, Price: money.KZT(50000)},
+	}, OrderStatusCompleted)
+	if err != nil {
+		t.Fatalf("NewOrder: %v", err)
+	}
+	return order
+}
+
+func TestReturnMovesThroughItsStatuses(t *testing.T) {
+	ret, err := NewReturn(newDeliveredOrder(t), []ReturnLine{{ProductID: "milk", Quantity: 2}}, "spoiled", nil, nil)
+	if err != nil {
+		t.Fatalf("NewReturn: %v", err)
+	}
+
+	steps := []struct {
+		name string
+		do   func() error
+		want ReturnStatus
+	}{
+		{"approve", func() error { return ret.Approve("ok") }, ReturnStatusApproved},
+		{"receive", func() error { return ret.Receive([]string{"milk"}, "") }, ReturnStatusReceived},
+		{"start refund", ret.StartRefund, ReturnStatusRefunding},
+		{"mark refunded", ret.MarkRefunded, ReturnStatusRefunded},
+	}
+	for _, step := range steps {
+		if err := step.do(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if ret.Status != step.want {
+			t.Fatalf("after %s status = %s, want %s", step.name, ret.Status, step.want)
+		}
+	}
+	if ret.RefundID == "" || ret.Lines[0].Sellable {
+		t.Fatalf("refund ID %q, sellable %v; want an ID and the damaged line unsellable", ret.RefundID, ret.Lines[0].Sellable)
+	}
+}
+
+func TestReturnRefusesOutOfOrderSteps(t *testing.T) {
+	ret, err := NewReturn(newDeliveredOrder(t), []ReturnLine{{ProductID: "tea", Quantity: 1}}, "wrong item", nil, nil)
+	if err != nil {
+		t.Fatalf("NewReturn: %v", err)
+	}
+
+	if ret.Receive(nil, "") == nil || ret.StartRefund() == nil || ret.MarkRefunded() == nil {
+		t.Fatal("a requested return was received or refunded")
+	}
+	if ret.Reject("") == nil {
+		t.Fatal("rejected without a reason")
+	}
+	if err := ret.Approve(""); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if ret.Approve("") == nil {
+		t.Fatal("approved twice")
+	}
+	if err := ret.Receive(nil, ""); err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+	if ret.Reject("too late") == nil {
+		t.Fatal("rejected a received return")
+	}
+	if err := ret.StartRefund(); err != nil {
+		t.Fatalf("StartRefund: %v", err)
+	}
+	if ret.StartRefund() == nil {
+		t.Fatal("started the refund twice")
+	}
+}
+
+func TestNewReturnLimitsQuantitiesToWhatIsLeft(t *testing.T) {
+	order := newDeliveredOrder(t)
+
+	open, err := NewReturn(order, []ReturnLine{{ProductID: "milk", Quantity: 1}}, "spoiled", nil, nil)
+	if err != nil {
+		t.Fatalf("NewReturn: %v", err)
+	}
+	if _, err := NewReturn(order, []ReturnLine{{ProductID: "milk", Quantity: 2}}, "spoiled", nil, []*Return{open}); err == nil {
+		t.Fatal("returned units claimed by an open return")
+	}
+
+	open.Status = ReturnStatusRejected
+	if _, err := NewReturn(order, []ReturnLine{{ProductID: "milk", Quantity: 2}}, "spoiled", nil, []*Return{open}); err != nil {
+		t.Fatalf("units of a rejected return: %v", err)
+	}
+
+	order.Status = OrderStatusPaid
+	if _, err := NewReturn(order, []ReturnLine{{ProductID: "milk", Quantity: 1}}, "spoiled", nil, nil); err == nil {
+		t.Fatal("returned items of an undelivered order")
+	}
+}
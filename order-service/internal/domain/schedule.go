@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Cadence string
+
+const (
+	CadenceWeekly   Cadence = "weekly"
+	CadenceBiweekly Cadence = "biweekly"
+	CadenceMonthly  Cadence = "monthly"
+)
+
+func (c Cadence) IsValid() bool {
+	return c == CadenceWeekly || c == CadenceBiweekly || c == CadenceMonthly
+}
+
+// Next returns the run after t. Monthly runs stay on anchorDay, or on the last
+// day of shorter months.
+func (c Cadence) Next(t time.Time, anchorDay int) time.Time {
+	switch c {
+	case CadenceBiweekly:
+		return t.AddDate(0, 0, 14)
+	case CadenceMonthly:
+		year, month, _ := t.Date()
+		first := time.Date(year, month+1, 1, t.Hour(), t.Minute(), t.Second(), 0, t.Location())
+		lastDay := first.AddDate(0, 1, -1).Day()
+		day := anchorDay
+		if day > lastDay {
+			day = lastDay
+		}
+		return first.AddDate(0, 0, day-1)
+	default:
+		return t.AddDate(0, 0, 7)
+	}
+}
+
+type ScheduleStatus string
+
+const (
+	ScheduleStatusActive    ScheduleStatus = "active"
+	ScheduleStatusPaused    ScheduleStatus = "paused"
+	ScheduleStatusCancelled ScheduleStatus = "cancelled"
+)
+
+// ScheduleItem is a product and quantity to order on every run. Prices are
+// resolved from the inventory at run time.
+type ScheduleItem struct {
+	ProductID string
+	Quantity  int
+}
+
+// Schedule is a recurring order, such as a weekly water delivery.
+type Schedule struct {
+	ID          string
+	UserID      string
+	Items       []ScheduleItem
+	Address     string
+	Cadence     Cadence
+	Status      ScheduleStatus
+	StartAt     time.Time
+	NextRunAt   time.Time
+	LastRunAt   time.Time
+	LastOrderID string
+	LastError   string
+	// Failures counts the failed attempts of the current run.
+	Failures  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewSchedule(userID string, items []ScheduleItem, address string, cadence Cadence, startAt time.Time) (*Schedule, error) {
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+	if len(items) == 0 {
+		return nil, errors.New("schedule needs at least one item")
+	}
+	for _, item := range items {
+		if item.ProductID == "" || item.Quantity <= 0 {
+			return nil, errors.New("every schedule item needs a product and a positive quantity")
+		}
+	}
+	if address == "" {
+		return nil, errors.New("delivery address is required")
+	}
+	if !cadence.IsValid() {
+		return nil, errors.New("cadence must be weekly, biweekly or monthly")
+	}
+
+	now := time.Now()
+	if startAt.IsZero() || startAt.Before(now) {
+		startAt = now
+	}
+
+	return &Schedule{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Items:     items,
+		Address:   address,
+		Cadence:   cadence,
+		Status:    ScheduleStatusActive,
+		StartAt:   startAt,
+		NextRunAt: startAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// MaxRunAttempts is how often a run is tried before it is given up and the
+// schedule moves on to its next run.
+const MaxRunAttempts = 5
+
+// RunKey identifies the current run, so that a retried run finds the order
+// an earlier attempt placed.
+func (s *Schedule) RunKey() string {
+	return fmt.Sprintf("schedule:%s:%d", s.ID, s.NextRunAt.Unix())
+}
+
+// RetryDelay is how long a failed run waits before its next attempt: a
+// minute after the first failure, doubling with every further one.
+func (s *Schedule) RetryDelay() time.Duration {
+	if s.Failures < 1 {
+		return time.Minute
+	}
+	return time.Minute << (s.Failures - 1)
+}
+
+func (s *Schedule) NextAfter(t time.Time) time.Time {
+	return s.Cadence.Next(t, s.StartAt.Day())
+}
+
+func (s *Schedule) Pause() error {
+	if s.Status != ScheduleStatusActive {
+		return errors.New("only active schedules can be paused")
+	}
+	s.Status = ScheduleStatusPaused
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+// Resume reactivates a paused schedule. Runs missed while paused are not made
+// up; the next run is the first one from now on.
+func (s *Schedule) Resume(now time.Time) error {
+	if s.Status != ScheduleStatusPaused {
+		return errors.New("only paused schedules can be resumed")
+	}
+	for s.NextRunAt.Before(now) {
+		s.NextRunAt = s.NextAfter(s.NextRunAt)
+	}
+	s.Status = ScheduleStatusActive
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+func (s *Schedule) SkipNext() error {
+	if s.Status == ScheduleStatusCancelled {
+		return errors.New("schedule is cancelled")
+	}
+	s.NextRunAt = s.NextAfter(s.NextRunAt)
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+func (s *Schedule) Cancel() error {
+	if s.Status == ScheduleStatusCancelled {
+		return errors.New("schedule is already cancelled")
+	}
+	s.Status = ScheduleStatusCancelled
+	s.UpdatedAt = time.Now()
+	return nil
+}
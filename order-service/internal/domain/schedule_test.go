@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 9, 30, 0, 0, time.UTC)
+}
+
+func TestCadenceNext(t *testing.T) {
+	tests := []struct {
+		name      string
+		cadence   Cadence
+		from      time.Time
+		anchorDay int
+		want      time.Time
+	}{
+		{"weekly", CadenceWeekly, day(2025, time.March, 28), 28, day(2025, time.April, 4)},
+		{"biweekly across a year", CadenceBiweekly, day(2025, time.December, 25), 25, day(2026, time.January, 8)},
+		{"monthly keeps the anchor day", CadenceMonthly, day(2025, time.January, 15), 15, day(2025, time.February, 15)},
+		{"monthly clamps to February", CadenceMonthly, day(2025, time.January, 31), 31, day(2025, time.February, 28)},
+		{"monthly clamps to a leap February", CadenceMonthly, day(2024, time.January, 31), 31, day(2024, time.February, 29)},
+		{"monthly returns to the anchor after a short month", CadenceMonthly, day(2025, time.February, 28), 31, day(2025, time.March, 31)},
+		{"monthly clamps to a 30-day month", CadenceMonthly, day(2025, time.March, 31), 31, day(2025, time.April, 30)},
+		{"monthly across a year", CadenceMonthly, day(2025, time.December, 30), 30, day(2026, time.January, 30)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cadence.Next(tt.from, tt.anchorDay); !got.Equal(tt.want) {
+				t.Fatalf("Next(%s, %d) = %s, want %s", tt.from.Format(time.DateOnly), tt.anchorDay,
+					got.Format(time.DateTime), tt.want.Format(time.DateTime))
+			}
+		})
+	}
+}
+
+func TestScheduleRunKeyNamesOneRun(t *testing.T) {
+	schedule, err := NewSchedule("user-1", []ScheduleItem{{ProductID: "water", Quantity: 2}}, "Astana", CadenceWeekly, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("NewSchedule: %v", err)
+	}
+
+	key := schedule.RunKey()
+	if again := schedule.RunKey(); again != key {
+		t.Fatalf("RunKey changed from %s to %s for the same run", key, again)
+	}
+
+	schedule.Failures = 3
+	if retried := schedule.RunKey(); retried != key {
+		t.Fatalf("retried run has key %s, want %s", retried, key)
+	}
+
+	schedule.NextRunAt = schedule.NextAfter(schedule.NextRunAt)
+	if next := schedule.RunKey(); next == key {
+		t.Fatalf("next run has the same key %s", key)
+	}
+}
+
+func TestScheduleRetryDelayDoubles(t *testing.T) {
+	schedule := &Schedule{}
+	for failures, want := range []time.Duration{time.Minute, time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute} {
+		schedule.Failures = failures
+		if got := schedule.RetryDelay(); got != want {
+			t.Errorf("RetryDelay after %d failures = %s, want %s", failures, got, want)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"proto/money"
+
+	"github.com/google/uuid"
+)
+
+// SubstitutionPreference is what the customer wants done when a line turns
+// out to be missing during picking.
+type SubstitutionPreference string
+
+const (
+	SubstituteRefund    SubstitutionPreference = "refund"
+	SubstituteBestMatch SubstitutionPreference = "best_match"
+	SubstituteSpecific  SubstitutionPreference = "specific"
+)
+
+func ParseSubstitutionPreference(s string) (SubstitutionPreference, error) {
+	switch p := SubstitutionPreference(s); p {
+	case "":
+		return SubstituteBestMatch, nil
+	case SubstituteRefund, SubstituteBestMatch, SubstituteSpecific:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown substitution preference: %s", s)
+	}
+}
+
+type SubstitutionStatus string
+
+const (
+	SubstitutionProposed SubstitutionStatus = "proposed"
+	SubstitutionApproved SubstitutionStatus = "approved"
+	SubstitutionRejected SubstitutionStatus = "rejected"
+	SubstitutionExpired  SubstitutionStatus = "expired"
+	// SubstitutionRefunded means the line was dropped without a substitute.
+	SubstitutionRefunded SubstitutionStatus = "refunded"
+)
+
+// Substitution records how a missing line was handled. Unless it is approved
+// the missing line is removed from the order. On a paid order the difference
+// the substitution knocked off the total is refunded as RefundAmount; a
+// substitute may not cost more than the line it replaces, since nothing
+// collects the extra.
+type Substitution struct {
+	ID                  string
+	ProductID           string
+	Quantity            int
+	SubstituteProductID string
+	SubstituteQuantity  int
+	Price               money.Money
+	TaxClass            TaxClass
+	Status              SubstitutionStatus
+	ProposedAt          time.Time
+	ExpiresAt           time.Time
+	DecidedAt           time.Time
+	RefundAmount        money.Money
+	// RefundOwed is set until RefundAmount has been refunded, and Unpublished
+	// until the substitution.proposed event has been published.
+	RefundOwed  bool
+	Unpublished bool
+}
+
+// Unsettled reports whether a refund or an event is still owed for the
+// substitution.
+func (s Substitution) Unsettled() bool {
+	return s.RefundOwed || s.Unpublished
+}
+
+// Preference returns the customer's choice for the line, best match if none
+// was made.
+func (i OrderItem) Preference() SubstitutionPreference {
+	if i.Substitution == "" {
+		return SubstituteBestMatch
+	}
+	return i.Substitution
+}
+
+func (o *Order) SetSubstitutionPreference(productID string, preference SubstitutionPreference, substituteProductID string) error {
+	if !o.canSubstitute() {
+		return fmt.Errorf("order %s cannot be changed in status %s", o.ID, o.Status)
+	}
+	if preference == SubstituteSpecific && substituteProductID == "" {
+		return errors.New("a specific substitution needs a substitute product")
+	}
+	if preference != SubstituteSpecific {
+		substituteProductID = ""
+	}
+
+	idx := o.itemIndex(productID)
+	if idx < 0 {
+		return fmt.Errorf("product %s is not in the order", productID)
+	}
+
+	o.Items[idx].Substitution = preference
+	o.Items[idx].SubstituteProductID = substituteProductID
+	o.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// ProposeSubstitution handles a line the picker could not find. Without a
+// substitute, or when the customer asked for a refund, the line is dropped at
+// once. A substitute the customer chose in advance is applied at once; any
+// other substitute waits for the customer until expiresAt.
+func (o *Order) ProposeSubstitution(productID, substituteProductID string, quantity int, price money.Money, taxClass TaxClass, now, expiresAt time.Time) (*Substitution, error) {
+	if !o.canSubstitute() {
+		return nil, fmt.Errorf("order %s cannot be changed in status %s", o.ID, o.Status)
+	}
+
+	idx := o.itemIndex(productID)
+	if idx < 0 {
+		return nil, fmt.Errorf("product %s is not in the order", productID)
+	}
+	for _, s := range o.Substitutions {
+		if s.ProductID == productID && s.Status == SubstitutionProposed {
+			return nil, fmt.Errorf("a substitution for product %s is already awaiting the customer", productID)
+		}
+	}
+
+	item := o.Items[idx]
+	s := Substitution{
+		ID:         uuid.New().String(),
+		ProductID:  productID,
+		Quantity:   item.Quantity,
+		ProposedAt: now,
+	}
+
+	preference := item.Preference()
+	switch {
+	case substituteProductID == "" || preference == SubstituteRefund:
+		s.Status = SubstitutionRefunded
+		s.DecidedAt = now
+	case substituteProductID == productID:
+		return nil, errors.New("a product cannot substitute itself")
+	case preference == SubstituteSpecific && substituteProductID != item.SubstituteProductID:
+		return nil, fmt.Errorf("the customer only accepts product %s as a substitute", item.SubstituteProductID)
+	case quantity <= 0:
+		return nil, errors.New("substitute quantity must be positive")
+	case !price.SameCurrency(item.Price):
+		return nil, errors.New("substitute must be priced in the order currency")
+	case o.Status == OrderStatusPaid && price.Mul(quantity).Amount > item.Price.Mul(item.Quantity).Amount:
+		return nil, errors.New("a substitute for a paid order cannot cost more than the missing line")
+	default:
+		s.SubstituteProductID = substituteProductID
+		s.SubstituteQuantity = quantity
+		s.Price = price
+		s.TaxClass = taxClass
+		s.Status = SubstitutionProposed
+		s.ExpiresAt = expiresAt
+		s.Unpublished = true
+		if preference == SubstituteSpecific {
+			s.Status = SubstitutionApproved
+			s.DecidedAt = now
+		}
+	}
+
+	o.Substitutions = append(o.Substitutions, s)
+	if err := o.resolve(&o.Substitutions[len(o.Substitutions)-1]); err != nil {
+		return nil, err
+	}
+
+	return &o.Substitutions[len(o.Substitutions)-1], nil
+}
+
+// DecideSubstitution records the customer's answer. An answer that comes after
+// the timeout leaves the substitution expired.
+func (o *Order) DecideSubstitution(id string, approve bool, now time.Time) (*Substitution, error) {
+	var s *Substitution
+	for i := range o.Substitutions {
+		if o.Substitutions[i].ID == id {
+			s = &o.Substitutions[i]
+			break
+		}
+	}
+	if s == nil {
+		return nil, fmt.Errorf("substitution %s not found", id)
+	}
+	if s.Status != SubstitutionProposed {
+		return nil, fmt.Errorf("substitution %s is already %s", id, s.Status)
+	}
+	if !o.canSubstitute() {
+		return nil, fmt.Errorf("order %s cannot be changed in status %s", o.ID, o.Status)
+	}
+
+	switch {
+	case !now.Before(s.ExpiresAt):
+		s.Status = SubstitutionExpired
+	case approve:
+		s.Status = SubstitutionApproved
+	default:
+		s.Status = SubstitutionRejected
+	}
+	s.DecidedAt = now
+	if err := o.resolve(s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// ExpireSubstitutions drops the lines of proposals the customer did not answer
+// in time and returns them.
+func (o *Order) ExpireSubstitutions(now time.Time) ([]Substitution, error) {
+	var expired []Substitution
+	for i := range o.Substitutions {
+		s := &o.Substitutions[i]
+		if s.Status != SubstitutionProposed || now.Before(s.ExpiresAt) {
+			continue
+		}
+		s.Status = SubstitutionExpired
+		s.DecidedAt = now
+		if err := o.resolve(s); err != nil {
+			return nil, err
+		}
+		expired = append(expired, *s)
+	}
+	return expired, nil
+}
+
+// resolve adjusts the order lines once a substitution is decided and, on a
+// paid order, records what has to be refunded.
+func (o *Order) resolve(s *Substitution) error {
+	if s.Status == SubstitutionProposed {
+		return nil
+	}
+	before := o.Total
+
+	idx := o.itemIndex(s.ProductID)
+	if idx >= 0 {
+		o.Items = append(o.Items[:idx], o.Items[idx+1:]...)
+	}
+
+	if s.Status == SubstitutionApproved {
+		if j := o.itemIndex(s.SubstituteProductID); j >= 0 {
+			o.Items[j].Quantity += s.SubstituteQuantity
+		} else {
+			o.Items = append(o.Items, OrderItem{
+				ProductID: s.SubstituteProductID,
+				Quantity:  s.SubstituteQuantity,
+				Price:     s.Price,
+				TaxClass:  s.TaxClass,
+			})
+		}
+	}
+
+	if err := o.CalculateTotals(); err != nil {
+		return err
+	}
+	if o.Status == OrderStatusPaid {
+		refund, err := before.Sub(o.Total)
+		if err != nil {
+			return err
+		}
+		if refund.Amount > 0 {
+			s.RefundAmount = refund
+			s.RefundOwed = true
+		}
+	}
+	// A decided substitution is no longer announced to the customer.
+	s.Unpublished = false
+	o.UpdatedAt = time.Now()
+	return nil
+}
+
+func (o *Order) canSubstitute() bool {
+	return o.Status.IsModifiable() || o.Status == OrderStatusPaid
+}
+
+func (o *Order) itemIndex(productID string) int {
+	for i := range o.Items {
+		if o.Items[i].ProductID == productID {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,210 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"proto/money"
+)
+
+// newSubstitutionOrder returns an order of 1 000.00 KZT tea and 2 × 500.00
+// KZT milk, 2 000.00 KZT in total.
+func newSubstitutionOrder(t *testing.T, status OrderStatus) *Order {
+	t.Helper()
+	order, err := NewOrder("u1", []OrderItem{
+		{ProductID: "tea", Quantity: 1, Price: money.KZT(100000)},
+		{ProductID: "milk", Quantity: 2, Price: money.KZT(50000)},
+	}, status)
+	if err != nil {
+		t.Fatalf("NewOrder: %v", err)
+	}
+	return order
+}
+
+func TestProposeSubstitutionDropsTheLineForARefund(t *testing.T) {
+	now := time.Now()
+	order := newSubstitutionOrder(t, OrderStatusPending)
+	if err := order.SetSubstitutionPreference("milk", SubstituteRefund, ""); err != nil {
+		t.Fatalf("SetSubstitutionPreference: %v", err)
+	}
+
+	s, err := order.ProposeSubstitution("milk", "oat-milk", 2, money.KZT(45000), TaxClassStandard, now, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("ProposeSubstitution: %v", err)
+	}
+	if s.Status != SubstitutionRefunded || s.Unsettled() {
+		t.Fatalf("substitution is %s, unsettled %v; want refunded and nothing owed before payment", s.Status, s.Unsettled())
+	}
+	if len(order.Items) != 1 || order.Items[0].ProductID != "tea" || order.Total != money.KZT(100000) {
+		t.Fatalf("order has %v totalling %v, want only tea at 1000.00 KZT", order.Items, order.Total)
+	}
+}
+
+func TestProposeSubstitutionAppliesTheChosenSubstitute(t *testing.T) {
+	now := time.Now()
+	order := newSubstitutionOrder(t, OrderStatusPending)
+	if err := order.SetSubstitutionPreference("milk", SubstituteSpecific, "oat-milk"); err != nil {
+		t.Fatalf("SetSubstitutionPreference: %v", err)
+	}
+
+	if _, err := order.ProposeSubstitution("milk", "kefir", 2, money.KZT(40000), TaxClassStandard, now, now.Add(time.Hour)); err == nil {
+		t.Fatal("a substitute the customer did not choose was accepted")
+	}
+
+	s, err := order.ProposeSubstitution("milk", "oat-milk", 2, money.KZT(45000), TaxClassStandard, now, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("ProposeSubstitution: %v", err)
+	}
+	if s.Status != SubstitutionApproved || s.Unpublished {
+		t.Fatalf("substitution is %s, unpublished %v; want approved without asking", s.Status, s.Unpublished)
+	}
+	if order.itemIndex("milk") >= 0 {
+		t.Fatal("the missing line is still in the order")
+	}
+	oat := order.Items[order.itemIndex("oat-milk")]
+	if oat.Quantity != 2 || oat.GrossAmount != money.KZT(90000) {
+		t.Fatalf("substitute line = %d for %v, want 2 for 900.00 KZT", oat.Quantity, oat.GrossAmount)
+	}
+	if order.Total != money.KZT(190000) {
+		t.Fatalf("total = %v, want 1900.00 KZT", order.Total)
+	}
+}
+
+func TestBestMatchSubstitutionWaitsForTheCustomer(t *testing.T) {
+	now := time.Now()
+	expiresAt := now.Add(10 * time.Minute)
+
+	tests := []struct {
+		name      string
+		approve   bool
+		at        time.Time
+		want      SubstitutionStatus
+		wantLines int
+		wantTotal money.Money
+	}{
+		{"approved", true, now.Add(time.Minute), SubstitutionApproved, 2, money.KZT(190000)},
+		{"rejected", false, now.Add(time.Minute), SubstitutionRejected, 1, money.KZT(100000)},
+		{"answered too late", true, expiresAt, SubstitutionExpired, 1, money.KZT(100000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := newSubstitutionOrder(t, OrderStatusPending)
+			proposed, err := order.ProposeSubstitution("milk", "oat-milk", 2, money.KZT(45000), TaxClassStandard, now, expiresAt)
+			if err != nil {
+				t.Fatalf("ProposeSubstitution: %v", err)
+			}
+			if proposed.Status != SubstitutionProposed || !proposed.Unpublished {
+				t.Fatalf("substitution is %s, unpublished %v; want proposed and awaiting its event", proposed.Status, proposed.Unpublished)
+			}
+			if order.Total != money.KZT(200000) || order.itemIndex("milk") < 0 {
+				t.Fatalf("a proposal changed the order to %v totalling %v", order.Items, order.Total)
+			}
+
+			s, err := order.DecideSubstitution(proposed.ID, tt.approve, tt.at)
+			if err != nil {
+				t.Fatalf("DecideSubstitution: %v", err)
+			}
+			if s.Status != tt.want || s.Unpublished {
+				t.Fatalf("substitution is %s, unpublished %v; want %s", s.Status, s.Unpublished, tt.want)
+			}
+			if order.itemIndex("milk") >= 0 || len(order.Items) != tt.wantLines {
+				t.Fatalf("order lines = %v, want %d without milk", order.Items, tt.wantLines)
+			}
+			if order.Total != tt.wantTotal {
+				t.Fatalf("total = %v, want %v", order.Total, tt.wantTotal)
+			}
+
+			if _, err := order.DecideSubstitution(proposed.ID, true, tt.at); err == nil {
+				t.Fatal("a decided substitution was decided again")
+			}
+		})
+	}
+}
+
+func TestExpireSubstitutionsDropsUnansweredLines(t *testing.T) {
+	now := time.Now()
+	expiresAt := now.Add(10 * time.Minute)
+	order := newSubstitutionOrder(t, OrderStatusPending)
+	proposed, err := order.ProposeSubstitution("milk", "oat-milk", 2, money.KZT(45000), TaxClassStandard, now, expiresAt)
+	if err != nil {
+		t.Fatalf("ProposeSubstitution: %v", err)
+	}
+
+	expired, err := order.ExpireSubstitutions(expiresAt.Add(-time.Second))
+	if err != nil || len(expired) != 0 {
+		t.Fatalf("ExpireSubstitutions before the timeout = %v, %v; want nothing", expired, err)
+	}
+
+	expired, err = order.ExpireSubstitutions(expiresAt)
+	if err != nil {
+		t.Fatalf("ExpireSubstitutions: %v", err)
+	}
+	if len(expired) != 1 || expired[0].ID != proposed.ID || expired[0].Status != SubstitutionExpired {
+		t.Fatalf("expired = %v, want substitution %s", expired, proposed.ID)
+	}
+	if len(order.Items) != 1 || order.Total != money.KZT(100000) {
+		t.Fatalf("order has %v totalling %v, want only tea at 1000.00 KZT", order.Items, order.Total)
+	}
+}
+
+func TestSubstitutionOnAPaidOrderRefundsTheDifference(t *testing.T) {
+	now := time.Now()
+	expiresAt := now.Add(10 * time.Minute)
+
+	tests := []struct {
+		name       string
+		substitute string
+		price      money.Money
+		approve    bool
+		wantRefund money.Money
+	}{
+		{"dropped line", "", money.Money{}, false, money.KZT(100000)},
+		{"cheaper substitute", "oat-milk", money.KZT(45000), true, money.KZT(10000)},
+		{"substitute at the same price", "oat-milk", money.KZT(50000), true, money.Money{}},
+		{"rejected substitute", "oat-milk", money.KZT(45000), false, money.KZT(100000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := newSubstitutionOrder(t, OrderStatusPaid)
+			s, err := order.ProposeSubstitution("milk", tt.substitute, 2, tt.price, TaxClassStandard, now, expiresAt)
+			if err != nil {
+				t.Fatalf("ProposeSubstitution: %v", err)
+			}
+			if s.Status == SubstitutionProposed {
+				if s.RefundOwed {
+					t.Fatal("a refund is owed before the customer answered")
+				}
+				if s, err = order.DecideSubstitution(s.ID, tt.approve, now.Add(time.Minute)); err != nil {
+					t.Fatalf("DecideSubstitution: %v", err)
+				}
+			}
+
+			if s.RefundAmount != tt.wantRefund || s.RefundOwed != (tt.wantRefund.Amount > 0) {
+				t.Fatalf("refund = %v, owed %v; want %v", s.RefundAmount, s.RefundOwed, tt.wantRefund)
+			}
+			if want := money.KZT(200000 - tt.wantRefund.Amount); order.Total != want {
+				t.Fatalf("total = %v, want %v", order.Total, want)
+			}
+		})
+	}
+}
+
+func TestPaidOrderRefusesAPricierSubstitute(t *testing.T) {
+	now := time.Now()
+	order := newSubstitutionOrder(t, OrderStatusPaid)
+
+	if _, err := order.ProposeSubstitution("milk", "oat-milk", 2, money.KZT(50001), TaxClassStandard, now, now.Add(time.Hour)); err == nil {
+		t.Fatal("a substitute costing more than the paid line was accepted")
+	}
+	if _, err := order.ProposeSubstitution("milk", "oat-milk", 3, money.KZT(40000), TaxClassStandard, now, now.Add(time.Hour)); err == nil {
+		t.Fatal("a larger quantity costing more than the paid line was accepted")
+	}
+	if len(order.Substitutions) != 0 || order.Total != money.KZT(200000) {
+		t.Fatalf("a refused substitute changed the order: %v, total %v", order.Substitutions, order.Total)
+	}
+
+	pending := newSubstitutionOrder(t, OrderStatusPending)
+	if _, err := pending.ProposeSubstitution("milk", "oat-milk", 2, money.KZT(60000), TaxClassStandard, now, now.Add(time.Hour)); err != nil {
+		t.Fatalf("a pricier substitute before payment: %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "proto/money"
+
+type TaxClass string
+
+const (
+	TaxClassStandard TaxClass = "standard"
+	TaxClassExempt   TaxClass = "exempt"
+)
+
+// VATRateKZ is the Kazakh VAT rate in basis points (12%).
+const VATRateKZ = 1200
+
+// Rate returns the VAT rate of the class in basis points. Unknown and empty
+// classes are taxed at the standard rate.
+func (c TaxClass) Rate() int {
+	if c == TaxClassExempt {
+		return 0
+	}
+	return VATRateKZ
+}
+
+// TaxLine sums up the order lines that share a VAT rate.
+type TaxLine struct {
+	TaxRate     int
+	NetAmount   money.Money
+	TaxAmount   money.Money
+	GrossAmount money.Money
+}
+
+// splitGross extracts the VAT contained in a VAT-inclusive amount. Shelf
+// prices in Kazakhstan include VAT, so the tax is gross*rate/(1+rate),
+// rounded to the nearest tiyn.
+func splitGross(gross money.Money, rate int) (net, tax money.Money) {
+	tax = gross.MulRatio(int64(rate), int64(10000+rate))
+	return money.New(gross.Amount-tax.Amount, gross.Currency), tax
+}
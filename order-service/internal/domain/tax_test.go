@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"proto/money"
+)
+
+func TestCalculateTotalsSplitsVATPerLineAndRate(t *testing.T) {
+	order, err := NewOrder("user-1", []OrderItem{
+		{ProductID: "bread", Quantity: 2, Price: money.KZT(56000), TaxClass: TaxClassStandard},
+		{ProductID: "milk", Quantity: 1, Price: money.KZT(45000), TaxClass: TaxClassExempt},
+		{ProductID: "tea", Quantity: 1, Price: money.KZT(10000)},
+	}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bread := order.Items[0]
+	if bread.GrossAmount != money.KZT(112000) || bread.TaxAmount != money.KZT(12000) || bread.NetAmount != money.KZT(100000) {
+		t.Fatalf("bread line = %v net, %v tax, %v gross", bread.NetAmount, bread.TaxAmount, bread.GrossAmount)
+	}
+	if milk := order.Items[1]; milk.TaxRate != 0 || milk.TaxAmount != money.KZT(0) {
+		t.Fatalf("exempt line taxed at %d: %v", milk.TaxRate, milk.TaxAmount)
+	}
+	if tea := order.Items[2]; tea.TaxClass != TaxClassStandard || tea.TaxRate != VATRateKZ {
+		t.Fatalf("line without class = %s at %d, want standard", tea.TaxClass, tea.TaxRate)
+	}
+
+	if order.Total != money.KZT(167000) {
+		t.Fatalf("total = %v, want 1670.00 KZT", order.Total)
+	}
+	if sum, _ := order.NetTotal.Add(order.TaxTotal); sum != order.Total {
+		t.Fatalf("net %v + tax %v != total %v", order.NetTotal, order.TaxTotal, order.Total)
+	}
+	if len(order.TaxBreakdown) != 2 {
+		t.Fatalf("got %d tax lines, want 2", len(order.TaxBreakdown))
+	}
+	for _, line := range order.TaxBreakdown {
+		if line.TaxRate == VATRateKZ && line.GrossAmount != money.KZT(122000) {
+			t.Errorf("standard rate gross = %v, want 1220.00 KZT", line.GrossAmount)
+		}
+		if line.TaxRate == 0 && line.GrossAmount != money.KZT(45000) {
+			t.Errorf("exempt gross = %v, want 450.00 KZT", line.GrossAmount)
+		}
+	}
+}
+
+func TestCalculateTotalsRejectsMixedCurrencies(t *testing.T) {
+	_, err := NewOrder("user-1", []OrderItem{
+		{ProductID: "bread", Quantity: 1, Price: money.KZT(56000)},
+		{ProductID: "import", Quantity: 1, Price: money.New(1000, "USD")},
+	}, "")
+	if !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Fatalf("err = %v, want currency mismatch", err)
+	}
+}
@@ -3,22 +3,179 @@ package database
 import (
 	"sync"
 	"time"
+
+	"proto/money"
 )
 
 type OrderItemDTO struct {
-	ProductID string  `bson:"product_id"`
-	Quantity  int     `bson:"quantity"`
-	Price     float64 `bson:"price"`
+	ProductID           string      `bson:"product_id"`
+	Quantity            int         `bson:"quantity"`
+	Price               money.Money `bson:"price"`
+	TaxClass            string      `bson:"tax_class"`
+	TaxRate             int         `bson:"tax_rate"`
+	NetAmount           money.Money `bson:"net_amount"`
+	TaxAmount           money.Money `bson:"tax_amount"`
+	GrossAmount         money.Money `bson:"gross_amount"`
+	Substitution        string      `bson:"substitution,omitempty"`
+	SubstituteProductID string      `bson:"substitute_product_id,omitempty"`
+}
+
+type TaxLineDTO struct {
+	TaxRate     int         `bson:"tax_rate"`
+	NetAmount   money.Money `bson:"net_amount"`
+	TaxAmount   money.Money `bson:"tax_amount"`
+	GrossAmount money.Money `bson:"gross_amount"`
+}
+
+type RefundLineDTO struct {
+	ProductID string      `bson:"product_id"`
+	Quantity  int         `bson:"quantity"`
+	Amount    money.Money `bson:"amount"`
+}
+
+type RefundDTO struct {
+	ID               string          `bson:"id"`
+	PaymentID        string          `bson:"payment_id"`
+	Amount           money.Money     `bson:"amount"`
+	Reason           string          `bson:"reason,omitempty"`
+	Lines            []RefundLineDTO `bson:"lines,omitempty"`
+	Status           string          `bson:"status,omitempty"`
+	ProviderRefundID string          `bson:"provider_refund_id"`
+	CreatedAt        time.Time       `bson:"created_at"`
+}
+
+type OrderReceiptDTO struct {
+	ID           string      `bson:"id"`
+	Type         string      `bson:"type"`
+	RefundID     string      `bson:"refund_id,omitempty"`
+	FiscalNumber string      `bson:"fiscal_number"`
+	QRURL        string      `bson:"qr_url"`
+	Total        money.Money `bson:"total"`
+	IssuedAt     time.Time   `bson:"issued_at"`
+}
+
+type PendingReceiptDTO struct {
+	ID            string     `bson:"_id"`
+	OrderID       string     `bson:"order_id"`
+	Type          string     `bson:"type"`
+	RefundID      string     `bson:"refund_id,omitempty"`
+	Method        string     `bson:"method"`
+	Attempts      int        `bson:"attempts"`
+	LastError     string     `bson:"last_error,omitempty"`
+	NextAttemptAt time.Time  `bson:"next_attempt_at"`
+	LockedUntil   *time.Time `bson:"locked_until,omitempty"`
+	CreatedAt     time.Time  `bson:"created_at"`
+}
+
+type SubstitutionDTO struct {
+	ID                  string      `bson:"id"`
+	ProductID           string      `bson:"product_id"`
+	Quantity            int         `bson:"quantity"`
+	SubstituteProductID string      `bson:"substitute_product_id,omitempty"`
+	SubstituteQuantity  int         `bson:"substitute_quantity,omitempty"`
+	Price               money.Money `bson:"price"`
+	TaxClass            string      `bson:"tax_class,omitempty"`
+	Status              string      `bson:"status"`
+	ProposedAt          time.Time   `bson:"proposed_at"`
+	ExpiresAt           time.Time   `bson:"expires_at,omitempty"`
+	DecidedAt           time.Time   `bson:"decided_at,omitempty"`
+	RefundAmount        money.Money `bson:"refund_amount,omitempty"`
+	RefundOwed          bool        `bson:"refund_owed,omitempty"`
+	Unpublished         bool        `bson:"unpublished,omitempty"`
 }
 
 type OrderDTO struct {
-	ID        string         `bson:"_id,omitempty"`
-	UserID    string         `bson:"user_id"`
-	Items     []OrderItemDTO `bson:"items"`
-	Total     float64        `bson:"total"`
-	Status    string         `bson:"status"`
-	CreatedAt time.Time      `bson:"created_at"`
-	UpdatedAt time.Time      `bson:"updated_at"`
+	ID                 string            `bson:"_id,omitempty"`
+	UserID             string            `bson:"user_id"`
+	Items              []OrderItemDTO    `bson:"items"`
+	Address            string            `bson:"address,omitempty"`
+	NetTotal           money.Money       `bson:"net_total"`
+	TaxTotal           money.Money       `bson:"tax_total"`
+	Total              money.Money       `bson:"total"`
+	TaxBreakdown       []TaxLineDTO      `bson:"tax_breakdown"`
+	Refunds            []RefundDTO       `bson:"refunds,omitempty"`
+	Receipts           []OrderReceiptDTO `bson:"receipts,omitempty"`
+	Substitutions      []SubstitutionDTO `bson:"substitutions,omitempty"`
+	Status             string            `bson:"status"`
+	ExpiredAt          *time.Time        `bson:"expired_at,omitempty"`
+	ExpiryUnpublished  bool              `bson:"expiry_unpublished,omitempty"`
+	ExpiryLockedUntil  *time.Time        `bson:"expiry_locked_until,omitempty"`
+	CreatedUnpublished bool              `bson:"created_unpublished,omitempty"`
+	CreatedLockedUntil *time.Time        `bson:"created_locked_until,omitempty"`
+	IdempotencyKey     string            `bson:"idempotency_key,omitempty"`
+	CreatedAt          time.Time         `bson:"created_at"`
+	UpdatedAt          time.Time         `bson:"updated_at"`
+}
+
+type PaymentIntentDTO struct {
+	ID                string      `bson:"_id,omitempty"`
+	OrderID           string      `bson:"order_id"`
+	Amount            money.Money `bson:"amount"`
+	Method            string      `bson:"method"`
+	Status            string      `bson:"status"`
+	Provider          string      `bson:"provider"`
+	ProviderPaymentID string      `bson:"provider_payment_id"`
+	RedirectURL       string      `bson:"redirect_url,omitempty"`
+	QRPayload         string      `bson:"qr_payload,omitempty"`
+	CreatedAt         time.Time   `bson:"created_at"`
+	UpdatedAt         time.Time   `bson:"updated_at"`
+}
+
+type ScheduleItemDTO struct {
+	ProductID string `bson:"product_id"`
+	Quantity  int    `bson:"quantity"`
+}
+
+type ScheduleDTO struct {
+	ID          string            `bson:"_id,omitempty"`
+	UserID      string            `bson:"user_id"`
+	Items       []ScheduleItemDTO `bson:"items"`
+	Address     string            `bson:"address"`
+	Cadence     string            `bson:"cadence"`
+	Status      string            `bson:"status"`
+	StartAt     time.Time         `bson:"start_at"`
+	NextRunAt   time.Time         `bson:"next_run_at"`
+	LastRunAt   time.Time         `bson:"last_run_at,omitempty"`
+	LastOrderID string            `bson:"last_order_id,omitempty"`
+	LastError   string            `bson:"last_error,omitempty"`
+	Failures    int               `bson:"failures,omitempty"`
+	LockedUntil *time.Time        `bson:"locked_until,omitempty"`
+	CreatedAt   time.Time         `bson:"created_at"`
+	UpdatedAt   time.Time         `bson:"updated_at"`
+}
+
+type ReturnLineDTO struct {
+	ProductID string `bson:"product_id"`
+	Quantity  int    `bson:"quantity"`
+	Sellable  bool   `bson:"sellable"`
+}
+
+type ReturnDTO struct {
+	ID        string          `bson:"_id,omitempty"`
+	OrderID   string          `bson:"order_id"`
+	UserID    string          `bson:"user_id"`
+	Lines     []ReturnLineDTO `bson:"lines"`
+	Reason    string          `bson:"reason"`
+	Photos    []string        `bson:"photos,omitempty"`
+	Status    string          `bson:"status"`
+	AdminNote string          `bson:"admin_note,omitempty"`
+	RefundID  string          `bson:"refund_id,omitempty"`
+	CreatedAt time.Time       `bson:"created_at"`
+	UpdatedAt time.Time       `bson:"updated_at"`
+}
+
+type CartItemDTO struct {
+	ProductID string      `json:"product_id"`
+	Quantity  int         `json:"quantity"`
+	Price     money.Money `json:"price"`
+	AddedAt   time.Time   `json:"added_at"`
+}
+
+type CartDTO struct {
+	UserID    string        `json:"user_id,omitempty"`
+	SessionID string        `json:"session_id,omitempty"`
+	Items     []CartItemDTO `json:"items"`
+	UpdatedAt time.Time     `json:"updated_at"`
 }
 
 type InMemoryDB struct {
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"log"
+
+	"proto/money"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// migrateLegacyMoney rewrites float amounts in major units into
+// {amount, currency} documents in tiyn. Until it has run, the float values
+// are still readable through money.Money's BSON decoder.
+func (m *MongoDBConnector) migrateLegacyMoney(ctx context.Context) error {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"total": bson.M{"$type": "double"}},
+		bson.M{"items.price": bson.M{"$type": "double"}},
+	}}
+
+	update := bson.A{
+		bson.M{"$set": bson.M{
+			"total":     legacyMoneyExpr("$total"),
+			"net_total": legacyMoneyExpr("$net_total"),
+			"tax_total": legacyMoneyExpr("$tax_total"),
+			"items": bson.M{"$map": bson.M{
+				"input": "$items",
+				"as":    "item",
+				"in": bson.M{"$mergeObjects": bson.A{"$$item", bson.M{
+					"price":        legacyMoneyExpr("$$item.price"),
+					"net_amount":   legacyMoneyExpr("$$item.net_amount"),
+					"tax_amount":   legacyMoneyExpr("$$item.tax_amount"),
+					"gross_amount": legacyMoneyExpr("$$item.gross_amount"),
+				}}},
+			}},
+			"tax_breakdown": bson.M{"$map": bson.M{
+				"input": bson.M{"$ifNull": bson.A{"$tax_breakdown", bson.A{}}},
+				"as":    "line",
+				"in": bson.M{"$mergeObjects": bson.A{"$$line", bson.M{
+					"net_amount":   legacyMoneyExpr("$$line.net_amount"),
+					"tax_amount":   legacyMoneyExpr("$$line.tax_amount"),
+					"gross_amount": legacyMoneyExpr("$$line.gross_amount"),
+				}}},
+			}},
+		}},
+	}
+
+	result, err := m.OrderCollection().UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.ModifiedCount > 0 {
+		log.Printf("Migrated %d orders to tiyn amounts", result.ModifiedCount)
+	}
+
+	return nil
+}
+
+// legacyMoneyExpr converts a float field in major units into a money
+// document, leaving documents and missing fields untouched.
+func legacyMoneyExpr(field string) bson.M {
+	return bson.M{"$cond": bson.A{
+		bson.M{"$eq": bson.A{bson.M{"$type": field}, "double"}},
+		bson.M{
+			"amount":   bson.M{"$toLong": bson.M{"$round": bson.A{bson.M{"$multiply": bson.A{field, 100}}, 0}}},
+			"currency": money.DefaultCurrency,
+		},
+		field,
+	}}
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"infra/moneybson"
 	"log"
 	"order-service/internal/config"
 	"time"
@@ -20,13 +21,92 @@ func (m *MongoDBConnector) OrderCollection() *mongo.Collection {
 	return m.Database.Collection("orders")
 }
 
+func (m *MongoDBConnector) PaymentCollection() *mongo.Collection {
+	return m.Database.Collection("payments")
+}
+
+func (m *MongoDBConnector) ScheduleCollection() *mongo.Collection {
+	return m.Database.Collection("schedules")
+}
+
+func (m *MongoDBConnector) ReturnCollection() *mongo.Collection {
+	return m.Database.Collection("returns")
+}
+
+func (m *MongoDBConnector) PendingReceiptCollection() *mongo.Collection {
+	return m.Database.Collection("pending_receipts")
+}
+
 func (m *MongoDBConnector) initIndexes(ctx context.Context) error {
 
 	userIDIndex := mongo.IndexModel{
 		Keys: bson.M{"user_id": 1},
 	}
 
-	_, err := m.OrderCollection().Indexes().CreateOne(ctx, userIDIndex)
+	_, err := m.OrderCollection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		userIDIndex,
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.M{"expiry_unpublished": 1}},
+		{Keys: bson.M{"created_unpublished": 1}},
+		{Keys: bson.D{{Key: "substitutions.status", Value: 1}, {Key: "substitutions.expires_at", Value: 1}}},
+		{Keys: bson.M{"substitutions.refund_owed": 1}},
+		{Keys: bson.M{"substitutions.unpublished": 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = m.PaymentCollection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.M{"order_id": 1}},
+		{Keys: bson.M{"provider_payment_id": 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// At most one open intent per order, so an order cannot be charged twice.
+	_, err = m.PaymentCollection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.M{"order_id": 1},
+		Options: options.Index().
+			SetName("order_id_pending_unique").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"status": "pending"}),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = m.OrderCollection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.M{"idempotency_key": 1},
+		Options: options.Index().
+			SetName("idempotency_key_unique").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"idempotency_key": bson.M{"$type": "string"}}),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = m.ScheduleCollection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.M{"user_id": 1}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_run_at", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = m.ReturnCollection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.M{"order_id": 1}},
+		{Keys: bson.M{"user_id": 1}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = m.PendingReceiptCollection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.M{"next_attempt_at": 1},
+	})
 	if err != nil {
 		return err
 	}
@@ -38,7 +118,7 @@ func NewMongoDB(cfg *config.Config) (*MongoDBConnector, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.MongoDB.Timeout)*time.Second)
 	defer cancel()
 
-	clientOptions := options.Client().ApplyURI(cfg.MongoDB.URI)
+	clientOptions := options.Client().ApplyURI(cfg.MongoDB.URI).SetRegistry(moneybson.Registry())
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
 		return nil, err
@@ -64,6 +144,10 @@ func NewMongoDB(cfg *config.Config) (*MongoDBConnector, error) {
 		log.Println("MongoDB indexes created successfully")
 	}
 
+	if err := mongodb.migrateLegacyMoney(ctx); err != nil {
+		log.Printf("Warning: failed to migrate legacy order amounts: %v", err)
+	}
+
 	return mongodb, nil
 }
 
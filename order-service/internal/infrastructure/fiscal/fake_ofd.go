@@ -0,0 +1,72 @@
+package fiscal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"order-service/internal/domain"
+)
+
+const FakeOFDName = "fake"
+
+// FakeOFD records receipts in memory instead of sending them to an operator.
+// Fiscal numbers are sequential and resubmitting a receipt returns its
+// original registration.
+type FakeOFD struct {
+	mu            sync.Mutex
+	qrBaseURL     string
+	receipts      []domain.FiscalReceipt
+	registrations map[string]*Registration
+}
+
+func NewFakeOFD(qrBaseURL string) *FakeOFD {
+	return &FakeOFD{
+		qrBaseURL:     qrBaseURL,
+		registrations: make(map[string]*Registration),
+	}
+}
+
+func (o *FakeOFD) Name() string {
+	return FakeOFDName
+}
+
+func (o *FakeOFD) SubmitReceipt(ctx context.Context, receipt *domain.FiscalReceipt) (*Registration, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if registration, ok := o.registrations[receipt.ID]; ok {
+		return registration, nil
+	}
+	if len(receipt.Lines) == 0 {
+		return nil, fmt.Errorf("receipt %s has no lines", receipt.ID)
+	}
+
+	fiscalNumber := fmt.Sprintf("%010d", len(o.receipts)+1)
+	registration := &Registration{
+		FiscalNumber: fiscalNumber,
+		QRURL: fmt.Sprintf("%s?i=%s&f=%s&s=%d.%02d&t=%s",
+			o.qrBaseURL, fiscalNumber, receipt.Cashier.KKMRegistrationNumber,
+			receipt.Total.Amount/100, receipt.Total.Amount%100,
+			receipt.IssuedAt.Format("20060102T150405")),
+		RegisteredAt: receipt.IssuedAt,
+	}
+
+	recorded := *receipt
+	recorded.FiscalNumber = registration.FiscalNumber
+	recorded.QRURL = registration.QRURL
+	o.receipts = append(o.receipts, recorded)
+	o.registrations[receipt.ID] = registration
+
+	return registration, nil
+}
+
+// Receipts returns every receipt submitted so far.
+func (o *FakeOFD) Receipts() []domain.FiscalReceipt {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	receipts := make([]domain.FiscalReceipt, len(o.receipts))
+	copy(receipts, o.receipts)
+	return receipts
+}
@@ -0,0 +1,21 @@
+package fiscal
+
+import (
+	"context"
+	"time"
+
+	"order-service/internal/domain"
+)
+
+// Registration is the OFD's acknowledgement of a fiscal receipt.
+type Registration struct {
+	FiscalNumber string
+	QRURL        string
+	RegisteredAt time.Time
+}
+
+// OFDProvider submits fiscal receipts to an Operator of Fiscal Data.
+type OFDProvider interface {
+	Name() string
+	SubmitReceipt(ctx context.Context, receipt *domain.FiscalReceipt) (*Registration, error)
+}
@@ -0,0 +1,105 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"order-service/internal/config"
+	"order-service/internal/domain"
+	inventorypb "proto/inventory"
+	"proto/money"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// ErrCatalogUnavailable is returned when the inventory service cannot be
+// reached. The connection is only attempted on the first call, so this is
+// where an unreachable inventory shows up.
+var ErrCatalogUnavailable = errors.New("inventory service is unavailable")
+
+type ProductInfo struct {
+	ID       string
+	Name     string
+	Price    money.Money
+	Stock    int
+	TaxClass domain.TaxClass
+}
+
+type ProductCatalog interface {
+	GetProduct(ctx context.Context, productID string) (*ProductInfo, error)
+	Close() error
+}
+
+type GRPCProductCatalog struct {
+	conn   *grpc.ClientConn
+	client inventorypb.InventoryServiceClient
+}
+
+func NewGRPCProductCatalog(cfg *config.Config) (*GRPCProductCatalog, error) {
+	conn, err := grpc.Dial(cfg.Services.Inventory, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Inventory service client configured for %s", cfg.Services.Inventory)
+
+	return &GRPCProductCatalog{
+		conn:   conn,
+		client: inventorypb.NewInventoryServiceClient(conn),
+	}, nil
+}
+
+// GetProduct returns the product with its effective tax class: the product's
+// own class, or its category's when the product does not set one.
+func (c *GRPCProductCatalog) GetProduct(ctx context.Context, productID string) (*ProductInfo, error) {
+	res, err := c.client.GetProduct(ctx, &inventorypb.ProductID{Id: productID})
+	if err != nil {
+		return nil, catalogError(err)
+	}
+	if res.GetProduct() == nil {
+		return nil, fmt.Errorf("product not found: %s", productID)
+	}
+
+	product := res.GetProduct()
+	taxClass := domain.TaxClass(product.TaxClass)
+
+	if taxClass == "" && product.CategoryId != "" {
+		catRes, err := c.client.GetCategory(ctx, &inventorypb.CategoryID{Id: product.CategoryId})
+		if err != nil {
+			return nil, catalogError(err)
+		}
+		if catRes.GetCategory() != nil {
+			taxClass = domain.TaxClass(catRes.GetCategory().TaxClass)
+		}
+	}
+
+	if taxClass == "" {
+		taxClass = domain.TaxClassStandard
+	}
+
+	return &ProductInfo{
+		ID:       product.Id,
+		Name:     product.Name,
+		Price:    money.FromProto(product.Price),
+		Stock:    int(product.Stock),
+		TaxClass: taxClass,
+	}, nil
+}
+
+func catalogError(err error) error {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return fmt.Errorf("%w: %v", ErrCatalogUnavailable, err)
+	default:
+		return err
+	}
+}
+
+func (c *GRPCProductCatalog) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,22 @@
+package invoice
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+)
+
+//go:embed templates/invoice.html.tmpl
+var templateFS embed.FS
+
+var htmlTemplate = template.Must(template.New("invoice.html.tmpl").Funcs(template.FuncMap{
+	"money": FormatMoney,
+}).ParseFS(templateFS, "templates/invoice.html.tmpl"))
+
+func RenderHTML(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
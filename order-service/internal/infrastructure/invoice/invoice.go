@@ -0,0 +1,227 @@
+package invoice
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"order-service/internal/domain"
+	"proto/money"
+)
+
+type Locale string
+
+const (
+	LocaleRU Locale = "ru"
+	LocaleKK Locale = "kk"
+)
+
+// ParseLocale maps a language tag such as "kk-KZ" to a supported locale and
+// falls back to Russian.
+func ParseLocale(tag string) Locale {
+	if strings.HasPrefix(strings.ToLower(strings.TrimSpace(tag)), "kk") {
+		return LocaleKK
+	}
+	return LocaleRU
+}
+
+type Company struct {
+	Name     string
+	BIN      string
+	Address  string
+	BankName string
+	IBAN     string
+	BIK      string
+}
+
+// Buyer is the customer the invoice is made out to. B2B clients pass their
+// company name and BIN; otherwise the invoice names the customer account.
+type Buyer struct {
+	Name   string
+	BIN    string
+	UserID string
+}
+
+type Line struct {
+	Number    int
+	Name      string
+	Quantity  int
+	UnitPrice money.Money
+	TaxRate   int
+	TaxAmount money.Money
+	Amount    money.Money
+}
+
+type Receipt struct {
+	Type         domain.ReceiptType
+	FiscalNumber string
+	QRURL        string
+	Total        money.Money
+}
+
+type Document struct {
+	Number        string
+	IssuedAt      time.Time
+	Locale        Locale
+	Seller        Company
+	Buyer         Buyer
+	Lines         []Line
+	TaxBreakdown  []domain.TaxLine
+	NetTotal      money.Money
+	TaxTotal      money.Money
+	Total         money.Money
+	RefundedTotal money.Money
+	Receipts      []Receipt
+}
+
+// NewDocument lays out an order as an invoice. names maps product IDs to
+// display names; products without a name are shown by ID.
+func NewDocument(order *domain.Order, names map[string]string, seller Company, buyer Buyer, locale Locale) *Document {
+	lines := make([]Line, len(order.Items))
+	for i, item := range order.Items {
+		name := names[item.ProductID]
+		if name == "" {
+			name = item.ProductID
+		}
+		lines[i] = Line{
+			Number:    i + 1,
+			Name:      name,
+			Quantity:  item.Quantity,
+			UnitPrice: item.Price,
+			TaxRate:   item.TaxRate,
+			TaxAmount: item.TaxAmount,
+			Amount:    item.GrossAmount,
+		}
+	}
+
+	receipts := make([]Receipt, len(order.Receipts))
+	for i, receipt := range order.Receipts {
+		receipts[i] = Receipt{
+			Type:         receipt.Type,
+			FiscalNumber: receipt.FiscalNumber,
+			QRURL:        receipt.QRURL,
+			Total:        receipt.Total,
+		}
+	}
+
+	if buyer.UserID == "" {
+		buyer.UserID = order.UserID
+	}
+
+	return &Document{
+		Number:        Number(order),
+		IssuedAt:      order.CreatedAt,
+		Locale:        locale,
+		Seller:        seller,
+		Buyer:         buyer,
+		Lines:         lines,
+		TaxBreakdown:  order.TaxBreakdown,
+		NetTotal:      order.NetTotal,
+		TaxTotal:      order.TaxTotal,
+		Total:         order.Total,
+		RefundedTotal: order.RefundedTotal(),
+		Receipts:      receipts,
+	}
+}
+
+// Number derives a stable invoice number from the order ID.
+func Number(order *domain.Order) string {
+	id := strings.ReplaceAll(order.ID, "-", "")
+	if len(id) > 10 {
+		id = id[:10]
+	}
+	return "INV-" + order.CreatedAt.Format("20060102") + "-" + strings.ToUpper(id)
+}
+
+func (d *Document) Labels() Labels {
+	if d.Locale == LocaleKK {
+		return labelsKK
+	}
+	return labelsRU
+}
+
+// FormatMoney formats an amount the way Kazakh documents do: spaces between
+// thousands, a decimal comma and the tenge sign.
+func FormatMoney(m money.Money) string {
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := fmt.Sprintf("%d", amount/100)
+	var grouped strings.Builder
+	for i, r := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteRune(' ')
+		}
+		grouped.WriteRune(r)
+	}
+
+	symbol := m.Currency
+	if symbol == "" || symbol == money.DefaultCurrency {
+		symbol = "₸"
+	}
+	return fmt.Sprintf("%s%s,%02d %s", sign, grouped.String(), amount%100, symbol)
+}
+
+// Rate formats a VAT rate given in basis points.
+func (d *Document) Rate(rate int) string {
+	if rate == 0 {
+		return d.Labels().NoVAT
+	}
+	if rate%100 == 0 {
+		return fmt.Sprintf("%d%%", rate/100)
+	}
+	return fmt.Sprintf("%d,%02d%%", rate/100, rate%100)
+}
+
+func (d *Document) ReceiptTypeLabel(receiptType domain.ReceiptType) string {
+	labels := d.Labels()
+	if receiptType == domain.ReceiptTypeSaleReturn {
+		return labels.ReturnReceipt
+	}
+	return labels.SaleReceipt
+}
+
+func (d *Document) Heading() string {
+	labels := d.Labels()
+	date := d.IssuedAt.Format("02.01.2006")
+	if d.Locale == LocaleKK {
+		return fmt.Sprintf("%s № %s, %s %s", labels.Title, d.Number, date, labels.Date)
+	}
+	return fmt.Sprintf("%s № %s %s %s", labels.Title, d.Number, labels.Date, date)
+}
+
+const (
+	FormatHTML = "html"
+	FormatPDF  = "pdf"
+)
+
+// File is a rendered invoice ready to be downloaded.
+type File struct {
+	ContentType string
+	Filename    string
+	Content     []byte
+}
+
+// Render produces the invoice in the requested format, HTML by default.
+func Render(doc *Document, format string) (*File, error) {
+	switch format {
+	case FormatPDF:
+		content, err := RenderPDF(doc)
+		if err != nil {
+			return nil, err
+		}
+		return &File{ContentType: "application/pdf", Filename: doc.Number + ".pdf", Content: content}, nil
+	case FormatHTML, "":
+		content, err := RenderHTML(doc)
+		if err != nil {
+			return nil, err
+		}
+		return &File{ContentType: "text/html; charset=utf-8", Filename: doc.Number + ".html", Content: content}, nil
+	default:
+		return nil, fmt.Errorf("unsupported invoice format: %s", format)
+	}
+}
@@ -0,0 +1,107 @@
+package invoice
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"order-service/internal/domain"
+	"proto/money"
+)
+
+// newInvoiceOrder returns an order of 2 000.00 KZT: tea at 12% VAT and milk
+// exempt from it.
+func newInvoiceOrder(t *testing.T) *domain.Order {
+	t.Helper()
+	order, err := domain.NewOrder("u1", []domain.OrderItem{
+		{ProductID: "tea", Quantity: 1, Price: money.KZT(100000), TaxClass: domain.TaxClassStandard},
+		{ProductID: "milk", Quantity: 2, Price: money.KZT(50000), TaxClass: domain.TaxClassExempt},
+	}, domain.OrderStatusPending)
+	if err != nil {
+		t.Fatalf("NewOrder: %v", err)
+	}
+	order.ID = "3f2a9c1e-0000-4000-8000-000000000001"
+	order.CreatedAt = time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	return order
+}
+
+func TestNewDocumentCarriesTheVATOfEveryLine(t *testing.T) {
+	doc := NewDocument(newInvoiceOrder(t), map[string]string{"tea": "Чай"}, Company{Name: "KazakhDelivery"}, Buyer{}, LocaleRU)
+
+	if doc.Total != money.KZT(200000) || doc.TaxTotal != money.KZT(10714) || doc.NetTotal != money.KZT(189286) {
+		t.Fatalf("totals = %s net %s VAT %s, want 2000.00, net 1892.86, VAT 107.14", doc.Total, doc.NetTotal, doc.TaxTotal)
+	}
+	if doc.Lines[0].Name != "Чай" || doc.Lines[0].TaxRate != 1200 || doc.Lines[0].TaxAmount != money.KZT(10714) {
+		t.Fatalf("tea line = %+v, want Чай at 12%% with 107.14 VAT", doc.Lines[0])
+	}
+	if doc.Lines[1].Name != "milk" || doc.Lines[1].TaxRate != 0 || !doc.Lines[1].TaxAmount.IsZero() || doc.Lines[1].Amount != money.KZT(100000) {
+		t.Fatalf("milk line = %+v, want it by ID, without VAT, for 1000.00", doc.Lines[1])
+	}
+	if doc.Buyer.UserID != "u1" || doc.Number != "INV-20260314-3F2A9C1E00" {
+		t.Fatalf("buyer %q, number %q", doc.Buyer.UserID, doc.Number)
+	}
+}
+
+func TestRenderHTMLShowsTotalsAndTaxLines(t *testing.T) {
+	doc := NewDocument(newInvoiceOrder(t), nil, Company{Name: "KazakhDelivery", BIN: "123456789012"}, Buyer{Name: "ТОО Ромашка"}, LocaleRU)
+
+	file, err := Render(doc, FormatHTML)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if file.ContentType != "text/html; charset=utf-8" || file.Filename != doc.Number+".html" {
+		t.Fatalf("file is %s named %s", file.ContentType, file.Filename)
+	}
+
+	html := string(file.Content)
+	for _, want := range []string{
+		"Счёт на оплату № INV-20260314-3F2A9C1E00 от 14.03.2026",
+		"ТОО Ромашка",
+		"БИН: 123456789012",
+		`<td class="num">12%</td>`,
+		`<td class="num">Без НДС</td>`,
+		"<tr><td>Итого без НДС:</td><td class=\"num\">1 892,86 ₸</td></tr>",
+		"<tr><td>в т.ч. НДС 12%:</td><td class=\"num\">107,14 ₸</td></tr>",
+		"<tr class=\"grand\"><td>Всего к оплате:</td><td class=\"num\">2 000,00 ₸</td></tr>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("invoice lacks %q", want)
+		}
+	}
+	// Exempt lines add no row of their own to the VAT totals.
+	if n := strings.Count(html, "в т.ч. НДС"); n != 1 {
+		t.Errorf("invoice has %d VAT total rows, want 1", n)
+	}
+	if strings.Contains(html, "Возвращено") {
+		t.Error("invoice of an unrefunded order shows a refund")
+	}
+}
+
+func TestRenderHTMLInKazakh(t *testing.T) {
+	doc := NewDocument(newInvoiceOrder(t), nil, Company{Name: "KazakhDelivery"}, Buyer{}, ParseLocale("kk-KZ"))
+
+	content, err := RenderHTML(doc)
+	if err != nil {
+		t.Fatalf("RenderHTML: %v", err)
+	}
+	if !strings.Contains(string(content), `<html lang="kk">`) || !strings.Contains(string(content), labelsKK.Title) {
+		t.Fatal("invoice is not in Kazakh")
+	}
+}
+
+func TestRenderPDF(t *testing.T) {
+	doc := NewDocument(newInvoiceOrder(t), nil, Company{Name: "KazakhDelivery"}, Buyer{}, LocaleKK)
+
+	file, err := Render(doc, FormatPDF)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if file.ContentType != "application/pdf" || !bytes.HasPrefix(file.Content, []byte("%PDF-")) {
+		t.Fatalf("file is %s starting with %q", file.ContentType, file.Content[:min(len(file.Content), 8)])
+	}
+
+	if _, err := Render(doc, "docx"); err == nil {
+		t.Fatal("rendered an unsupported format")
+	}
+}
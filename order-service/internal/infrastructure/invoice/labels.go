@@ -0,0 +1,88 @@
+package invoice
+
+type Labels struct {
+	Title         string
+	Date          string
+	Seller        string
+	Buyer         string
+	Customer      string
+	BIN           string
+	Address       string
+	Bank          string
+	IBAN          string
+	BIK           string
+	LineNumber    string
+	Name          string
+	Quantity      string
+	UnitPrice     string
+	VATRate       string
+	VATAmount     string
+	Amount        string
+	NetTotal      string
+	VATTotal      string
+	Total         string
+	Refunded      string
+	VATIncluded   string
+	NoVAT         string
+	Receipts      string
+	SaleReceipt   string
+	ReturnReceipt string
+}
+
+var labelsRU = Labels{
+	Title:         "Счёт на оплату",
+	Date:          "от",
+	Seller:        "Поставщик",
+	Buyer:         "Покупатель",
+	Customer:      "Клиент",
+	BIN:           "БИН",
+	Address:       "Адрес",
+	Bank:          "Банк",
+	IBAN:          "ИИК",
+	BIK:           "БИК",
+	LineNumber:    "№",
+	Name:          "Наименование",
+	Quantity:      "Кол-во",
+	UnitPrice:     "Цена",
+	VATRate:       "Ставка НДС",
+	VATAmount:     "Сумма НДС",
+	Amount:        "Сумма",
+	NetTotal:      "Итого без НДС",
+	VATTotal:      "НДС",
+	Total:         "Всего к оплате",
+	Refunded:      "Возвращено",
+	VATIncluded:   "в т.ч. НДС",
+	NoVAT:         "Без НДС",
+	Receipts:      "Фискальные чеки",
+	SaleReceipt:   "Продажа",
+	ReturnReceipt: "Возврат продажи",
+}
+
+var labelsKK = Labels{
+	Title:         "Төлем шоты",
+	Date:          "күні",
+	Seller:        "Жеткізуші",
+	Buyer:         "Сатып алушы",
+	Customer:      "Клиент",
+	BIN:           "БСН",
+	Address:       "Мекенжай",
+	Bank:          "Банк",
+	IBAN:          "ЖСК",
+	BIK:           "БСК",
+	LineNumber:    "№",
+	Name:          "Атауы",
+	Quantity:      "Саны",
+	UnitPrice:     "Бағасы",
+	VATRate:       "ҚҚС мөлшерлемесі",
+	VATAmount:     "ҚҚС сомасы",
+	Amount:        "Сомасы",
+	NetTotal:      "ҚҚС-сыз барлығы",
+	VATTotal:      "ҚҚС",
+	Total:         "Төлеуге барлығы",
+	Refunded:      "Қайтарылды",
+	VATIncluded:   "оның ішінде ҚҚС",
+	NoVAT:         "ҚҚС-сыз",
+	Receipts:      "Фискалдық чектер",
+	SaleReceipt:   "Сату",
+	ReturnReceipt: "Сатуды қайтару",
+}
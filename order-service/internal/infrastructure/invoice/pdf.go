@@ -0,0 +1,183 @@
+package invoice
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// DejaVu Sans covers Cyrillic including the Kazakh letters, which the core
+// PDF fonts do not.
+var (
+	//go:embed fonts/DejaVuSans.ttf
+	fontRegular []byte
+	//go:embed fonts/DejaVuSans-Bold.ttf
+	fontBold []byte
+)
+
+const (
+	fontFamily = "DejaVu"
+	lineHeight = 5.0
+)
+
+var columnWidths = []float64{8, 62, 16, 27, 22, 27, 28}
+
+// RenderPDF draws the invoice as an A4 PDF without external tools. Output is
+// deterministic for the same document.
+func RenderPDF(doc *Document) ([]byte, error) {
+	labels := doc.Labels()
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(fontFamily, "", fontRegular)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", fontBold)
+	pdf.SetTitle(doc.Heading(), true)
+	pdf.SetCreationDate(doc.IssuedAt)
+	pdf.SetModificationDate(doc.IssuedAt)
+	pdf.SetCatalogSort(true)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AddPage()
+
+	pdf.SetFont(fontFamily, "B", 14)
+	pdf.MultiCell(0, 7, doc.Heading(), "", "L", false)
+	pdf.Ln(4)
+
+	drawParties(pdf, doc, labels)
+	pdf.Ln(4)
+
+	pdf.SetFont(fontFamily, "B", 8)
+	pdf.SetFillColor(235, 235, 235)
+	drawRow(pdf, []string{
+		labels.LineNumber, labels.Name, labels.Quantity, labels.UnitPrice,
+		labels.VATRate, labels.VATAmount, labels.Amount,
+	}, "CCCCCCC", true)
+
+	pdf.SetFont(fontFamily, "", 8)
+	for _, line := range doc.Lines {
+		drawRow(pdf, []string{
+			fmt.Sprintf("%d", line.Number),
+			line.Name,
+			fmt.Sprintf("%d", line.Quantity),
+			FormatMoney(line.UnitPrice),
+			doc.Rate(line.TaxRate),
+			FormatMoney(line.TaxAmount),
+			FormatMoney(line.Amount),
+		}, "RLRRRRR", false)
+	}
+	pdf.Ln(3)
+
+	pdf.SetFont(fontFamily, "", 9)
+	drawTotal(pdf, labels.NetTotal, FormatMoney(doc.NetTotal))
+	for _, taxLine := range doc.TaxBreakdown {
+		if taxLine.TaxRate == 0 {
+			continue
+		}
+		drawTotal(pdf, labels.VATIncluded+" "+doc.Rate(taxLine.TaxRate), FormatMoney(taxLine.TaxAmount))
+	}
+	pdf.SetFont(fontFamily, "B", 11)
+	drawTotal(pdf, labels.Total, FormatMoney(doc.Total))
+	if !doc.RefundedTotal.IsZero() {
+		pdf.SetFont(fontFamily, "", 9)
+		drawTotal(pdf, labels.Refunded, FormatMoney(doc.RefundedTotal))
+	}
+
+	if len(doc.Receipts) > 0 {
+		pdf.Ln(6)
+		pdf.SetFont(fontFamily, "B", 10)
+		pdf.CellFormat(0, 6, labels.Receipts, "", 1, "L", false, 0, "")
+		pdf.SetFont(fontFamily, "", 8)
+		for _, receipt := range doc.Receipts {
+			pdf.MultiCell(0, lineHeight, fmt.Sprintf("%s № %s, %s: %s",
+				doc.ReceiptTypeLabel(receipt.Type), receipt.FiscalNumber,
+				FormatMoney(receipt.Total), receipt.QRURL), "", "L", false)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func drawParties(pdf *fpdf.Fpdf, doc *Document, labels Labels) {
+	seller := []string{doc.Seller.Name}
+	for _, field := range [][2]string{
+		{labels.BIN, doc.Seller.BIN},
+		{labels.Address, doc.Seller.Address},
+		{labels.Bank, doc.Seller.BankName},
+		{labels.IBAN, doc.Seller.IBAN},
+		{labels.BIK, doc.Seller.BIK},
+	} {
+		if field[1] != "" {
+			seller = append(seller, field[0]+": "+field[1])
+		}
+	}
+
+	var buyer []string
+	if doc.Buyer.Name != "" {
+		buyer = append(buyer, doc.Buyer.Name)
+	}
+	if doc.Buyer.BIN != "" {
+		buyer = append(buyer, labels.BIN+": "+doc.Buyer.BIN)
+	}
+	buyer = append(buyer, labels.Customer+": "+doc.Buyer.UserID)
+
+	left, top := pdf.GetXY()
+	drawParty(pdf, left, top, labels.Seller, seller)
+	sellerBottom := pdf.GetY()
+	drawParty(pdf, left+100, top, labels.Buyer, buyer)
+	if sellerBottom > pdf.GetY() {
+		pdf.SetY(sellerBottom)
+	}
+}
+
+func drawParty(pdf *fpdf.Fpdf, x, y float64, title string, lines []string) {
+	pdf.SetXY(x, y)
+	pdf.SetFont(fontFamily, "B", 10)
+	pdf.CellFormat(90, 6, title, "", 2, "L", false, 0, "")
+	pdf.SetFont(fontFamily, "", 9)
+	for _, line := range lines {
+		pdf.SetX(x)
+		pdf.MultiCell(90, lineHeight, line, "", "L", false)
+	}
+}
+
+// drawRow draws one table row, wrapping every cell so the row is as tall as
+// its longest cell.
+func drawRow(pdf *fpdf.Fpdf, cells []string, aligns string, fill bool) {
+	wrapped := make([][]string, len(cells))
+	rows := 1
+	for i, cell := range cells {
+		wrapped[i] = pdf.SplitText(cell, columnWidths[i]-2)
+		if len(wrapped[i]) > rows {
+			rows = len(wrapped[i])
+		}
+	}
+	height := float64(rows) * lineHeight
+
+	_, pageHeight := pdf.GetPageSize()
+	_, _, _, bottom := pdf.GetMargins()
+	if pdf.GetY()+height > pageHeight-bottom {
+		pdf.AddPage()
+	}
+
+	left, y := pdf.GetXY()
+	x := left
+	for i, lines := range wrapped {
+		pdf.SetXY(x, y)
+		pdf.CellFormat(columnWidths[i], height, "", "1", 0, "", fill, 0, "")
+		for j, line := range lines {
+			pdf.SetXY(x, y+float64(j)*lineHeight)
+			pdf.CellFormat(columnWidths[i], lineHeight, line, "", 0, string(aligns[i]), false, 0, "")
+		}
+		x += columnWidths[i]
+	}
+	pdf.SetXY(left, y+height)
+}
+
+func drawTotal(pdf *fpdf.Fpdf, label, value string) {
+	pdf.CellFormat(142, 6, label+":", "", 0, "R", false, 0, "")
+	pdf.CellFormat(48, 6, value, "", 1, "R", false, 0, "")
+}
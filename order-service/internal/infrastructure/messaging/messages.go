@@ -1,19 +1,44 @@
 package messaging
 
-type OrderItem struct {
-	ProductID string  `json:"product_id"`
-	Quantity  int     `json:"quantity"`
-	Price     float64 `json:"price"`
-}
+import "proto/events"
 
-type OrderCreatedEvent struct {
-	OrderID   string      `json:"order_id"`
-	UserID    string      `json:"user_id"`
-	Items     []OrderItem `json:"items"`
-	Total     float64     `json:"total"`
-	Timestamp int64       `json:"timestamp"`
-}
+// The events are defined in proto/events, shared with their consumers.
+type (
+	OrderItem                  = events.OrderItem
+	OrderCreatedEvent          = events.OrderCreated
+	RefundedItem               = events.RefundedItem
+	OrderRefundedEvent         = events.OrderRefunded
+	ScheduledOrderCreatedEvent = events.ScheduledOrderCreated
+	StockDelta                 = events.StockDelta
+	OrderModifiedEvent         = events.OrderModified
+	SubstitutionProposedEvent  = events.SubstitutionProposed
+	ReturnedItem               = events.ReturnedItem
+	ReturnReceivedEvent        = events.ReturnReceived
+	OrderExpiredEvent          = events.OrderExpired
+	OrderStatusChangedEvent    = events.OrderStatusChanged
+)
 
 const (
-	SubjectOrderCreated = "order.created"
+	SubjectOrderCreated  = events.TypeOrderCreated
+	SubjectOrderExpired  = events.TypeOrderExpired
+	SubjectOrderModified = events.TypeOrderModified
+
+	SubjectSubstitutionProposed = events.TypeSubstitutionProposed
+	SubjectReturnReceived       = events.TypeReturnReceived
+	SubjectOrderRefunded        = events.TypeOrderRefunded
+	SubjectOrderScheduled       = events.TypeOrderScheduled
+
+	SubjectOrderConfirmed  = events.TypeOrderConfirmed
+	SubjectOrderDispatched = events.TypeOrderDispatched
+	SubjectOrderDelivered  = events.TypeOrderDelivered
+	SubjectOrderCancelled  = events.TypeOrderCancelled
 )
+
+// eventSource is the CloudEvents source of the events published here.
+const eventSource = "/order-service"
+
+// StatusSubject returns the subject announcing that an order reached status.
+// Statuses with events of their own, such as paid or refunded, have none.
+func StatusSubject(status string) (string, bool) {
+	return events.StatusType(status)
+}
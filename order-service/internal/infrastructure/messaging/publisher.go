@@ -1,22 +1,34 @@
 package messaging
 
 import (
-	"encoding/json"
 	"fmt"
 	"log"
 	"order-service/internal/config"
 	"time"
 
+	"infra/streams"
+	"proto/events"
+
 	"github.com/nats-io/nats.go"
 )
 
 type EventPublisher interface {
 	PublishOrderCreated(event OrderCreatedEvent) error
+	PublishOrderRefunded(event OrderRefundedEvent) error
+	PublishScheduledOrderCreated(event ScheduledOrderCreatedEvent) error
+	PublishOrderExpired(event OrderExpiredEvent) error
+	PublishOrderModified(event OrderModifiedEvent) error
+	PublishSubstitutionProposed(event SubstitutionProposedEvent) error
+	PublishReturnReceived(event ReturnReceivedEvent) error
+	PublishOrderStatusChanged(event OrderStatusChangedEvent) error
 	Close()
 }
 
+// NATSPublisher publishes to JetStream and waits until the stream stored the
+// event.
 type NATSPublisher struct {
 	conn *nats.Conn
+	js   nats.JetStreamContext
 }
 
 func NewNATSPublisher(cfg *config.Config) (*NATSPublisher, error) {
@@ -31,10 +43,22 @@ func NewNATSPublisher(cfg *config.Config) (*NATSPublisher, error) {
 		return nil, err
 	}
 
+	js, err := nc.JetStream()
+	if err == nil {
+		err = streams.EnsureStream(js, streams.OrderStream)
+	}
+	if err != nil {
+		log.Printf("[%s] Failed to set up JetStream: %v [latency: %v]",
+			time.Now().Format(time.RFC3339Nano), err, time.Since(startTime))
+		nc.Close()
+		return nil, err
+	}
+
 	log.Printf("[%s] Successfully connected to NATS [latency: %v]",
 		time.Now().Format(time.RFC3339Nano), time.Since(startTime))
 	return &NATSPublisher{
 		conn: nc,
+		js:   js,
 	}, nil
 }
 
@@ -47,17 +71,17 @@ func (p *NATSPublisher) PublishOrderCreated(event OrderCreatedEvent) error {
 		event.Timestamp = time.Now().UnixNano()
 	}
 
-	eventBytes, err := json.Marshal(event)
+	msg, err := newEventMsg(SubjectOrderCreated, event.OrderID, &event)
 	if err != nil {
 		log.Printf("[%s] Error marshalling order created event: %v [latency: %v]",
 			time.Now().Format(time.RFC3339Nano), err, time.Since(startTime))
 		return err
 	}
 
-	messageSizeKB := float64(len(eventBytes)) / 1024.0
+	messageSizeKB := float64(len(msg.Data)) / 1024.0
 
 	publishTime := time.Now()
-	err = p.conn.Publish(SubjectOrderCreated, eventBytes)
+	_, err = p.js.PublishMsg(msg)
 	if err != nil {
 		log.Printf("[%s] Error publishing order created event: %v [latency: %v]",
 			time.Now().Format(time.RFC3339Nano), err, time.Since(startTime))
@@ -75,6 +99,104 @@ func (p *NATSPublisher) PublishOrderCreated(event OrderCreatedEvent) error {
 	return nil
 }
 
+func (p *NATSPublisher) PublishOrderRefunded(event OrderRefundedEvent) error {
+	if event.Timestamp == 0 {
+		event.Timestamp = time.Now().UnixNano()
+	}
+
+	return p.publish(SubjectOrderRefunded, event.OrderID, &event)
+}
+
+func (p *NATSPublisher) PublishScheduledOrderCreated(event ScheduledOrderCreatedEvent) error {
+	if event.Timestamp == 0 {
+		event.Timestamp = time.Now().UnixNano()
+	}
+
+	return p.publish(SubjectOrderScheduled, event.OrderID, &event)
+}
+
+func (p *NATSPublisher) PublishOrderExpired(event OrderExpiredEvent) error {
+	if event.Timestamp == 0 {
+		event.Timestamp = time.Now().UnixNano()
+	}
+
+	return p.publish(SubjectOrderExpired, event.OrderID, &event)
+}
+
+func (p *NATSPublisher) PublishOrderModified(event OrderModifiedEvent) error {
+	if event.Timestamp == 0 {
+		event.Timestamp = time.Now().UnixNano()
+	}
+
+	return p.publish(SubjectOrderModified, event.OrderID, &event)
+}
+
+func (p *NATSPublisher) PublishSubstitutionProposed(event SubstitutionProposedEvent) error {
+	if event.Timestamp == 0 {
+		event.Timestamp = time.Now().UnixNano()
+	}
+
+	return p.publish(SubjectSubstitutionProposed, event.OrderID, &event)
+}
+
+func (p *NATSPublisher) PublishReturnReceived(event ReturnReceivedEvent) error {
+	if event.Timestamp == 0 {
+		event.Timestamp = time.Now().UnixNano()
+	}
+
+	return p.publish(SubjectReturnReceived, event.OrderID, &event)
+}
+
+func (p *NATSPublisher) PublishOrderStatusChanged(event OrderStatusChangedEvent) error {
+	subject, ok := StatusSubject(event.Status)
+	if !ok {
+		return fmt.Errorf("no event subject for order status %s", event.Status)
+	}
+	if event.Timestamp == 0 {
+		event.Timestamp = time.Now().UnixNano()
+	}
+
+	return p.publish(subject, event.OrderID, &event)
+}
+
+func (p *NATSPublisher) publish(subject, orderID string, event events.Event) error {
+	startTime := time.Now()
+
+	msg, err := newEventMsg(subject, orderID, event)
+	if err != nil {
+		log.Printf("[%s] Error marshalling %s event: %v [latency: %v]",
+			time.Now().Format(time.RFC3339Nano), subject, err, time.Since(startTime))
+		return err
+	}
+
+	if _, err := p.js.PublishMsg(msg); err != nil {
+		log.Printf("[%s] Error publishing %s event: %v [latency: %v]",
+			time.Now().Format(time.RFC3339Nano), subject, err, time.Since(startTime))
+		return err
+	}
+
+	log.Printf("[%s] Published %s event for order ID: %s (%.2f KB) [latency: %v]",
+		time.Now().Format(time.RFC3339Nano), subject, orderID,
+		float64(len(msg.Data))/1024.0, time.Since(startTime))
+
+	return nil
+}
+
+// newEventMsg wraps event in a CloudEvents envelope of the subject's type,
+// with the envelope in the headers and the event in protobuf in the body.
+func newEventMsg(subject, orderID string, event events.Event) (*nats.Msg, error) {
+	envelope, err := events.New(eventSource, subject, orderID, event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nats.Msg{
+		Subject: subject,
+		Header:  envelope.Header(),
+		Data:    envelope.Data,
+	}, nil
+}
+
 func (p *NATSPublisher) Close() {
 	closeTime := time.Now()
 
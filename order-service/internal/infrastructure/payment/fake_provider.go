@@ -0,0 +1,122 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"order-service/internal/domain"
+	"proto/money"
+)
+
+const FakeProviderName = "fake"
+
+// FakeProvider is a deterministic provider for local runs and tests. The same
+// intent always gets the same provider ID, URLs and callback signature, and
+// callbacks are signed with HMAC-SHA256 like a real acquirer would.
+type FakeProvider struct {
+	secret  []byte
+	baseURL string
+}
+
+type fakeCallbackPayload struct {
+	PaymentID string `json:"payment_id"`
+	Status    string `json:"status"`
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+}
+
+func NewFakeProvider(secret, baseURL string) *FakeProvider {
+	return &FakeProvider{
+		secret:  []byte(secret),
+		baseURL: baseURL,
+	}
+}
+
+func (p *FakeProvider) Name() string {
+	return FakeProviderName
+}
+
+func (p *FakeProvider) CreateCardPayment(ctx context.Context, intent *domain.PaymentIntent) (*CheckoutSession, error) {
+	paymentID := p.paymentID(intent)
+	return &CheckoutSession{
+		ProviderPaymentID: paymentID,
+		RedirectURL:       fmt.Sprintf("%s/card/%s", p.baseURL, paymentID),
+	}, nil
+}
+
+func (p *FakeProvider) CreateQRPayment(ctx context.Context, intent *domain.PaymentIntent) (*CheckoutSession, error) {
+	paymentID := p.paymentID(intent)
+	return &CheckoutSession{
+		ProviderPaymentID: paymentID,
+		QRPayload: fmt.Sprintf("%s/qr/%s?amount=%d&currency=%s",
+			p.baseURL, paymentID, intent.Amount.Amount, intent.Amount.Currency),
+	}, nil
+}
+
+func (p *FakeProvider) CancelPayment(ctx context.Context, intent *domain.PaymentIntent) error {
+	if intent.Status != domain.PaymentStatusPending {
+		return fmt.Errorf("payment %s is %s, not pending", p.paymentID(intent), intent.Status)
+	}
+	return nil
+}
+
+func (p *FakeProvider) ParseCallback(payload []byte, signature string) (*CallbackEvent, error) {
+	if !hmac.Equal([]byte(p.Sign(payload)), []byte(signature)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var body fakeCallbackPayload
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return nil, fmt.Errorf("invalid callback payload: %w", err)
+	}
+
+	return &CallbackEvent{
+		ProviderPaymentID: body.PaymentID,
+		Status:            domain.PaymentStatus(body.Status),
+		Amount:            money.New(body.Amount, body.Currency),
+	}, nil
+}
+
+func (p *FakeProvider) Refund(ctx context.Context, intent *domain.PaymentIntent, refund *domain.Refund) (string, error) {
+	if intent.Status != domain.PaymentStatusSucceeded {
+		return "", fmt.Errorf("payment %s is %s, not captured", p.paymentID(intent), intent.Status)
+	}
+	if refund.Amount.Amount > intent.Amount.Amount {
+		return "", fmt.Errorf("refund of %s exceeds captured %s", refund.Amount, intent.Amount)
+	}
+
+	sum := sha256.Sum256([]byte(refund.ID))
+	return "fake_rf_" + hex.EncodeToString(sum[:8]), nil
+}
+
+// Sign returns the hex HMAC-SHA256 signature the fake acquirer puts on a
+// callback payload.
+func (p *FakeProvider) Sign(payload []byte) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// BuildCallback produces the signed webhook the fake acquirer would send when
+// the intent reaches the given status.
+func (p *FakeProvider) BuildCallback(intent *domain.PaymentIntent, status domain.PaymentStatus) ([]byte, string, error) {
+	payload, err := json.Marshal(fakeCallbackPayload{
+		PaymentID: p.paymentID(intent),
+		Status:    string(status),
+		Amount:    intent.Amount.Amount,
+		Currency:  intent.Amount.Currency,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, p.Sign(payload), nil
+}
+
+func (p *FakeProvider) paymentID(intent *domain.PaymentIntent) string {
+	sum := sha256.Sum256([]byte(intent.ID))
+	return "fake_" + hex.EncodeToString(sum[:8])
+}
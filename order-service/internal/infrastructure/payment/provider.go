@@ -0,0 +1,41 @@
+package payment
+
+import (
+	"context"
+	"errors"
+
+	"order-service/internal/domain"
+	"proto/money"
+)
+
+var ErrInvalidSignature = errors.New("invalid payment callback signature")
+
+// CheckoutSession is what the customer needs to complete a payment: a hosted
+// card page or a QR payload for the banking app.
+type CheckoutSession struct {
+	ProviderPaymentID string
+	RedirectURL       string
+	QRPayload         string
+}
+
+// CallbackEvent is a verified status update received from the provider.
+type CallbackEvent struct {
+	ProviderPaymentID string
+	Status            domain.PaymentStatus
+	Amount            money.Money
+}
+
+type PaymentProvider interface {
+	Name() string
+	CreateCardPayment(ctx context.Context, intent *domain.PaymentIntent) (*CheckoutSession, error)
+	CreateQRPayment(ctx context.Context, intent *domain.PaymentIntent) (*CheckoutSession, error)
+	// CancelPayment voids a checkout session the customer has not completed,
+	// so it can no longer be paid.
+	CancelPayment(ctx context.Context, intent *domain.PaymentIntent) error
+	// ParseCallback verifies the webhook signature and decodes the payload.
+	// It returns ErrInvalidSignature when the signature does not match.
+	ParseCallback(payload []byte, signature string) (*CallbackEvent, error)
+	// Refund returns the captured amount to the customer and returns the
+	// provider's refund ID.
+	Refund(ctx context.Context, intent *domain.PaymentIntent, refund *domain.Refund) (string, error)
+}
@@ -10,6 +10,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type mongoOrderRepository struct {
@@ -21,33 +22,50 @@ func NewMongoOrderRepository(db *database.MongoDBConnector) *mongoOrderRepositor
 }
 
 func (r *mongoOrderRepository) Create(ctx context.Context, order *domain.Order) (*domain.Order, error) {
-	itemDTOs := make([]database.OrderItemDTO, len(order.Items))
-	for i, item := range order.Items {
-		itemDTOs[i] = database.OrderItemDTO{
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
-		}
-	}
-
 	orderDTO := &database.OrderDTO{
-		ID:        order.ID,
-		UserID:    order.UserID,
-		Items:     itemDTOs,
-		Total:     order.Total,
-		Status:    string(order.Status),
-		CreatedAt: order.CreatedAt,
-		UpdatedAt: order.UpdatedAt,
+		ID:             order.ID,
+		UserID:         order.UserID,
+		Items:          toOrderItemDTOs(order.Items),
+		Address:        order.Address,
+		NetTotal:       order.NetTotal,
+		TaxTotal:       order.TaxTotal,
+		Total:          order.Total,
+		TaxBreakdown:   toTaxLineDTOs(order.TaxBreakdown),
+		Refunds:        toRefundDTOs(order.Refunds),
+		Substitutions:  toSubstitutionDTOs(order.Substitutions),
+		Status:         string(order.Status),
+		IdempotencyKey: order.IdempotencyKey,
+		CreatedAt:      order.CreatedAt,
+		UpdatedAt:      order.UpdatedAt,
+
+		CreatedUnpublished: true,
 	}
 
 	_, err := r.db.OrderCollection().InsertOne(ctx, orderDTO)
 	if err != nil {
+		if order.IdempotencyKey != "" && mongo.IsDuplicateKeyError(err) {
+			return r.GetByIdempotencyKey(ctx, order.IdempotencyKey)
+		}
 		return nil, err
 	}
 
 	return order, nil
 }
 
+func (r *mongoOrderRepository) GetByIdempotencyKey(ctx context.Context, key string) (*domain.Order, error) {
+	var orderDTO database.OrderDTO
+
+	err := r.db.OrderCollection().FindOne(ctx, bson.M{"idempotency_key": key}).Decode(&orderDTO)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toDomainOrder(&orderDTO), nil
+}
+
 func (r *mongoOrderRepository) GetByID(ctx context.Context, id string) (*domain.Order, error) {
 	var orderDTO database.OrderDTO
 
@@ -61,44 +79,23 @@ func (r *mongoOrderRepository) GetByID(ctx context.Context, id string) (*domain.
 		return nil, err
 	}
 
-	orderItems := make([]domain.OrderItem, len(orderDTO.Items))
-	for i, item := range orderDTO.Items {
-		orderItems[i] = domain.OrderItem{
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
-		}
-	}
-
-	return &domain.Order{
-		ID:        orderDTO.ID,
-		UserID:    orderDTO.UserID,
-		Items:     orderItems,
-		Total:     orderDTO.Total,
-		Status:    domain.OrderStatus(orderDTO.Status),
-		CreatedAt: orderDTO.CreatedAt,
-		UpdatedAt: orderDTO.UpdatedAt,
-	}, nil
+	return toDomainOrder(&orderDTO), nil
 }
 
 func (r *mongoOrderRepository) Update(ctx context.Context, order *domain.Order) (*domain.Order, error) {
-	itemDTOs := make([]database.OrderItemDTO, len(order.Items))
-	for i, item := range order.Items {
-		itemDTOs[i] = database.OrderItemDTO{
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
-		}
-	}
-
 	filter := bson.M{"_id": order.ID}
 	update := bson.M{
 		"$set": bson.M{
-			"user_id":    order.UserID,
-			"items":      itemDTOs,
-			"total":      order.Total,
-			"status":     string(order.Status),
-			"updated_at": time.Now(),
+			"user_id":       order.UserID,
+			"items":         toOrderItemDTOs(order.Items),
+			"address":       order.Address,
+			"net_total":     order.NetTotal,
+			"tax_total":     order.TaxTotal,
+			"total":         order.Total,
+			"tax_breakdown": toTaxLineDTOs(order.TaxBreakdown),
+			"substitutions": toSubstitutionDTOs(order.Substitutions),
+			"status":        string(order.Status),
+			"updated_at":    time.Now(),
 		},
 	}
 
@@ -114,6 +111,23 @@ func (r *mongoOrderRepository) Update(ctx context.Context, order *domain.Order)
 	return order, nil
 }
 
+func (r *mongoOrderRepository) UpdateStatus(ctx context.Context, order *domain.Order, from domain.OrderStatus) (bool, error) {
+	filter := bson.M{"_id": order.ID, "status": string(from)}
+	update := bson.M{
+		"$set": bson.M{
+			"status":     string(order.Status),
+			"updated_at": order.UpdatedAt,
+		},
+	}
+
+	result, err := r.db.OrderCollection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
 func (r *mongoOrderRepository) ListByUserID(ctx context.Context, userID string) ([]*domain.Order, error) {
 	filter := bson.M{"user_id": userID}
 	cursor, err := r.db.OrderCollection().Find(ctx, filter)
@@ -128,26 +142,494 @@ func (r *mongoOrderRepository) ListByUserID(ctx context.Context, userID string)
 	}
 
 	orders := make([]*domain.Order, len(orderDTOs))
-	for i, dto := range orderDTOs {
-		orderItems := make([]domain.OrderItem, len(dto.Items))
-		for j, item := range dto.Items {
-			orderItems[j] = domain.OrderItem{
-				ProductID: item.ProductID,
-				Quantity:  item.Quantity,
-				Price:     item.Price,
+	for i := range orderDTOs {
+		orders[i] = toDomainOrder(&orderDTOs[i])
+	}
+
+	return orders, nil
+}
+
+// AddReceipt appends a receipt without touching the rest of the order, so it
+// cannot overwrite a concurrent status change or refund.
+func (r *mongoOrderRepository) AddReceipt(ctx context.Context, orderID string, receipt domain.OrderReceipt) error {
+	filter := bson.M{"_id": orderID}
+	update := bson.M{
+		"$push": bson.M{"receipts": database.OrderReceiptDTO{
+			ID:           receipt.ID,
+			Type:         string(receipt.Type),
+			RefundID:     receipt.RefundID,
+			FiscalNumber: receipt.FiscalNumber,
+			QRURL:        receipt.QRURL,
+			Total:        receipt.Total,
+			IssuedAt:     receipt.IssuedAt,
+		}},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := r.db.OrderCollection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("order not found")
+	}
+
+	return nil
+}
+
+// AddRefund reserves a refund on an order that is still paid or completed and
+// was not changed since lastUpdatedAt, so two concurrent refunds cannot both
+// be checked against the same refunded total.
+func (r *mongoOrderRepository) AddRefund(ctx context.Context, orderID string, refund domain.Refund, lastUpdatedAt time.Time) (bool, error) {
+	filter := bson.M{
+		"_id":        orderID,
+		"updated_at": lastUpdatedAt,
+		"refunds.id": bson.M{"$ne": refund.ID},
+		"status": bson.M{"$in": bson.A{
+			string(domain.OrderStatusPaid),
+			string(domain.OrderStatusCompleted),
+		}},
+	}
+	update := bson.M{
+		"$push": bson.M{"refunds": toRefundDTOs([]domain.Refund{refund})[0]},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := r.db.OrderCollection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+func (r *mongoOrderRepository) CompleteRefund(ctx context.Context, orderID, refundID, providerRefundID string, fullyRefunded bool) error {
+	set := bson.M{
+		"refunds.$.status":             string(domain.RefundStatusCompleted),
+		"refunds.$.provider_refund_id": providerRefundID,
+		"updated_at":                   time.Now(),
+	}
+	if fullyRefunded {
+		set["status"] = string(domain.OrderStatusRefunded)
+	}
+
+	result, err := r.db.OrderCollection().UpdateOne(ctx, bson.M{"_id": orderID, "refunds.id": refundID}, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("refund not found")
+	}
+
+	return nil
+}
+
+func (r *mongoOrderRepository) RemoveRefund(ctx context.Context, orderID, refundID string) error {
+	update := bson.M{
+		"$pull": bson.M{"refunds": bson.M{"id": refundID}},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+
+	_, err := r.db.OrderCollection().UpdateOne(ctx, bson.M{"_id": orderID}, update)
+	return err
+}
+
+func (r *mongoOrderRepository) UpdateItems(ctx context.Context, order *domain.Order) (bool, error) {
+	filter := bson.M{
+		"_id": order.ID,
+		"status": bson.M{"$in": bson.A{
+			string(domain.OrderStatusPending),
+			string(domain.OrderStatusConfirmed),
+		}},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"items":         toOrderItemDTOs(order.Items),
+			"net_total":     order.NetTotal,
+			"tax_total":     order.TaxTotal,
+			"total":         order.Total,
+			"tax_breakdown": toTaxLineDTOs(order.TaxBreakdown),
+			"updated_at":    time.Now(),
+		},
+	}
+
+	result, err := r.db.OrderCollection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+func (r *mongoOrderRepository) UpdateLines(ctx context.Context, order *domain.Order, lastUpdatedAt time.Time) (bool, error) {
+	filter := bson.M{"_id": order.ID, "updated_at": lastUpdatedAt}
+	update := bson.M{
+		"$set": bson.M{
+			"items":         toOrderItemDTOs(order.Items),
+			"net_total":     order.NetTotal,
+			"tax_total":     order.TaxTotal,
+			"total":         order.Total,
+			"tax_breakdown": toTaxLineDTOs(order.TaxBreakdown),
+			"substitutions": toSubstitutionDTOs(order.Substitutions),
+			"updated_at":    time.Now(),
+		},
+	}
+
+	result, err := r.db.OrderCollection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+func (r *mongoOrderRepository) ListWithExpiredSubstitutions(ctx context.Context, now time.Time) ([]*domain.Order, error) {
+	filter := bson.M{"substitutions": bson.M{"$elemMatch": bson.M{
+		"status":     string(domain.SubstitutionProposed),
+		"expires_at": bson.M{"$lte": now},
+	}}}
+	cursor, err := r.db.OrderCollection().Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var orderDTOs []database.OrderDTO
+	if err := cursor.All(ctx, &orderDTOs); err != nil {
+		return nil, err
+	}
+
+	orders := make([]*domain.Order, len(orderDTOs))
+	for i := range orderDTOs {
+		orders[i] = toDomainOrder(&orderDTOs[i])
+	}
+
+	return orders, nil
+}
+
+func (r *mongoOrderRepository) ListWithUnsettledSubstitutions(ctx context.Context) ([]*domain.Order, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"substitutions.refund_owed": true},
+		bson.M{"substitutions.unpublished": true},
+	}}
+	cursor, err := r.db.OrderCollection().Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var orderDTOs []database.OrderDTO
+	if err := cursor.All(ctx, &orderDTOs); err != nil {
+		return nil, err
+	}
+
+	orders := make([]*domain.Order, len(orderDTOs))
+	for i := range orderDTOs {
+		orders[i] = toDomainOrder(&orderDTOs[i])
+	}
+
+	return orders, nil
+}
+
+func (r *mongoOrderRepository) SettleSubstitution(ctx context.Context, orderID, substitutionID string, refunded, published bool) error {
+	unset := bson.M{}
+	if refunded {
+		unset["substitutions.$[s].refund_owed"] = ""
+	}
+	if published {
+		unset["substitutions.$[s].unpublished"] = ""
+	}
+	if len(unset) == 0 {
+		return nil
+	}
+
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{
+		Filters: bson.A{bson.M{"s.id": substitutionID}},
+	})
+	_, err := r.db.OrderCollection().UpdateOne(ctx, bson.M{"_id": orderID}, bson.M{"$unset": unset}, opts)
+	return err
+}
+
+func (r *mongoOrderRepository) ClaimExpired(ctx context.Context, createdBefore, now, leaseUntil time.Time) (*domain.Order, error) {
+	filter := bson.M{
+		"$and": bson.A{
+			bson.M{"$or": bson.A{
+				bson.M{"status": string(domain.OrderStatusPending), "created_at": bson.M{"$lt": createdBefore}},
+				bson.M{"expiry_unpublished": true},
+			}},
+			bson.M{"$or": bson.A{
+				bson.M{"expiry_locked_until": bson.M{"$exists": false}},
+				bson.M{"expiry_locked_until": bson.M{"$lte": now}},
+			}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"expiry_locked_until": leaseUntil}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.M{"created_at": 1}).
+		SetReturnDocument(options.After)
+
+	var orderDTO database.OrderDTO
+	err := r.db.OrderCollection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&orderDTO)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toDomainOrder(&orderDTO), nil
+}
+
+func (r *mongoOrderRepository) Expire(ctx context.Context, orderID string, expiredAt time.Time) (bool, error) {
+	filter := bson.M{"_id": orderID, "status": string(domain.OrderStatusPending)}
+	update := bson.M{
+		"$set": bson.M{
+			"status":             string(domain.OrderStatusCancelled),
+			"expired_at":         expiredAt,
+			"expiry_unpublished": true,
+			"updated_at":         time.Now(),
+		},
+	}
+
+	result, err := r.db.OrderCollection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+func (r *mongoOrderRepository) ReleaseExpiry(ctx context.Context, orderID string, published bool) error {
+	unset := bson.M{"expiry_locked_until": ""}
+	if published {
+		unset["expiry_unpublished"] = ""
+	}
+
+	_, err := r.db.OrderCollection().UpdateOne(ctx, bson.M{"_id": orderID}, bson.M{"$unset": unset})
+	return err
+}
+
+func (r *mongoOrderRepository) ClaimUnpublishedCreated(ctx context.Context, createdBefore, now, leaseUntil time.Time) (*domain.Order, error) {
+	filter := bson.M{
+		"created_unpublished": true,
+		"created_at":          bson.M{"$lt": createdBefore},
+		"$or": bson.A{
+			bson.M{"created_locked_until": bson.M{"$exists": false}},
+			bson.M{"created_locked_until": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"created_locked_until": leaseUntil}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.M{"created_at": 1}).
+		SetReturnDocument(options.After)
+
+	var orderDTO database.OrderDTO
+	err := r.db.OrderCollection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&orderDTO)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toDomainOrder(&orderDTO), nil
+}
+
+func (r *mongoOrderRepository) ReleaseCreated(ctx context.Context, orderID string, published bool) error {
+	unset := bson.M{"created_locked_until": ""}
+	if published {
+		unset["created_unpublished"] = ""
+	}
+
+	_, err := r.db.OrderCollection().UpdateOne(ctx, bson.M{"_id": orderID}, bson.M{"$unset": unset})
+	return err
+}
+
+func toOrderItemDTOs(items []domain.OrderItem) []database.OrderItemDTO {
+	itemDTOs := make([]database.OrderItemDTO, len(items))
+	for i, item := range items {
+		itemDTOs[i] = database.OrderItemDTO{
+			ProductID:   item.ProductID,
+			Quantity:    item.Quantity,
+			Price:       item.Price,
+			TaxClass:    string(item.TaxClass),
+			TaxRate:     item.TaxRate,
+			NetAmount:   item.NetAmount,
+			TaxAmount:   item.TaxAmount,
+			GrossAmount: item.GrossAmount,
+
+			Substitution:        string(item.Substitution),
+			SubstituteProductID: item.SubstituteProductID,
+		}
+	}
+	return itemDTOs
+}
+
+func toSubstitutionDTOs(substitutions []domain.Substitution) []database.SubstitutionDTO {
+	dtos := make([]database.SubstitutionDTO, len(substitutions))
+	for i, s := range substitutions {
+		dtos[i] = database.SubstitutionDTO{
+			ID:                  s.ID,
+			ProductID:           s.ProductID,
+			Quantity:            s.Quantity,
+			SubstituteProductID: s.SubstituteProductID,
+			SubstituteQuantity:  s.SubstituteQuantity,
+			Price:               s.Price,
+			TaxClass:            string(s.TaxClass),
+			Status:              string(s.Status),
+			ProposedAt:          s.ProposedAt,
+			ExpiresAt:           s.ExpiresAt,
+			DecidedAt:           s.DecidedAt,
+			RefundAmount:        s.RefundAmount,
+			RefundOwed:          s.RefundOwed,
+			Unpublished:         s.Unpublished,
+		}
+	}
+	return dtos
+}
+
+func toTaxLineDTOs(lines []domain.TaxLine) []database.TaxLineDTO {
+	lineDTOs := make([]database.TaxLineDTO, len(lines))
+	for i, line := range lines {
+		lineDTOs[i] = database.TaxLineDTO{
+			TaxRate:     line.TaxRate,
+			NetAmount:   line.NetAmount,
+			TaxAmount:   line.TaxAmount,
+			GrossAmount: line.GrossAmount,
+		}
+	}
+	return lineDTOs
+}
+
+func toRefundDTOs(refunds []domain.Refund) []database.RefundDTO {
+	refundDTOs := make([]database.RefundDTO, len(refunds))
+	for i, refund := range refunds {
+		lineDTOs := make([]database.RefundLineDTO, len(refund.Lines))
+		for j, line := range refund.Lines {
+			lineDTOs[j] = database.RefundLineDTO{
+				ProductID: line.ProductID,
+				Quantity:  line.Quantity,
+				Amount:    line.Amount,
 			}
 		}
+		refundDTOs[i] = database.RefundDTO{
+			ID:               refund.ID,
+			PaymentID:        refund.PaymentID,
+			Amount:           refund.Amount,
+			Reason:           refund.Reason,
+			Lines:            lineDTOs,
+			Status:           string(refund.Status),
+			ProviderRefundID: refund.ProviderRefundID,
+			CreatedAt:        refund.CreatedAt,
+		}
+	}
+	return refundDTOs
+}
+
+func toDomainOrder(dto *database.OrderDTO) *domain.Order {
+	items := make([]domain.OrderItem, len(dto.Items))
+	for i, item := range dto.Items {
+		items[i] = domain.OrderItem{
+			ProductID:   item.ProductID,
+			Quantity:    item.Quantity,
+			Price:       item.Price,
+			TaxClass:    domain.TaxClass(item.TaxClass),
+			TaxRate:     item.TaxRate,
+			NetAmount:   item.NetAmount,
+			TaxAmount:   item.TaxAmount,
+			GrossAmount: item.GrossAmount,
+
+			Substitution:        domain.SubstitutionPreference(item.Substitution),
+			SubstituteProductID: item.SubstituteProductID,
+		}
+	}
 
-		orders[i] = &domain.Order{
-			ID:        dto.ID,
-			UserID:    dto.UserID,
-			Items:     orderItems,
-			Total:     dto.Total,
-			Status:    domain.OrderStatus(dto.Status),
-			CreatedAt: dto.CreatedAt,
-			UpdatedAt: dto.UpdatedAt,
+	substitutions := make([]domain.Substitution, len(dto.Substitutions))
+	for i, s := range dto.Substitutions {
+		substitutions[i] = domain.Substitution{
+			ID:                  s.ID,
+			ProductID:           s.ProductID,
+			Quantity:            s.Quantity,
+			SubstituteProductID: s.SubstituteProductID,
+			SubstituteQuantity:  s.SubstituteQuantity,
+			Price:               s.Price,
+			TaxClass:            domain.TaxClass(s.TaxClass),
+			Status:              domain.SubstitutionStatus(s.Status),
+			ProposedAt:          s.ProposedAt,
+			ExpiresAt:           s.ExpiresAt,
+			DecidedAt:           s.DecidedAt,
+			RefundAmount:        s.RefundAmount,
+			RefundOwed:          s.RefundOwed,
+			Unpublished:         s.Unpublished,
 		}
 	}
 
-	return orders, nil
+	taxBreakdown := make([]domain.TaxLine, len(dto.TaxBreakdown))
+	for i, line := range dto.TaxBreakdown {
+		taxBreakdown[i] = domain.TaxLine{
+			TaxRate:     line.TaxRate,
+			NetAmount:   line.NetAmount,
+			TaxAmount:   line.TaxAmount,
+			GrossAmount: line.GrossAmount,
+		}
+	}
+
+	refunds := make([]domain.Refund, len(dto.Refunds))
+	for i, refund := range dto.Refunds {
+		lines := make([]domain.RefundLine, len(refund.Lines))
+		for j, line := range refund.Lines {
+			lines[j] = domain.RefundLine{
+				ProductID: line.ProductID,
+				Quantity:  line.Quantity,
+				Amount:    line.Amount,
+			}
+		}
+		refunds[i] = domain.Refund{
+			ID:               refund.ID,
+			PaymentID:        refund.PaymentID,
+			Amount:           refund.Amount,
+			Reason:           refund.Reason,
+			Lines:            lines,
+			Status:           domain.RefundStatus(refund.Status),
+			ProviderRefundID: refund.ProviderRefundID,
+			CreatedAt:        refund.CreatedAt,
+		}
+	}
+
+	receipts := make([]domain.OrderReceipt, len(dto.Receipts))
+	for i, receipt := range dto.Receipts {
+		receipts[i] = domain.OrderReceipt{
+			ID:           receipt.ID,
+			Type:         domain.ReceiptType(receipt.Type),
+			RefundID:     receipt.RefundID,
+			FiscalNumber: receipt.FiscalNumber,
+			QRURL:        receipt.QRURL,
+			Total:        receipt.Total,
+			IssuedAt:     receipt.IssuedAt,
+		}
+	}
+
+	var expiredAt time.Time
+	if dto.ExpiredAt != nil {
+		expiredAt = *dto.ExpiredAt
+	}
+
+	return &domain.Order{
+		ID:             dto.ID,
+		UserID:         dto.UserID,
+		Items:          items,
+		Address:        dto.Address,
+		NetTotal:       dto.NetTotal,
+		TaxTotal:       dto.TaxTotal,
+		Total:          dto.Total,
+		TaxBreakdown:   taxBreakdown,
+		Refunds:        refunds,
+		Receipts:       receipts,
+		Substitutions:  substitutions,
+		Status:         domain.OrderStatus(dto.Status),
+		ExpiredAt:      expiredAt,
+		IdempotencyKey: dto.IdempotencyKey,
+		CreatedAt:      dto.CreatedAt,
+		UpdatedAt:      dto.UpdatedAt,
+	}
 }
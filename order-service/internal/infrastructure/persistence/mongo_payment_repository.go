@@ -0,0 +1,150 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoPaymentRepository struct {
+	db *database.MongoDBConnector
+}
+
+func NewMongoPaymentRepository(db *database.MongoDBConnector) *mongoPaymentRepository {
+	return &mongoPaymentRepository{db: db}
+}
+
+func (r *mongoPaymentRepository) Create(ctx context.Context, intent *domain.PaymentIntent) (*domain.PaymentIntent, error) {
+	_, err := r.db.PaymentCollection().InsertOne(ctx, toPaymentIntentDTO(intent))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, errors.New("another payment for this order is already open, please retry")
+		}
+		return nil, err
+	}
+
+	return intent, nil
+}
+
+func (r *mongoPaymentRepository) GetByID(ctx context.Context, id string) (*domain.PaymentIntent, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r *mongoPaymentRepository) GetByProviderPaymentID(ctx context.Context, provider, providerPaymentID string) (*domain.PaymentIntent, error) {
+	return r.findOne(ctx, bson.M{"provider": provider, "provider_payment_id": providerPaymentID})
+}
+
+func (r *mongoPaymentRepository) Update(ctx context.Context, intent *domain.PaymentIntent) (*domain.PaymentIntent, error) {
+	filter := bson.M{"_id": intent.ID}
+	update := bson.M{
+		"$set": bson.M{
+			"status":              string(intent.Status),
+			"provider_payment_id": intent.ProviderPaymentID,
+			"redirect_url":        intent.RedirectURL,
+			"qr_payload":          intent.QRPayload,
+			"updated_at":          time.Now(),
+		},
+	}
+
+	result, err := r.db.PaymentCollection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.MatchedCount == 0 {
+		return nil, nil
+	}
+
+	return intent, nil
+}
+
+func (r *mongoPaymentRepository) UpdateStatus(ctx context.Context, intent *domain.PaymentIntent, from domain.PaymentStatus) (bool, error) {
+	filter := bson.M{"_id": intent.ID, "status": string(from)}
+	update := bson.M{
+		"$set": bson.M{
+			"status":     string(intent.Status),
+			"updated_at": time.Now(),
+		},
+	}
+
+	result, err := r.db.PaymentCollection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+func (r *mongoPaymentRepository) ListByOrderID(ctx context.Context, orderID string) ([]*domain.PaymentIntent, error) {
+	findOptions := options.Find().SetSort(bson.M{"created_at": 1})
+	cursor, err := r.db.PaymentCollection().Find(ctx, bson.M{"order_id": orderID}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var dtos []database.PaymentIntentDTO
+	if err := cursor.All(ctx, &dtos); err != nil {
+		return nil, err
+	}
+
+	intents := make([]*domain.PaymentIntent, len(dtos))
+	for i := range dtos {
+		intents[i] = toDomainPaymentIntent(&dtos[i])
+	}
+
+	return intents, nil
+}
+
+func (r *mongoPaymentRepository) findOne(ctx context.Context, filter bson.M) (*domain.PaymentIntent, error) {
+	var dto database.PaymentIntentDTO
+
+	err := r.db.PaymentCollection().FindOne(ctx, filter).Decode(&dto)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toDomainPaymentIntent(&dto), nil
+}
+
+func toPaymentIntentDTO(intent *domain.PaymentIntent) *database.PaymentIntentDTO {
+	return &database.PaymentIntentDTO{
+		ID:                intent.ID,
+		OrderID:           intent.OrderID,
+		Amount:            intent.Amount,
+		Method:            string(intent.Method),
+		Status:            string(intent.Status),
+		Provider:          intent.Provider,
+		ProviderPaymentID: intent.ProviderPaymentID,
+		RedirectURL:       intent.RedirectURL,
+		QRPayload:         intent.QRPayload,
+		CreatedAt:         intent.CreatedAt,
+		UpdatedAt:         intent.UpdatedAt,
+	}
+}
+
+func toDomainPaymentIntent(dto *database.PaymentIntentDTO) *domain.PaymentIntent {
+	return &domain.PaymentIntent{
+		ID:                dto.ID,
+		OrderID:           dto.OrderID,
+		Amount:            dto.Amount,
+		Method:            domain.PaymentMethod(dto.Method),
+		Status:            domain.PaymentStatus(dto.Status),
+		Provider:          dto.Provider,
+		ProviderPaymentID: dto.ProviderPaymentID,
+		RedirectURL:       dto.RedirectURL,
+		QRPayload:         dto.QRPayload,
+		CreatedAt:         dto.CreatedAt,
+		UpdatedAt:         dto.UpdatedAt,
+	}
+}
@@ -0,0 +1,91 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoPendingReceiptRepository struct {
+	db *database.MongoDBConnector
+}
+
+func NewMongoPendingReceiptRepository(db *database.MongoDBConnector) *mongoPendingReceiptRepository {
+	return &mongoPendingReceiptRepository{db: db}
+}
+
+func (r *mongoPendingReceiptRepository) Create(ctx context.Context, receipt *domain.PendingReceipt) error {
+	_, err := r.db.PendingReceiptCollection().InsertOne(ctx, database.PendingReceiptDTO{
+		ID:            receipt.ID,
+		OrderID:       receipt.OrderID,
+		Type:          string(receipt.Type),
+		RefundID:      receipt.RefundID,
+		Method:        string(receipt.Method),
+		Attempts:      receipt.Attempts,
+		LastError:     receipt.LastError,
+		NextAttemptAt: receipt.NextAttemptAt,
+		CreatedAt:     receipt.CreatedAt,
+	})
+	return err
+}
+
+func (r *mongoPendingReceiptRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time) (*domain.PendingReceipt, error) {
+	filter := bson.M{
+		"next_attempt_at": bson.M{"$lte": now},
+		"$or": bson.A{
+			bson.M{"locked_until": bson.M{"$exists": false}},
+			bson.M{"locked_until": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"locked_until": leaseUntil}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.M{"next_attempt_at": 1}).
+		SetReturnDocument(options.After)
+
+	var dto database.PendingReceiptDTO
+	err := r.db.PendingReceiptCollection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&dto)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &domain.PendingReceipt{
+		ID:            dto.ID,
+		OrderID:       dto.OrderID,
+		Type:          domain.ReceiptType(dto.Type),
+		RefundID:      dto.RefundID,
+		Method:        domain.PaymentMethod(dto.Method),
+		Attempts:      dto.Attempts,
+		LastError:     dto.LastError,
+		NextAttemptAt: dto.NextAttemptAt,
+		CreatedAt:     dto.CreatedAt,
+	}, nil
+}
+
+func (r *mongoPendingReceiptRepository) RecordFailure(ctx context.Context, receipt *domain.PendingReceipt) error {
+	update := bson.M{
+		"$set": bson.M{
+			"attempts":        receipt.Attempts,
+			"last_error":      receipt.LastError,
+			"next_attempt_at": receipt.NextAttemptAt,
+		},
+		"$unset": bson.M{"locked_until": ""},
+	}
+
+	_, err := r.db.PendingReceiptCollection().UpdateOne(ctx, bson.M{"_id": receipt.ID}, update)
+	return err
+}
+
+func (r *mongoPendingReceiptRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.PendingReceiptCollection().DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
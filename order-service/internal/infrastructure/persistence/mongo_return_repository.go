@@ -0,0 +1,149 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoReturnRepository struct {
+	db *database.MongoDBConnector
+}
+
+func NewMongoReturnRepository(db *database.MongoDBConnector) *mongoReturnRepository {
+	return &mongoReturnRepository{db: db}
+}
+
+func (r *mongoReturnRepository) Create(ctx context.Context, ret *domain.Return) (*domain.Return, error) {
+	_, err := r.db.ReturnCollection().InsertOne(ctx, toReturnDTO(ret))
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mongoReturnRepository) GetByID(ctx context.Context, id string) (*domain.Return, error) {
+	var dto database.ReturnDTO
+	err := r.db.ReturnCollection().FindOne(ctx, bson.M{"_id": id}).Decode(&dto)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toDomainReturn(&dto), nil
+}
+
+func (r *mongoReturnRepository) List(ctx context.Context, filter ReturnListFilter) ([]*domain.Return, error) {
+	query := bson.M{}
+	if filter.UserID != "" {
+		query["user_id"] = filter.UserID
+	}
+	if filter.OrderID != "" {
+		query["order_id"] = filter.OrderID
+	}
+	if filter.Status != "" {
+		query["status"] = string(filter.Status)
+	}
+
+	findOptions := options.Find().SetSort(bson.M{"created_at": 1})
+	cursor, err := r.db.ReturnCollection().Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var dtos []database.ReturnDTO
+	if err := cursor.All(ctx, &dtos); err != nil {
+		return nil, err
+	}
+
+	returns := make([]*domain.Return, len(dtos))
+	for i := range dtos {
+		returns[i] = toDomainReturn(&dtos[i])
+	}
+
+	return returns, nil
+}
+
+func (r *mongoReturnRepository) Update(ctx context.Context, ret *domain.Return, from domain.ReturnStatus) (bool, error) {
+	filter := bson.M{"_id": ret.ID, "status": string(from)}
+	update := bson.M{
+		"$set": bson.M{
+			"lines":      toReturnLineDTOs(ret.Lines),
+			"status":     string(ret.Status),
+			"admin_note": ret.AdminNote,
+			"refund_id":  ret.RefundID,
+			"updated_at": time.Now(),
+		},
+	}
+
+	result, err := r.db.ReturnCollection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+func toReturnDTO(ret *domain.Return) *database.ReturnDTO {
+	return &database.ReturnDTO{
+		ID:        ret.ID,
+		OrderID:   ret.OrderID,
+		UserID:    ret.UserID,
+		Lines:     toReturnLineDTOs(ret.Lines),
+		Reason:    ret.Reason,
+		Photos:    ret.Photos,
+		Status:    string(ret.Status),
+		AdminNote: ret.AdminNote,
+		RefundID:  ret.RefundID,
+		CreatedAt: ret.CreatedAt,
+		UpdatedAt: ret.UpdatedAt,
+	}
+}
+
+func toReturnLineDTOs(lines []domain.ReturnLine) []database.ReturnLineDTO {
+	dtos := make([]database.ReturnLineDTO, len(lines))
+	for i, line := range lines {
+		dtos[i] = database.ReturnLineDTO{
+			ProductID: line.ProductID,
+			Quantity:  line.Quantity,
+			Sellable:  line.Sellable,
+		}
+	}
+	return dtos
+}
+
+func toDomainReturn(dto *database.ReturnDTO) *domain.Return {
+	lines := make([]domain.ReturnLine, len(dto.Lines))
+	for i, line := range dto.Lines {
+		lines[i] = domain.ReturnLine{
+			ProductID: line.ProductID,
+			Quantity:  line.Quantity,
+			Sellable:  line.Sellable,
+		}
+	}
+
+	return &domain.Return{
+		ID:        dto.ID,
+		OrderID:   dto.OrderID,
+		UserID:    dto.UserID,
+		Lines:     lines,
+		Reason:    dto.Reason,
+		Photos:    dto.Photos,
+		Status:    domain.ReturnStatus(dto.Status),
+		AdminNote: dto.AdminNote,
+		RefundID:  dto.RefundID,
+		CreatedAt: dto.CreatedAt,
+		UpdatedAt: dto.UpdatedAt,
+	}
+}
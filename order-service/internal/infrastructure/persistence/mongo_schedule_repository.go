@@ -0,0 +1,215 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoScheduleRepository struct {
+	db *database.MongoDBConnector
+}
+
+func NewMongoScheduleRepository(db *database.MongoDBConnector) *mongoScheduleRepository {
+	return &mongoScheduleRepository{db: db}
+}
+
+func (r *mongoScheduleRepository) Create(ctx context.Context, schedule *domain.Schedule) (*domain.Schedule, error) {
+	_, err := r.db.ScheduleCollection().InsertOne(ctx, toScheduleDTO(schedule))
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+func (r *mongoScheduleRepository) GetByID(ctx context.Context, id string) (*domain.Schedule, error) {
+	var dto database.ScheduleDTO
+	err := r.db.ScheduleCollection().FindOne(ctx, bson.M{"_id": id}).Decode(&dto)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toDomainSchedule(&dto), nil
+}
+
+func (r *mongoScheduleRepository) ListByUserID(ctx context.Context, userID string) ([]*domain.Schedule, error) {
+	findOptions := options.Find().SetSort(bson.M{"created_at": 1})
+	cursor, err := r.db.ScheduleCollection().Find(ctx, bson.M{"user_id": userID}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var dtos []database.ScheduleDTO
+	if err := cursor.All(ctx, &dtos); err != nil {
+		return nil, err
+	}
+
+	schedules := make([]*domain.Schedule, len(dtos))
+	for i := range dtos {
+		schedules[i] = toDomainSchedule(&dtos[i])
+	}
+
+	return schedules, nil
+}
+
+func (r *mongoScheduleRepository) Update(ctx context.Context, schedule *domain.Schedule, lastUpdatedAt time.Time) (bool, error) {
+	filter := bson.M{"_id": schedule.ID, "updated_at": lastUpdatedAt}
+	update := bson.M{
+		"$set": bson.M{
+			"status":      string(schedule.Status),
+			"next_run_at": schedule.NextRunAt,
+			"updated_at":  schedule.UpdatedAt,
+		},
+	}
+
+	result, err := r.db.ScheduleCollection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+func (r *mongoScheduleRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time) (*domain.Schedule, error) {
+	filter := bson.M{
+		"status":      string(domain.ScheduleStatusActive),
+		"next_run_at": bson.M{"$lte": now},
+		"$or": bson.A{
+			bson.M{"locked_until": bson.M{"$exists": false}},
+			bson.M{"locked_until": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"locked_until": leaseUntil}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.M{"next_run_at": 1}).
+		SetReturnDocument(options.After)
+
+	var dto database.ScheduleDTO
+	err := r.db.ScheduleCollection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&dto)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toDomainSchedule(&dto), nil
+}
+
+func (r *mongoScheduleRepository) CompleteRun(ctx context.Context, schedule *domain.Schedule, runAt time.Time) error {
+	outcome := bson.M{
+		"last_run_at":   schedule.LastRunAt,
+		"last_order_id": schedule.LastOrderID,
+		"last_error":    schedule.LastError,
+		"failures":      schedule.Failures,
+		"updated_at":    time.Now(),
+	}
+	return r.recordRun(ctx, schedule, runAt, outcome, bson.M{"next_run_at": schedule.NextRunAt}, nil)
+}
+
+func (r *mongoScheduleRepository) RecordFailure(ctx context.Context, schedule *domain.Schedule, runAt, retryAt time.Time) error {
+	outcome := bson.M{
+		"last_run_at": schedule.LastRunAt,
+		"last_error":  schedule.LastError,
+		"updated_at":  time.Now(),
+	}
+	return r.recordRun(ctx, schedule, runAt, outcome, bson.M{"failures": schedule.Failures}, &retryAt)
+}
+
+// recordRun stores the outcome of the run due at runAt. If the schedule still
+// waits for that run, the run fields are stored too and the schedule stays
+// leased until lockUntil, or is released when it is nil. Otherwise only the
+// outcome is stored, the failure count is reset and the lease released.
+func (r *mongoScheduleRepository) recordRun(ctx context.Context, schedule *domain.Schedule, runAt time.Time, outcome, run bson.M, lockUntil *time.Time) error {
+	set := bson.M{}
+	for field, value := range outcome {
+		set[field] = value
+	}
+	for field, value := range run {
+		set[field] = value
+	}
+	update := bson.M{"$set": set}
+	if lockUntil != nil {
+		set["locked_until"] = *lockUntil
+	} else {
+		update["$unset"] = bson.M{"locked_until": ""}
+	}
+
+	result, err := r.db.ScheduleCollection().UpdateOne(ctx, bson.M{"_id": schedule.ID, "next_run_at": runAt}, update)
+	if err != nil || result.MatchedCount > 0 {
+		return err
+	}
+
+	outcome["failures"] = 0
+	_, err = r.db.ScheduleCollection().UpdateOne(ctx, bson.M{"_id": schedule.ID}, bson.M{
+		"$set":   outcome,
+		"$unset": bson.M{"locked_until": ""},
+	})
+	return err
+}
+
+func toScheduleDTO(schedule *domain.Schedule) *database.ScheduleDTO {
+	items := make([]database.ScheduleItemDTO, len(schedule.Items))
+	for i, item := range schedule.Items {
+		items[i] = database.ScheduleItemDTO{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		}
+	}
+
+	return &database.ScheduleDTO{
+		ID:          schedule.ID,
+		UserID:      schedule.UserID,
+		Items:       items,
+		Address:     schedule.Address,
+		Cadence:     string(schedule.Cadence),
+		Status:      string(schedule.Status),
+		StartAt:     schedule.StartAt,
+		NextRunAt:   schedule.NextRunAt,
+		LastRunAt:   schedule.LastRunAt,
+		LastOrderID: schedule.LastOrderID,
+		LastError:   schedule.LastError,
+		Failures:    schedule.Failures,
+		CreatedAt:   schedule.CreatedAt,
+		UpdatedAt:   schedule.UpdatedAt,
+	}
+}
+
+func toDomainSchedule(dto *database.ScheduleDTO) *domain.Schedule {
+	items := make([]domain.ScheduleItem, len(dto.Items))
+	for i, item := range dto.Items {
+		items[i] = domain.ScheduleItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		}
+	}
+
+	return &domain.Schedule{
+		ID:          dto.ID,
+		UserID:      dto.UserID,
+		Items:       items,
+		Address:     dto.Address,
+		Cadence:     domain.Cadence(dto.Cadence),
+		Status:      domain.ScheduleStatus(dto.Status),
+		StartAt:     dto.StartAt,
+		NextRunAt:   dto.NextRunAt,
+		LastRunAt:   dto.LastRunAt,
+		LastOrderID: dto.LastOrderID,
+		LastError:   dto.LastError,
+		Failures:    dto.Failures,
+		CreatedAt:   dto.CreatedAt,
+		UpdatedAt:   dto.UpdatedAt,
+	}
+}
@@ -0,0 +1,196 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/database"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// maxCartRetries bounds optimistic retries when two requests change the same
+// cart at once.
+const maxCartRetries = 5
+
+type redisCartRepository struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func NewRedisCartRepository(cache *database.RedisCache, ttl time.Duration) *redisCartRepository {
+	return &redisCartRepository{
+		client: cache.Client,
+		ttl:    ttl,
+	}
+}
+
+func (r *redisCartRepository) TTL() time.Duration {
+	return r.ttl
+}
+
+func (r *redisCartRepository) Get(ctx context.Context, owner domain.CartOwner) (*domain.Cart, error) {
+	return r.load(ctx, r.client, owner)
+}
+
+// Update applies fn to the stored cart inside a WATCH transaction, so
+// concurrent changes to the same cart are retried instead of lost.
+func (r *redisCartRepository) Update(ctx context.Context, owner domain.CartOwner, fn func(cart *domain.Cart) error) (*domain.Cart, error) {
+	key := cartKey(owner)
+	var updated *domain.Cart
+
+	txf := func(tx *redis.Tx) error {
+		cart, err := r.load(ctx, tx, owner)
+		if err != nil {
+			return err
+		}
+		if err := fn(cart); err != nil {
+			return err
+		}
+		data, err := json.Marshal(toCartDTO(cart))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if cart.IsEmpty() {
+				pipe.Del(ctx, key)
+			} else {
+				pipe.Set(ctx, key, data, r.ttl)
+			}
+			return nil
+		})
+		updated = cart
+		return err
+	}
+
+	if err := r.watch(ctx, txf, key); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Merge moves the items of one cart into another and deletes the source in
+// the same transaction, so a retried login cannot merge twice.
+func (r *redisCartRepository) Merge(ctx context.Context, from, into domain.CartOwner) (*domain.Cart, error) {
+	fromKey, intoKey := cartKey(from), cartKey(into)
+	var merged *domain.Cart
+
+	txf := func(tx *redis.Tx) error {
+		source, err := r.load(ctx, tx, from)
+		if err != nil {
+			return err
+		}
+		target, err := r.load(ctx, tx, into)
+		if err != nil {
+			return err
+		}
+		target.Merge(source)
+
+		data, err := json.Marshal(toCartDTO(target))
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if !target.IsEmpty() {
+				pipe.Set(ctx, intoKey, data, r.ttl)
+			}
+			pipe.Del(ctx, fromKey)
+			return nil
+		})
+		merged = target
+		return err
+	}
+
+	if err := r.watch(ctx, txf, fromKey, intoKey); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// Take atomically removes the cart and returns it.
+func (r *redisCartRepository) Take(ctx context.Context, owner domain.CartOwner) (*domain.Cart, error) {
+	val, err := r.client.GetDel(ctx, cartKey(owner)).Result()
+	if errors.Is(err, redis.Nil) {
+		return domain.NewCart(owner), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeCart(owner, val)
+}
+
+func (r *redisCartRepository) watch(ctx context.Context, txf func(tx *redis.Tx) error, keys ...string) error {
+	for i := 0; i < maxCartRetries; i++ {
+		err := r.client.Watch(ctx, txf, keys...)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+	return errors.New("cart is being modified concurrently, please retry")
+}
+
+func (r *redisCartRepository) load(ctx context.Context, client redis.Cmdable, owner domain.CartOwner) (*domain.Cart, error) {
+	val, err := client.Get(ctx, cartKey(owner)).Result()
+	if errors.Is(err, redis.Nil) {
+		return domain.NewCart(owner), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeCart(owner, val)
+}
+
+func cartKey(owner domain.CartOwner) string {
+	if owner.IsAnonymous() {
+		return fmt.Sprintf("cart:session:%s", owner.SessionID)
+	}
+	return fmt.Sprintf("cart:user:%s", owner.UserID)
+}
+
+func decodeCart(owner domain.CartOwner, val string) (*domain.Cart, error) {
+	var dto database.CartDTO
+	if err := json.Unmarshal([]byte(val), &dto); err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.CartItem, len(dto.Items))
+	for i, item := range dto.Items {
+		items[i] = domain.CartItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Price:     item.Price,
+			AddedAt:   item.AddedAt,
+		}
+	}
+
+	return &domain.Cart{
+		Owner:     owner,
+		Items:     items,
+		UpdatedAt: dto.UpdatedAt,
+	}, nil
+}
+
+func toCartDTO(cart *domain.Cart) *database.CartDTO {
+	items := make([]database.CartItemDTO, len(cart.Items))
+	for i, item := range cart.Items {
+		items[i] = database.CartItemDTO{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Price:     item.Price,
+			AddedAt:   item.AddedAt,
+		}
+	}
+
+	return &database.CartDTO{
+		UserID:    cart.Owner.UserID,
+		SessionID: cart.Owner.SessionID,
+		Items:     items,
+		UpdatedAt: cart.UpdatedAt,
+	}
+}
@@ -3,11 +3,132 @@ package persistence
 import (
 	"context"
 	"order-service/internal/domain"
+	"time"
 )
 
 type OrderRepository interface {
+	// Create stores a new order and records that its order.created event is
+	// yet to be published. When another order already holds its idempotency
+	// key, nothing is stored and that order is returned.
 	Create(ctx context.Context, order *domain.Order) (*domain.Order, error)
 	GetByID(ctx context.Context, id string) (*domain.Order, error)
+	GetByIdempotencyKey(ctx context.Context, key string) (*domain.Order, error)
 	Update(ctx context.Context, order *domain.Order) (*domain.Order, error)
+	// UpdateStatus stores the order's new status only if it is still in
+	// status from. It returns false when the status changed in the meantime.
+	UpdateStatus(ctx context.Context, order *domain.Order, from domain.OrderStatus) (bool, error)
 	ListByUserID(ctx context.Context, userID string) ([]*domain.Order, error)
+	AddReceipt(ctx context.Context, orderID string, receipt domain.OrderReceipt) error
+	// AddRefund reserves a pending refund only while the order is paid or
+	// completed, unchanged since lastUpdatedAt and without a refund of the
+	// same ID. It returns false when the order was changed, for example by
+	// another refund, in the meantime.
+	AddRefund(ctx context.Context, orderID string, refund domain.Refund, lastUpdatedAt time.Time) (bool, error)
+	// CompleteRefund marks a pending refund paid out and, when nothing
+	// captured is left, the order refunded.
+	CompleteRefund(ctx context.Context, orderID, refundID, providerRefundID string, fullyRefunded bool) error
+	// RemoveRefund drops a pending refund the provider refused.
+	RemoveRefund(ctx context.Context, orderID, refundID string) error
+	// UpdateItems stores new lines and totals only while the order is still
+	// modifiable. It returns false when the order was paid, cancelled or
+	// expired in the meantime.
+	UpdateItems(ctx context.Context, order *domain.Order) (bool, error)
+	// UpdateLines stores the lines, totals and substitutions of an order only
+	// if it was not changed since it was read at lastUpdatedAt.
+	UpdateLines(ctx context.Context, order *domain.Order, lastUpdatedAt time.Time) (bool, error)
+	ListWithExpiredSubstitutions(ctx context.Context, now time.Time) ([]*domain.Order, error)
+	// ListWithUnsettledSubstitutions returns orders with a substitution whose
+	// refund or substitution.proposed event is still owed.
+	ListWithUnsettledSubstitutions(ctx context.Context) ([]*domain.Order, error)
+	// SettleSubstitution clears what is no longer owed for a substitution
+	// without touching the rest of the order.
+	SettleSubstitution(ctx context.Context, orderID, substitutionID string, refunded, published bool) error
+	// ClaimExpired atomically leases one order that has been pending since
+	// before createdBefore, or that expired without its order.expired event
+	// being published, so several instances never expire the same order.
+	ClaimExpired(ctx context.Context, createdBefore, now, leaseUntil time.Time) (*domain.Order, error)
+	// Expire cancels an order only if it is still pending and records that
+	// its order.expired event is yet to be published. It returns false when
+	// the order has left the pending status in the meantime.
+	Expire(ctx context.Context, orderID string, expiredAt time.Time) (bool, error)
+	// ReleaseExpiry releases the lease, marking the event published if done.
+	ReleaseExpiry(ctx context.Context, orderID string, published bool) error
+	// ClaimUnpublishedCreated atomically leases one order created before
+	// createdBefore whose order.created event has not been published.
+	ClaimUnpublishedCreated(ctx context.Context, createdBefore, now, leaseUntil time.Time) (*domain.Order, error)
+	// ReleaseCreated releases the lease, marking the event published if done.
+	ReleaseCreated(ctx context.Context, orderID string, published bool) error
+}
+
+// CartRepository stores carts with a sliding expiry. Get returns an empty cart
+// when the owner has none.
+type CartRepository interface {
+	Get(ctx context.Context, owner domain.CartOwner) (*domain.Cart, error)
+	Update(ctx context.Context, owner domain.CartOwner, fn func(cart *domain.Cart) error) (*domain.Cart, error)
+	Merge(ctx context.Context, from, into domain.CartOwner) (*domain.Cart, error)
+	Take(ctx context.Context, owner domain.CartOwner) (*domain.Cart, error)
+	TTL() time.Duration
+}
+
+type ScheduleRepository interface {
+	Create(ctx context.Context, schedule *domain.Schedule) (*domain.Schedule, error)
+	GetByID(ctx context.Context, id string) (*domain.Schedule, error)
+	ListByUserID(ctx context.Context, userID string) ([]*domain.Schedule, error)
+	// Update stores the status and next run of a schedule only if it was not
+	// changed since it was read at lastUpdatedAt. It returns false when a run
+	// or another request changed it in the meantime.
+	Update(ctx context.Context, schedule *domain.Schedule, lastUpdatedAt time.Time) (bool, error)
+	// ClaimDue atomically leases one active schedule whose run is due, so
+	// several order-service instances never run the same schedule twice.
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time) (*domain.Schedule, error)
+	// CompleteRun records the outcome of the run due at runAt and releases
+	// the lease. The next run is only moved if it is still runAt; when the
+	// customer paused, skipped or resumed the schedule during the run, their
+	// next run is kept.
+	CompleteRun(ctx context.Context, schedule *domain.Schedule, runAt time.Time) error
+	// RecordFailure stores the error and failure count of the run due at
+	// runAt and keeps the schedule leased until retryAt. When the customer
+	// moved the next run in the meantime, the failed run is not retried and
+	// the lease is released.
+	RecordFailure(ctx context.Context, schedule *domain.Schedule, runAt, retryAt time.Time) error
+}
+
+// ReturnListFilter selects returns by any combination of its fields; empty
+// fields match everything.
+type ReturnListFilter struct {
+	UserID  string
+	OrderID string
+	Status  domain.ReturnStatus
+}
+
+type ReturnRepository interface {
+	Create(ctx context.Context, ret *domain.Return) (*domain.Return, error)
+	GetByID(ctx context.Context, id string) (*domain.Return, error)
+	List(ctx context.Context, filter ReturnListFilter) ([]*domain.Return, error)
+	// Update stores the return only if it is still in status from, so two
+	// admins cannot, for example, receive the same parcel twice.
+	Update(ctx context.Context, ret *domain.Return, from domain.ReturnStatus) (bool, error)
+}
+
+type PaymentRepository interface {
+	Create(ctx context.Context, intent *domain.PaymentIntent) (*domain.PaymentIntent, error)
+	GetByID(ctx context.Context, id string) (*domain.PaymentIntent, error)
+	GetByProviderPaymentID(ctx context.Context, provider, providerPaymentID string) (*domain.PaymentIntent, error)
+	Update(ctx context.Context, intent *domain.PaymentIntent) (*domain.PaymentIntent, error)
+	// UpdateStatus stores the intent's new status only if it is still in
+	// status from, so of two concurrent callbacks only one applies.
+	UpdateStatus(ctx context.Context, intent *domain.PaymentIntent, from domain.PaymentStatus) (bool, error)
+	ListByOrderID(ctx context.Context, orderID string) ([]*domain.PaymentIntent, error)
+}
+
+// PendingReceiptRepository keeps the receipts still to be registered with the
+// OFD.
+type PendingReceiptRepository interface {
+	Create(ctx context.Context, receipt *domain.PendingReceipt) error
+	// ClaimDue atomically leases one receipt whose next attempt is due, so
+	// several instances never submit the same receipt at once.
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time) (*domain.PendingReceipt, error)
+	// RecordFailure stores the failed attempt and releases the lease.
+	RecordFailure(ctx context.Context, receipt *domain.PendingReceipt) error
+	Delete(ctx context.Context, id string) error
 }
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"order-service/internal/application"
+	"order-service/internal/domain"
+	cartpb "proto/cart"
+)
+
+type CartHandler struct {
+	cartpb.UnimplementedCartServiceServer
+	cartUseCase *application.CartUseCase
+}
+
+func NewCartHandler(cartUseCase *application.CartUseCase) *CartHandler {
+	return &CartHandler{
+		cartUseCase: cartUseCase,
+	}
+}
+
+func (h *CartHandler) GetCart(ctx context.Context, req *cartpb.GetCartRequest) (*cartpb.CartResponse, error) {
+	cart, err := h.cartUseCase.GetCart(ctx, toCartOwner(req.Owner))
+	if err != nil {
+		log.Printf("Error getting cart: %v", err)
+		return nil, err
+	}
+
+	return &cartpb.CartResponse{Cart: convertToProtoCart(cart)}, nil
+}
+
+func (h *CartHandler) AddItem(ctx context.Context, req *cartpb.AddItemRequest) (*cartpb.CartResponse, error) {
+	cart, err := h.cartUseCase.AddItem(ctx, toCartOwner(req.Owner), req.ProductId, int(req.Quantity))
+	if err != nil {
+		log.Printf("Error adding cart item: %v", err)
+		return nil, err
+	}
+
+	return &cartpb.CartResponse{Cart: convertToProtoCart(cart)}, nil
+}
+
+func (h *CartHandler) UpdateItem(ctx context.Context, req *cartpb.UpdateItemRequest) (*cartpb.CartResponse, error) {
+	cart, err := h.cartUseCase.UpdateItem(ctx, toCartOwner(req.Owner), req.ProductId, int(req.Quantity))
+	if err != nil {
+		log.Printf("Error updating cart item: %v", err)
+		return nil, err
+	}
+
+	return &cartpb.CartResponse{Cart: convertToProtoCart(cart)}, nil
+}
+
+func (h *CartHandler) RemoveItem(ctx context.Context, req *cartpb.RemoveItemRequest) (*cartpb.CartResponse, error) {
+	cart, err := h.cartUseCase.RemoveItem(ctx, toCartOwner(req.Owner), req.ProductId)
+	if err != nil {
+		log.Printf("Error removing cart item: %v", err)
+		return nil, err
+	}
+
+	return &cartpb.CartResponse{Cart: convertToProtoCart(cart)}, nil
+}
+
+func (h *CartHandler) MergeCart(ctx context.Context, req *cartpb.MergeCartRequest) (*cartpb.CartResponse, error) {
+	cart, err := h.cartUseCase.MergeCart(ctx, req.UserId, req.SessionId)
+	if err != nil {
+		log.Printf("Error merging carts: %v", err)
+		return nil, err
+	}
+
+	return &cartpb.CartResponse{Cart: convertToProtoCart(cart)}, nil
+}
+
+func (h *CartHandler) Checkout(ctx context.Context, req *cartpb.CheckoutRequest) (*cartpb.CheckoutResponse, error) {
+	createdOrder, err := h.cartUseCase.Checkout(ctx, req.UserId, req.Address)
+	if err != nil {
+		log.Printf("Error checking out cart: %v", err)
+		return nil, err
+	}
+
+	return &cartpb.CheckoutResponse{Order: convertToProtoOrder(createdOrder)}, nil
+}
+
+func (h *CartHandler) Reorder(ctx context.Context, req *cartpb.ReorderRequest) (*cartpb.ReorderResponse, error) {
+	result, err := h.cartUseCase.Reorder(ctx, req.OrderId, req.UserId, req.CreateOrder)
+	if err != nil {
+		log.Printf("Error reordering: %v", err)
+		return nil, err
+	}
+
+	changes := make([]*cartpb.ReorderChange, len(result.Changes))
+	for i, change := range result.Changes {
+		changes[i] = &cartpb.ReorderChange{
+			ProductId:        change.ProductID,
+			Name:             change.Name,
+			Kind:             string(change.Kind),
+			PreviousQuantity: int32(change.PreviousQuantity),
+			Quantity:         int32(change.Quantity),
+			PreviousPrice:    change.PreviousPrice.ToProto(),
+		}
+		if change.Kind != domain.ReorderUnavailable {
+			changes[i].CurrentPrice = change.CurrentPrice.ToProto()
+		}
+	}
+
+	res := &cartpb.ReorderResponse{Changes: changes}
+	if result.Cart != nil {
+		res.Cart = convertToProtoCart(result.Cart)
+	}
+	if result.Order != nil {
+		res.Order = convertToProtoOrder(result.Order)
+	}
+
+	return res, nil
+}
+
+func toCartOwner(owner *cartpb.CartOwner) domain.CartOwner {
+	return domain.CartOwner{
+		UserID:    owner.GetUserId(),
+		SessionID: owner.GetSessionId(),
+	}
+}
+
+func convertToProtoCart(cart *domain.PricedCart) *cartpb.Cart {
+	items := make([]*cartpb.CartItem, len(cart.Lines))
+	for i, line := range cart.Lines {
+		items[i] = &cartpb.CartItem{
+			ProductId:      line.ProductID,
+			Name:           line.Name,
+			Quantity:       int32(line.Quantity),
+			UnitPrice:      line.UnitPrice.ToProto(),
+			LineTotal:      line.LineTotal.ToProto(),
+			AvailableStock: int32(line.AvailableStock),
+			OutOfStock:     line.OutOfStock,
+			Unavailable:    line.Unavailable,
+			PriceChanged:   line.PriceChanged,
+		}
+		if line.PriceChanged {
+			items[i].PreviousPrice = line.PreviousPrice.ToProto()
+		}
+	}
+
+	return &cartpb.Cart{
+		Owner: &cartpb.CartOwner{
+			UserId:    cart.Owner.UserID,
+			SessionId: cart.Owner.SessionID,
+		},
+		Items:     items,
+		Total:     cart.Total.ToProto(),
+		HasIssues: cart.HasIssues,
+		UpdatedAt: cart.UpdatedAt.Format(time.RFC3339),
+		ExpiresAt: cart.ExpiresAt.Format(time.RFC3339),
+	}
+}
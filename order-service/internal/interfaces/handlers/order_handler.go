@@ -3,47 +3,61 @@ package handlers
 import (
 	"context"
 	"log"
+	"time"
 
 	"order-service/internal/application"
 	"order-service/internal/domain"
+	"order-service/internal/infrastructure/invoice"
+	"proto/money"
 	"proto/order"
 )
 
 type OrderHandler struct {
 	order.UnimplementedOrderServiceServer
-	orderUseCase *application.OrderUseCase
+	orderUseCase        *application.OrderUseCase
+	invoiceUseCase      *application.InvoiceUseCase
+	substitutionUseCase *application.SubstitutionUseCase
+	payments            application.PaymentCanceller
 }
 
-func NewOrderHandler(orderUseCase *application.OrderUseCase) *OrderHandler {
+func NewOrderHandler(orderUseCase *application.OrderUseCase, invoiceUseCase *application.InvoiceUseCase, substitutionUseCase *application.SubstitutionUseCase, payments application.PaymentCanceller) *OrderHandler {
 	return &OrderHandler{
-		orderUseCase: orderUseCase,
+		orderUseCase:        orderUseCase,
+		invoiceUseCase:      invoiceUseCase,
+		substitutionUseCase: substitutionUseCase,
+		payments:            payments,
 	}
 }
 
+// CreateOrder places an order at the catalog prices. An item price, if the
+// client sends one, is only checked against the catalog.
 func (h *OrderHandler) CreateOrder(ctx context.Context, req *order.OrderRequest) (*order.OrderResponse, error) {
+	for _, item := range req.Order.Items {
+		if _, err := domain.ParseSubstitutionPreference(item.Substitution); err != nil {
+			return nil, err
+		}
+	}
+
 	items := make([]domain.OrderItem, len(req.Order.Items))
 	for i, item := range req.Order.Items {
 		items[i] = domain.OrderItem{
 			ProductID: item.ProductId,
 			Quantity:  int(item.Quantity),
-			Price:     float64(item.Price),
+			Price:     money.FromProto(item.Price),
+
+			Substitution:        domain.SubstitutionPreference(item.Substitution),
+			SubstituteProductID: item.SubstituteProductId,
 		}
 	}
 
-	createdOrder, err := h.orderUseCase.CreateOrder(ctx, req.Order.UserId, items)
+	createdOrder, err := h.orderUseCase.CreateOrder(ctx, req.Order.UserId, items, req.Order.Address)
 	if err != nil {
 		log.Printf("Error creating order: %v", err)
 		return nil, err
 	}
 
 	return &order.OrderResponse{
-		Order: &order.Order{
-			Id:     createdOrder.ID,
-			UserId: createdOrder.UserID,
-			Items:  convertToProtoItems(createdOrder.Items),
-			Total:  float32(createdOrder.Total),
-			Status: string(createdOrder.Status),
-		},
+		Order: convertToProtoOrder(createdOrder),
 	}, nil
 }
 
@@ -59,13 +73,7 @@ func (h *OrderHandler) GetOrder(ctx context.Context, req *order.OrderID) (*order
 	}
 
 	return &order.OrderResponse{
-		Order: &order.Order{
-			Id:     domainOrder.ID,
-			UserId: domainOrder.UserID,
-			Items:  convertToProtoItems(domainOrder.Items),
-			Total:  float32(domainOrder.Total),
-			Status: string(domainOrder.Status),
-		},
+		Order: convertToProtoOrder(domainOrder),
 	}, nil
 }
 
@@ -81,13 +89,90 @@ func (h *OrderHandler) UpdateOrder(ctx context.Context, req *order.OrderRequest)
 	}
 
 	return &order.OrderResponse{
-		Order: &order.Order{
-			Id:     domainOrder.ID,
-			UserId: domainOrder.UserID,
-			Items:  convertToProtoItems(domainOrder.Items),
-			Total:  float32(domainOrder.Total),
-			Status: string(domainOrder.Status),
-		},
+		Order: convertToProtoOrder(domainOrder),
+	}, nil
+}
+
+func (h *OrderHandler) ModifyOrder(ctx context.Context, req *order.ModifyOrderRequest) (*order.ModifyOrderResponse, error) {
+	changes := make([]domain.OrderLineChange, len(req.Changes))
+	for i, change := range req.Changes {
+		changes[i] = domain.OrderLineChange{
+			ProductID: change.ProductId,
+			Quantity:  int(change.Quantity),
+		}
+	}
+
+	modifiedOrder, deltas, err := h.orderUseCase.ModifyOrder(ctx, req.OrderId, changes, h.payments)
+	if err != nil {
+		log.Printf("Error modifying order: %v", err)
+		return nil, err
+	}
+
+	if modifiedOrder == nil {
+		return &order.ModifyOrderResponse{}, nil
+	}
+
+	protoDeltas := make([]*order.QuantityDelta, len(deltas))
+	for i, delta := range deltas {
+		protoDeltas[i] = &order.QuantityDelta{
+			ProductId: delta.ProductID,
+			Delta:     int32(delta.Delta),
+		}
+	}
+
+	return &order.ModifyOrderResponse{
+		Order:  convertToProtoOrder(modifiedOrder),
+		Deltas: protoDeltas,
+	}, nil
+}
+
+func (h *OrderHandler) SetSubstitutionPreference(ctx context.Context, req *order.SubstitutionPreferenceRequest) (*order.OrderResponse, error) {
+	updatedOrder, err := h.substitutionUseCase.SetPreference(ctx, req.OrderId, req.UserId, req.ProductId, req.Preference, req.SubstituteProductId)
+	if err != nil {
+		log.Printf("Error setting substitution preference: %v", err)
+		return nil, err
+	}
+
+	if updatedOrder == nil {
+		return &order.OrderResponse{}, nil
+	}
+
+	return &order.OrderResponse{
+		Order: convertToProtoOrder(updatedOrder),
+	}, nil
+}
+
+func (h *OrderHandler) ProposeSubstitution(ctx context.Context, req *order.ProposeSubstitutionRequest) (*order.SubstitutionResponse, error) {
+	updatedOrder, substitution, err := h.substitutionUseCase.Propose(ctx, req.OrderId, req.ProductId, req.SubstituteProductId, int(req.Quantity))
+	if err != nil {
+		log.Printf("Error proposing substitution: %v", err)
+		return nil, err
+	}
+
+	if updatedOrder == nil {
+		return &order.SubstitutionResponse{}, nil
+	}
+
+	return &order.SubstitutionResponse{
+		Order:        convertToProtoOrder(updatedOrder),
+		Substitution: convertToProtoSubstitution(substitution),
+	}, nil
+}
+
+func (h *OrderHandler) DecideSubstitution(ctx context.Context, req *order.DecideSubstitutionRequest) (*order.SubstitutionResponse, error) {
+	updatedOrder, substitution, err := h.substitutionUseCase.Decide(ctx, req.OrderId, req.UserId, req.SubstitutionId, req.Approve)
+	if err != nil {
+		log.Printf("Error deciding substitution: %v", err)
+		return nil, err
+	}
+
+	if updatedOrder == nil {
+		return &order.SubstitutionResponse{}, nil
+	}
+
+	return &order.SubstitutionResponse{
+		Order:        convertToProtoOrder(updatedOrder),
+		Substitution: convertToProtoSubstitution(substitution),
 	}, nil
 }
 
@@ -100,13 +185,7 @@ func (h *OrderHandler) ListOrders(ctx context.Context, req *order.UserID) (*orde
 
 	protoOrders := make([]*order.Order, len(orders))
 	for i, domainOrder := range orders {
-		protoOrders[i] = &order.Order{
-			Id:     domainOrder.ID,
-			UserId: domainOrder.UserID,
-			Items:  convertToProtoItems(domainOrder.Items),
-			Total:  float32(domainOrder.Total),
-			Status: string(domainOrder.Status),
-		}
+		protoOrders[i] = convertToProtoOrder(domainOrder)
 	}
 
 	return &order.OrderListResponse{
@@ -122,14 +201,149 @@ func (h *OrderHandler) CheckStock(ctx context.Context, req *order.StockCheckRequ
 	}, nil
 }
 
+func (h *OrderHandler) GetInvoice(ctx context.Context, req *order.InvoiceRequest) (*order.InvoiceResponse, error) {
+	file, err := h.invoiceUseCase.GetInvoice(ctx, req.OrderId, req.Format, invoice.ParseLocale(req.Locale), invoice.Buyer{
+		Name: req.BuyerName,
+		BIN:  req.BuyerBin,
+	})
+	if err != nil {
+		log.Printf("Error rendering invoice: %v", err)
+		return nil, err
+	}
+
+	if file == nil {
+		return &order.InvoiceResponse{}, nil
+	}
+
+	return &order.InvoiceResponse{
+		ContentType: file.ContentType,
+		Filename:    file.Filename,
+		Content:     file.Content,
+	}, nil
+}
+
+func convertToProtoOrder(o *domain.Order) *order.Order {
+	return &order.Order{
+		Id:            o.ID,
+		UserId:        o.UserID,
+		Address:       o.Address,
+		Items:         convertToProtoItems(o.Items),
+		Total:         o.Total.ToProto(),
+		Status:        string(o.Status),
+		NetTotal:      o.NetTotal.ToProto(),
+		TaxTotal:      o.TaxTotal.ToProto(),
+		TaxBreakdown:  convertToProtoTaxLines(o.TaxBreakdown),
+		Refunds:       convertToProtoRefunds(o.Refunds),
+		RefundedTotal: o.RefundedTotal().ToProto(),
+		Receipts:      convertToProtoReceipts(o.Receipts),
+		Substitutions: convertToProtoSubstitutions(o.Substitutions),
+	}
+}
+
+func convertToProtoSubstitutions(substitutions []domain.Substitution) []*order.Substitution {
+	protoSubstitutions := make([]*order.Substitution, len(substitutions))
+	for i := range substitutions {
+		protoSubstitutions[i] = convertToProtoSubstitution(&substitutions[i])
+	}
+	return protoSubstitutions
+}
+
+func convertToProtoSubstitution(s *domain.Substitution) *order.Substitution {
+	protoSubstitution := &order.Substitution{
+		Id:                  s.ID,
+		ProductId:           s.ProductID,
+		Quantity:            int32(s.Quantity),
+		SubstituteProductId: s.SubstituteProductID,
+		SubstituteQuantity:  int32(s.SubstituteQuantity),
+		Status:              string(s.Status),
+		ProposedAt:          s.ProposedAt.Format(time.RFC3339),
+	}
+	if s.SubstituteProductID != "" {
+		protoSubstitution.Price = s.Price.ToProto()
+	}
+	if !s.ExpiresAt.IsZero() {
+		protoSubstitution.ExpiresAt = s.ExpiresAt.Format(time.RFC3339)
+	}
+	if !s.DecidedAt.IsZero() {
+		protoSubstitution.DecidedAt = s.DecidedAt.Format(time.RFC3339)
+	}
+	return protoSubstitution
+}
+
+func convertToProtoReceipts(receipts []domain.OrderReceipt) []*order.Receipt {
+	protoReceipts := make([]*order.Receipt, len(receipts))
+	for i, receipt := range receipts {
+		protoReceipts[i] = &order.Receipt{
+			Id:           receipt.ID,
+			Type:         string(receipt.Type),
+			RefundId:     receipt.RefundID,
+			FiscalNumber: receipt.FiscalNumber,
+			QrUrl:        receipt.QRURL,
+			Total:        receipt.Total.ToProto(),
+			IssuedAt:     receipt.IssuedAt.Format(time.RFC3339),
+		}
+	}
+	return protoReceipts
+}
+
+func convertToProtoRefunds(refunds []domain.Refund) []*order.Refund {
+	protoRefunds := make([]*order.Refund, len(refunds))
+	for i := range refunds {
+		protoRefunds[i] = convertToProtoRefund(&refunds[i])
+	}
+	return protoRefunds
+}
+
+func convertToProtoRefund(refund *domain.Refund) *order.Refund {
+	lines := make([]*order.RefundLine, len(refund.Lines))
+	for i, line := range refund.Lines {
+		lines[i] = &order.RefundLine{
+			ProductId: line.ProductID,
+			Quantity:  int32(line.Quantity),
+			Amount:    line.Amount.ToProto(),
+		}
+	}
+
+	return &order.Refund{
+		Id:               refund.ID,
+		PaymentId:        refund.PaymentID,
+		Amount:           refund.Amount.ToProto(),
+		Reason:           refund.Reason,
+		Lines:            lines,
+		ProviderRefundId: refund.ProviderRefundID,
+		CreatedAt:        refund.CreatedAt.Format(time.RFC3339),
+	}
+}
+
 func convertToProtoItems(items []domain.OrderItem) []*order.OrderItem {
 	protoItems := make([]*order.OrderItem, len(items))
 	for i, item := range items {
 		protoItems[i] = &order.OrderItem{
-			ProductId: item.ProductID,
-			Quantity:  int32(item.Quantity),
-			Price:     float32(item.Price),
+			ProductId:   item.ProductID,
+			Quantity:    int32(item.Quantity),
+			Price:       item.Price.ToProto(),
+			TaxClass:    string(item.TaxClass),
+			TaxRate:     int32(item.TaxRate),
+			NetAmount:   item.NetAmount.ToProto(),
+			TaxAmount:   item.TaxAmount.ToProto(),
+			GrossAmount: item.GrossAmount.ToProto(),
+
+			Substitution:        string(item.Preference()),
+			SubstituteProductId: item.SubstituteProductID,
 		}
 	}
 	return protoItems
 }
+
+func convertToProtoTaxLines(lines []domain.TaxLine) []*order.TaxLine {
+	protoLines := make([]*order.TaxLine, len(lines))
+	for i, line := range lines {
+		protoLines[i] = &order.TaxLine{
+			TaxRate:     int32(line.TaxRate),
+			NetAmount:   line.NetAmount.ToProto(),
+			TaxAmount:   line.TaxAmount.ToProto(),
+			GrossAmount: line.GrossAmount.ToProto(),
+		}
+	}
+	return protoLines
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"order-service/internal/application"
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/payment"
+	"proto/money"
+	paymentpb "proto/payment"
+)
+
+type PaymentHandler struct {
+	paymentpb.UnimplementedPaymentServiceServer
+	paymentUseCase *application.PaymentUseCase
+}
+
+func NewPaymentHandler(paymentUseCase *application.PaymentUseCase) *PaymentHandler {
+	return &PaymentHandler{
+		paymentUseCase: paymentUseCase,
+	}
+}
+
+func (h *PaymentHandler) CreatePayment(ctx context.Context, req *paymentpb.CreatePaymentRequest) (*paymentpb.PaymentResponse, error) {
+	intent, err := h.paymentUseCase.CreatePayment(ctx, req.OrderId, domain.PaymentMethod(req.Method))
+	if err != nil {
+		log.Printf("Error creating payment: %v", err)
+		return nil, err
+	}
+
+	return &paymentpb.PaymentResponse{
+		Payment: convertToProtoPayment(intent),
+	}, nil
+}
+
+func (h *PaymentHandler) GetPayment(ctx context.Context, req *paymentpb.PaymentID) (*paymentpb.PaymentResponse, error) {
+	intent, err := h.paymentUseCase.GetPayment(ctx, req.Id)
+	if err != nil {
+		log.Printf("Error getting payment: %v", err)
+		return nil, err
+	}
+
+	if intent == nil {
+		return &paymentpb.PaymentResponse{}, nil
+	}
+
+	return &paymentpb.PaymentResponse{
+		Payment: convertToProtoPayment(intent),
+	}, nil
+}
+
+func (h *PaymentHandler) ListOrderPayments(ctx context.Context, req *paymentpb.OrderPaymentsRequest) (*paymentpb.PaymentListResponse, error) {
+	intents, err := h.paymentUseCase.ListOrderPayments(ctx, req.OrderId)
+	if err != nil {
+		log.Printf("Error listing payments: %v", err)
+		return nil, err
+	}
+
+	protoPayments := make([]*paymentpb.PaymentIntent, len(intents))
+	for i, intent := range intents {
+		protoPayments[i] = convertToProtoPayment(intent)
+	}
+
+	return &paymentpb.PaymentListResponse{
+		Payments: protoPayments,
+	}, nil
+}
+
+func (h *PaymentHandler) HandlePaymentCallback(ctx context.Context, req *paymentpb.PaymentCallbackRequest) (*paymentpb.PaymentCallbackResponse, error) {
+	intent, err := h.paymentUseCase.HandleCallback(ctx, req.Provider, req.Payload, req.Signature)
+	if errors.Is(err, payment.ErrInvalidSignature) {
+		log.Printf("Rejected %s payment callback: %v", req.Provider, err)
+		return &paymentpb.PaymentCallbackResponse{
+			Accepted: false,
+			Message:  err.Error(),
+		}, nil
+	}
+	if err != nil {
+		log.Printf("Error handling payment callback: %v", err)
+		return nil, err
+	}
+
+	return &paymentpb.PaymentCallbackResponse{
+		Accepted: true,
+		Payment:  convertToProtoPayment(intent),
+	}, nil
+}
+
+func (h *PaymentHandler) Refund(ctx context.Context, req *paymentpb.RefundRequest) (*paymentpb.RefundResponse, error) {
+	lines := make([]domain.RefundLine, len(req.Lines))
+	for i, line := range req.Lines {
+		lines[i] = domain.RefundLine{
+			ProductID: line.ProductId,
+			Quantity:  int(line.Quantity),
+		}
+	}
+
+	var amount money.Money
+	if req.Amount != nil {
+		amount = money.FromProto(req.Amount)
+	}
+
+	refundedOrder, refund, err := h.paymentUseCase.Refund(ctx, req.OrderId, lines, amount, req.Reason)
+	if err != nil {
+		log.Printf("Error refunding order: %v", err)
+		return nil, err
+	}
+
+	return &paymentpb.RefundResponse{
+		Order:  convertToProtoOrder(refundedOrder),
+		Refund: convertToProtoRefund(refund),
+	}, nil
+}
+
+func convertToProtoPayment(intent *domain.PaymentIntent) *paymentpb.PaymentIntent {
+	return &paymentpb.PaymentIntent{
+		Id:                intent.ID,
+		OrderId:           intent.OrderID,
+		Amount:            intent.Amount.ToProto(),
+		Method:            string(intent.Method),
+		Status:            string(intent.Status),
+		Provider:          intent.Provider,
+		ProviderPaymentId: intent.ProviderPaymentID,
+		RedirectUrl:       intent.RedirectURL,
+		QrPayload:         intent.QRPayload,
+		CreatedAt:         intent.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:         intent.UpdatedAt.Format(time.RFC3339),
+	}
+}
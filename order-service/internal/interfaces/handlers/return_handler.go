@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"order-service/internal/application"
+	"order-service/internal/domain"
+	"order-service/internal/infrastructure/persistence"
+	rmapb "proto/rma"
+)
+
+type ReturnHandler struct {
+	rmapb.UnimplementedReturnServiceServer
+	returnUseCase *application.ReturnUseCase
+}
+
+func NewReturnHandler(returnUseCase *application.ReturnUseCase) *ReturnHandler {
+	return &ReturnHandler{
+		returnUseCase: returnUseCase,
+	}
+}
+
+func (h *ReturnHandler) CreateReturn(ctx context.Context, req *rmapb.CreateReturnRequest) (*rmapb.ReturnResponse, error) {
+	lines := make([]domain.ReturnLine, len(req.Lines))
+	for i, line := range req.Lines {
+		lines[i] = domain.ReturnLine{
+			ProductID: line.ProductId,
+			Quantity:  int(line.Quantity),
+		}
+	}
+
+	ret, err := h.returnUseCase.CreateReturn(ctx, req.OrderId, req.UserId, lines, req.Reason, req.Photos)
+	if err != nil {
+		log.Printf("Error creating return: %v", err)
+		return nil, err
+	}
+
+	return &rmapb.ReturnResponse{Return: convertToProtoReturn(ret)}, nil
+}
+
+func (h *ReturnHandler) GetReturn(ctx context.Context, req *rmapb.GetReturnRequest) (*rmapb.ReturnResponse, error) {
+	ret, err := h.returnUseCase.GetReturn(ctx, req.Id, req.UserId)
+	if err != nil {
+		log.Printf("Error getting return: %v", err)
+		return nil, err
+	}
+
+	if ret == nil {
+		return &rmapb.ReturnResponse{}, nil
+	}
+
+	return &rmapb.ReturnResponse{Return: convertToProtoReturn(ret)}, nil
+}
+
+func (h *ReturnHandler) ListReturns(ctx context.Context, req *rmapb.ListReturnsRequest) (*rmapb.ReturnListResponse, error) {
+	returns, err := h.returnUseCase.ListReturns(ctx, persistence.ReturnListFilter{
+		UserID:  req.UserId,
+		OrderID: req.OrderId,
+		Status:  domain.ReturnStatus(req.Status),
+	})
+	if err != nil {
+		log.Printf("Error listing returns: %v", err)
+		return nil, err
+	}
+
+	protoReturns := make([]*rmapb.Return, len(returns))
+	for i, ret := range returns {
+		protoReturns[i] = convertToProtoReturn(ret)
+	}
+
+	return &rmapb.ReturnListResponse{Returns: protoReturns}, nil
+}
+
+func (h *ReturnHandler) ApproveReturn(ctx context.Context, req *rmapb.ReviewReturnRequest) (*rmapb.ReturnResponse, error) {
+	return h.respond(h.returnUseCase.ApproveReturn(ctx, req.Id, req.Note))
+}
+
+func (h *ReturnHandler) RejectReturn(ctx context.Context, req *rmapb.ReviewReturnRequest) (*rmapb.ReturnResponse, error) {
+	return h.respond(h.returnUseCase.RejectReturn(ctx, req.Id, req.Note))
+}
+
+func (h *ReturnHandler) ReceiveReturn(ctx context.Context, req *rmapb.ReceiveReturnRequest) (*rmapb.ReturnResponse, error) {
+	return h.respond(h.returnUseCase.ReceiveReturn(ctx, req.Id, req.UnsellableProductIds, req.Note))
+}
+
+func (h *ReturnHandler) RefundReturn(ctx context.Context, req *rmapb.ReviewReturnRequest) (*rmapb.ReturnResponse, error) {
+	return h.respond(h.returnUseCase.RefundReturn(ctx, req.Id))
+}
+
+func (h *ReturnHandler) respond(ret *domain.Return, err error) (*rmapb.ReturnResponse, error) {
+	if err != nil {
+		log.Printf("Error reviewing return: %v", err)
+		return nil, err
+	}
+
+	if ret == nil {
+		return &rmapb.ReturnResponse{}, nil
+	}
+
+	return &rmapb.ReturnResponse{Return: convertToProtoReturn(ret)}, nil
+}
+
+func convertToProtoReturn(ret *domain.Return) *rmapb.Return {
+	lines := make([]*rmapb.ReturnLine, len(ret.Lines))
+	for i, line := range ret.Lines {
+		lines[i] = &rmapb.ReturnLine{
+			ProductId: line.ProductID,
+			Quantity:  int32(line.Quantity),
+			Sellable:  line.Sellable,
+		}
+	}
+
+	return &rmapb.Return{
+		Id:        ret.ID,
+		OrderId:   ret.OrderID,
+		UserId:    ret.UserID,
+		Lines:     lines,
+		Reason:    ret.Reason,
+		Photos:    ret.Photos,
+		Status:    string(ret.Status),
+		AdminNote: ret.AdminNote,
+		RefundId:  ret.RefundID,
+		CreatedAt: ret.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: ret.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"order-service/internal/application"
+	"order-service/internal/domain"
+	schedulepb "proto/schedule"
+)
+
+type ScheduleHandler struct {
+	schedulepb.UnimplementedScheduleServiceServer
+	scheduleUseCase *application.ScheduleUseCase
+}
+
+func NewScheduleHandler(scheduleUseCase *application.ScheduleUseCase) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleUseCase: scheduleUseCase,
+	}
+}
+
+func (h *ScheduleHandler) CreateSchedule(ctx context.Context, req *schedulepb.CreateScheduleRequest) (*schedulepb.ScheduleResponse, error) {
+	var startAt time.Time
+	if req.StartAt != "" {
+		parsed, err := time.Parse(time.RFC3339, req.StartAt)
+		if err != nil {
+			return nil, fmt.Errorf("start_at must be an RFC 3339 time: %w", err)
+		}
+		startAt = parsed
+	}
+
+	items := make([]domain.ScheduleItem, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = domain.ScheduleItem{
+			ProductID: item.ProductId,
+			Quantity:  int(item.Quantity),
+		}
+	}
+
+	schedule, err := h.scheduleUseCase.CreateSchedule(ctx, req.UserId, items, req.Address, domain.Cadence(req.Cadence), startAt)
+	if err != nil {
+		log.Printf("Error creating schedule: %v", err)
+		return nil, err
+	}
+
+	return &schedulepb.ScheduleResponse{Schedule: convertToProtoSchedule(schedule)}, nil
+}
+
+func (h *ScheduleHandler) GetSchedule(ctx context.Context, req *schedulepb.ScheduleRequest) (*schedulepb.ScheduleResponse, error) {
+	schedule, err := h.scheduleUseCase.GetSchedule(ctx, req.Id, req.UserId)
+	if err != nil {
+		log.Printf("Error getting schedule: %v", err)
+		return nil, err
+	}
+
+	if schedule == nil {
+		return &schedulepb.ScheduleResponse{}, nil
+	}
+
+	return &schedulepb.ScheduleResponse{Schedule: convertToProtoSchedule(schedule)}, nil
+}
+
+func (h *ScheduleHandler) ListSchedules(ctx context.Context, req *schedulepb.ListSchedulesRequest) (*schedulepb.ScheduleListResponse, error) {
+	schedules, err := h.scheduleUseCase.ListSchedules(ctx, req.UserId)
+	if err != nil {
+		log.Printf("Error listing schedules: %v", err)
+		return nil, err
+	}
+
+	protoSchedules := make([]*schedulepb.Schedule, len(schedules))
+	for i, schedule := range schedules {
+		protoSchedules[i] = convertToProtoSchedule(schedule)
+	}
+
+	return &schedulepb.ScheduleListResponse{Schedules: protoSchedules}, nil
+}
+
+func (h *ScheduleHandler) PauseSchedule(ctx context.Context, req *schedulepb.ScheduleRequest) (*schedulepb.ScheduleResponse, error) {
+	return h.respond(h.scheduleUseCase.PauseSchedule(ctx, req.Id, req.UserId))
+}
+
+func (h *ScheduleHandler) ResumeSchedule(ctx context.Context, req *schedulepb.ScheduleRequest) (*schedulepb.ScheduleResponse, error) {
+	return h.respond(h.scheduleUseCase.ResumeSchedule(ctx, req.Id, req.UserId))
+}
+
+func (h *ScheduleHandler) SkipNextRun(ctx context.Context, req *schedulepb.ScheduleRequest) (*schedulepb.ScheduleResponse, error) {
+	return h.respond(h.scheduleUseCase.SkipNext(ctx, req.Id, req.UserId))
+}
+
+func (h *ScheduleHandler) CancelSchedule(ctx context.Context, req *schedulepb.ScheduleRequest) (*schedulepb.ScheduleResponse, error) {
+	return h.respond(h.scheduleUseCase.CancelSchedule(ctx, req.Id, req.UserId))
+}
+
+func (h *ScheduleHandler) respond(schedule *domain.Schedule, err error) (*schedulepb.ScheduleResponse, error) {
+	if err != nil {
+		log.Printf("Error updating schedule: %v", err)
+		return nil, err
+	}
+
+	if schedule == nil {
+		return &schedulepb.ScheduleResponse{}, nil
+	}
+
+	return &schedulepb.ScheduleResponse{Schedule: convertToProtoSchedule(schedule)}, nil
+}
+
+func convertToProtoSchedule(schedule *domain.Schedule) *schedulepb.Schedule {
+	items := make([]*schedulepb.ScheduleItem, len(schedule.Items))
+	for i, item := range schedule.Items {
+		items[i] = &schedulepb.ScheduleItem{
+			ProductId: item.ProductID,
+			Quantity:  int32(item.Quantity),
+		}
+	}
+
+	protoSchedule := &schedulepb.Schedule{
+		Id:          schedule.ID,
+		UserId:      schedule.UserID,
+		Items:       items,
+		Address:     schedule.Address,
+		Cadence:     string(schedule.Cadence),
+		Status:      string(schedule.Status),
+		NextRunAt:   schedule.NextRunAt.Format(time.RFC3339),
+		LastOrderId: schedule.LastOrderID,
+		LastError:   schedule.LastError,
+		CreatedAt:   schedule.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   schedule.UpdatedAt.Format(time.RFC3339),
+	}
+	if !schedule.LastRunAt.IsZero() {
+		protoSchedule.LastRunAt = schedule.LastRunAt.Format(time.RFC3339)
+	}
+
+	return protoSchedule
+}
@@ -1,21 +1,34 @@
 package routes
 
 import (
+	"fmt"
 	"log"
 	"order-service/internal/application"
 	"order-service/internal/config"
+	"order-service/internal/domain"
 	"order-service/internal/infrastructure/database"
+	"order-service/internal/infrastructure/fiscal"
+	"order-service/internal/infrastructure/inventory"
+	"order-service/internal/infrastructure/invoice"
 	"order-service/internal/infrastructure/messaging"
+	"order-service/internal/infrastructure/payment"
 	"order-service/internal/infrastructure/persistence"
 	"order-service/internal/interfaces/handlers"
+	"time"
 
+	cartpb "proto/cart"
 	"proto/order"
+	paymentpb "proto/payment"
+	rmapb "proto/rma"
+	schedulepb "proto/schedule"
 
 	"google.golang.org/grpc"
 )
 
 type Services struct {
-	RedisCache *database.RedisCache
+	RedisCache     *database.RedisCache
+	ProductCatalog inventory.ProductCatalog
+	Workers        []*application.PeriodicWorker
 }
 
 func RegisterGRPCServices(grpcServer *grpc.Server, db *database.MongoDBConnector, publisher messaging.EventPublisher) *Services {
@@ -26,15 +39,109 @@ func RegisterGRPCServices(grpcServer *grpc.Server, db *database.MongoDBConnector
 		redisCache = nil
 	}
 
+	var productCatalog inventory.ProductCatalog
+	grpcCatalog, err := inventory.NewGRPCProductCatalog(cfg)
+	if err != nil {
+		log.Printf("Warning: Failed to create inventory client: %v. Orders cannot be placed until it is available.", err)
+	} else {
+		productCatalog = grpcCatalog
+	}
+
 	orderRepo := persistence.NewMongoOrderRepository(db)
 
-	orderUseCase := application.NewOrderUseCase(orderRepo, publisher, redisCache)
+	orderUseCase := application.NewOrderUseCase(orderRepo, publisher, redisCache, productCatalog)
+	paymentRepo := persistence.NewMongoPaymentRepository(db)
+	paymentProvider, err := newPaymentProvider(cfg)
+	if err != nil {
+		log.Fatalf("Failed to create payment provider: %v", err)
+	}
+	pendingReceiptRepo := persistence.NewMongoPendingReceiptRepository(db)
+	receiptUseCase := application.NewReceiptUseCase(orderUseCase, pendingReceiptRepo, newOFDProvider(cfg), productCatalog, domain.Cashier{
+		Name:                  cfg.Fiscal.CashierName,
+		KKMRegistrationNumber: cfg.Fiscal.KKMRegistrationNumber,
+		SellerBIN:             cfg.Fiscal.SellerBIN,
+		SellerName:            cfg.Fiscal.SellerName,
+	})
+	receiptWorker := application.NewReceiptWorker(receiptUseCase,
+		time.Duration(cfg.Fiscal.RetryInterval)*time.Second, time.Duration(cfg.Fiscal.RetryLease)*time.Second)
+	receiptWorker.Start()
+	paymentUseCase := application.NewPaymentUseCase(paymentRepo, orderUseCase, receiptUseCase, paymentProvider)
+	expiryWorker := application.NewExpiryWorker(orderUseCase, paymentUseCase, time.Duration(cfg.Expiry.TTL)*time.Second,
+		time.Duration(cfg.Expiry.Interval)*time.Second, time.Duration(cfg.Expiry.Lease)*time.Second)
+	expiryWorker.Start()
+	orderCreatedWorker := application.NewOrderCreatedWorker(orderUseCase,
+		time.Duration(cfg.NATS.RetryInterval)*time.Second, time.Duration(cfg.NATS.RetryLease)*time.Second)
+	orderCreatedWorker.Start()
+
+	invoiceUseCase := application.NewInvoiceUseCase(orderUseCase, productCatalog, invoice.Company{
+		Name:     cfg.Invoice.CompanyName,
+		BIN:      cfg.Invoice.CompanyBIN,
+		Address:  cfg.Invoice.Address,
+		BankName: cfg.Invoice.BankName,
+		IBAN:     cfg.Invoice.IBAN,
+		BIK:      cfg.Invoice.BIK,
+	})
+
+	var cartRepo persistence.CartRepository
+	if redisCache != nil {
+		cartRepo = persistence.NewRedisCartRepository(redisCache, time.Duration(cfg.Cart.TTL)*time.Second)
+	} else {
+		log.Println("Warning: Redis is unavailable, cart operations are disabled.")
+	}
+	cartUseCase := application.NewCartUseCase(cartRepo, orderUseCase, productCatalog)
+
+	scheduleRepo := persistence.NewMongoScheduleRepository(db)
+	scheduleUseCase := application.NewScheduleUseCase(scheduleRepo, orderUseCase, productCatalog, publisher)
+	scheduleWorker := application.NewScheduleWorker(scheduleUseCase,
+		time.Duration(cfg.Scheduler.Interval)*time.Second, time.Duration(cfg.Scheduler.Lease)*time.Second)
+	scheduleWorker.Start()
 
-	orderHandler := handlers.NewOrderHandler(orderUseCase)
+	substitutionUseCase := application.NewSubstitutionUseCase(orderRepo, orderUseCase, productCatalog, publisher, paymentUseCase,
+		time.Duration(cfg.Substitution.Timeout)*time.Second)
+	substitutionWorker := application.NewSubstitutionWorker(substitutionUseCase, time.Duration(cfg.Substitution.Interval)*time.Second)
+	substitutionWorker.Start()
+
+	returnRepo := persistence.NewMongoReturnRepository(db)
+	returnUseCase := application.NewReturnUseCase(returnRepo, orderUseCase, paymentUseCase, publisher)
+
+	orderHandler := handlers.NewOrderHandler(orderUseCase, invoiceUseCase, substitutionUseCase, paymentUseCase)
+	scheduleHandler := handlers.NewScheduleHandler(scheduleUseCase)
+	cartHandler := handlers.NewCartHandler(cartUseCase)
+	paymentHandler := handlers.NewPaymentHandler(paymentUseCase)
+	returnHandler := handlers.NewReturnHandler(returnUseCase)
 
 	order.RegisterOrderServiceServer(grpcServer, orderHandler)
+	paymentpb.RegisterPaymentServiceServer(grpcServer, paymentHandler)
+	cartpb.RegisterCartServiceServer(grpcServer, cartHandler)
+	schedulepb.RegisterScheduleServiceServer(grpcServer, scheduleHandler)
+	rmapb.RegisterReturnServiceServer(grpcServer, returnHandler)
 
 	return &Services{
-		RedisCache: redisCache,
+		RedisCache:     redisCache,
+		ProductCatalog: productCatalog,
+		Workers:        []*application.PeriodicWorker{expiryWorker, orderCreatedWorker, scheduleWorker, substitutionWorker, receiptWorker},
+	}
+}
+
+// newPaymentProvider refuses an unknown provider name instead of taking
+// payments through the fake provider by mistake.
+func newPaymentProvider(cfg *config.Config) (payment.PaymentProvider, error) {
+	switch cfg.Payment.Provider {
+	case payment.FakeProviderName:
+		log.Println("Using fake payment provider")
+		return payment.NewFakeProvider(cfg.Payment.WebhookSecret, cfg.Payment.BaseURL), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", cfg.Payment.Provider)
 	}
 }
+
+func newOFDProvider(cfg *config.Config) fiscal.OFDProvider {
+	switch cfg.Fiscal.Provider {
+	case fiscal.FakeOFDName:
+		log.Println("Using fake OFD, fiscal receipts are only recorded locally")
+	default:
+		log.Printf("Warning: unknown OFD provider %q, falling back to the fake OFD", cfg.Fiscal.Provider)
+	}
+
+	return fiscal.NewFakeOFD(cfg.Fiscal.QRBaseURL)
+}
@@ -0,0 +1,256 @@
+// Package events defines the events the services exchange over NATS and the
+// CloudEvents envelope they travel in.
+package events
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	SpecVersion = "1.0"
+
+	ContentTypeProtobuf = "application/protobuf"
+	ContentTypeJSON     = "application/json"
+	// ContentTypeCloudEventsJSON is the Content-Type header of a message that
+	// carries a whole envelope as JSON.
+	ContentTypeCloudEventsJSON = "application/cloudevents+json"
+
+	HeaderContentType = "Content-Type"
+)
+
+// Headers carrying the envelope of a message in binary content mode, where the
+// body is the data alone.
+const (
+	headerID            = "ce-id"
+	headerSource        = "ce-source"
+	headerSpecVersion   = "ce-specversion"
+	headerType          = "ce-type"
+	headerSubject       = "ce-subject"
+	headerTime          = "ce-time"
+	headerSchemaVersion = "ce-schemaversion"
+)
+
+// ErrUnsupportedVersion is returned for events of a schema version the
+// service does not know yet. They are dead-lettered and can be redriven once
+// the service is upgraded.
+var ErrUnsupportedVersion = errors.New("unsupported event schema version")
+
+// Event is the data of an envelope. It is published as its message in
+// events.proto and read from JSON too, as published before.
+type Event interface {
+	// SchemaVersion is the version of the struct's schema. It changes when a
+	// field is removed or changes meaning, not when one is added.
+	SchemaVersion() int
+	ToProto() proto.Message
+	fromProto(proto.Message)
+}
+
+// Upgrader is implemented by events that can read older versions of their
+// schema.
+type Upgrader interface {
+	Upgrade(envelope *Envelope) error
+}
+
+// Envelope holds the CloudEvents 1.0 attributes of an event and its encoded
+// data. SchemaVersion is an extension attribute.
+type Envelope struct {
+	ID              string
+	Source          string
+	SpecVersion     string
+	Type            string
+	Subject         string
+	Time            time.Time
+	DataContentType string
+	SchemaVersion   int
+	Data            []byte
+}
+
+// jsonEnvelope is the JSON format of an envelope, in which the events were
+// published before they were encoded with protobuf.
+type jsonEnvelope struct {
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	SpecVersion     string          `json:"specversion"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	SchemaVersion   int             `json:"schemaversion"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// New wraps event, encoded with protobuf. Subject names what the event is
+// about, an order ID for example.
+func New(source, eventType, subject string, event Event) (*Envelope, error) {
+	data, err := proto.Marshal(event.ToProto())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		ID:              newID(),
+		Source:          source,
+		SpecVersion:     SpecVersion,
+		Type:            eventType,
+		Subject:         subject,
+		Time:            time.Now().UTC(),
+		DataContentType: ContentTypeProtobuf,
+		SchemaVersion:   event.SchemaVersion(),
+		Data:            data,
+	}, nil
+}
+
+// Header returns the message headers of the envelope in binary content mode.
+// The message body is Data.
+func (e *Envelope) Header() map[string][]string {
+	header := map[string][]string{
+		HeaderContentType:   {e.DataContentType},
+		headerID:            {e.ID},
+		headerSource:        {e.Source},
+		headerSpecVersion:   {e.SpecVersion},
+		headerType:          {e.Type},
+		headerTime:          {e.Time.Format(time.RFC3339Nano)},
+		headerSchemaVersion: {strconv.Itoa(e.SchemaVersion)},
+	}
+	if e.Subject != "" {
+		header[headerSubject] = []string{e.Subject}
+	}
+	return header
+}
+
+// Parse reads an envelope from a message. The envelope is in the headers, or
+// in the body as JSON. A JSON body without envelope, as published before
+// envelopes were introduced, is taken as version 1 of the event named by the
+// NATS subject.
+func Parse(natsSubject string, header map[string][]string, body []byte) (*Envelope, error) {
+	var envelope *Envelope
+	if specVersion := headerValue(header, headerSpecVersion); specVersion != "" {
+		envelope = &Envelope{
+			ID:              headerValue(header, headerID),
+			Source:          headerValue(header, headerSource),
+			SpecVersion:     specVersion,
+			Type:            headerValue(header, headerType),
+			Subject:         headerValue(header, headerSubject),
+			DataContentType: headerValue(header, HeaderContentType),
+			SchemaVersion:   1,
+			Data:            body,
+		}
+		envelope.Time, _ = time.Parse(time.RFC3339Nano, headerValue(header, headerTime))
+		if version := headerValue(header, headerSchemaVersion); version != "" {
+			var err error
+			if envelope.SchemaVersion, err = strconv.Atoi(version); err != nil {
+				return nil, fmt.Errorf("invalid event schema version %q", version)
+			}
+		}
+	} else {
+		var structured jsonEnvelope
+		if err := json.Unmarshal(body, &structured); err != nil {
+			return nil, err
+		}
+		if structured.SpecVersion == "" {
+			return &Envelope{
+				Source:          "unknown",
+				SpecVersion:     SpecVersion,
+				Type:            natsSubject,
+				DataContentType: ContentTypeJSON,
+				SchemaVersion:   1,
+				Data:            body,
+			}, nil
+		}
+		envelope = &Envelope{
+			ID:              structured.ID,
+			Source:          structured.Source,
+			SpecVersion:     structured.SpecVersion,
+			Type:            structured.Type,
+			Subject:         structured.Subject,
+			Time:            structured.Time,
+			DataContentType: structured.DataContentType,
+			SchemaVersion:   structured.SchemaVersion,
+			Data:            structured.Data,
+		}
+	}
+
+	if envelope.SpecVersion != SpecVersion {
+		return nil, fmt.Errorf("unsupported CloudEvents spec version %q", envelope.SpecVersion)
+	}
+	if envelope.ID == "" || envelope.Type == "" {
+		return nil, errors.New("event envelope without id or type")
+	}
+	if envelope.DataContentType == "" {
+		envelope.DataContentType = ContentTypeJSON
+	}
+	return envelope, nil
+}
+
+// Decode reads the data into event. Older schema versions are read when event
+// is an Upgrader, newer ones are rejected.
+func (e *Envelope) Decode(event Event) error {
+	switch {
+	case e.SchemaVersion == event.SchemaVersion():
+		return e.decode(event)
+	case e.SchemaVersion < event.SchemaVersion():
+		if upgrader, ok := event.(Upgrader); ok {
+			return upgrader.Upgrade(e)
+		}
+	}
+	return fmt.Errorf("%w: %s version %d", ErrUnsupportedVersion, e.Type, e.SchemaVersion)
+}
+
+func (e *Envelope) decode(event Event) error {
+	switch e.DataContentType {
+	case ContentTypeProtobuf:
+		msg := event.ToProto().ProtoReflect().New().Interface()
+		if err := proto.Unmarshal(e.Data, msg); err != nil {
+			return err
+		}
+		event.fromProto(msg)
+		return nil
+	case ContentTypeJSON:
+		return json.Unmarshal(e.Data, event)
+	default:
+		return fmt.Errorf("unsupported event data content type %q", e.DataContentType)
+	}
+}
+
+// Event decodes the data into the event of the envelope's type.
+func (e *Envelope) Event() (Event, error) {
+	newEvent, ok := eventTypes[e.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type %s", e.Type)
+	}
+	event := newEvent()
+	if err := e.Decode(event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// headerValue looks a header up the way it was set, then regardless of case.
+func headerValue(header map[string][]string, key string) string {
+	if values := header[key]; len(values) > 0 {
+		return values[0]
+	}
+	for name, values := range header {
+		if strings.EqualFold(name, key) && len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// newID returns a random UUID.
+func newID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,230 @@
+package events
+
+import "proto/money"
+
+// Event types. They match the NATS subjects the events are published on.
+const (
+	TypeOrderCreated  = "order.created"
+	TypeOrderExpired  = "order.expired"
+	TypeOrderModified = "order.modified"
+
+	TypeSubstitutionProposed = "order.substitution_proposed"
+	TypeReturnReceived       = "return.received"
+	TypeOrderRefunded        = "order.refunded"
+	TypeOrderScheduled       = "order.scheduled"
+
+	TypeOrderConfirmed  = "order.confirmed"
+	TypeOrderDispatched = "order.dispatched"
+	TypeOrderDelivered  = "order.delivered"
+	TypeOrderCancelled  = "order.cancelled"
+
+	TypeStockChanged = "stock.changed"
+)
+
+// SubjectDeadLetter is where events that failed on their last delivery go.
+const SubjectDeadLetter = "dead.letter.queue"
+
+// eventTypes creates the event of each type.
+var eventTypes = map[string]func() Event{
+	TypeOrderCreated:         func() Event { return new(OrderCreated) },
+	TypeOrderExpired:         func() Event { return new(OrderExpired) },
+	TypeOrderModified:        func() Event { return new(OrderModified) },
+	TypeSubstitutionProposed: func() Event { return new(SubstitutionProposed) },
+	TypeReturnReceived:       func() Event { return new(ReturnReceived) },
+	TypeOrderRefunded:        func() Event { return new(OrderRefunded) },
+	TypeOrderScheduled:       func() Event { return new(ScheduledOrderCreated) },
+	TypeOrderConfirmed:       func() Event { return new(OrderStatusChanged) },
+	TypeOrderDispatched:      func() Event { return new(OrderStatusChanged) },
+	TypeOrderDelivered:       func() Event { return new(OrderStatusChanged) },
+	TypeOrderCancelled:       func() Event { return new(OrderStatusChanged) },
+	TypeStockChanged:         func() Event { return new(StockChanged) },
+}
+
+// StatusType returns the type of the event announcing that an order reached
+// status. Statuses with events of their own, such as paid or refunded, have
+// none.
+func StatusType(status string) (string, bool) {
+	switch status {
+	case "confirmed":
+		return TypeOrderConfirmed, true
+	case "dispatched":
+		return TypeOrderDispatched, true
+	case "completed":
+		return TypeOrderDelivered, true
+	case "cancelled":
+		return TypeOrderCancelled, true
+	default:
+		return "", false
+	}
+}
+
+type OrderItem struct {
+	ProductID string      `json:"product_id"`
+	Quantity  int         `json:"quantity"`
+	Price     money.Money `json:"price"`
+}
+
+type OrderCreated struct {
+	OrderID   string      `json:"order_id"`
+	UserID    string      `json:"user_id"`
+	Items     []OrderItem `json:"items"`
+	Total     money.Money `json:"total"`
+	Timestamp int64       `json:"timestamp"`
+}
+
+func (*OrderCreated) SchemaVersion() int { return 1 }
+
+func (e *OrderCreated) ProductIDs() []string {
+	return itemProductIDs(e.Items)
+}
+
+// OrderExpired announces a pending order cancelled for non-payment so the
+// stock it holds can be released.
+type OrderExpired struct {
+	OrderID   string      `json:"order_id"`
+	UserID    string      `json:"user_id"`
+	Items     []OrderItem `json:"items"`
+	Total     money.Money `json:"total"`
+	ExpiredAt int64       `json:"expired_at"`
+	Timestamp int64       `json:"timestamp"`
+}
+
+func (*OrderExpired) SchemaVersion() int { return 1 }
+
+func (e *OrderExpired) ProductIDs() []string {
+	return itemProductIDs(e.Items)
+}
+
+type StockDelta struct {
+	ProductID string `json:"product_id"`
+	Delta     int    `json:"delta"`
+}
+
+// OrderModified carries how much more (positive delta) or less (negative
+// delta) of each product an order holds after it was modified.
+type OrderModified struct {
+	OrderID   string       `json:"order_id"`
+	UserID    string       `json:"user_id"`
+	Deltas    []StockDelta `json:"deltas"`
+	Total     money.Money  `json:"total"`
+	Timestamp int64        `json:"timestamp"`
+}
+
+func (*OrderModified) SchemaVersion() int { return 1 }
+
+func (e *OrderModified) ProductIDs() []string {
+	ids := make([]string, len(e.Deltas))
+	for i, delta := range e.Deltas {
+		ids[i] = delta.ProductID
+	}
+	return ids
+}
+
+type RefundedItem struct {
+	ProductID string      `json:"product_id"`
+	Quantity  int         `json:"quantity"`
+	Amount    money.Money `json:"amount"`
+}
+
+type OrderRefunded struct {
+	OrderID       string         `json:"order_id"`
+	UserID        string         `json:"user_id"`
+	RefundID      string         `json:"refund_id"`
+	Amount        money.Money    `json:"amount"`
+	RefundedTotal money.Money    `json:"refunded_total"`
+	FullyRefunded bool           `json:"fully_refunded"`
+	Items         []RefundedItem `json:"items,omitempty"`
+	Reason        string         `json:"reason,omitempty"`
+	Timestamp     int64          `json:"timestamp"`
+}
+
+func (*OrderRefunded) SchemaVersion() int { return 1 }
+
+// ScheduledOrderCreated announces an order placed by a recurring schedule so
+// the customer can be notified.
+type ScheduledOrderCreated struct {
+	ScheduleID      string      `json:"schedule_id"`
+	OrderID         string      `json:"order_id"`
+	UserID          string      `json:"user_id"`
+	Address         string      `json:"address"`
+	Total           money.Money `json:"total"`
+	SkippedProducts []string    `json:"skipped_products,omitempty"`
+	NextRunAt       int64       `json:"next_run_at"`
+	Timestamp       int64       `json:"timestamp"`
+}
+
+func (*ScheduledOrderCreated) SchemaVersion() int { return 1 }
+
+// SubstitutionProposed asks the customer to approve or reject a substitute
+// for a missing product before ExpiresAt.
+type SubstitutionProposed struct {
+	OrderID             string      `json:"order_id"`
+	UserID              string      `json:"user_id"`
+	SubstitutionID      string      `json:"substitution_id"`
+	ProductID           string      `json:"product_id"`
+	SubstituteProductID string      `json:"substitute_product_id"`
+	Quantity            int         `json:"quantity"`
+	Price               money.Money `json:"price"`
+	ExpiresAt           int64       `json:"expires_at"`
+	Timestamp           int64       `json:"timestamp"`
+}
+
+func (*SubstitutionProposed) SchemaVersion() int { return 1 }
+
+type ReturnedItem struct {
+	ProductID string `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+	Sellable  bool   `json:"sellable"`
+}
+
+// ReturnReceived announces a returned parcel checked in at the warehouse.
+// Only sellable items are restocked.
+type ReturnReceived struct {
+	ReturnID  string         `json:"return_id"`
+	OrderID   string         `json:"order_id"`
+	UserID    string         `json:"user_id"`
+	Items     []ReturnedItem `json:"items"`
+	Timestamp int64          `json:"timestamp"`
+}
+
+func (*ReturnReceived) SchemaVersion() int { return 1 }
+
+func (e *ReturnReceived) ProductIDs() []string {
+	ids := make([]string, len(e.Items))
+	for i, item := range e.Items {
+		ids[i] = item.ProductID
+	}
+	return ids
+}
+
+// OrderStatusChanged announces a step in the order lifecycle. Its type is
+// the one of the new status, see StatusType.
+type OrderStatusChanged struct {
+	OrderID        string      `json:"order_id"`
+	UserID         string      `json:"user_id"`
+	Status         string      `json:"status"`
+	PreviousStatus string      `json:"previous_status"`
+	Total          money.Money `json:"total"`
+	Timestamp      int64       `json:"timestamp"`
+}
+
+func (*OrderStatusChanged) SchemaVersion() int { return 1 }
+
+// StockChanged announces a new stock level of a product, whether from
+// orders, returns or an edit in the catalog.
+type StockChanged struct {
+	ProductID     string `json:"product_id"`
+	PreviousStock int    `json:"previous_stock"`
+	Stock         int    `json:"stock"`
+	Timestamp     int64  `json:"timestamp"`
+}
+
+func (*StockChanged) SchemaVersion() int { return 1 }
+
+func itemProductIDs(items []OrderItem) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ProductID
+	}
+	return ids
+}
@@ -0,0 +1,288 @@
+package events
+
+import (
+	"proto/events/eventspb"
+	"proto/money"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Conversions between the events and their messages in events.proto, which
+// is how they are published.
+
+func (e *OrderCreated) ToProto() proto.Message {
+	return &eventspb.OrderCreated{
+		OrderId:   e.OrderID,
+		UserId:    e.UserID,
+		Items:     orderItemsToProto(e.Items),
+		Total:     e.Total.ToProto(),
+		Timestamp: e.Timestamp,
+	}
+}
+
+func (e *OrderCreated) fromProto(m proto.Message) {
+	pb := m.(*eventspb.OrderCreated)
+	*e = OrderCreated{
+		OrderID:   pb.OrderId,
+		UserID:    pb.UserId,
+		Items:     orderItemsFromProto(pb.Items),
+		Total:     money.FromProto(pb.Total),
+		Timestamp: pb.Timestamp,
+	}
+}
+
+func (e *OrderExpired) ToProto() proto.Message {
+	return &eventspb.OrderExpired{
+		OrderId:   e.OrderID,
+		UserId:    e.UserID,
+		Items:     orderItemsToProto(e.Items),
+		Total:     e.Total.ToProto(),
+		ExpiredAt: e.ExpiredAt,
+		Timestamp: e.Timestamp,
+	}
+}
+
+func (e *OrderExpired) fromProto(m proto.Message) {
+	pb := m.(*eventspb.OrderExpired)
+	*e = OrderExpired{
+		OrderID:   pb.OrderId,
+		UserID:    pb.UserId,
+		Items:     orderItemsFromProto(pb.Items),
+		Total:     money.FromProto(pb.Total),
+		ExpiredAt: pb.ExpiredAt,
+		Timestamp: pb.Timestamp,
+	}
+}
+
+func (e *OrderModified) ToProto() proto.Message {
+	deltas := make([]*eventspb.StockDelta, len(e.Deltas))
+	for i, delta := range e.Deltas {
+		deltas[i] = &eventspb.StockDelta{ProductId: delta.ProductID, Delta: int32(delta.Delta)}
+	}
+	return &eventspb.OrderModified{
+		OrderId:   e.OrderID,
+		UserId:    e.UserID,
+		Deltas:    deltas,
+		Total:     e.Total.ToProto(),
+		Timestamp: e.Timestamp,
+	}
+}
+
+func (e *OrderModified) fromProto(m proto.Message) {
+	pb := m.(*eventspb.OrderModified)
+	deltas := make([]StockDelta, len(pb.Deltas))
+	for i, delta := range pb.Deltas {
+		deltas[i] = StockDelta{ProductID: delta.ProductId, Delta: int(delta.Delta)}
+	}
+	*e = OrderModified{
+		OrderID:   pb.OrderId,
+		UserID:    pb.UserId,
+		Deltas:    deltas,
+		Total:     money.FromProto(pb.Total),
+		Timestamp: pb.Timestamp,
+	}
+}
+
+func (e *OrderRefunded) ToProto() proto.Message {
+	items := make([]*eventspb.RefundedItem, len(e.Items))
+	for i, item := range e.Items {
+		items[i] = &eventspb.RefundedItem{
+			ProductId: item.ProductID,
+			Quantity:  int32(item.Quantity),
+			Amount:    item.Amount.ToProto(),
+		}
+	}
+	return &eventspb.OrderRefunded{
+		OrderId:       e.OrderID,
+		UserId:        e.UserID,
+		RefundId:      e.RefundID,
+		Amount:        e.Amount.ToProto(),
+		RefundedTotal: e.RefundedTotal.ToProto(),
+		FullyRefunded: e.FullyRefunded,
+		Items:         items,
+		Reason:        e.Reason,
+		Timestamp:     e.Timestamp,
+	}
+}
+
+func (e *OrderRefunded) fromProto(m proto.Message) {
+	pb := m.(*eventspb.OrderRefunded)
+	var items []RefundedItem
+	for _, item := range pb.Items {
+		items = append(items, RefundedItem{
+			ProductID: item.ProductId,
+			Quantity:  int(item.Quantity),
+			Amount:    money.FromProto(item.Amount),
+		})
+	}
+	*e = OrderRefunded{
+		OrderID:       pb.OrderId,
+		UserID:        pb.UserId,
+		RefundID:      pb.RefundId,
+		Amount:        money.FromProto(pb.Amount),
+		RefundedTotal: money.FromProto(pb.RefundedTotal),
+		FullyRefunded: pb.FullyRefunded,
+		Items:         items,
+		Reason:        pb.Reason,
+		Timestamp:     pb.Timestamp,
+	}
+}
+
+func (e *ScheduledOrderCreated) ToProto() proto.Message {
+	return &eventspb.ScheduledOrderCreated{
+		ScheduleId:      e.ScheduleID,
+		OrderId:         e.OrderID,
+		UserId:          e.UserID,
+		Address:         e.Address,
+		Total:           e.Total.ToProto(),
+		SkippedProducts: e.SkippedProducts,
+		NextRunAt:       e.NextRunAt,
+		Timestamp:       e.Timestamp,
+	}
+}
+
+func (e *ScheduledOrderCreated) fromProto(m proto.Message) {
+	pb := m.(*eventspb.ScheduledOrderCreated)
+	*e = ScheduledOrderCreated{
+		ScheduleID:      pb.ScheduleId,
+		OrderID:         pb.OrderId,
+		UserID:          pb.UserId,
+		Address:         pb.Address,
+		Total:           money.FromProto(pb.Total),
+		SkippedProducts: pb.SkippedProducts,
+		NextRunAt:       pb.NextRunAt,
+		Timestamp:       pb.Timestamp,
+	}
+}
+
+func (e *SubstitutionProposed) ToProto() proto.Message {
+	return &eventspb.SubstitutionProposed{
+		OrderId:             e.OrderID,
+		UserId:              e.UserID,
+		SubstitutionId:      e.SubstitutionID,
+		ProductId:           e.ProductID,
+		SubstituteProductId: e.SubstituteProductID,
+		Quantity:            int32(e.Quantity),
+		Price:               e.Price.ToProto(),
+		ExpiresAt:           e.ExpiresAt,
+		Timestamp:           e.Timestamp,
+	}
+}
+
+func (e *SubstitutionProposed) fromProto(m proto.Message) {
+	pb := m.(*eventspb.SubstitutionProposed)
+	*e = SubstitutionProposed{
+		OrderID:             pb.OrderId,
+		UserID:              pb.UserId,
+		SubstitutionID:      pb.SubstitutionId,
+		ProductID:           pb.ProductId,
+		SubstituteProductID: pb.SubstituteProductId,
+		Quantity:            int(pb.Quantity),
+		Price:               money.FromProto(pb.Price),
+		ExpiresAt:           pb.ExpiresAt,
+		Timestamp:           pb.Timestamp,
+	}
+}
+
+func (e *ReturnReceived) ToProto() proto.Message {
+	items := make([]*eventspb.ReturnedItem, len(e.Items))
+	for i, item := range e.Items {
+		items[i] = &eventspb.ReturnedItem{
+			ProductId: item.ProductID,
+			Quantity:  int32(item.Quantity),
+			Sellable:  item.Sellable,
+		}
+	}
+	return &eventspb.ReturnReceived{
+		ReturnId:  e.ReturnID,
+		OrderId:   e.OrderID,
+		UserId:    e.UserID,
+		Items:     items,
+		Timestamp: e.Timestamp,
+	}
+}
+
+func (e *ReturnReceived) fromProto(m proto.Message) {
+	pb := m.(*eventspb.ReturnReceived)
+	items := make([]ReturnedItem, len(pb.Items))
+	for i, item := range pb.Items {
+		items[i] = ReturnedItem{
+			ProductID: item.ProductId,
+			Quantity:  int(item.Quantity),
+			Sellable:  item.Sellable,
+		}
+	}
+	*e = ReturnReceived{
+		ReturnID:  pb.ReturnId,
+		OrderID:   pb.OrderId,
+		UserID:    pb.UserId,
+		Items:     items,
+		Timestamp: pb.Timestamp,
+	}
+}
+
+func (e *OrderStatusChanged) ToProto() proto.Message {
+	return &eventspb.OrderStatusChanged{
+		OrderId:        e.OrderID,
+		UserId:         e.UserID,
+		Status:         e.Status,
+		PreviousStatus: e.PreviousStatus,
+		Total:          e.Total.ToProto(),
+		Timestamp:      e.Timestamp,
+	}
+}
+
+func (e *OrderStatusChanged) fromProto(m proto.Message) {
+	pb := m.(*eventspb.OrderStatusChanged)
+	*e = OrderStatusChanged{
+		OrderID:        pb.OrderId,
+		UserID:         pb.UserId,
+		Status:         pb.Status,
+		PreviousStatus: pb.PreviousStatus,
+		Total:          money.FromProto(pb.Total),
+		Timestamp:      pb.Timestamp,
+	}
+}
+
+func (e *StockChanged) ToProto() proto.Message {
+	return &eventspb.StockChanged{
+		ProductId:     e.ProductID,
+		PreviousStock: int32(e.PreviousStock),
+		Stock:         int32(e.Stock),
+		Timestamp:     e.Timestamp,
+	}
+}
+
+func (e *StockChanged) fromProto(m proto.Message) {
+	pb := m.(*eventspb.StockChanged)
+	*e = StockChanged{
+		ProductID:     pb.ProductId,
+		PreviousStock: int(pb.PreviousStock),
+		Stock:         int(pb.Stock),
+		Timestamp:     pb.Timestamp,
+	}
+}
+
+func orderItemsToProto(items []OrderItem) []*eventspb.OrderItem {
+	pb := make([]*eventspb.OrderItem, len(items))
+	for i, item := range items {
+		pb[i] = &eventspb.OrderItem{
+			ProductId: item.ProductID,
+			Quantity:  int32(item.Quantity),
+			Price:     item.Price.ToProto(),
+		}
+	}
+	return pb
+}
+
+func orderItemsFromProto(pb []*eventspb.OrderItem) []OrderItem {
+	items := make([]OrderItem, len(pb))
+	for i, item := range pb {
+		items[i] = OrderItem{
+			ProductID: item.ProductId,
+			Quantity:  int(item.Quantity),
+			Price:     money.FromProto(item.Price),
+		}
+	}
+	return items
+}
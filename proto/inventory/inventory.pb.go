@@ -9,6 +9,7 @@ package inventory
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	common "proto/common"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -22,13 +23,18 @@ const (
 )
 
 type Product struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Price         float32                `protobuf:"fixed32,4,opt,name=price,proto3" json:"price,omitempty"`
-	Stock         int32                  `protobuf:"varint,5,opt,name=stock,proto3" json:"stock,omitempty"`
-	CategoryId    string                 `protobuf:"bytes,6,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Stock       int32                  `protobuf:"varint,5,opt,name=stock,proto3" json:"stock,omitempty"`
+	CategoryId  string                 `protobuf:"bytes,6,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	// VAT class of the product: "standard" or "exempt". Empty inherits the category's class.
+	TaxClass string        `protobuf:"bytes,7,opt,name=tax_class,json=taxClass,proto3" json:"tax_class,omitempty"`
+	Price    *common.Money `protobuf:"bytes,8,opt,name=price,proto3" json:"price,omitempty"`
+	// Average rating of the approved reviews, 0 when there are none.
+	RatingAverage float64 `protobuf:"fixed64,9,opt,name=rating_average,json=ratingAverage,proto3" json:"rating_average,omitempty"`
+	RatingCount   int32   `protobuf:"varint,10,opt,name=rating_count,json=ratingCount,proto3" json:"rating_count,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -84,13 +90,6 @@ func (x *Product) GetDescription() string {
 	return ""
 }
 
-func (x *Product) GetPrice() float32 {
-	if x != nil {
-		return x.Price
-	}
-	return 0
-}
-
 func (x *Product) GetStock() int32 {
 	if x != nil {
 		return x.Stock
@@ -105,11 +104,41 @@ func (x *Product) GetCategoryId() string {
 	return ""
 }
 
+func (x *Product) GetTaxClass() string {
+	if x != nil {
+		return x.TaxClass
+	}
+	return ""
+}
+
+func (x *Product) GetPrice() *common.Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *Product) GetRatingAverage() float64 {
+	if x != nil {
+		return x.RatingAverage
+	}
+	return 0
+}
+
+func (x *Product) GetRatingCount() int32 {
+	if x != nil {
+		return x.RatingCount
+	}
+	return 0
+}
+
 type Category struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// VAT class applied to products of this category that do not set their own.
+	TaxClass      string `protobuf:"bytes,4,opt,name=tax_class,json=taxClass,proto3" json:"tax_class,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -165,6 +194,13 @@ func (x *Category) GetDescription() string {
 	return ""
 }
 
+func (x *Category) GetTaxClass() string {
+	if x != nil {
+		return x.TaxClass
+	}
+	return ""
+}
+
 type ProductID struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -638,9 +674,11 @@ func (x *DecreaseStockRequest) GetQuantity() int32 {
 }
 
 type DecreaseStockResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Stock left after the decrease.
+	Stock         int32 `protobuf:"varint,3,opt,name=stock,proto3" json:"stock,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -689,26 +727,35 @@ func (x *DecreaseStockResponse) GetMessage() string {
 	return ""
 }
 
-type Empty struct {
+func (x *DecreaseStockResponse) GetStock() int32 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+type StockItem struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Empty) Reset() {
-	*x = Empty{}
+func (x *StockItem) Reset() {
+	*x = StockItem{}
 	mi := &file_inventory_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Empty) String() string {
+func (x *StockItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Empty) ProtoMessage() {}
+func (*StockItem) ProtoMessage() {}
 
-func (x *Empty) ProtoReflect() protoreflect.Message {
+func (x *StockItem) ProtoReflect() protoreflect.Message {
 	mi := &file_inventory_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -720,61 +767,1176 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
-func (*Empty) Descriptor() ([]byte, []int) {
+// Deprecated: Use StockItem.ProtoReflect.Descriptor instead.
+func (*StockItem) Descriptor() ([]byte, []int) {
 	return file_inventory_proto_rawDescGZIP(), []int{13}
 }
 
-var File_inventory_proto protoreflect.FileDescriptor
+func (x *StockItem) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
 
-const file_inventory_proto_rawDesc = "" +
-	"\n" +
-	"\x0finventory.proto\x12\tinventory\"\x9c\x01\n" +
-	"\aProduct\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
-	"\x05price\x18\x04 \x01(\x02R\x05price\x12\x14\n" +
-	"\x05stock\x18\x05 \x01(\x05R\x05stock\x12\x1f\n" +
-	"\vcategory_id\x18\x06 \x01(\tR\n" +
-	"categoryId\"P\n" +
-	"\bCategory\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\"\x1b\n" +
-	"\tProductID\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"\x1c\n" +
-	"\n" +
-	"CategoryID\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\">\n" +
-	"\x0eProductRequest\x12,\n" +
-	"\aproduct\x18\x01 \x01(\v2\x12.inventory.ProductR\aproduct\"?\n" +
-	"\x0fProductResponse\x12,\n" +
-	"\aproduct\x18\x01 \x01(\v2\x12.inventory.ProductR\aproduct\"B\n" +
-	"\x0fCategoryRequest\x12/\n" +
-	"\bcategory\x18\x01 \x01(\v2\x13.inventory.CategoryR\bcategory\"C\n" +
-	"\x10CategoryResponse\x12/\n" +
-	"\bcategory\x18\x01 \x01(\v2\x13.inventory.CategoryR\bcategory\"_\n" +
-	"\x12ProductListRequest\x12\x1f\n" +
-	"\vcategory_id\x18\x01 \x01(\tR\n" +
-	"categoryId\x12\x12\n" +
-	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x14\n" +
-	"\x05limit\x18\x03 \x01(\x05R\x05limit\"[\n" +
-	"\x13ProductListResponse\x12.\n" +
-	"\bproducts\x18\x01 \x03(\v2\x12.inventory.ProductR\bproducts\x12\x14\n" +
-	"\x05total\x18\x02 \x01(\x05R\x05total\"K\n" +
-	"\x14CategoryListResponse\x123\n" +
-	"\n" +
-	"categories\x18\x01 \x03(\v2\x13.inventory.CategoryR\n" +
-	"categories\"Q\n" +
-	"\x14DecreaseStockRequest\x12\x1d\n" +
-	"\n" +
-	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
-	"\bquantity\x18\x02 \x01(\x05R\bquantity\"K\n" +
-	"\x15DecreaseStockResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"\a\n" +
-	"\x05Empty2\x97\x06\n" +
+func (x *StockItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type DecreaseStockBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*StockItem           `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecreaseStockBatchRequest) Reset() {
+	*x = DecreaseStockBatchRequest{}
+	mi := &file_inventory_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecreaseStockBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecreaseStockBatchRequest) ProtoMessage() {}
+
+func (x *DecreaseStockBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecreaseStockBatchRequest.ProtoReflect.Descriptor instead.
+func (*DecreaseStockBatchRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DecreaseStockBatchRequest) GetItems() []*StockItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type StockLevel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Stock         int32                  `protobuf:"varint,2,opt,name=stock,proto3" json:"stock,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockLevel) Reset() {
+	*x = StockLevel{}
+	mi := &file_inventory_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockLevel) ProtoMessage() {}
+
+func (x *StockLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockLevel.ProtoReflect.Descriptor instead.
+func (*StockLevel) Descriptor() ([]byte, []int) {
+	return file_inventory_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *StockLevel) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *StockLevel) GetStock() int32 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+type DecreaseStockBatchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// False when one item is short, in which case no stock was taken.
+	Success       bool          `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string        `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Stock         []*StockLevel `protobuf:"bytes,3,rep,name=stock,proto3" json:"stock,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecreaseStockBatchResponse) Reset() {
+	*x = DecreaseStockBatchResponse{}
+	mi := &file_inventory_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecreaseStockBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecreaseStockBatchResponse) ProtoMessage() {}
+
+func (x *DecreaseStockBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecreaseStockBatchResponse.ProtoReflect.Descriptor instead.
+func (*DecreaseStockBatchResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DecreaseStockBatchResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DecreaseStockBatchResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *DecreaseStockBatchResponse) GetStock() []*StockLevel {
+	if x != nil {
+		return x.Stock
+	}
+	return nil
+}
+
+type Review struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	UserId    string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Delivered order that verifies the purchase.
+	OrderId string `protobuf:"bytes,4,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Rating  int32  `protobuf:"varint,5,opt,name=rating,proto3" json:"rating,omitempty"`
+	Text    string `protobuf:"bytes,6,opt,name=text,proto3" json:"text,omitempty"`
+	// "pending", "approved" or "rejected".
+	Status         string `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	ModerationNote string `protobuf:"bytes,8,opt,name=moderation_note,json=moderationNote,proto3" json:"moderation_note,omitempty"`
+	CreatedAt      string `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      string `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Review) Reset() {
+	*x = Review{}
+	mi := &file_inventory_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Review) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Review) ProtoMessage() {}
+
+func (x *Review) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Review.ProtoReflect.Descriptor instead.
+func (*Review) Descriptor() ([]byte, []int) {
+	return file_inventory_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Review) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Review) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *Review) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Review) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Review) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *Review) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Review) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Review) GetModerationNote() string {
+	if x != nil {
+		return x.ModerationNote
+	}
+	return ""
+}
+
+func (x *Review) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Review) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type CreateReviewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Rating        int32                  `protobuf:"varint,3,opt,name=rating,proto3" json:"rating,omitempty"`
+	Text          string                 `protobuf:"bytes,4,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateReviewRequest) Reset() {
+	*x = CreateReviewRequest{}
+	mi := &file_inventory_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateReviewRequest) ProtoMessage() {}
+
+func (x *CreateReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateReviewRequest.ProtoReflect.Descriptor instead.
+func (*CreateReviewRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CreateReviewRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *CreateReviewRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateReviewRequest) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *CreateReviewRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type ListReviewsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Empty lists reviews of all products.
+	ProductId string `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Page      int32  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	Limit     int32  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// "newest" (default), "oldest", "highest" or "lowest".
+	Sort string `protobuf:"bytes,4,opt,name=sort,proto3" json:"sort,omitempty"`
+	// Defaults to "approved".
+	Status        string `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewsRequest) Reset() {
+	*x = ListReviewsRequest{}
+	mi := &file_inventory_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewsRequest) ProtoMessage() {}
+
+func (x *ListReviewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewsRequest.ProtoReflect.Descriptor instead.
+func (*ListReviewsRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListReviewsRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ListReviewsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListReviewsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListReviewsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListReviewsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ListReviewsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reviews       []*Review              `protobuf:"bytes,1,rep,name=reviews,proto3" json:"reviews,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewsResponse) Reset() {
+	*x = ListReviewsResponse{}
+	mi := &file_inventory_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewsResponse) ProtoMessage() {}
+
+func (x *ListReviewsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewsResponse.ProtoReflect.Descriptor instead.
+func (*ListReviewsResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListReviewsResponse) GetReviews() []*Review {
+	if x != nil {
+		return x.Reviews
+	}
+	return nil
+}
+
+func (x *ListReviewsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type ModerateReviewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Approve       bool                   `protobuf:"varint,2,opt,name=approve,proto3" json:"approve,omitempty"`
+	Note          string                 `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModerateReviewRequest) Reset() {
+	*x = ModerateReviewRequest{}
+	mi := &file_inventory_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModerateReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerateReviewRequest) ProtoMessage() {}
+
+func (x *ModerateReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerateReviewRequest.ProtoReflect.Descriptor instead.
+func (*ModerateReviewRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ModerateReviewRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ModerateReviewRequest) GetApprove() bool {
+	if x != nil {
+		return x.Approve
+	}
+	return false
+}
+
+func (x *ModerateReviewRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type ReviewResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Review        *Review                `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewResponse) Reset() {
+	*x = ReviewResponse{}
+	mi := &file_inventory_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewResponse) ProtoMessage() {}
+
+func (x *ReviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewResponse.ProtoReflect.Descriptor instead.
+func (*ReviewResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ReviewResponse) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+// DeadLetter is an event that failed on its last delivery to a consumer.
+type DeadLetter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint64                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	SourceSubject string                 `protobuf:"bytes,2,opt,name=source_subject,json=sourceSubject,proto3" json:"source_subject,omitempty"`
+	// Durable consumer the event failed in.
+	Consumer       string `protobuf:"bytes,3,opt,name=consumer,proto3" json:"consumer,omitempty"`
+	Error          string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	Attempts       int32  `protobuf:"varint,5,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	FirstFailureAt string `protobuf:"bytes,6,opt,name=first_failure_at,json=firstFailureAt,proto3" json:"first_failure_at,omitempty"`
+	FailedAt       string `protobuf:"bytes,7,opt,name=failed_at,json=failedAt,proto3" json:"failed_at,omitempty"`
+	// The event as it was published.
+	Data          []byte `protobuf:"bytes,8,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeadLetter) Reset() {
+	*x = DeadLetter{}
+	mi := &file_inventory_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeadLetter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetter) ProtoMessage() {}
+
+func (x *DeadLetter) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetter.ProtoReflect.Descriptor instead.
+func (*DeadLetter) Descriptor() ([]byte, []int) {
+	return file_inventory_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *DeadLetter) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *DeadLetter) GetSourceSubject() string {
+	if x != nil {
+		return x.SourceSubject
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetConsumer() string {
+	if x != nil {
+		return x.Consumer
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *DeadLetter) GetFirstFailureAt() string {
+	if x != nil {
+		return x.FirstFailureAt
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetFailedAt() string {
+	if x != nil {
+		return x.FailedAt
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type DeadLetterID struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint64                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeadLetterID) Reset() {
+	*x = DeadLetterID{}
+	mi := &file_inventory_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeadLetterID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetterID) ProtoMessage() {}
+
+func (x *DeadLetterID) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetterID.ProtoReflect.Descriptor instead.
+func (*DeadLetterID) Descriptor() ([]byte, []int) {
+	return file_inventory_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *DeadLetterID) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+type DeadLetterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeadLetter    *DeadLetter            `protobuf:"bytes,1,opt,name=dead_letter,json=deadLetter,proto3" json:"dead_letter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeadLetterResponse) Reset() {
+	*x = DeadLetterResponse{}
+	mi := &file_inventory_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeadLetterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetterResponse) ProtoMessage() {}
+
+func (x *DeadLetterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetterResponse.ProtoReflect.Descriptor instead.
+func (*DeadLetterResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *DeadLetterResponse) GetDeadLetter() *DeadLetter {
+	if x != nil {
+		return x.DeadLetter
+	}
+	return nil
+}
+
+type ListDeadLettersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Empty lists dead letters from all subjects.
+	SourceSubject string `protobuf:"bytes,1,opt,name=source_subject,json=sourceSubject,proto3" json:"source_subject,omitempty"`
+	Page          int32  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadLettersRequest) Reset() {
+	*x = ListDeadLettersRequest{}
+	mi := &file_inventory_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadLettersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadLettersRequest) ProtoMessage() {}
+
+func (x *ListDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListDeadLettersRequest) GetSourceSubject() string {
+	if x != nil {
+		return x.SourceSubject
+	}
+	return ""
+}
+
+func (x *ListDeadLettersRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListDeadLettersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListDeadLettersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeadLetters   []*DeadLetter          `protobuf:"bytes,1,rep,name=dead_letters,json=deadLetters,proto3" json:"dead_letters,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadLettersResponse) Reset() {
+	*x = ListDeadLettersResponse{}
+	mi := &file_inventory_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadLettersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadLettersResponse) ProtoMessage() {}
+
+func (x *ListDeadLettersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadLettersResponse.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListDeadLettersResponse) GetDeadLetters() []*DeadLetter {
+	if x != nil {
+		return x.DeadLetters
+	}
+	return nil
+}
+
+func (x *ListDeadLettersResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type PurgeDeadLettersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Set to purge a single dead letter.
+	Sequence uint64 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// Empty purges dead letters from all subjects.
+	SourceSubject string `protobuf:"bytes,2,opt,name=source_subject,json=sourceSubject,proto3" json:"source_subject,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeDeadLettersRequest) Reset() {
+	*x = PurgeDeadLettersRequest{}
+	mi := &file_inventory_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeDeadLettersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeDeadLettersRequest) ProtoMessage() {}
+
+func (x *PurgeDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*PurgeDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *PurgeDeadLettersRequest) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *PurgeDeadLettersRequest) GetSourceSubject() string {
+	if x != nil {
+		return x.SourceSubject
+	}
+	return ""
+}
+
+type PurgeDeadLettersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Purged        int32                  `protobuf:"varint,1,opt,name=purged,proto3" json:"purged,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeDeadLettersResponse) Reset() {
+	*x = PurgeDeadLettersResponse{}
+	mi := &file_inventory_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeDeadLettersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeDeadLettersResponse) ProtoMessage() {}
+
+func (x *PurgeDeadLettersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeDeadLettersResponse.ProtoReflect.Descriptor instead.
+func (*PurgeDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *PurgeDeadLettersResponse) GetPurged() int32 {
+	if x != nil {
+		return x.Purged
+	}
+	return 0
+}
+
+type Empty struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Empty) Reset() {
+	*x = Empty{}
+	mi := &file_inventory_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Empty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Empty) ProtoMessage() {}
+
+func (x *Empty) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
+func (*Empty) Descriptor() ([]byte, []int) {
+	return file_inventory_proto_rawDescGZIP(), []int{30}
+}
+
+var File_inventory_proto protoreflect.FileDescriptor
+
+const file_inventory_proto_rawDesc = "" +
+	"\n" +
+	"\x0finventory.proto\x12\tinventory\x1a\fcommon.proto\"\x98\x02\n" +
+	"\aProduct\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05stock\x18\x05 \x01(\x05R\x05stock\x12\x1f\n" +
+	"\vcategory_id\x18\x06 \x01(\tR\n" +
+	"categoryId\x12\x1b\n" +
+	"\ttax_class\x18\a \x01(\tR\btaxClass\x12#\n" +
+	"\x05price\x18\b \x01(\v2\r.common.MoneyR\x05price\x12%\n" +
+	"\x0erating_average\x18\t \x01(\x01R\rratingAverage\x12!\n" +
+	"\frating_count\x18\n" +
+	" \x01(\x05R\vratingCountJ\x04\b\x04\x10\x05\"m\n" +
+	"\bCategory\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1b\n" +
+	"\ttax_class\x18\x04 \x01(\tR\btaxClass\"\x1b\n" +
+	"\tProductID\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x1c\n" +
+	"\n" +
+	"CategoryID\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\">\n" +
+	"\x0eProductRequest\x12,\n" +
+	"\aproduct\x18\x01 \x01(\v2\x12.inventory.ProductR\aproduct\"?\n" +
+	"\x0fProductResponse\x12,\n" +
+	"\aproduct\x18\x01 \x01(\v2\x12.inventory.ProductR\aproduct\"B\n" +
+	"\x0fCategoryRequest\x12/\n" +
+	"\bcategory\x18\x01 \x01(\v2\x13.inventory.CategoryR\bcategory\"C\n" +
+	"\x10CategoryResponse\x12/\n" +
+	"\bcategory\x18\x01 \x01(\v2\x13.inventory.CategoryR\bcategory\"_\n" +
+	"\x12ProductListRequest\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\tR\n" +
+	"categoryId\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"[\n" +
+	"\x13ProductListResponse\x12.\n" +
+	"\bproducts\x18\x01 \x03(\v2\x12.inventory.ProductR\bproducts\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"K\n" +
+	"\x14CategoryListResponse\x123\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\v2\x13.inventory.CategoryR\n" +
+	"categories\"Q\n" +
+	"\x14DecreaseStockRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"a\n" +
+	"\x15DecreaseStockResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
+	"\x05stock\x18\x03 \x01(\x05R\x05stock\"F\n" +
+	"\tStockItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"G\n" +
+	"\x19DecreaseStockBatchRequest\x12*\n" +
+	"\x05items\x18\x01 \x03(\v2\x14.inventory.StockItemR\x05items\"A\n" +
+	"\n" +
+	"StockLevel\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x14\n" +
+	"\x05stock\x18\x02 \x01(\x05R\x05stock\"}\n" +
+	"\x1aDecreaseStockBatchResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12+\n" +
+	"\x05stock\x18\x03 \x03(\v2\x15.inventory.StockLevelR\x05stock\"\x96\x02\n" +
+	"\x06Review\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x19\n" +
+	"\border_id\x18\x04 \x01(\tR\aorderId\x12\x16\n" +
+	"\x06rating\x18\x05 \x01(\x05R\x06rating\x12\x12\n" +
+	"\x04text\x18\x06 \x01(\tR\x04text\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12'\n" +
+	"\x0fmoderation_note\x18\b \x01(\tR\x0emoderationNote\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\tR\tupdatedAt\"y\n" +
+	"\x13CreateReviewRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06rating\x18\x03 \x01(\x05R\x06rating\x12\x12\n" +
+	"\x04text\x18\x04 \x01(\tR\x04text\"\x89\x01\n" +
+	"\x12ListReviewsRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x12\n" +
+	"\x04sort\x18\x04 \x01(\tR\x04sort\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\"X\n" +
+	"\x13ListReviewsResponse\x12+\n" +
+	"\areviews\x18\x01 \x03(\v2\x11.inventory.ReviewR\areviews\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"U\n" +
+	"\x15ModerateReviewRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\aapprove\x18\x02 \x01(\bR\aapprove\x12\x12\n" +
+	"\x04note\x18\x03 \x01(\tR\x04note\";\n" +
+	"\x0eReviewResponse\x12)\n" +
+	"\x06review\x18\x01 \x01(\v2\x11.inventory.ReviewR\x06review\"\xf8\x01\n" +
+	"\n" +
+	"DeadLetter\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x12%\n" +
+	"\x0esource_subject\x18\x02 \x01(\tR\rsourceSubject\x12\x1a\n" +
+	"\bconsumer\x18\x03 \x01(\tR\bconsumer\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12\x1a\n" +
+	"\battempts\x18\x05 \x01(\x05R\battempts\x12(\n" +
+	"\x10first_failure_at\x18\x06 \x01(\tR\x0efirstFailureAt\x12\x1b\n" +
+	"\tfailed_at\x18\a \x01(\tR\bfailedAt\x12\x12\n" +
+	"\x04data\x18\b \x01(\fR\x04data\"*\n" +
+	"\fDeadLetterID\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\"L\n" +
+	"\x12DeadLetterResponse\x126\n" +
+	"\vdead_letter\x18\x01 \x01(\v2\x15.inventory.DeadLetterR\n" +
+	"deadLetter\"i\n" +
+	"\x16ListDeadLettersRequest\x12%\n" +
+	"\x0esource_subject\x18\x01 \x01(\tR\rsourceSubject\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"i\n" +
+	"\x17ListDeadLettersResponse\x128\n" +
+	"\fdead_letters\x18\x01 \x03(\v2\x15.inventory.DeadLetterR\vdeadLetters\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"\\\n" +
+	"\x17PurgeDeadLettersRequest\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x12%\n" +
+	"\x0esource_subject\x18\x02 \x01(\tR\rsourceSubject\"2\n" +
+	"\x18PurgeDeadLettersResponse\x12\x16\n" +
+	"\x06purged\x18\x01 \x01(\x05R\x06purged\"\a\n" +
+	"\x05Empty2\xaf\v\n" +
 	"\x10InventoryService\x12F\n" +
 	"\rCreateProduct\x12\x19.inventory.ProductRequest\x1a\x1a.inventory.ProductResponse\x12>\n" +
 	"\n" +
@@ -787,7 +1949,15 @@ const file_inventory_proto_rawDesc = "" +
 	"\x0eUpdateCategory\x12\x1a.inventory.CategoryRequest\x1a\x1b.inventory.CategoryResponse\x129\n" +
 	"\x0eDeleteCategory\x12\x15.inventory.CategoryID\x1a\x10.inventory.Empty\x12C\n" +
 	"\x0eListCategories\x12\x10.inventory.Empty\x1a\x1f.inventory.CategoryListResponse\x12R\n" +
-	"\rDecreaseStock\x12\x1f.inventory.DecreaseStockRequest\x1a .inventory.DecreaseStockResponseB\x11Z\x0fproto/inventoryb\x06proto3"
+	"\rDecreaseStock\x12\x1f.inventory.DecreaseStockRequest\x1a .inventory.DecreaseStockResponse\x12a\n" +
+	"\x12DecreaseStockBatch\x12$.inventory.DecreaseStockBatchRequest\x1a%.inventory.DecreaseStockBatchResponse\x12I\n" +
+	"\fCreateReview\x12\x1e.inventory.CreateReviewRequest\x1a\x19.inventory.ReviewResponse\x12L\n" +
+	"\vListReviews\x12\x1d.inventory.ListReviewsRequest\x1a\x1e.inventory.ListReviewsResponse\x12M\n" +
+	"\x0eModerateReview\x12 .inventory.ModerateReviewRequest\x1a\x19.inventory.ReviewResponse\x12X\n" +
+	"\x0fListDeadLetters\x12!.inventory.ListDeadLettersRequest\x1a\".inventory.ListDeadLettersResponse\x12G\n" +
+	"\rGetDeadLetter\x12\x17.inventory.DeadLetterID\x1a\x1d.inventory.DeadLetterResponse\x12K\n" +
+	"\x11RedriveDeadLetter\x12\x17.inventory.DeadLetterID\x1a\x1d.inventory.DeadLetterResponse\x12[\n" +
+	"\x10PurgeDeadLetters\x12\".inventory.PurgeDeadLettersRequest\x1a#.inventory.PurgeDeadLettersResponseB\x11Z\x0fproto/inventoryb\x06proto3"
 
 var (
 	file_inventory_proto_rawDescOnce sync.Once
@@ -801,57 +1971,98 @@ func file_inventory_proto_rawDescGZIP() []byte {
 	return file_inventory_proto_rawDescData
 }
 
-var file_inventory_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_inventory_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
 var file_inventory_proto_goTypes = []any{
-	(*Product)(nil),               // 0: inventory.Product
-	(*Category)(nil),              // 1: inventory.Category
-	(*ProductID)(nil),             // 2: inventory.ProductID
-	(*CategoryID)(nil),            // 3: inventory.CategoryID
-	(*ProductRequest)(nil),        // 4: inventory.ProductRequest
-	(*ProductResponse)(nil),       // 5: inventory.ProductResponse
-	(*CategoryRequest)(nil),       // 6: inventory.CategoryRequest
-	(*CategoryResponse)(nil),      // 7: inventory.CategoryResponse
-	(*ProductListRequest)(nil),    // 8: inventory.ProductListRequest
-	(*ProductListResponse)(nil),   // 9: inventory.ProductListResponse
-	(*CategoryListResponse)(nil),  // 10: inventory.CategoryListResponse
-	(*DecreaseStockRequest)(nil),  // 11: inventory.DecreaseStockRequest
-	(*DecreaseStockResponse)(nil), // 12: inventory.DecreaseStockResponse
-	(*Empty)(nil),                 // 13: inventory.Empty
+	(*Product)(nil),                    // 0: inventory.Product
+	(*Category)(nil),                   // 1: inventory.Category
+	(*ProductID)(nil),                  // 2: inventory.ProductID
+	(*CategoryID)(nil),                 // 3: inventory.CategoryID
+	(*ProductRequest)(nil),             // 4: inventory.ProductRequest
+	(*ProductResponse)(nil),            // 5: inventory.ProductResponse
+	(*CategoryRequest)(nil),            // 6: inventory.CategoryRequest
+	(*CategoryResponse)(nil),           // 7: inventory.CategoryResponse
+	(*ProductListRequest)(nil),         // 8: inventory.ProductListRequest
+	(*ProductListResponse)(nil),        // 9: inventory.ProductListResponse
+	(*CategoryListResponse)(nil),       // 10: inventory.CategoryListResponse
+	(*DecreaseStockRequest)(nil),       // 11: inventory.DecreaseStockRequest
+	(*DecreaseStockResponse)(nil),      // 12: inventory.DecreaseStockResponse
+	(*StockItem)(nil),                  // 13: inventory.StockItem
+	(*DecreaseStockBatchRequest)(nil),  // 14: inventory.DecreaseStockBatchRequest
+	(*StockLevel)(nil),                 // 15: inventory.StockLevel
+	(*DecreaseStockBatchResponse)(nil), // 16: inventory.DecreaseStockBatchResponse
+	(*Review)(nil),                     // 17: inventory.Review
+	(*CreateReviewRequest)(nil),        // 18: inventory.CreateReviewRequest
+	(*ListReviewsRequest)(nil),         // 19: inventory.ListReviewsRequest
+	(*ListReviewsResponse)(nil),        // 20: inventory.ListReviewsResponse
+	(*ModerateReviewRequest)(nil),      // 21: inventory.ModerateReviewRequest
+	(*ReviewResponse)(nil),             // 22: inventory.ReviewResponse
+	(*DeadLetter)(nil),                 // 23: inventory.DeadLetter
+	(*DeadLetterID)(nil),               // 24: inventory.DeadLetterID
+	(*DeadLetterResponse)(nil),         // 25: inventory.DeadLetterResponse
+	(*ListDeadLettersRequest)(nil),     // 26: inventory.ListDeadLettersRequest
+	(*ListDeadLettersResponse)(nil),    // 27: inventory.ListDeadLettersResponse
+	(*PurgeDeadLettersRequest)(nil),    // 28: inventory.PurgeDeadLettersRequest
+	(*PurgeDeadLettersResponse)(nil),   // 29: inventory.PurgeDeadLettersResponse
+	(*Empty)(nil),                      // 30: inventory.Empty
+	(*common.Money)(nil),               // 31: common.Money
 }
 var file_inventory_proto_depIdxs = []int32{
-	0,  // 0: inventory.ProductRequest.product:type_name -> inventory.Product
-	0,  // 1: inventory.ProductResponse.product:type_name -> inventory.Product
-	1,  // 2: inventory.CategoryRequest.category:type_name -> inventory.Category
-	1,  // 3: inventory.CategoryResponse.category:type_name -> inventory.Category
-	0,  // 4: inventory.ProductListResponse.products:type_name -> inventory.Product
-	1,  // 5: inventory.CategoryListResponse.categories:type_name -> inventory.Category
-	4,  // 6: inventory.InventoryService.CreateProduct:input_type -> inventory.ProductRequest
-	2,  // 7: inventory.InventoryService.GetProduct:input_type -> inventory.ProductID
-	4,  // 8: inventory.InventoryService.UpdateProduct:input_type -> inventory.ProductRequest
-	2,  // 9: inventory.InventoryService.DeleteProduct:input_type -> inventory.ProductID
-	8,  // 10: inventory.InventoryService.ListProducts:input_type -> inventory.ProductListRequest
-	6,  // 11: inventory.InventoryService.CreateCategory:input_type -> inventory.CategoryRequest
-	3,  // 12: inventory.InventoryService.GetCategory:input_type -> inventory.CategoryID
-	6,  // 13: inventory.InventoryService.UpdateCategory:input_type -> inventory.CategoryRequest
-	3,  // 14: inventory.InventoryService.DeleteCategory:input_type -> inventory.CategoryID
-	13, // 15: inventory.InventoryService.ListCategories:input_type -> inventory.Empty
-	11, // 16: inventory.InventoryService.DecreaseStock:input_type -> inventory.DecreaseStockRequest
-	5,  // 17: inventory.InventoryService.CreateProduct:output_type -> inventory.ProductResponse
-	5,  // 18: inventory.InventoryService.GetProduct:output_type -> inventory.ProductResponse
-	5,  // 19: inventory.InventoryService.UpdateProduct:output_type -> inventory.ProductResponse
-	13, // 20: inventory.InventoryService.DeleteProduct:output_type -> inventory.Empty
-	9,  // 21: inventory.InventoryService.ListProducts:output_type -> inventory.ProductListResponse
-	7,  // 22: inventory.InventoryService.CreateCategory:output_type -> inventory.CategoryResponse
-	7,  // 23: inventory.InventoryService.GetCategory:output_type -> inventory.CategoryResponse
-	7,  // 24: inventory.InventoryService.UpdateCategory:output_type -> inventory.CategoryResponse
-	13, // 25: inventory.InventoryService.DeleteCategory:output_type -> inventory.Empty
-	10, // 26: inventory.InventoryService.ListCategories:output_type -> inventory.CategoryListResponse
-	12, // 27: inventory.InventoryService.DecreaseStock:output_type -> inventory.DecreaseStockResponse
-	17, // [17:28] is the sub-list for method output_type
-	6,  // [6:17] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	31, // 0: inventory.Product.price:type_name -> common.Money
+	0,  // 1: inventory.ProductRequest.product:type_name -> inventory.Product
+	0,  // 2: inventory.ProductResponse.product:type_name -> inventory.Product
+	1,  // 3: inventory.CategoryRequest.category:type_name -> inventory.Category
+	1,  // 4: inventory.CategoryResponse.category:type_name -> inventory.Category
+	0,  // 5: inventory.ProductListResponse.products:type_name -> inventory.Product
+	1,  // 6: inventory.CategoryListResponse.categories:type_name -> inventory.Category
+	13, // 7: inventory.DecreaseStockBatchRequest.items:type_name -> inventory.StockItem
+	15, // 8: inventory.DecreaseStockBatchResponse.stock:type_name -> inventory.StockLevel
+	17, // 9: inventory.ListReviewsResponse.reviews:type_name -> inventory.Review
+	17, // 10: inventory.ReviewResponse.review:type_name -> inventory.Review
+	23, // 11: inventory.DeadLetterResponse.dead_letter:type_name -> inventory.DeadLetter
+	23, // 12: inventory.ListDeadLettersResponse.dead_letters:type_name -> inventory.DeadLetter
+	4,  // 13: inventory.InventoryService.CreateProduct:input_type -> inventory.ProductRequest
+	2,  // 14: inventory.InventoryService.GetProduct:input_type -> inventory.ProductID
+	4,  // 15: inventory.InventoryService.UpdateProduct:input_type -> inventory.ProductRequest
+	2,  // 16: inventory.InventoryService.DeleteProduct:input_type -> inventory.ProductID
+	8,  // 17: inventory.InventoryService.ListProducts:input_type -> inventory.ProductListRequest
+	6,  // 18: inventory.InventoryService.CreateCategory:input_type -> inventory.CategoryRequest
+	3,  // 19: inventory.InventoryService.GetCategory:input_type -> inventory.CategoryID
+	6,  // 20: inventory.InventoryService.UpdateCategory:input_type -> inventory.CategoryRequest
+	3,  // 21: inventory.InventoryService.DeleteCategory:input_type -> inventory.CategoryID
+	30, // 22: inventory.InventoryService.ListCategories:input_type -> inventory.Empty
+	11, // 23: inventory.InventoryService.DecreaseStock:input_type -> inventory.DecreaseStockRequest
+	14, // 24: inventory.InventoryService.DecreaseStockBatch:input_type -> inventory.DecreaseStockBatchRequest
+	18, // 25: inventory.InventoryService.CreateReview:input_type -> inventory.CreateReviewRequest
+	19, // 26: inventory.InventoryService.ListReviews:input_type -> inventory.ListReviewsRequest
+	21, // 27: inventory.InventoryService.ModerateReview:input_type -> inventory.ModerateReviewRequest
+	26, // 28: inventory.InventoryService.ListDeadLetters:input_type -> inventory.ListDeadLettersRequest
+	24, // 29: inventory.InventoryService.GetDeadLetter:input_type -> inventory.DeadLetterID
+	24, // 30: inventory.InventoryService.RedriveDeadLetter:input_type -> inventory.DeadLetterID
+	28, // 31: inventory.InventoryService.PurgeDeadLetters:input_type -> inventory.PurgeDeadLettersRequest
+	5,  // 32: inventory.InventoryService.CreateProduct:output_type -> inventory.ProductResponse
+	5,  // 33: inventory.InventoryService.GetProduct:output_type -> inventory.ProductResponse
+	5,  // 34: inventory.InventoryService.UpdateProduct:output_type -> inventory.ProductResponse
+	30, // 35: inventory.InventoryService.DeleteProduct:output_type -> inventory.Empty
+	9,  // 36: inventory.InventoryService.ListProducts:output_type -> inventory.ProductListResponse
+	7,  // 37: inventory.InventoryService.CreateCategory:output_type -> inventory.CategoryResponse
+	7,  // 38: inventory.InventoryService.GetCategory:output_type -> inventory.CategoryResponse
+	7,  // 39: inventory.InventoryService.UpdateCategory:output_type -> inventory.CategoryResponse
+	30, // 40: inventory.InventoryService.DeleteCategory:output_type -> inventory.Empty
+	10, // 41: inventory.InventoryService.ListCategories:output_type -> inventory.CategoryListResponse
+	12, // 42: inventory.InventoryService.DecreaseStock:output_type -> inventory.DecreaseStockResponse
+	16, // 43: inventory.InventoryService.DecreaseStockBatch:output_type -> inventory.DecreaseStockBatchResponse
+	22, // 44: inventory.InventoryService.CreateReview:output_type -> inventory.ReviewResponse
+	20, // 45: inventory.InventoryService.ListReviews:output_type -> inventory.ListReviewsResponse
+	22, // 46: inventory.InventoryService.ModerateReview:output_type -> inventory.ReviewResponse
+	27, // 47: inventory.InventoryService.ListDeadLetters:output_type -> inventory.ListDeadLettersResponse
+	25, // 48: inventory.InventoryService.GetDeadLetter:output_type -> inventory.DeadLetterResponse
+	25, // 49: inventory.InventoryService.RedriveDeadLetter:output_type -> inventory.DeadLetterResponse
+	29, // 50: inventory.InventoryService.PurgeDeadLetters:output_type -> inventory.PurgeDeadLettersResponse
+	32, // [32:51] is the sub-list for method output_type
+	13, // [13:32] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_inventory_proto_init() }
@@ -865,7 +2076,7 @@ func file_inventory_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_inventory_proto_rawDesc), len(file_inventory_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   14,
+			NumMessages:   31,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,17 +19,25 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	InventoryService_CreateProduct_FullMethodName  = "/inventory.InventoryService/CreateProduct"
-	InventoryService_GetProduct_FullMethodName     = "/inventory.InventoryService/GetProduct"
-	InventoryService_UpdateProduct_FullMethodName  = "/inventory.InventoryService/UpdateProduct"
-	InventoryService_DeleteProduct_FullMethodName  = "/inventory.InventoryService/DeleteProduct"
-	InventoryService_ListProducts_FullMethodName   = "/inventory.InventoryService/ListProducts"
-	InventoryService_CreateCategory_FullMethodName = "/inventory.InventoryService/CreateCategory"
-	InventoryService_GetCategory_FullMethodName    = "/inventory.InventoryService/GetCategory"
-	InventoryService_UpdateCategory_FullMethodName = "/inventory.InventoryService/UpdateCategory"
-	InventoryService_DeleteCategory_FullMethodName = "/inventory.InventoryService/DeleteCategory"
-	InventoryService_ListCategories_FullMethodName = "/inventory.InventoryService/ListCategories"
-	InventoryService_DecreaseStock_FullMethodName  = "/inventory.InventoryService/DecreaseStock"
+	InventoryService_CreateProduct_FullMethodName      = "/inventory.InventoryService/CreateProduct"
+	InventoryService_GetProduct_FullMethodName         = "/inventory.InventoryService/GetProduct"
+	InventoryService_UpdateProduct_FullMethodName      = "/inventory.InventoryService/UpdateProduct"
+	InventoryService_DeleteProduct_FullMethodName      = "/inventory.InventoryService/DeleteProduct"
+	InventoryService_ListProducts_FullMethodName       = "/inventory.InventoryService/ListProducts"
+	InventoryService_CreateCategory_FullMethodName     = "/inventory.InventoryService/CreateCategory"
+	InventoryService_GetCategory_FullMethodName        = "/inventory.InventoryService/GetCategory"
+	InventoryService_UpdateCategory_FullMethodName     = "/inventory.InventoryService/UpdateCategory"
+	InventoryService_DeleteCategory_FullMethodName     = "/inventory.InventoryService/DeleteCategory"
+	InventoryService_ListCategories_FullMethodName     = "/inventory.InventoryService/ListCategories"
+	InventoryService_DecreaseStock_FullMethodName      = "/inventory.InventoryService/DecreaseStock"
+	InventoryService_DecreaseStockBatch_FullMethodName = "/inventory.InventoryService/DecreaseStockBatch"
+	InventoryService_CreateReview_FullMethodName       = "/inventory.InventoryService/CreateReview"
+	InventoryService_ListReviews_FullMethodName        = "/inventory.InventoryService/ListReviews"
+	InventoryService_ModerateReview_FullMethodName     = "/inventory.InventoryService/ModerateReview"
+	InventoryService_ListDeadLetters_FullMethodName    = "/inventory.InventoryService/ListDeadLetters"
+	InventoryService_GetDeadLetter_FullMethodName      = "/inventory.InventoryService/GetDeadLetter"
+	InventoryService_RedriveDeadLetter_FullMethodName  = "/inventory.InventoryService/RedriveDeadLetter"
+	InventoryService_PurgeDeadLetters_FullMethodName   = "/inventory.InventoryService/PurgeDeadLetters"
 )
 
 // InventoryServiceClient is the client API for InventoryService service.
@@ -48,6 +56,16 @@ type InventoryServiceClient interface {
 	ListCategories(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*CategoryListResponse, error)
 	// Decrease the stock of a product
 	DecreaseStock(ctx context.Context, in *DecreaseStockRequest, opts ...grpc.CallOption) (*DecreaseStockResponse, error)
+	// Decrease the stock of several products, all or nothing
+	DecreaseStockBatch(ctx context.Context, in *DecreaseStockBatchRequest, opts ...grpc.CallOption) (*DecreaseStockBatchResponse, error)
+	CreateReview(ctx context.Context, in *CreateReviewRequest, opts ...grpc.CallOption) (*ReviewResponse, error)
+	ListReviews(ctx context.Context, in *ListReviewsRequest, opts ...grpc.CallOption) (*ListReviewsResponse, error)
+	ModerateReview(ctx context.Context, in *ModerateReviewRequest, opts ...grpc.CallOption) (*ReviewResponse, error)
+	ListDeadLetters(ctx context.Context, in *ListDeadLettersRequest, opts ...grpc.CallOption) (*ListDeadLettersResponse, error)
+	GetDeadLetter(ctx context.Context, in *DeadLetterID, opts ...grpc.CallOption) (*DeadLetterResponse, error)
+	// Publish a dead letter again for the consumer it failed in
+	RedriveDeadLetter(ctx context.Context, in *DeadLetterID, opts ...grpc.CallOption) (*DeadLetterResponse, error)
+	PurgeDeadLetters(ctx context.Context, in *PurgeDeadLettersRequest, opts ...grpc.CallOption) (*PurgeDeadLettersResponse, error)
 }
 
 type inventoryServiceClient struct {
@@ -168,6 +186,86 @@ func (c *inventoryServiceClient) DecreaseStock(ctx context.Context, in *Decrease
 	return out, nil
 }
 
+func (c *inventoryServiceClient) DecreaseStockBatch(ctx context.Context, in *DecreaseStockBatchRequest, opts ...grpc.CallOption) (*DecreaseStockBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecreaseStockBatchResponse)
+	err := c.cc.Invoke(ctx, InventoryService_DecreaseStockBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) CreateReview(ctx context.Context, in *CreateReviewRequest, opts ...grpc.CallOption) (*ReviewResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReviewResponse)
+	err := c.cc.Invoke(ctx, InventoryService_CreateReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) ListReviews(ctx context.Context, in *ListReviewsRequest, opts ...grpc.CallOption) (*ListReviewsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListReviewsResponse)
+	err := c.cc.Invoke(ctx, InventoryService_ListReviews_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) ModerateReview(ctx context.Context, in *ModerateReviewRequest, opts ...grpc.CallOption) (*ReviewResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReviewResponse)
+	err := c.cc.Invoke(ctx, InventoryService_ModerateReview_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) ListDeadLetters(ctx context.Context, in *ListDeadLettersRequest, opts ...grpc.CallOption) (*ListDeadLettersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDeadLettersResponse)
+	err := c.cc.Invoke(ctx, InventoryService_ListDeadLetters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) GetDeadLetter(ctx context.Context, in *DeadLetterID, opts ...grpc.CallOption) (*DeadLetterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeadLetterResponse)
+	err := c.cc.Invoke(ctx, InventoryService_GetDeadLetter_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) RedriveDeadLetter(ctx context.Context, in *DeadLetterID, opts ...grpc.CallOption) (*DeadLetterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeadLetterResponse)
+	err := c.cc.Invoke(ctx, InventoryService_RedriveDeadLetter_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) PurgeDeadLetters(ctx context.Context, in *PurgeDeadLettersRequest, opts ...grpc.CallOption) (*PurgeDeadLettersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PurgeDeadLettersResponse)
+	err := c.cc.Invoke(ctx, InventoryService_PurgeDeadLetters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InventoryServiceServer is the server API for InventoryService service.
 // All implementations must embed UnimplementedInventoryServiceServer
 // for forward compatibility.
@@ -184,6 +282,16 @@ type InventoryServiceServer interface {
 	ListCategories(context.Context, *Empty) (*CategoryListResponse, error)
 	// Decrease the stock of a product
 	DecreaseStock(context.Context, *DecreaseStockRequest) (*DecreaseStockResponse, error)
+	// Decrease the stock of several products, all or nothing
+	DecreaseStockBatch(context.Context, *DecreaseStockBatchRequest) (*DecreaseStockBatchResponse, error)
+	CreateReview(context.Context, *CreateReviewRequest) (*ReviewResponse, error)
+	ListReviews(context.Context, *ListReviewsRequest) (*ListReviewsResponse, error)
+	ModerateReview(context.Context, *ModerateReviewRequest) (*ReviewResponse, error)
+	ListDeadLetters(context.Context, *ListDeadLettersRequest) (*ListDeadLettersResponse, error)
+	GetDeadLetter(context.Context, *DeadLetterID) (*DeadLetterResponse, error)
+	// Publish a dead letter again for the consumer it failed in
+	RedriveDeadLetter(context.Context, *DeadLetterID) (*DeadLetterResponse, error)
+	PurgeDeadLetters(context.Context, *PurgeDeadLettersRequest) (*PurgeDeadLettersResponse, error)
 	mustEmbedUnimplementedInventoryServiceServer()
 }
 
@@ -227,6 +335,30 @@ func (UnimplementedInventoryServiceServer) ListCategories(context.Context, *Empt
 func (UnimplementedInventoryServiceServer) DecreaseStock(context.Context, *DecreaseStockRequest) (*DecreaseStockResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DecreaseStock not implemented")
 }
+func (UnimplementedInventoryServiceServer) DecreaseStockBatch(context.Context, *DecreaseStockBatchRequest) (*DecreaseStockBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecreaseStockBatch not implemented")
+}
+func (UnimplementedInventoryServiceServer) CreateReview(context.Context, *CreateReviewRequest) (*ReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateReview not implemented")
+}
+func (UnimplementedInventoryServiceServer) ListReviews(context.Context, *ListReviewsRequest) (*ListReviewsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReviews not implemented")
+}
+func (UnimplementedInventoryServiceServer) ModerateReview(context.Context, *ModerateReviewRequest) (*ReviewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModerateReview not implemented")
+}
+func (UnimplementedInventoryServiceServer) ListDeadLetters(context.Context, *ListDeadLettersRequest) (*ListDeadLettersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeadLetters not implemented")
+}
+func (UnimplementedInventoryServiceServer) GetDeadLetter(context.Context, *DeadLetterID) (*DeadLetterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeadLetter not implemented")
+}
+func (UnimplementedInventoryServiceServer) RedriveDeadLetter(context.Context, *DeadLetterID) (*DeadLetterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RedriveDeadLetter not implemented")
+}
+func (UnimplementedInventoryServiceServer) PurgeDeadLetters(context.Context, *PurgeDeadLettersRequest) (*PurgeDeadLettersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeDeadLetters not implemented")
+}
 func (UnimplementedInventoryServiceServer) mustEmbedUnimplementedInventoryServiceServer() {}
 func (UnimplementedInventoryServiceServer) testEmbeddedByValue()                          {}
 
@@ -446,6 +578,150 @@ func _InventoryService_DecreaseStock_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _InventoryService_DecreaseStockBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecreaseStockBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).DecreaseStockBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_DecreaseStockBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).DecreaseStockBatch(ctx, req.(*DecreaseStockBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_CreateReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).CreateReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_CreateReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).CreateReview(ctx, req.(*CreateReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_ListReviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReviewsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).ListReviews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_ListReviews_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).ListReviews(ctx, req.(*ListReviewsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_ModerateReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModerateReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).ModerateReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_ModerateReview_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).ModerateReview(ctx, req.(*ModerateReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_ListDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeadLettersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).ListDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_ListDeadLetters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).ListDeadLetters(ctx, req.(*ListDeadLettersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_GetDeadLetter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeadLetterID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).GetDeadLetter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_GetDeadLetter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).GetDeadLetter(ctx, req.(*DeadLetterID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_RedriveDeadLetter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeadLetterID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).RedriveDeadLetter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_RedriveDeadLetter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).RedriveDeadLetter(ctx, req.(*DeadLetterID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_PurgeDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeDeadLettersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).PurgeDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_PurgeDeadLetters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).PurgeDeadLetters(ctx, req.(*PurgeDeadLettersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // InventoryService_ServiceDesc is the grpc.ServiceDesc for InventoryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -497,6 +773,38 @@ var InventoryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DecreaseStock",
 			Handler:    _InventoryService_DecreaseStock_Handler,
 		},
+		{
+			MethodName: "DecreaseStockBatch",
+			Handler:    _InventoryService_DecreaseStockBatch_Handler,
+		},
+		{
+			MethodName: "CreateReview",
+			Handler:    _InventoryService_CreateReview_Handler,
+		},
+		{
+			MethodName: "ListReviews",
+			Handler:    _InventoryService_ListReviews_Handler,
+		},
+		{
+			MethodName: "ModerateReview",
+			Handler:    _InventoryService_ModerateReview_Handler,
+		},
+		{
+			MethodName: "ListDeadLetters",
+			Handler:    _InventoryService_ListDeadLetters_Handler,
+		},
+		{
+			MethodName: "GetDeadLetter",
+			Handler:    _InventoryService_GetDeadLetter_Handler,
+		},
+		{
+			MethodName: "RedriveDeadLetter",
+			Handler:    _InventoryService_RedriveDeadLetter_Handler,
+		},
+		{
+			MethodName: "PurgeDeadLetters",
+			Handler:    _InventoryService_PurgeDeadLetters_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "inventory.proto",
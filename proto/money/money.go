@@ -0,0 +1,167 @@
+// Package money provides an exact monetary amount shared by the services.
+// Amounts are kept in minor units (tiyn for KZT) so totals never drift the
+// way float prices did. Legacy float prices stored in MongoDB are decoded by
+// infra/moneybson.
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"proto/common"
+)
+
+const DefaultCurrency = "KZT"
+
+// ErrCurrencyMismatch is returned when amounts in two different currencies
+// are combined.
+var ErrCurrencyMismatch = errors.New("money: currency mismatch")
+
+// minorUnits is the number of minor units in a major unit. KZT and every
+// currency we accept use two decimals.
+const minorUnits = 100
+
+type Money struct {
+	Amount   int64  `bson:"amount" json:"amount"`
+	Currency string `bson:"currency" json:"currency"`
+}
+
+func New(amount int64, currency string) Money {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	return Money{Amount: amount, Currency: currency}
+}
+
+func KZT(amount int64) Money {
+	return New(amount, DefaultCurrency)
+}
+
+// FromFloat converts a legacy amount in major units (tenge) to Money.
+func FromFloat(major float64, currency string) Money {
+	return New(int64(math.Round(major*minorUnits)), currency)
+}
+
+func FromProto(m *common.Money) Money {
+	if m == nil {
+		return KZT(0)
+	}
+	return New(m.Amount, m.Currency)
+}
+
+func (m Money) ToProto() *common.Money {
+	return &common.Money{Amount: m.Amount, Currency: m.currency()}
+}
+
+// Add returns m+o. A zero-value Money takes the currency of the other operand;
+// amounts in two different currencies cannot be added and return
+// ErrCurrencyMismatch.
+func (m Money) Add(o Money) (Money, error) {
+	currency, err := m.pickCurrency(o)
+	if err != nil {
+		return Money{}, err
+	}
+	return New(m.Amount+o.Amount, currency), nil
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	currency, err := m.pickCurrency(o)
+	if err != nil {
+		return Money{}, err
+	}
+	return New(m.Amount-o.Amount, currency), nil
+}
+
+func (m Money) Mul(n int) Money {
+	return New(m.Amount*int64(n), m.Currency)
+}
+
+// MulRatio returns m*num/den rounded half away from zero to the nearest
+// minor unit.
+func (m Money) MulRatio(num, den int64) Money {
+	n := m.Amount * num
+	q, r := n/den, n%den
+	if r < 0 {
+		r = -r
+	}
+	if 2*r >= den {
+		if n < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return New(q, m.Currency)
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+func (m Money) SameCurrency(o Money) bool {
+	return m.currency() == o.currency()
+}
+
+// Float returns the amount in major units. It is meant for display only.
+func (m Money) Float() float64 {
+	return float64(m.Amount) / minorUnits
+}
+
+func (m Money) String() string {
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%02d %s", sign, amount/minorUnits, amount%minorUnits, m.currency())
+}
+
+func (m Money) currency() string {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+	return m.Currency
+}
+
+func (m Money) pickCurrency(o Money) (string, error) {
+	if m.Currency == "" {
+		return o.Currency, nil
+	}
+	if o.Currency != "" && o.Currency != m.Currency {
+		return "", fmt.Errorf("%w: cannot combine %s and %s amounts", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	return m.Currency, nil
+}
+
+type moneyFields Money
+
+// UnmarshalJSON accepts the {amount, currency} object and, for events and
+// cache entries written by older releases, a bare number in major units.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '{' {
+		var fields moneyFields
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return err
+		}
+		*m = New(fields.Amount, fields.Currency)
+		return nil
+	}
+
+	var major *float64
+	if err := json.Unmarshal(data, &major); err != nil {
+		return err
+	}
+	if major == nil {
+		*m = Money{}
+		return nil
+	}
+	*m = FromFloat(*major, DefaultCurrency)
+	return nil
+}
@@ -9,6 +9,7 @@ package order
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	common "proto/common"
 	_ "proto/inventory"
 	reflect "reflect"
 	sync "sync"
@@ -23,12 +24,25 @@ const (
 )
 
 type OrderItem struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	Price         float32                `protobuf:"fixed32,3,opt,name=price,proto3" json:"price,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity  int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	TaxClass  string                 `protobuf:"bytes,4,opt,name=tax_class,json=taxClass,proto3" json:"tax_class,omitempty"`
+	// VAT rate in basis points (1200 = 12%).
+	TaxRate int32 `protobuf:"varint,5,opt,name=tax_rate,json=taxRate,proto3" json:"tax_rate,omitempty"`
+	// Unit price including VAT, taken from the catalog. A price sent to
+	// CreateOrder may be left out and is refused if it differs.
+	Price       *common.Money `protobuf:"bytes,9,opt,name=price,proto3" json:"price,omitempty"`
+	NetAmount   *common.Money `protobuf:"bytes,10,opt,name=net_amount,json=netAmount,proto3" json:"net_amount,omitempty"`
+	TaxAmount   *common.Money `protobuf:"bytes,11,opt,name=tax_amount,json=taxAmount,proto3" json:"tax_amount,omitempty"`
+	GrossAmount *common.Money `protobuf:"bytes,12,opt,name=gross_amount,json=grossAmount,proto3" json:"gross_amount,omitempty"`
+	// What to do if the product is missing at picking: "refund",
+	// "best_match" (default) or "specific".
+	Substitution string `protobuf:"bytes,13,opt,name=substitution,proto3" json:"substitution,omitempty"`
+	// The only accepted substitute when substitution is "specific".
+	SubstituteProductId string `protobuf:"bytes,14,opt,name=substitute_product_id,json=substituteProductId,proto3" json:"substitute_product_id,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
 }
 
 func (x *OrderItem) Reset() {
@@ -75,40 +89,87 @@ func (x *OrderItem) GetQuantity() int32 {
 	return 0
 }
 
-func (x *OrderItem) GetPrice() float32 {
+func (x *OrderItem) GetTaxClass() string {
 	if x != nil {
-		return x.Price
+		return x.TaxClass
+	}
+	return ""
+}
+
+func (x *OrderItem) GetTaxRate() int32 {
+	if x != nil {
+		return x.TaxRate
 	}
 	return 0
 }
 
-type Order struct {
+func (x *OrderItem) GetPrice() *common.Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *OrderItem) GetNetAmount() *common.Money {
+	if x != nil {
+		return x.NetAmount
+	}
+	return nil
+}
+
+func (x *OrderItem) GetTaxAmount() *common.Money {
+	if x != nil {
+		return x.TaxAmount
+	}
+	return nil
+}
+
+func (x *OrderItem) GetGrossAmount() *common.Money {
+	if x != nil {
+		return x.GrossAmount
+	}
+	return nil
+}
+
+func (x *OrderItem) GetSubstitution() string {
+	if x != nil {
+		return x.Substitution
+	}
+	return ""
+}
+
+func (x *OrderItem) GetSubstituteProductId() string {
+	if x != nil {
+		return x.SubstituteProductId
+	}
+	return ""
+}
+
+// VAT summary for all order lines sharing the same rate.
+type TaxLine struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Items         []*OrderItem           `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
-	Total         float32                `protobuf:"fixed32,4,opt,name=total,proto3" json:"total,omitempty"`
-	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
-	CreatedAt     string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     string                 `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	TaxRate       int32                  `protobuf:"varint,1,opt,name=tax_rate,json=taxRate,proto3" json:"tax_rate,omitempty"`
+	NetAmount     *common.Money          `protobuf:"bytes,5,opt,name=net_amount,json=netAmount,proto3" json:"net_amount,omitempty"`
+	TaxAmount     *common.Money          `protobuf:"bytes,6,opt,name=tax_amount,json=taxAmount,proto3" json:"tax_amount,omitempty"`
+	GrossAmount   *common.Money          `protobuf:"bytes,7,opt,name=gross_amount,json=grossAmount,proto3" json:"gross_amount,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Order) Reset() {
-	*x = Order{}
+func (x *TaxLine) Reset() {
+	*x = TaxLine{}
 	mi := &file_order_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Order) String() string {
+func (x *TaxLine) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Order) ProtoMessage() {}
+func (*TaxLine) ProtoMessage() {}
 
-func (x *Order) ProtoReflect() protoreflect.Message {
+func (x *TaxLine) ProtoReflect() protoreflect.Message {
 	mi := &file_order_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -120,82 +181,1060 @@ func (x *Order) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Order.ProtoReflect.Descriptor instead.
-func (*Order) Descriptor() ([]byte, []int) {
+// Deprecated: Use TaxLine.ProtoReflect.Descriptor instead.
+func (*TaxLine) Descriptor() ([]byte, []int) {
 	return file_order_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *Order) GetId() string {
+func (x *TaxLine) GetTaxRate() int32 {
+	if x != nil {
+		return x.TaxRate
+	}
+	return 0
+}
+
+func (x *TaxLine) GetNetAmount() *common.Money {
+	if x != nil {
+		return x.NetAmount
+	}
+	return nil
+}
+
+func (x *TaxLine) GetTaxAmount() *common.Money {
+	if x != nil {
+		return x.TaxAmount
+	}
+	return nil
+}
+
+func (x *TaxLine) GetGrossAmount() *common.Money {
+	if x != nil {
+		return x.GrossAmount
+	}
+	return nil
+}
+
+type RefundLine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Amount        *common.Money          `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefundLine) Reset() {
+	*x = RefundLine{}
+	mi := &file_order_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundLine) ProtoMessage() {}
+
+func (x *RefundLine) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundLine.ProtoReflect.Descriptor instead.
+func (*RefundLine) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RefundLine) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *RefundLine) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *RefundLine) GetAmount() *common.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+type Refund struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PaymentId string                 `protobuf:"bytes,2,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	Amount    *common.Money          `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Reason    string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Empty when an amount was refunded without returning specific lines.
+	Lines            []*RefundLine `protobuf:"bytes,5,rep,name=lines,proto3" json:"lines,omitempty"`
+	ProviderRefundId string        `protobuf:"bytes,6,opt,name=provider_refund_id,json=providerRefundId,proto3" json:"provider_refund_id,omitempty"`
+	CreatedAt        string        `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Refund) Reset() {
+	*x = Refund{}
+	mi := &file_order_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Refund) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Refund) ProtoMessage() {}
+
+func (x *Refund) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Refund.ProtoReflect.Descriptor instead.
+func (*Refund) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Refund) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-func (x *Order) GetUserId() string {
+func (x *Refund) GetPaymentId() string {
 	if x != nil {
-		return x.UserId
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *Refund) GetAmount() *common.Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *Refund) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Refund) GetLines() []*RefundLine {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *Refund) GetProviderRefundId() string {
+	if x != nil {
+		return x.ProviderRefundId
+	}
+	return ""
+}
+
+func (x *Refund) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+// Fiscal receipt registered with the OFD for a sale or a refund.
+type Receipt struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// "sale" or "sale_return".
+	Type          string        `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	RefundId      string        `protobuf:"bytes,3,opt,name=refund_id,json=refundId,proto3" json:"refund_id,omitempty"`
+	FiscalNumber  string        `protobuf:"bytes,4,opt,name=fiscal_number,json=fiscalNumber,proto3" json:"fiscal_number,omitempty"`
+	QrUrl         string        `protobuf:"bytes,5,opt,name=qr_url,json=qrUrl,proto3" json:"qr_url,omitempty"`
+	Total         *common.Money `protobuf:"bytes,6,opt,name=total,proto3" json:"total,omitempty"`
+	IssuedAt      string        `protobuf:"bytes,7,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Receipt) Reset() {
+	*x = Receipt{}
+	mi := &file_order_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Receipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Receipt) ProtoMessage() {}
+
+func (x *Receipt) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Receipt.ProtoReflect.Descriptor instead.
+func (*Receipt) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Receipt) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Receipt) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Receipt) GetRefundId() string {
+	if x != nil {
+		return x.RefundId
+	}
+	return ""
+}
+
+func (x *Receipt) GetFiscalNumber() string {
+	if x != nil {
+		return x.FiscalNumber
+	}
+	return ""
+}
+
+func (x *Receipt) GetQrUrl() string {
+	if x != nil {
+		return x.QrUrl
+	}
+	return ""
+}
+
+func (x *Receipt) GetTotal() *common.Money {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *Receipt) GetIssuedAt() string {
+	if x != nil {
+		return x.IssuedAt
 	}
 	return ""
 }
 
-func (x *Order) GetItems() []*OrderItem {
-	if x != nil {
-		return x.Items
-	}
-	return nil
+// A missing product and what replaced it, if anything.
+type Substitution struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Id                  string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId           string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity            int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	SubstituteProductId string                 `protobuf:"bytes,4,opt,name=substitute_product_id,json=substituteProductId,proto3" json:"substitute_product_id,omitempty"`
+	SubstituteQuantity  int32                  `protobuf:"varint,5,opt,name=substitute_quantity,json=substituteQuantity,proto3" json:"substitute_quantity,omitempty"`
+	// Unit price of the substitute including VAT.
+	Price *common.Money `protobuf:"bytes,6,opt,name=price,proto3" json:"price,omitempty"`
+	// "proposed", "approved", "rejected", "expired" or "refunded".
+	Status     string `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	ProposedAt string `protobuf:"bytes,8,opt,name=proposed_at,json=proposedAt,proto3" json:"proposed_at,omitempty"`
+	// Proposals left unanswered until then are treated as rejected.
+	ExpiresAt     string `protobuf:"bytes,9,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	DecidedAt     string `protobuf:"bytes,10,opt,name=decided_at,json=decidedAt,proto3" json:"decided_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Substitution) Reset() {
+	*x = Substitution{}
+	mi := &file_order_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Substitution) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Substitution) ProtoMessage() {}
+
+func (x *Substitution) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Substitution.ProtoReflect.Descriptor instead.
+func (*Substitution) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Substitution) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Substitution) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *Substitution) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *Substitution) GetSubstituteProductId() string {
+	if x != nil {
+		return x.SubstituteProductId
+	}
+	return ""
+}
+
+func (x *Substitution) GetSubstituteQuantity() int32 {
+	if x != nil {
+		return x.SubstituteQuantity
+	}
+	return 0
+}
+
+func (x *Substitution) GetPrice() *common.Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *Substitution) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Substitution) GetProposedAt() string {
+	if x != nil {
+		return x.ProposedAt
+	}
+	return ""
+}
+
+func (x *Substitution) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *Substitution) GetDecidedAt() string {
+	if x != nil {
+		return x.DecidedAt
+	}
+	return ""
+}
+
+type Order struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId       string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Items        []*OrderItem           `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	Status       string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt    string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt    string                 `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	TaxBreakdown []*TaxLine             `protobuf:"bytes,10,rep,name=tax_breakdown,json=taxBreakdown,proto3" json:"tax_breakdown,omitempty"`
+	// Gross total including VAT.
+	Total         *common.Money   `protobuf:"bytes,11,opt,name=total,proto3" json:"total,omitempty"`
+	NetTotal      *common.Money   `protobuf:"bytes,12,opt,name=net_total,json=netTotal,proto3" json:"net_total,omitempty"`
+	TaxTotal      *common.Money   `protobuf:"bytes,13,opt,name=tax_total,json=taxTotal,proto3" json:"tax_total,omitempty"`
+	Refunds       []*Refund       `protobuf:"bytes,14,rep,name=refunds,proto3" json:"refunds,omitempty"`
+	RefundedTotal *common.Money   `protobuf:"bytes,15,opt,name=refunded_total,json=refundedTotal,proto3" json:"refunded_total,omitempty"`
+	Receipts      []*Receipt      `protobuf:"bytes,16,rep,name=receipts,proto3" json:"receipts,omitempty"`
+	Address       string          `protobuf:"bytes,17,opt,name=address,proto3" json:"address,omitempty"`
+	Substitutions []*Substitution `protobuf:"bytes,18,rep,name=substitutions,proto3" json:"substitutions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_order_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Order) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Order) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Order) GetItems() []*OrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Order) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Order) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Order) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *Order) GetTaxBreakdown() []*TaxLine {
+	if x != nil {
+		return x.TaxBreakdown
+	}
+	return nil
+}
+
+func (x *Order) GetTotal() *common.Money {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *Order) GetNetTotal() *common.Money {
+	if x != nil {
+		return x.NetTotal
+	}
+	return nil
+}
+
+func (x *Order) GetTaxTotal() *common.Money {
+	if x != nil {
+		return x.TaxTotal
+	}
+	return nil
+}
+
+func (x *Order) GetRefunds() []*Refund {
+	if x != nil {
+		return x.Refunds
+	}
+	return nil
+}
+
+func (x *Order) GetRefundedTotal() *common.Money {
+	if x != nil {
+		return x.RefundedTotal
+	}
+	return nil
+}
+
+func (x *Order) GetReceipts() []*Receipt {
+	if x != nil {
+		return x.Receipts
+	}
+	return nil
+}
+
+func (x *Order) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Order) GetSubstitutions() []*Substitution {
+	if x != nil {
+		return x.Substitutions
+	}
+	return nil
+}
+
+type OrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderRequest) Reset() {
+	*x = OrderRequest{}
+	mi := &file_order_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderRequest) ProtoMessage() {}
+
+func (x *OrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderRequest.ProtoReflect.Descriptor instead.
+func (*OrderRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *OrderRequest) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type OrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderResponse) Reset() {
+	*x = OrderResponse{}
+	mi := &file_order_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderResponse) ProtoMessage() {}
+
+func (x *OrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderResponse.ProtoReflect.Descriptor instead.
+func (*OrderResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *OrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type OrderID struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderID) Reset() {
+	*x = OrderID{}
+	mi := &file_order_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderID) ProtoMessage() {}
+
+func (x *OrderID) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderID.ProtoReflect.Descriptor instead.
+func (*OrderID) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *OrderID) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type UserID struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserID) Reset() {
+	*x = UserID{}
+	mi := &file_order_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserID) ProtoMessage() {}
+
+func (x *UserID) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserID.ProtoReflect.Descriptor instead.
+func (*UserID) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UserID) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type OrderListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderListResponse) Reset() {
+	*x = OrderListResponse{}
+	mi := &file_order_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderListResponse) ProtoMessage() {}
+
+func (x *OrderListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderListResponse.ProtoReflect.Descriptor instead.
+func (*OrderListResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *OrderListResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+type StockCheckRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockCheckRequest) Reset() {
+	*x = StockCheckRequest{}
+	mi := &file_order_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockCheckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockCheckRequest) ProtoMessage() {}
+
+func (x *StockCheckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockCheckRequest.ProtoReflect.Descriptor instead.
+func (*StockCheckRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *StockCheckRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *StockCheckRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type StockCheckResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Available     bool                   `protobuf:"varint,1,opt,name=available,proto3" json:"available,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockCheckResponse) Reset() {
+	*x = StockCheckResponse{}
+	mi := &file_order_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockCheckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockCheckResponse) ProtoMessage() {}
+
+func (x *StockCheckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockCheckResponse.ProtoReflect.Descriptor instead.
+func (*StockCheckResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StockCheckResponse) GetAvailable() bool {
+	if x != nil {
+		return x.Available
+	}
+	return false
+}
+
+// Sets the quantity of a product; 0 removes the line.
+type OrderLineChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderLineChange) Reset() {
+	*x = OrderLineChange{}
+	mi := &file_order_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderLineChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderLineChange) ProtoMessage() {}
+
+func (x *OrderLineChange) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderLineChange.ProtoReflect.Descriptor instead.
+func (*OrderLineChange) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *OrderLineChange) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *OrderLineChange) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type ModifyOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Changes       []*OrderLineChange     `protobuf:"bytes,2,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModifyOrderRequest) Reset() {
+	*x = ModifyOrderRequest{}
+	mi := &file_order_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModifyOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModifyOrderRequest) ProtoMessage() {}
+
+func (x *ModifyOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModifyOrderRequest.ProtoReflect.Descriptor instead.
+func (*ModifyOrderRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ModifyOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *ModifyOrderRequest) GetChanges() []*OrderLineChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+// How much more (positive) or less (negative) of a product the order holds.
+type QuantityDelta struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Delta         int32                  `protobuf:"varint,2,opt,name=delta,proto3" json:"delta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuantityDelta) Reset() {
+	*x = QuantityDelta{}
+	mi := &file_order_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuantityDelta) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *Order) GetTotal() float32 {
+func (*QuantityDelta) ProtoMessage() {}
+
+func (x *QuantityDelta) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[16]
 	if x != nil {
-		return x.Total
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *Order) GetStatus() string {
-	if x != nil {
-		return x.Status
-	}
-	return ""
+// Deprecated: Use QuantityDelta.ProtoReflect.Descriptor instead.
+func (*QuantityDelta) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *Order) GetCreatedAt() string {
+func (x *QuantityDelta) GetProductId() string {
 	if x != nil {
-		return x.CreatedAt
+		return x.ProductId
 	}
 	return ""
 }
 
-func (x *Order) GetUpdatedAt() string {
+func (x *QuantityDelta) GetDelta() int32 {
 	if x != nil {
-		return x.UpdatedAt
+		return x.Delta
 	}
-	return ""
+	return 0
 }
 
-type OrderRequest struct {
+type ModifyOrderResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	Deltas        []*QuantityDelta       `protobuf:"bytes,2,rep,name=deltas,proto3" json:"deltas,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OrderRequest) Reset() {
-	*x = OrderRequest{}
-	mi := &file_order_proto_msgTypes[2]
+func (x *ModifyOrderResponse) Reset() {
+	*x = ModifyOrderResponse{}
+	mi := &file_order_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrderRequest) String() string {
+func (x *ModifyOrderResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrderRequest) ProtoMessage() {}
+func (*ModifyOrderResponse) ProtoMessage() {}
 
-func (x *OrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[2]
+func (x *ModifyOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -206,40 +1245,51 @@ func (x *OrderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrderRequest.ProtoReflect.Descriptor instead.
-func (*OrderRequest) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use ModifyOrderResponse.ProtoReflect.Descriptor instead.
+func (*ModifyOrderResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *OrderRequest) GetOrder() *Order {
+func (x *ModifyOrderResponse) GetOrder() *Order {
 	if x != nil {
 		return x.Order
 	}
 	return nil
 }
 
-type OrderResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ModifyOrderResponse) GetDeltas() []*QuantityDelta {
+	if x != nil {
+		return x.Deltas
+	}
+	return nil
 }
 
-func (x *OrderResponse) Reset() {
-	*x = OrderResponse{}
-	mi := &file_order_proto_msgTypes[3]
+type SubstitutionPreferenceRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	OrderId             string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId              string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ProductId           string                 `protobuf:"bytes,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Preference          string                 `protobuf:"bytes,4,opt,name=preference,proto3" json:"preference,omitempty"`
+	SubstituteProductId string                 `protobuf:"bytes,5,opt,name=substitute_product_id,json=substituteProductId,proto3" json:"substitute_product_id,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *SubstitutionPreferenceRequest) Reset() {
+	*x = SubstitutionPreferenceRequest{}
+	mi := &file_order_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrderResponse) String() string {
+func (x *SubstitutionPreferenceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrderResponse) ProtoMessage() {}
+func (*SubstitutionPreferenceRequest) ProtoMessage() {}
 
-func (x *OrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[3]
+func (x *SubstitutionPreferenceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -250,40 +1300,73 @@ func (x *OrderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrderResponse.ProtoReflect.Descriptor instead.
-func (*OrderResponse) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use SubstitutionPreferenceRequest.ProtoReflect.Descriptor instead.
+func (*SubstitutionPreferenceRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *OrderResponse) GetOrder() *Order {
+func (x *SubstitutionPreferenceRequest) GetOrderId() string {
 	if x != nil {
-		return x.Order
+		return x.OrderId
 	}
-	return nil
+	return ""
 }
 
-type OrderID struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *SubstitutionPreferenceRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
 }
 
-func (x *OrderID) Reset() {
-	*x = OrderID{}
-	mi := &file_order_proto_msgTypes[4]
+func (x *SubstitutionPreferenceRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *SubstitutionPreferenceRequest) GetPreference() string {
+	if x != nil {
+		return x.Preference
+	}
+	return ""
+}
+
+func (x *SubstitutionPreferenceRequest) GetSubstituteProductId() string {
+	if x != nil {
+		return x.SubstituteProductId
+	}
+	return ""
+}
+
+// Sent by the picker for a product that is missing. Without a substitute the
+// line is dropped.
+type ProposeSubstitutionRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	OrderId             string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	ProductId           string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	SubstituteProductId string                 `protobuf:"bytes,3,opt,name=substitute_product_id,json=substituteProductId,proto3" json:"substitute_product_id,omitempty"`
+	Quantity            int32                  `protobuf:"varint,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ProposeSubstitutionRequest) Reset() {
+	*x = ProposeSubstitutionRequest{}
+	mi := &file_order_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrderID) String() string {
+func (x *ProposeSubstitutionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrderID) ProtoMessage() {}
+func (*ProposeSubstitutionRequest) ProtoMessage() {}
 
-func (x *OrderID) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[4]
+func (x *ProposeSubstitutionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -294,40 +1377,64 @@ func (x *OrderID) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrderID.ProtoReflect.Descriptor instead.
-func (*OrderID) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use ProposeSubstitutionRequest.ProtoReflect.Descriptor instead.
+func (*ProposeSubstitutionRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *OrderID) GetId() string {
+func (x *ProposeSubstitutionRequest) GetOrderId() string {
 	if x != nil {
-		return x.Id
+		return x.OrderId
 	}
 	return ""
 }
 
-type UserID struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ProposeSubstitutionRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
 }
 
-func (x *UserID) Reset() {
-	*x = UserID{}
-	mi := &file_order_proto_msgTypes[5]
+func (x *ProposeSubstitutionRequest) GetSubstituteProductId() string {
+	if x != nil {
+		return x.SubstituteProductId
+	}
+	return ""
+}
+
+func (x *ProposeSubstitutionRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type DecideSubstitutionRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	OrderId        string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId         string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SubstitutionId string                 `protobuf:"bytes,3,opt,name=substitution_id,json=substitutionId,proto3" json:"substitution_id,omitempty"`
+	Approve        bool                   `protobuf:"varint,4,opt,name=approve,proto3" json:"approve,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DecideSubstitutionRequest) Reset() {
+	*x = DecideSubstitutionRequest{}
+	mi := &file_order_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserID) String() string {
+func (x *DecideSubstitutionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserID) ProtoMessage() {}
+func (*DecideSubstitutionRequest) ProtoMessage() {}
 
-func (x *UserID) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[5]
+func (x *DecideSubstitutionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -338,40 +1445,62 @@ func (x *UserID) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UserID.ProtoReflect.Descriptor instead.
-func (*UserID) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use DecideSubstitutionRequest.ProtoReflect.Descriptor instead.
+func (*DecideSubstitutionRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *UserID) GetId() string {
+func (x *DecideSubstitutionRequest) GetOrderId() string {
 	if x != nil {
-		return x.Id
+		return x.OrderId
 	}
 	return ""
 }
 
-type OrderListResponse struct {
+func (x *DecideSubstitutionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DecideSubstitutionRequest) GetSubstitutionId() string {
+	if x != nil {
+		return x.SubstitutionId
+	}
+	return ""
+}
+
+func (x *DecideSubstitutionRequest) GetApprove() bool {
+	if x != nil {
+		return x.Approve
+	}
+	return false
+}
+
+type SubstitutionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	Substitution  *Substitution          `protobuf:"bytes,2,opt,name=substitution,proto3" json:"substitution,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OrderListResponse) Reset() {
-	*x = OrderListResponse{}
-	mi := &file_order_proto_msgTypes[6]
+func (x *SubstitutionResponse) Reset() {
+	*x = SubstitutionResponse{}
+	mi := &file_order_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OrderListResponse) String() string {
+func (x *SubstitutionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OrderListResponse) ProtoMessage() {}
+func (*SubstitutionResponse) ProtoMessage() {}
 
-func (x *OrderListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[6]
+func (x *SubstitutionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -382,41 +1511,54 @@ func (x *OrderListResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OrderListResponse.ProtoReflect.Descriptor instead.
-func (*OrderListResponse) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use SubstitutionResponse.ProtoReflect.Descriptor instead.
+func (*SubstitutionResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *OrderListResponse) GetOrders() []*Order {
+func (x *SubstitutionResponse) GetOrder() *Order {
 	if x != nil {
-		return x.Orders
+		return x.Order
 	}
 	return nil
 }
 
-type StockCheckRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+func (x *SubstitutionResponse) GetSubstitution() *Substitution {
+	if x != nil {
+		return x.Substitution
+	}
+	return nil
+}
+
+type InvoiceRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	OrderId string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// "ru" (default) or "kk".
+	Locale string `protobuf:"bytes,2,opt,name=locale,proto3" json:"locale,omitempty"`
+	// "html" (default) or "pdf".
+	Format string `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`
+	// Optional company details for B2B invoices.
+	BuyerName     string `protobuf:"bytes,4,opt,name=buyer_name,json=buyerName,proto3" json:"buyer_name,omitempty"`
+	BuyerBin      string `protobuf:"bytes,5,opt,name=buyer_bin,json=buyerBin,proto3" json:"buyer_bin,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StockCheckRequest) Reset() {
-	*x = StockCheckRequest{}
-	mi := &file_order_proto_msgTypes[7]
+func (x *InvoiceRequest) Reset() {
+	*x = InvoiceRequest{}
+	mi := &file_order_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StockCheckRequest) String() string {
+func (x *InvoiceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StockCheckRequest) ProtoMessage() {}
+func (*InvoiceRequest) ProtoMessage() {}
 
-func (x *StockCheckRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[7]
+func (x *InvoiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -427,47 +1569,70 @@ func (x *StockCheckRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StockCheckRequest.ProtoReflect.Descriptor instead.
-func (*StockCheckRequest) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use InvoiceRequest.ProtoReflect.Descriptor instead.
+func (*InvoiceRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *StockCheckRequest) GetProductId() string {
+func (x *InvoiceRequest) GetOrderId() string {
 	if x != nil {
-		return x.ProductId
+		return x.OrderId
 	}
 	return ""
 }
 
-func (x *StockCheckRequest) GetQuantity() int32 {
+func (x *InvoiceRequest) GetLocale() string {
 	if x != nil {
-		return x.Quantity
+		return x.Locale
 	}
-	return 0
+	return ""
 }
 
-type StockCheckResponse struct {
+func (x *InvoiceRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *InvoiceRequest) GetBuyerName() string {
+	if x != nil {
+		return x.BuyerName
+	}
+	return ""
+}
+
+func (x *InvoiceRequest) GetBuyerBin() string {
+	if x != nil {
+		return x.BuyerBin
+	}
+	return ""
+}
+
+type InvoiceResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Available     bool                   `protobuf:"varint,1,opt,name=available,proto3" json:"available,omitempty"`
+	ContentType   string                 `protobuf:"bytes,1,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Filename      string                 `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
+	Content       []byte                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StockCheckResponse) Reset() {
-	*x = StockCheckResponse{}
-	mi := &file_order_proto_msgTypes[8]
+func (x *InvoiceResponse) Reset() {
+	*x = InvoiceResponse{}
+	mi := &file_order_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StockCheckResponse) String() string {
+func (x *InvoiceResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StockCheckResponse) ProtoMessage() {}
+func (*InvoiceResponse) ProtoMessage() {}
 
-func (x *StockCheckResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[8]
+func (x *InvoiceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -478,38 +1643,119 @@ func (x *StockCheckResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StockCheckResponse.ProtoReflect.Descriptor instead.
-func (*StockCheckResponse) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use InvoiceResponse.ProtoReflect.Descriptor instead.
+func (*InvoiceResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *StockCheckResponse) GetAvailable() bool {
+func (x *InvoiceResponse) GetContentType() string {
 	if x != nil {
-		return x.Available
+		return x.ContentType
 	}
-	return false
+	return ""
+}
+
+func (x *InvoiceResponse) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *InvoiceResponse) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
 }
 
 var File_order_proto protoreflect.FileDescriptor
 
 const file_order_proto_rawDesc = "" +
 	"\n" +
-	"\vorder.proto\x12\x05order\x1a\x0finventory.proto\"\\\n" +
+	"\vorder.proto\x12\x05order\x1a\x0finventory.proto\x1a\fcommon.proto\"\xa1\x03\n" +
 	"\tOrderItem\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
-	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12\x14\n" +
-	"\x05price\x18\x03 \x01(\x02R\x05price\"\xc4\x01\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12\x1b\n" +
+	"\ttax_class\x18\x04 \x01(\tR\btaxClass\x12\x19\n" +
+	"\btax_rate\x18\x05 \x01(\x05R\ataxRate\x12#\n" +
+	"\x05price\x18\t \x01(\v2\r.common.MoneyR\x05price\x12,\n" +
+	"\n" +
+	"net_amount\x18\n" +
+	" \x01(\v2\r.common.MoneyR\tnetAmount\x12,\n" +
+	"\n" +
+	"tax_amount\x18\v \x01(\v2\r.common.MoneyR\ttaxAmount\x120\n" +
+	"\fgross_amount\x18\f \x01(\v2\r.common.MoneyR\vgrossAmount\x12\"\n" +
+	"\fsubstitution\x18\r \x01(\tR\fsubstitution\x122\n" +
+	"\x15substitute_product_id\x18\x0e \x01(\tR\x13substituteProductIdJ\x04\b\x03\x10\x04J\x04\b\x06\x10\aJ\x04\b\a\x10\bJ\x04\b\b\x10\t\"\xc4\x01\n" +
+	"\aTaxLine\x12\x19\n" +
+	"\btax_rate\x18\x01 \x01(\x05R\ataxRate\x12,\n" +
+	"\n" +
+	"net_amount\x18\x05 \x01(\v2\r.common.MoneyR\tnetAmount\x12,\n" +
+	"\n" +
+	"tax_amount\x18\x06 \x01(\v2\r.common.MoneyR\ttaxAmount\x120\n" +
+	"\fgross_amount\x18\a \x01(\v2\r.common.MoneyR\vgrossAmountJ\x04\b\x02\x10\x03J\x04\b\x03\x10\x04J\x04\b\x04\x10\x05\"n\n" +
+	"\n" +
+	"RefundLine\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12%\n" +
+	"\x06amount\x18\x03 \x01(\v2\r.common.MoneyR\x06amount\"\xec\x01\n" +
+	"\x06Refund\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x02 \x01(\tR\tpaymentId\x12%\n" +
+	"\x06amount\x18\x03 \x01(\v2\r.common.MoneyR\x06amount\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12'\n" +
+	"\x05lines\x18\x05 \x03(\v2\x11.order.RefundLineR\x05lines\x12,\n" +
+	"\x12provider_refund_id\x18\x06 \x01(\tR\x10providerRefundId\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\"\xc8\x01\n" +
+	"\aReceipt\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1b\n" +
+	"\trefund_id\x18\x03 \x01(\tR\brefundId\x12#\n" +
+	"\rfiscal_number\x18\x04 \x01(\tR\ffiscalNumber\x12\x15\n" +
+	"\x06qr_url\x18\x05 \x01(\tR\x05qrUrl\x12#\n" +
+	"\x05total\x18\x06 \x01(\v2\r.common.MoneyR\x05total\x12\x1b\n" +
+	"\tissued_at\x18\a \x01(\tR\bissuedAt\"\xda\x02\n" +
+	"\fSubstitution\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x122\n" +
+	"\x15substitute_product_id\x18\x04 \x01(\tR\x13substituteProductId\x12/\n" +
+	"\x13substitute_quantity\x18\x05 \x01(\x05R\x12substituteQuantity\x12#\n" +
+	"\x05price\x18\x06 \x01(\v2\r.common.MoneyR\x05price\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12\x1f\n" +
+	"\vproposed_at\x18\b \x01(\tR\n" +
+	"proposedAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\t \x01(\tR\texpiresAt\x12\x1d\n" +
+	"\n" +
+	"decided_at\x18\n" +
+	" \x01(\tR\tdecidedAt\"\xd2\x04\n" +
 	"\x05Order\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12&\n" +
-	"\x05items\x18\x03 \x03(\v2\x10.order.OrderItemR\x05items\x12\x14\n" +
-	"\x05total\x18\x04 \x01(\x02R\x05total\x12\x16\n" +
+	"\x05items\x18\x03 \x03(\v2\x10.order.OrderItemR\x05items\x12\x16\n" +
 	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1d\n" +
 	"\n" +
 	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12\x1d\n" +
 	"\n" +
-	"updated_at\x18\a \x01(\tR\tupdatedAt\"2\n" +
+	"updated_at\x18\a \x01(\tR\tupdatedAt\x123\n" +
+	"\rtax_breakdown\x18\n" +
+	" \x03(\v2\x0e.order.TaxLineR\ftaxBreakdown\x12#\n" +
+	"\x05total\x18\v \x01(\v2\r.common.MoneyR\x05total\x12*\n" +
+	"\tnet_total\x18\f \x01(\v2\r.common.MoneyR\bnetTotal\x12*\n" +
+	"\ttax_total\x18\r \x01(\v2\r.common.MoneyR\btaxTotal\x12'\n" +
+	"\arefunds\x18\x0e \x03(\v2\r.order.RefundR\arefunds\x124\n" +
+	"\x0erefunded_total\x18\x0f \x01(\v2\r.common.MoneyR\rrefundedTotal\x12*\n" +
+	"\breceipts\x18\x10 \x03(\v2\x0e.order.ReceiptR\breceipts\x12\x18\n" +
+	"\aaddress\x18\x11 \x01(\tR\aaddress\x129\n" +
+	"\rsubstitutions\x18\x12 \x03(\v2\x13.order.SubstitutionR\rsubstitutionsJ\x04\b\x04\x10\x05J\x04\b\b\x10\tJ\x04\b\t\x10\n" +
+	"\"2\n" +
 	"\fOrderRequest\x12\"\n" +
 	"\x05order\x18\x01 \x01(\v2\f.order.OrderR\x05order\"3\n" +
 	"\rOrderResponse\x12\"\n" +
@@ -525,7 +1771,55 @@ const file_order_proto_rawDesc = "" +
 	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
 	"\bquantity\x18\x02 \x01(\x05R\bquantity\"2\n" +
 	"\x12StockCheckResponse\x12\x1c\n" +
-	"\tavailable\x18\x01 \x01(\bR\tavailable2\xae\x02\n" +
+	"\tavailable\x18\x01 \x01(\bR\tavailable\"L\n" +
+	"\x0fOrderLineChange\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"a\n" +
+	"\x12ModifyOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x120\n" +
+	"\achanges\x18\x02 \x03(\v2\x16.order.OrderLineChangeR\achanges\"D\n" +
+	"\rQuantityDelta\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x14\n" +
+	"\x05delta\x18\x02 \x01(\x05R\x05delta\"g\n" +
+	"\x13ModifyOrderResponse\x12\"\n" +
+	"\x05order\x18\x01 \x01(\v2\f.order.OrderR\x05order\x12,\n" +
+	"\x06deltas\x18\x02 \x03(\v2\x14.order.QuantityDeltaR\x06deltas\"\xc6\x01\n" +
+	"\x1dSubstitutionPreferenceRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x03 \x01(\tR\tproductId\x12\x1e\n" +
+	"\n" +
+	"preference\x18\x04 \x01(\tR\n" +
+	"preference\x122\n" +
+	"\x15substitute_product_id\x18\x05 \x01(\tR\x13substituteProductId\"\xa6\x01\n" +
+	"\x1aProposeSubstitutionRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x122\n" +
+	"\x15substitute_product_id\x18\x03 \x01(\tR\x13substituteProductId\x12\x1a\n" +
+	"\bquantity\x18\x04 \x01(\x05R\bquantity\"\x92\x01\n" +
+	"\x19DecideSubstitutionRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12'\n" +
+	"\x0fsubstitution_id\x18\x03 \x01(\tR\x0esubstitutionId\x12\x18\n" +
+	"\aapprove\x18\x04 \x01(\bR\aapprove\"s\n" +
+	"\x14SubstitutionResponse\x12\"\n" +
+	"\x05order\x18\x01 \x01(\v2\f.order.OrderR\x05order\x127\n" +
+	"\fsubstitution\x18\x02 \x01(\v2\x13.order.SubstitutionR\fsubstitution\"\x97\x01\n" +
+	"\x0eInvoiceRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x16\n" +
+	"\x06locale\x18\x02 \x01(\tR\x06locale\x12\x16\n" +
+	"\x06format\x18\x03 \x01(\tR\x06format\x12\x1d\n" +
+	"\n" +
+	"buyer_name\x18\x04 \x01(\tR\tbuyerName\x12\x1b\n" +
+	"\tbuyer_bin\x18\x05 \x01(\tR\bbuyerBin\"j\n" +
+	"\x0fInvoiceResponse\x12!\n" +
+	"\fcontent_type\x18\x01 \x01(\tR\vcontentType\x12\x1a\n" +
+	"\bfilename\x18\x02 \x01(\tR\bfilename\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\fR\acontent2\xb6\x05\n" +
 	"\fOrderService\x128\n" +
 	"\vCreateOrder\x12\x13.order.OrderRequest\x1a\x14.order.OrderResponse\x120\n" +
 	"\bGetOrder\x12\x0e.order.OrderID\x1a\x14.order.OrderResponse\x128\n" +
@@ -533,7 +1827,13 @@ const file_order_proto_rawDesc = "" +
 	"\n" +
 	"ListOrders\x12\r.order.UserID\x1a\x18.order.OrderListResponse\x12A\n" +
 	"\n" +
-	"CheckStock\x12\x18.order.StockCheckRequest\x1a\x19.order.StockCheckResponseB\rZ\vproto/orderb\x06proto3"
+	"CheckStock\x12\x18.order.StockCheckRequest\x1a\x19.order.StockCheckResponse\x12;\n" +
+	"\n" +
+	"GetInvoice\x12\x15.order.InvoiceRequest\x1a\x16.order.InvoiceResponse\x12D\n" +
+	"\vModifyOrder\x12\x19.order.ModifyOrderRequest\x1a\x1a.order.ModifyOrderResponse\x12W\n" +
+	"\x19SetSubstitutionPreference\x12$.order.SubstitutionPreferenceRequest\x1a\x14.order.OrderResponse\x12U\n" +
+	"\x13ProposeSubstitution\x12!.order.ProposeSubstitutionRequest\x1a\x1b.order.SubstitutionResponse\x12S\n" +
+	"\x12DecideSubstitution\x12 .order.DecideSubstitutionRequest\x1a\x1b.order.SubstitutionResponseB\rZ\vproto/orderb\x06proto3"
 
 var (
 	file_order_proto_rawDescOnce sync.Once
@@ -547,38 +1847,89 @@ func file_order_proto_rawDescGZIP() []byte {
 	return file_order_proto_rawDescData
 }
 
-var file_order_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_order_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_order_proto_goTypes = []any{
-	(*OrderItem)(nil),          // 0: order.OrderItem
-	(*Order)(nil),              // 1: order.Order
-	(*OrderRequest)(nil),       // 2: order.OrderRequest
-	(*OrderResponse)(nil),      // 3: order.OrderResponse
-	(*OrderID)(nil),            // 4: order.OrderID
-	(*UserID)(nil),             // 5: order.UserID
-	(*OrderListResponse)(nil),  // 6: order.OrderListResponse
-	(*StockCheckRequest)(nil),  // 7: order.StockCheckRequest
-	(*StockCheckResponse)(nil), // 8: order.StockCheckResponse
+	(*OrderItem)(nil),                     // 0: order.OrderItem
+	(*TaxLine)(nil),                       // 1: order.TaxLine
+	(*RefundLine)(nil),                    // 2: order.RefundLine
+	(*Refund)(nil),                        // 3: order.Refund
+	(*Receipt)(nil),                       // 4: order.Receipt
+	(*Substitution)(nil),                  // 5: order.Substitution
+	(*Order)(nil),                         // 6: order.Order
+	(*OrderRequest)(nil),                  // 7: order.OrderRequest
+	(*OrderResponse)(nil),                 // 8: order.OrderResponse
+	(*OrderID)(nil),                       // 9: order.OrderID
+	(*UserID)(nil),                        // 10: order.UserID
+	(*OrderListResponse)(nil),             // 11: order.OrderListResponse
+	(*StockCheckRequest)(nil),             // 12: order.StockCheckRequest
+	(*StockCheckResponse)(nil),            // 13: order.StockCheckResponse
+	(*OrderLineChange)(nil),               // 14: order.OrderLineChange
+	(*ModifyOrderRequest)(nil),            // 15: order.ModifyOrderRequest
+	(*QuantityDelta)(nil),                 // 16: order.QuantityDelta
+	(*ModifyOrderResponse)(nil),           // 17: order.ModifyOrderResponse
+	(*SubstitutionPreferenceRequest)(nil), // 18: order.SubstitutionPreferenceRequest
+	(*ProposeSubstitutionRequest)(nil),    // 19: order.ProposeSubstitutionRequest
+	(*DecideSubstitutionRequest)(nil),     // 20: order.DecideSubstitutionRequest
+	(*SubstitutionResponse)(nil),          // 21: order.SubstitutionResponse
+	(*InvoiceRequest)(nil),                // 22: order.InvoiceRequest
+	(*InvoiceResponse)(nil),               // 23: order.InvoiceResponse
+	(*common.Money)(nil),                  // 24: common.Money
 }
 var file_order_proto_depIdxs = []int32{
-	0, // 0: order.Order.items:type_name -> order.OrderItem
-	1, // 1: order.OrderRequest.order:type_name -> order.Order
-	1, // 2: order.OrderResponse.order:type_name -> order.Order
-	1, // 3: order.OrderListResponse.orders:type_name -> order.Order
-	2, // 4: order.OrderService.CreateOrder:input_type -> order.OrderRequest
-	4, // 5: order.OrderService.GetOrder:input_type -> order.OrderID
-	2, // 6: order.OrderService.UpdateOrder:input_type -> order.OrderRequest
-	5, // 7: order.OrderService.ListOrders:input_type -> order.UserID
-	7, // 8: order.OrderService.CheckStock:input_type -> order.StockCheckRequest
-	3, // 9: order.OrderService.CreateOrder:output_type -> order.OrderResponse
-	3, // 10: order.OrderService.GetOrder:output_type -> order.OrderResponse
-	3, // 11: order.OrderService.UpdateOrder:output_type -> order.OrderResponse
-	6, // 12: order.OrderService.ListOrders:output_type -> order.OrderListResponse
-	8, // 13: order.OrderService.CheckStock:output_type -> order.StockCheckResponse
-	9, // [9:14] is the sub-list for method output_type
-	4, // [4:9] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	24, // 0: order.OrderItem.price:type_name -> common.Money
+	24, // 1: order.OrderItem.net_amount:type_name -> common.Money
+	24, // 2: order.OrderItem.tax_amount:type_name -> common.Money
+	24, // 3: order.OrderItem.gross_amount:type_name -> common.Money
+	24, // 4: order.TaxLine.net_amount:type_name -> common.Money
+	24, // 5: order.TaxLine.tax_amount:type_name -> common.Money
+	24, // 6: order.TaxLine.gross_amount:type_name -> common.Money
+	24, // 7: order.RefundLine.amount:type_name -> common.Money
+	24, // 8: order.Refund.amount:type_name -> common.Money
+	2,  // 9: order.Refund.lines:type_name -> order.RefundLine
+	24, // 10: order.Receipt.total:type_name -> common.Money
+	24, // 11: order.Substitution.price:type_name -> common.Money
+	0,  // 12: order.Order.items:type_name -> order.OrderItem
+	1,  // 13: order.Order.tax_breakdown:type_name -> order.TaxLine
+	24, // 14: order.Order.total:type_name -> common.Money
+	24, // 15: order.Order.net_total:type_name -> common.Money
+	24, // 16: order.Order.tax_total:type_name -> common.Money
+	3,  // 17: order.Order.refunds:type_name -> order.Refund
+	24, // 18: order.Order.refunded_total:type_name -> common.Money
+	4,  // 19: order.Order.receipts:type_name -> order.Receipt
+	5,  // 20: order.Order.substitutions:type_name -> order.Substitution
+	6,  // 21: order.OrderRequest.order:type_name -> order.Order
+	6,  // 22: order.OrderResponse.order:type_name -> order.Order
+	6,  // 23: order.OrderListResponse.orders:type_name -> order.Order
+	14, // 24: order.ModifyOrderRequest.changes:type_name -> order.OrderLineChange
+	6,  // 25: order.ModifyOrderResponse.order:type_name -> order.Order
+	16, // 26: order.ModifyOrderResponse.deltas:type_name -> order.QuantityDelta
+	6,  // 27: order.SubstitutionResponse.order:type_name -> order.Order
+	5,  // 28: order.SubstitutionResponse.substitution:type_name -> order.Substitution
+	7,  // 29: order.OrderService.CreateOrder:input_type -> order.OrderRequest
+	9,  // 30: order.OrderService.GetOrder:input_type -> order.OrderID
+	7,  // 31: order.OrderService.UpdateOrder:input_type -> order.OrderRequest
+	10, // 32: order.OrderService.ListOrders:input_type -> order.UserID
+	12, // 33: order.OrderService.CheckStock:input_type -> order.StockCheckRequest
+	22, // 34: order.OrderService.GetInvoice:input_type -> order.InvoiceRequest
+	15, // 35: order.OrderService.ModifyOrder:input_type -> order.ModifyOrderRequest
+	18, // 36: order.OrderService.SetSubstitutionPreference:input_type -> order.SubstitutionPreferenceRequest
+	19, // 37: order.OrderService.ProposeSubstitution:input_type -> order.ProposeSubstitutionRequest
+	20, // 38: order.OrderService.DecideSubstitution:input_type -> order.DecideSubstitutionRequest
+	8,  // 39: order.OrderService.CreateOrder:output_type -> order.OrderResponse
+	8,  // 40: order.OrderService.GetOrder:output_type -> order.OrderResponse
+	8,  // 41: order.OrderService.UpdateOrder:output_type -> order.OrderResponse
+	11, // 42: order.OrderService.ListOrders:output_type -> order.OrderListResponse
+	13, // 43: order.OrderService.CheckStock:output_type -> order.StockCheckResponse
+	23, // 44: order.OrderService.GetInvoice:output_type -> order.InvoiceResponse
+	17, // 45: order.OrderService.ModifyOrder:output_type -> order.ModifyOrderResponse
+	8,  // 46: order.OrderService.SetSubstitutionPreference:output_type -> order.OrderResponse
+	21, // 47: order.OrderService.ProposeSubstitution:output_type -> order.SubstitutionResponse
+	21, // 48: order.OrderService.DecideSubstitution:output_type -> order.SubstitutionResponse
+	39, // [39:49] is the sub-list for method output_type
+	29, // [29:39] is the sub-list for method input_type
+	29, // [29:29] is the sub-list for extension type_name
+	29, // [29:29] is the sub-list for extension extendee
+	0,  // [0:29] is the sub-list for field type_name
 }
 
 func init() { file_order_proto_init() }
@@ -592,7 +1943,7 @@ func file_order_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_order_proto_rawDesc), len(file_order_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,11 +19,16 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	OrderService_CreateOrder_FullMethodName = "/order.OrderService/CreateOrder"
-	OrderService_GetOrder_FullMethodName    = "/order.OrderService/GetOrder"
-	OrderService_UpdateOrder_FullMethodName = "/order.OrderService/UpdateOrder"
-	OrderService_ListOrders_FullMethodName  = "/order.OrderService/ListOrders"
-	OrderService_CheckStock_FullMethodName  = "/order.OrderService/CheckStock"
+	OrderService_CreateOrder_FullMethodName               = "/order.OrderService/CreateOrder"
+	OrderService_GetOrder_FullMethodName                  = "/order.OrderService/GetOrder"
+	OrderService_UpdateOrder_FullMethodName               = "/order.OrderService/UpdateOrder"
+	OrderService_ListOrders_FullMethodName                = "/order.OrderService/ListOrders"
+	OrderService_CheckStock_FullMethodName                = "/order.OrderService/CheckStock"
+	OrderService_GetInvoice_FullMethodName                = "/order.OrderService/GetInvoice"
+	OrderService_ModifyOrder_FullMethodName               = "/order.OrderService/ModifyOrder"
+	OrderService_SetSubstitutionPreference_FullMethodName = "/order.OrderService/SetSubstitutionPreference"
+	OrderService_ProposeSubstitution_FullMethodName       = "/order.OrderService/ProposeSubstitution"
+	OrderService_DecideSubstitution_FullMethodName        = "/order.OrderService/DecideSubstitution"
 )
 
 // OrderServiceClient is the client API for OrderService service.
@@ -35,6 +40,11 @@ type OrderServiceClient interface {
 	UpdateOrder(ctx context.Context, in *OrderRequest, opts ...grpc.CallOption) (*OrderResponse, error)
 	ListOrders(ctx context.Context, in *UserID, opts ...grpc.CallOption) (*OrderListResponse, error)
 	CheckStock(ctx context.Context, in *StockCheckRequest, opts ...grpc.CallOption) (*StockCheckResponse, error)
+	GetInvoice(ctx context.Context, in *InvoiceRequest, opts ...grpc.CallOption) (*InvoiceResponse, error)
+	ModifyOrder(ctx context.Context, in *ModifyOrderRequest, opts ...grpc.CallOption) (*ModifyOrderResponse, error)
+	SetSubstitutionPreference(ctx context.Context, in *SubstitutionPreferenceRequest, opts ...grpc.CallOption) (*OrderResponse, error)
+	ProposeSubstitution(ctx context.Context, in *ProposeSubstitutionRequest, opts ...grpc.CallOption) (*SubstitutionResponse, error)
+	DecideSubstitution(ctx context.Context, in *DecideSubstitutionRequest, opts ...grpc.CallOption) (*SubstitutionResponse, error)
 }
 
 type orderServiceClient struct {
@@ -95,6 +105,56 @@ func (c *orderServiceClient) CheckStock(ctx context.Context, in *StockCheckReque
 	return out, nil
 }
 
+func (c *orderServiceClient) GetInvoice(ctx context.Context, in *InvoiceRequest, opts ...grpc.CallOption) (*InvoiceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InvoiceResponse)
+	err := c.cc.Invoke(ctx, OrderService_GetInvoice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ModifyOrder(ctx context.Context, in *ModifyOrderRequest, opts ...grpc.CallOption) (*ModifyOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ModifyOrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_ModifyOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) SetSubstitutionPreference(ctx context.Context, in *SubstitutionPreferenceRequest, opts ...grpc.CallOption) (*OrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_SetSubstitutionPreference_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ProposeSubstitution(ctx context.Context, in *ProposeSubstitutionRequest, opts ...grpc.CallOption) (*SubstitutionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubstitutionResponse)
+	err := c.cc.Invoke(ctx, OrderService_ProposeSubstitution_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) DecideSubstitution(ctx context.Context, in *DecideSubstitutionRequest, opts ...grpc.CallOption) (*SubstitutionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubstitutionResponse)
+	err := c.cc.Invoke(ctx, OrderService_DecideSubstitution_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrderServiceServer is the server API for OrderService service.
 // All implementations must embed UnimplementedOrderServiceServer
 // for forward compatibility.
@@ -104,6 +164,11 @@ type OrderServiceServer interface {
 	UpdateOrder(context.Context, *OrderRequest) (*OrderResponse, error)
 	ListOrders(context.Context, *UserID) (*OrderListResponse, error)
 	CheckStock(context.Context, *StockCheckRequest) (*StockCheckResponse, error)
+	GetInvoice(context.Context, *InvoiceRequest) (*InvoiceResponse, error)
+	ModifyOrder(context.Context, *ModifyOrderRequest) (*ModifyOrderResponse, error)
+	SetSubstitutionPreference(context.Context, *SubstitutionPreferenceRequest) (*OrderResponse, error)
+	ProposeSubstitution(context.Context, *ProposeSubstitutionRequest) (*SubstitutionResponse, error)
+	DecideSubstitution(context.Context, *DecideSubstitutionRequest) (*SubstitutionResponse, error)
 	mustEmbedUnimplementedOrderServiceServer()
 }
 
@@ -129,6 +194,21 @@ func (UnimplementedOrderServiceServer) ListOrders(context.Context, *UserID) (*Or
 func (UnimplementedOrderServiceServer) CheckStock(context.Context, *StockCheckRequest) (*StockCheckResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CheckStock not implemented")
 }
+func (UnimplementedOrderServiceServer) GetInvoice(context.Context, *InvoiceRequest) (*InvoiceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInvoice not implemented")
+}
+func (UnimplementedOrderServiceServer) ModifyOrder(context.Context, *ModifyOrderRequest) (*ModifyOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModifyOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) SetSubstitutionPreference(context.Context, *SubstitutionPreferenceRequest) (*OrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSubstitutionPreference not implemented")
+}
+func (UnimplementedOrderServiceServer) ProposeSubstitution(context.Context, *ProposeSubstitutionRequest) (*SubstitutionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProposeSubstitution not implemented")
+}
+func (UnimplementedOrderServiceServer) DecideSubstitution(context.Context, *DecideSubstitutionRequest) (*SubstitutionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecideSubstitution not implemented")
+}
 func (UnimplementedOrderServiceServer) mustEmbedUnimplementedOrderServiceServer() {}
 func (UnimplementedOrderServiceServer) testEmbeddedByValue()                      {}
 
@@ -240,6 +320,96 @@ func _OrderService_CheckStock_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderService_GetInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetInvoice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetInvoice(ctx, req.(*InvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ModifyOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModifyOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ModifyOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ModifyOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ModifyOrder(ctx, req.(*ModifyOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_SetSubstitutionPreference_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubstitutionPreferenceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).SetSubstitutionPreference(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_SetSubstitutionPreference_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).SetSubstitutionPreference(ctx, req.(*SubstitutionPreferenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ProposeSubstitution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProposeSubstitutionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ProposeSubstitution(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ProposeSubstitution_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ProposeSubstitution(ctx, req.(*ProposeSubstitutionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_DecideSubstitution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecideSubstitutionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).DecideSubstitution(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_DecideSubstitution_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).DecideSubstitution(ctx, req.(*DecideSubstitutionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // OrderService_ServiceDesc is the grpc.ServiceDesc for OrderService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -267,6 +437,26 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CheckStock",
 			Handler:    _OrderService_CheckStock_Handler,
 		},
+		{
+			MethodName: "GetInvoice",
+			Handler:    _OrderService_GetInvoice_Handler,
+		},
+		{
+			MethodName: "ModifyOrder",
+			Handler:    _OrderService_ModifyOrder_Handler,
+		},
+		{
+			MethodName: "SetSubstitutionPreference",
+			Handler:    _OrderService_SetSubstitutionPreference_Handler,
+		},
+		{
+			MethodName: "ProposeSubstitution",
+			Handler:    _OrderService_ProposeSubstitution_Handler,
+		},
+		{
+			MethodName: "DecideSubstitution",
+			Handler:    _OrderService_DecideSubstitution_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "order.proto",
@@ -0,0 +1,112 @@
+package shared_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"infra/moneybson"
+	"proto/money"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+)
+
+func TestMoneyArithmetic(t *testing.T) {
+	price := money.KZT(33333)
+
+	assert.Equal(t, money.KZT(99999), price.Mul(3))
+
+	sum, err := price.Mul(3).Add(price)
+	require.NoError(t, err)
+	assert.Equal(t, money.KZT(133332), sum)
+
+	diff, err := price.Mul(3).Sub(price)
+	require.NoError(t, err)
+	assert.Equal(t, money.KZT(66666), diff)
+
+	assert.Equal(t, "333.33 KZT", price.String())
+	assert.Equal(t, "-1.05 KZT", money.KZT(-105).String())
+}
+
+func TestMoneyRejectsMixedCurrencies(t *testing.T) {
+	kzt, usd := money.KZT(100), money.New(100, "USD")
+
+	_, err := kzt.Add(usd)
+	assert.ErrorIs(t, err, money.ErrCurrencyMismatch)
+	_, err = usd.Sub(kzt)
+	assert.ErrorIs(t, err, money.ErrCurrencyMismatch)
+
+	sum, err := money.Money{}.Add(usd)
+	require.NoError(t, err)
+	assert.Equal(t, usd, sum)
+
+	diff, err := kzt.Sub(money.Money{})
+	require.NoError(t, err)
+	assert.Equal(t, kzt, diff)
+}
+
+func TestMoneyMulRatioRoundsHalfAwayFromZero(t *testing.T) {
+	// VAT contained in 1 120.00 KZT at 12% is exactly 120.00 KZT.
+	assert.Equal(t, int64(12000), money.KZT(112000).MulRatio(1200, 11200).Amount)
+
+	assert.Equal(t, int64(1), money.KZT(1).MulRatio(1, 2).Amount)
+	assert.Equal(t, int64(-1), money.KZT(-1).MulRatio(1, 2).Amount)
+	assert.Equal(t, int64(0), money.KZT(1).MulRatio(1, 3).Amount)
+}
+
+func TestMoneyDefaultsToKZT(t *testing.T) {
+	assert.Equal(t, "KZT", money.New(100, "").Currency)
+	assert.Equal(t, "USD", money.New(100, "USD").Currency)
+	assert.Equal(t, "KZT", money.FromProto(nil).Currency)
+}
+
+func TestMoneyFromLegacyFloat(t *testing.T) {
+	assert.Equal(t, int64(1999), money.FromFloat(19.99, "").Amount)
+	assert.Equal(t, int64(30), money.FromFloat(0.1+0.2, "").Amount)
+}
+
+func TestMoneyDecodesLegacyBSON(t *testing.T) {
+	type product struct {
+		Price money.Money `bson:"price"`
+	}
+
+	// The services decode with the registry they set on their MongoDB client.
+	unmarshal := func(data []byte, val interface{}) error {
+		decoder, err := bson.NewDecoder(bsonrw.NewBSONDocumentReader(data))
+		if err != nil {
+			return err
+		}
+		if err := decoder.SetRegistry(moneybson.Registry()); err != nil {
+			return err
+		}
+		return decoder.Decode(val)
+	}
+
+	legacy, err := bson.Marshal(bson.M{"price": 1500.5})
+	require.NoError(t, err)
+
+	var decoded product
+	require.NoError(t, unmarshal(legacy, &decoded))
+	assert.Equal(t, money.KZT(150050), decoded.Price)
+
+	current, err := bson.Marshal(product{Price: money.New(250, "USD")})
+	require.NoError(t, err)
+
+	decoded = product{}
+	require.NoError(t, unmarshal(current, &decoded))
+	assert.Equal(t, money.New(250, "USD"), decoded.Price)
+}
+
+func TestMoneyDecodesLegacyJSON(t *testing.T) {
+	var item struct {
+		Price money.Money `json:"price"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"price": 12.34}`), &item))
+	assert.Equal(t, money.KZT(1234), item.Price)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"price": {"amount": 1234, "currency": "KZT"}}`), &item))
+	assert.Equal(t, money.KZT(1234), item.Price)
+}
@@ -11,6 +11,7 @@ import (
 
 	"user-service/internal/config"
 	"user-service/internal/infrastructure/database"
+	"user-service/internal/infrastructure/messaging"
 	"user-service/internal/interfaces/routes"
 
 	"google.golang.org/grpc"
@@ -31,9 +32,17 @@ func main() {
 		log.Fatalf("Failed to connect to MongoDB: %v", err)
 	}
 
+	var consumer messaging.EventConsumer
+	natsConsumer, err := messaging.NewNATSConsumer(cfg)
+	if err != nil {
+		log.Printf("Warning: failed to connect to NATS: %v. Order notifications will be disabled.", err)
+	} else {
+		consumer = natsConsumer
+	}
+
 	grpcServer := grpc.NewServer()
 
-	services := routes.RegisterGRPCServices(grpcServer, mongoDB, cfg)
+	services := routes.RegisterGRPCServices(grpcServer, mongoDB, cfg, consumer)
 
 	lis, err := net.Listen("tcp", ":"+cfg.Server.Port)
 	if err != nil {
@@ -56,7 +65,16 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	if consumer != nil {
+		consumer.Close()
+	}
 	grpcServer.GracefulStop()
+	if services.MailQueue != nil {
+		services.MailQueue.Stop()
+	}
+	if services.WebhookDispatcher != nil {
+		services.WebhookDispatcher.Stop()
+	}
 	if err := mongoDB.Close(ctx); err != nil {
 		log.Fatalf("Error while closing MongoDB connection: %v", err)
 	}
@@ -0,0 +1,60 @@
+// Command webhook-receiver is a local HTTP endpoint for trying out partner
+// webhooks. It checks the signature of every request and logs the event.
+//
+//	go run ./cmd/webhook-receiver -secret whsec_... -fail 2
+//
+// With -fail it answers the first requests with 500 to exercise retries.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"user-service/internal/infrastructure/webhook"
+)
+
+func main() {
+	addr := flag.String("addr", ":9090", "address to listen on")
+	secret := flag.String("secret", "", "secret of the webhook subscription")
+	fail := flag.Int64("fail", 0, "number of requests to answer with 500")
+	flag.Parse()
+
+	var received atomic.Int64
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := webhook.Verify(*secret, r.Header.Get(webhook.HeaderSignature), body, 5*time.Minute, time.Now()); err != nil {
+			log.Printf("Rejected %s: %v", r.Header.Get(webhook.HeaderID), err)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		if n := received.Add(1); n <= *fail {
+			log.Printf("Failing request %d of %s on purpose", n, r.Header.Get(webhook.HeaderID))
+			http.Error(w, "failing on purpose", http.StatusInternalServerError)
+			return
+		}
+
+		var payload webhook.Payload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		log.Printf("Received %s %s: %s", payload.Type, payload.ID, payload.Data)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	log.Printf("Webhook receiver listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
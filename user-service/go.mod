@@ -5,10 +5,12 @@ go 1.23.4
 require (
 	github.com/google/uuid v1.6.0
 	github.com/joho/godotenv v1.5.1
+	github.com/nats-io/nats.go v1.33.1
 	github.com/redis/go-redis/v9 v9.5.1
 	go.mongodb.org/mongo-driver v1.17.3
 	golang.org/x/crypto v0.32.0
 	google.golang.org/grpc v1.71.1
+	infra v0.0.0-00010101000000-000000000000
 	proto v0.0.0-00010101000000-000000000000
 )
 
@@ -16,8 +18,10 @@ require (
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
 	github.com/golang/snappy v0.0.4 // indirect
-	github.com/klauspost/compress v1.16.7 // indirect
+	github.com/klauspost/compress v1.17.7 // indirect
 	github.com/montanaflynn/stats v0.7.1 // indirect
+	github.com/nats-io/nkeys v0.4.7 // indirect
+	github.com/nats-io/nuid v1.0.1 // indirect
 	github.com/xdg-go/pbkdf2 v1.0.0 // indirect
 	github.com/xdg-go/scram v1.1.2 // indirect
 	github.com/xdg-go/stringprep v1.0.4 // indirect
@@ -30,4 +34,7 @@ require (
 	google.golang.org/protobuf v1.36.6 // indirect
 )
 
-replace proto => ../proto
+replace (
+	infra => ../infra
+	proto => ../proto
+)
@@ -0,0 +1,180 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"user-service/internal/config"
+	"user-service/internal/domain"
+	"user-service/internal/infrastructure/mail"
+	"user-service/internal/infrastructure/persistence"
+)
+
+// MailQueue stores outgoing emails in Mongo and delivers them from a pool of
+// workers, so an SMTP outage delays emails instead of losing them.
+type MailQueue struct {
+	repo         persistence.MailQueueRepository
+	sender       mail.Sender
+	policy       domain.RetryPolicy
+	workers      int
+	pollInterval time.Duration
+	lease        time.Duration
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+func NewMailQueue(repo persistence.MailQueueRepository, sender mail.Sender, cfg config.MailQueueConfig) *MailQueue {
+	return &MailQueue{
+		repo:   repo,
+		sender: sender,
+		policy: domain.RetryPolicy{
+			MaxAttempts: cfg.MaxAttempts,
+			BaseDelay:   time.Duration(cfg.BaseDelay) * time.Second,
+			MaxDelay:    time.Duration(cfg.MaxDelay) * time.Second,
+		},
+		workers:      cfg.Workers,
+		pollInterval: time.Duration(cfg.PollInterval) * time.Second,
+		lease:        time.Duration(cfg.Lease) * time.Second,
+	}
+}
+
+// EnqueueTemplate renders the named email and queues it for delivery.
+func (q *MailQueue) EnqueueTemplate(ctx context.Context, to, name string, language domain.Language, data mail.TemplateData) (*domain.OutboundEmail, error) {
+	rendered, err := mail.Render(name, language, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.Enqueue(ctx, to, name, rendered)
+}
+
+// Enqueue queues an email rendered from the named template.
+func (q *MailQueue) Enqueue(ctx context.Context, to, name string, rendered *mail.Email) (*domain.OutboundEmail, error) {
+	email := domain.NewOutboundEmail(to, name, rendered.Language, rendered.Subject, rendered.Text, rendered.HTML)
+	if err := q.repo.Enqueue(ctx, email); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Queued %s email %s to %s", name, email.ID, to)
+	return email, nil
+}
+
+func (q *MailQueue) List(ctx context.Context, status string, page, limit int) ([]*domain.OutboundEmail, int, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	return q.repo.List(ctx, domain.EmailStatus(status), page, limit)
+}
+
+// Resend queues a failed email again with a fresh set of attempts.
+func (q *MailQueue) Resend(ctx context.Context, id string) (*domain.OutboundEmail, error) {
+	email, err := q.repo.GetByID(ctx, id)
+	if err != nil || email == nil {
+		return nil, err
+	}
+
+	if err := email.Resend(time.Now()); err != nil {
+		return nil, err
+	}
+
+	ok, err := q.repo.Update(ctx, email, domain.EmailStatusFailed)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("email was changed concurrently, please retry")
+	}
+
+	log.Printf("Email %s to %s queued for resending", email.ID, email.To)
+	return email, nil
+}
+
+func (q *MailQueue) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	q.cancel = cancel
+
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.work(ctx)
+	}
+
+	log.Printf("Mail queue started with %d workers", q.workers)
+}
+
+// Stop waits for the emails being sent to finish.
+func (q *MailQueue) Stop() {
+	if q.cancel == nil {
+		return
+	}
+	q.cancel()
+	q.wg.Wait()
+	log.Println("Mail queue stopped")
+}
+
+func (q *MailQueue) work(ctx context.Context) {
+	defer q.wg.Done()
+
+	for {
+		// Keep sending while emails are due, then wait for the next poll.
+		for ctx.Err() == nil && q.deliverNext(ctx) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(q.pollInterval):
+		}
+	}
+}
+
+// deliverNext sends one due email and reports whether there was one.
+func (q *MailQueue) deliverNext(ctx context.Context) bool {
+	now := time.Now()
+	email, err := q.repo.ClaimDue(ctx, now, now.Add(q.lease))
+	if err != nil {
+		log.Printf("Failed to claim queued email: %v", err)
+		return false
+	}
+	if email == nil {
+		return false
+	}
+
+	sendErr := q.sender.Send(email.To, &mail.Email{
+		Language: email.Language,
+		Subject:  email.Subject,
+		Text:     email.Text,
+		HTML:     email.HTML,
+	})
+
+	if sendErr != nil {
+		email.RecordFailure(sendErr, time.Now(), q.policy)
+	} else {
+		email.MarkSent(time.Now())
+	}
+
+	if _, err := q.repo.Update(ctx, email, domain.EmailStatusQueued); err != nil {
+		// The lease expires and the email is sent again: better twice than
+		// never.
+		log.Printf("Failed to record delivery of email %s: %v", email.ID, err)
+		return true
+	}
+
+	switch email.Status {
+	case domain.EmailStatusSent:
+		log.Printf("Sent %s email %s to %s", email.Template, email.ID, email.To)
+	case domain.EmailStatusFailed:
+		log.Printf("Giving up on email %s to %s after %d attempts: %v", email.ID, email.To, email.Attempts, sendErr)
+	default:
+		log.Printf("Email %s to %s failed (attempt %d), retrying at %s: %v",
+			email.ID, email.To, email.Attempts, email.NextAttemptAt.Format(time.RFC3339), sendErr)
+	}
+
+	return true
+}
@@ -0,0 +1,265 @@
+package application
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"user-service/internal/config"
+	"user-service/internal/domain"
+	"user-service/internal/infrastructure/mail"
+)
+
+// smtpStandIn is a minimal SMTP server, like a local Mailpit. It refuses the
+// first reject messages with a temporary error and keeps the rest.
+type smtpStandIn struct {
+	listener net.Listener
+	mu       sync.Mutex
+	reject   int
+	messages []string
+}
+
+func startSMTPStandIn(t *testing.T, reject int) *smtpStandIn {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &smtpStandIn{listener: listener, reject: reject}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpStandIn) config() *config.Config {
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	return &config.Config{SMTP: config.SMTPConfig{Host: host, Port: port, FromName: "KazakhDelivery", FromAddress: "noreply@kazakhdelivery.kz"}}
+}
+
+func (s *smtpStandIn) serve(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 stand-in ESMTP")
+
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch command {
+		case "EHLO", "HELO":
+			text.PrintfLine("250 stand-in")
+		case "MAIL":
+			s.mu.Lock()
+			rejected := s.reject > 0
+			if rejected {
+				s.reject--
+			}
+			s.mu.Unlock()
+			if rejected {
+				text.PrintfLine("451 mailbox temporarily unavailable")
+				continue
+			}
+			text.PrintfLine("250 OK")
+		case "RCPT", "RSET", "NOOP":
+			text.PrintfLine("250 OK")
+		case "DATA":
+			text.PrintfLine("354 go ahead")
+			data, err := readData(text.R)
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, data)
+			s.mu.Unlock()
+			text.PrintfLine("250 queued")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func readData(r *bufio.Reader) (string, error) {
+	var data strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		if line == ".\r\n" {
+			return data.String(), nil
+		}
+		data.WriteString(line)
+	}
+}
+
+func (s *smtpStandIn) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.messages...)
+}
+
+// fakeMailQueue keeps queued emails in memory.
+type fakeMailQueue struct {
+	mu     sync.Mutex
+	emails map[string]*domain.OutboundEmail
+	leases map[string]time.Time
+}
+
+func newFakeMailQueue() *fakeMailQueue {
+	return &fakeMailQueue{emails: make(map[string]*domain.OutboundEmail), leases: make(map[string]time.Time)}
+}
+
+func (r *fakeMailQueue) Enqueue(ctx context.Context, email *domain.OutboundEmail) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *email
+	r.emails[email.ID] = &stored
+	return nil
+}
+
+func (r *fakeMailQueue) GetByID(ctx context.Context, id string) (*domain.OutboundEmail, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if email, ok := r.emails[id]; ok {
+		clone := *email
+		return &clone, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeMailQueue) ClaimDue(ctx context.Context, now, leaseUntil time.Time) (*domain.OutboundEmail, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, email := range r.emails {
+		if email.Status != domain.EmailStatusQueued || email.NextAttemptAt.After(now) || r.leases[id].After(now) {
+			continue
+		}
+		r.leases[id] = leaseUntil
+		clone := *email
+		return &clone, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeMailQueue) Update(ctx context.Context, email *domain.OutboundEmail, from domain.EmailStatus) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.emails[email.ID]
+	if !ok || stored.Status != from {
+		return false, nil
+	}
+	clone := *email
+	r.emails[email.ID] = &clone
+	delete(r.leases, email.ID)
+	return true, nil
+}
+
+func (r *fakeMailQueue) List(ctx context.Context, status domain.EmailStatus, page, limit int) ([]*domain.OutboundEmail, int, error) {
+	return nil, 0, nil
+}
+
+func newTestMailQueue(repo *fakeMailQueue, server *smtpStandIn, maxAttempts int) *MailQueue {
+	// No backoff, so a failed email is due again right away.
+	return NewMailQueue(repo, mail.NewMailService(server.config()), config.MailQueueConfig{
+		Workers:     1,
+		Lease:       60,
+		MaxAttempts: maxAttempts,
+	})
+}
+
+func TestMailQueueRetriesUntilTheServerAccepts(t *testing.T) {
+	ctx := context.Background()
+	server := startSMTPStandIn(t, 1)
+	repo := newFakeMailQueue()
+	queue := newTestMailQueue(repo, server, 3)
+
+	email, err := queue.EnqueueTemplate(ctx, "aigerim@example.kz", string(domain.NotificationOrderCreated), domain.LanguageKK,
+		mail.TemplateData{Username: "Aigerim", OrderID: "order-1", Total: "3 500.00 KZT"})
+	if err != nil {
+		t.Fatalf("EnqueueTemplate: %v", err)
+	}
+
+	if !queue.deliverNext(ctx) {
+		t.Fatal("no email was due")
+	}
+	failed, _ := repo.GetByID(ctx, email.ID)
+	if failed.Status != domain.EmailStatusQueued || failed.Attempts != 1 || !strings.Contains(failed.LastError, "451") {
+		t.Fatalf("after a refused attempt the email is %s after %d attempts (%q)", failed.Status, failed.Attempts, failed.LastError)
+	}
+
+	if !queue.deliverNext(ctx) {
+		t.Fatal("refused email was not retried")
+	}
+	sent, _ := repo.GetByID(ctx, email.ID)
+	if sent.Status != domain.EmailStatusSent || sent.Attempts != 2 || sent.SentAt == nil {
+		t.Fatalf("email is %s after %d attempts, want sent after 2", sent.Status, sent.Attempts)
+	}
+	if queue.deliverNext(ctx) {
+		t.Fatal("sent email was delivered again")
+	}
+
+	messages := server.received()
+	if len(messages) != 1 {
+		t.Fatalf("server received %d messages, want 1", len(messages))
+	}
+	for _, want := range []string{"To: aigerim@example.kz", "Content-Language: kk", "multipart/alternative", "text/plain", "text/html"} {
+		if !strings.Contains(messages[0], want) {
+			t.Errorf("message lacks %q", want)
+		}
+	}
+}
+
+func TestMailQueueGivesUpAndResends(t *testing.T) {
+	ctx := context.Background()
+	server := startSMTPStandIn(t, 2)
+	repo := newFakeMailQueue()
+	queue := newTestMailQueue(repo, server, 2)
+
+	email, err := queue.EnqueueTemplate(ctx, "aigerim@example.kz", string(domain.NotificationOrderCancelled), domain.LanguageRU,
+		mail.TemplateData{Username: "Aigerim", OrderID: "order-1", Total: "3 500.00 KZT"})
+	if err != nil {
+		t.Fatalf("EnqueueTemplate: %v", err)
+	}
+
+	for queue.deliverNext(ctx) {
+	}
+	failed, _ := repo.GetByID(ctx, email.ID)
+	if failed.Status != domain.EmailStatusFailed || failed.Attempts != 2 {
+		t.Fatalf("email is %s after %d attempts, want failed after 2", failed.Status, failed.Attempts)
+	}
+
+	if _, err := queue.Resend(ctx, email.ID); err != nil {
+		t.Fatalf("Resend: %v", err)
+	}
+	if !queue.deliverNext(ctx) {
+		t.Fatal("resent email was not delivered")
+	}
+	if sent, _ := repo.GetByID(ctx, email.ID); sent.Status != domain.EmailStatusSent {
+		t.Fatalf("resent email is %s, want sent", sent.Status)
+	}
+	if got := len(server.received()); got != 1 {
+		t.Fatalf("server received %d messages, want 1", got)
+	}
+	if _, err := queue.Resend(ctx, email.ID); err == nil {
+		t.Fatal("resent an email that was sent")
+	}
+}
@@ -0,0 +1,181 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"user-service/internal/domain"
+	"user-service/internal/infrastructure/mail"
+	"user-service/internal/infrastructure/messaging"
+	"user-service/internal/infrastructure/notify"
+	"user-service/internal/infrastructure/persistence"
+)
+
+var notificationKinds = map[string]domain.NotificationKind{
+	messaging.SubjectOrderCreated:    domain.NotificationOrderCreated,
+	messaging.SubjectOrderConfirmed:  domain.NotificationOrderConfirmed,
+	messaging.SubjectOrderDispatched: domain.NotificationOrderDispatched,
+	messaging.SubjectOrderDelivered:  domain.NotificationOrderDelivered,
+	messaging.SubjectOrderCancelled:  domain.NotificationOrderCancelled,
+}
+
+type NotificationUseCase struct {
+	prefsRepo persistence.NotificationPreferencesRepository
+	userRepo  persistence.UserRepository
+	mailQueue *MailQueue
+	sms       notify.SMSProvider
+	push      notify.PushProvider
+}
+
+func NewNotificationUseCase(prefsRepo persistence.NotificationPreferencesRepository, userRepo persistence.UserRepository, mailQueue *MailQueue, sms notify.SMSProvider, push notify.PushProvider) *NotificationUseCase {
+	return &NotificationUseCase{
+		prefsRepo: prefsRepo,
+		userRepo:  userRepo,
+		mailQueue: mailQueue,
+		sms:       sms,
+		push:      push,
+	}
+}
+
+func (uc *NotificationUseCase) GetPreferences(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+
+	prefs, err := uc.prefsRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if prefs == nil {
+		prefs = domain.DefaultNotificationPreferences(userID)
+	}
+
+	return prefs, nil
+}
+
+func (uc *NotificationUseCase) UpdatePreferences(ctx context.Context, userID string, channels map[domain.Channel]bool, phone, deviceToken string) (*domain.NotificationPreferences, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	prefs, err := uc.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := prefs.Update(channels, phone, deviceToken); err != nil {
+		return nil, err
+	}
+	if err := uc.prefsRepo.Save(ctx, prefs); err != nil {
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+// HandleOrderEvent notifies the customer over every channel they opted into
+// that the service has a provider for. A failing channel does not stop the
+// others; the event is only reported as failed when the customer cannot be
+// looked up.
+func (uc *NotificationUseCase) HandleOrderEvent(ctx context.Context, subject string, event *messaging.OrderEvent) error {
+	kind, ok := notificationKinds[subject]
+	if !ok {
+		return fmt.Errorf("no notification for subject %s", subject)
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, event.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		log.Printf("Skipping %s notification for order %s: user %s not found", kind, event.OrderID, event.UserID)
+		return nil
+	}
+
+	prefs, err := uc.GetPreferences(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	email, err := mail.Render(string(kind), user.PreferredLanguage(), mail.TemplateData{
+		Username: user.Username,
+		OrderID:  event.OrderID,
+		Total:    event.Total.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, channel := range domain.Channels {
+		if !prefs.Enabled(channel) || !uc.configured(channel) {
+			continue
+		}
+		if err := uc.send(ctx, channel, user, prefs, kind, email); err != nil {
+			log.Printf("Failed to send %s notification for order %s over %s: %v", kind, event.OrderID, channel, err)
+			continue
+		}
+		log.Printf("Sent %s notification for order %s over %s", kind, event.OrderID, channel)
+	}
+
+	return nil
+}
+
+// PreviewEmail renders an email template with sample data so admins can
+// check a translation before it reaches customers.
+func (uc *NotificationUseCase) PreviewEmail(name, languageTag string) (*mail.Email, error) {
+	language, err := domain.ParseLanguage(languageTag)
+	if err != nil {
+		return nil, err
+	}
+
+	email, err := mail.Render(name, language, mail.SampleData())
+	if err != nil {
+		return nil, fmt.Errorf("%w (available: %s)", err, strings.Join(mail.TemplateNames(), ", "))
+	}
+
+	return email, nil
+}
+
+// configured reports whether the channel has a provider. Channels without one
+// are off.
+func (uc *NotificationUseCase) configured(channel domain.Channel) bool {
+	switch channel {
+	case domain.ChannelEmail:
+		return uc.mailQueue != nil
+	case domain.ChannelSMS:
+		return uc.sms != nil
+	case domain.ChannelPush:
+		return uc.push != nil
+	default:
+		return true
+	}
+}
+
+func (uc *NotificationUseCase) send(ctx context.Context, channel domain.Channel, user *domain.User, prefs *domain.NotificationPreferences, kind domain.NotificationKind, email *mail.Email) error {
+	switch channel {
+	case domain.ChannelEmail:
+		if uc.mailQueue == nil {
+			return errors.New("mail service is not configured")
+		}
+		_, err := uc.mailQueue.Enqueue(ctx, user.Email, string(kind), email)
+		return err
+	case domain.ChannelSMS:
+		if uc.sms == nil {
+			return errors.New("SMS provider is not configured")
+		}
+		return uc.sms.SendSMS(ctx, prefs.Phone, email.Summary)
+	case domain.ChannelPush:
+		if uc.push == nil {
+			return errors.New("push provider is not configured")
+		}
+		return uc.push.SendPush(ctx, prefs.DeviceToken, email.Subject, email.Summary)
+	default:
+		return fmt.Errorf("unknown notification channel: %s", channel)
+	}
+}
@@ -0,0 +1,117 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"user-service/internal/domain"
+	"user-service/internal/infrastructure/messaging"
+	"user-service/internal/infrastructure/persistence"
+
+	"proto/money"
+)
+
+type sentMessage struct {
+	To    string
+	Title string
+	Body  string
+}
+
+// recordingSMS and recordingPush keep what would have been sent.
+type recordingSMS struct {
+	mu   sync.Mutex
+	sent []sentMessage
+}
+
+func (p *recordingSMS) SendSMS(ctx context.Context, phone, text string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent = append(p.sent, sentMessage{To: phone, Body: text})
+	return nil
+}
+
+type recordingPush struct {
+	mu   sync.Mutex
+	sent []sentMessage
+}
+
+func (p *recordingPush) SendPush(ctx context.Context, deviceToken, title, body string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent = append(p.sent, sentMessage{To: deviceToken, Title: title, Body: body})
+	return nil
+}
+
+type fakeUsers struct {
+	persistence.UserRepository
+	users map[string]*domain.User
+}
+
+func (r *fakeUsers) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	return r.users[id], nil
+}
+
+type fakePreferences struct {
+	prefs map[string]*domain.NotificationPreferences
+}
+
+func (r *fakePreferences) Get(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+	return r.prefs[userID], nil
+}
+
+func (r *fakePreferences) Save(ctx context.Context, prefs *domain.NotificationPreferences) error {
+	r.prefs[prefs.UserID] = prefs
+	return nil
+}
+
+func newNotificationFixture() (*fakeUsers, *fakePreferences) {
+	users := &fakeUsers{users: map[string]*domain.User{
+		"u1": {ID: "u1", Username: "aigerim", Email: "aigerim@example.kz", Language: domain.LanguageRU},
+	}}
+	prefs := &fakePreferences{prefs: map[string]*domain.NotificationPreferences{
+		"u1": {
+			UserID:      "u1",
+			Channels:    map[domain.Channel]bool{domain.ChannelEmail: true, domain.ChannelSMS: true, domain.ChannelPush: true},
+			Phone:       "+77010000000",
+			DeviceToken: "device-1",
+		},
+	}}
+	return users, prefs
+}
+
+func TestHandleOrderEventSendsOverConfiguredChannels(t *testing.T) {
+	users, prefs := newNotificationFixture()
+	sms, push := &recordingSMS{}, &recordingPush{}
+	uc := NewNotificationUseCase(prefs, users, nil, sms, push)
+
+	event := &messaging.OrderEvent{OrderID: "o1", UserID: "u1", Total: money.KZT(150000)}
+	if err := uc.HandleOrderEvent(context.Background(), messaging.SubjectOrderCreated, event); err != nil {
+		t.Fatalf("HandleOrderEvent: %v", err)
+	}
+
+	if len(sms.sent) != 1 || sms.sent[0].To != "+77010000000" || sms.sent[0].Body == "" {
+		t.Fatalf("sms sent = %+v, want one message to the phone", sms.sent)
+	}
+	if len(push.sent) != 1 || push.sent[0].To != "device-1" || push.sent[0].Title == "" {
+		t.Fatalf("push sent = %+v, want one notification to the device", push.sent)
+	}
+}
+
+func TestHandleOrderEventLeavesUnconfiguredChannelsOff(t *testing.T) {
+	users, prefs := newNotificationFixture()
+	sms := &recordingSMS{}
+	uc := NewNotificationUseCase(prefs, users, nil, sms, nil)
+
+	event := &messaging.OrderEvent{OrderID: "o1", UserID: "u1", Total: money.KZT(150000)}
+	if err := uc.HandleOrderEvent(context.Background(), messaging.SubjectOrderCancelled, event); err != nil {
+		t.Fatalf("HandleOrderEvent: %v", err)
+	}
+
+	if len(sms.sent) != 1 {
+		t.Fatalf("sms sent = %d, want 1", len(sms.sent))
+	}
+	if uc.configured(domain.ChannelPush) || uc.configured(domain.ChannelEmail) {
+		t.Fatal("channels without a provider are configured")
+	}
+}
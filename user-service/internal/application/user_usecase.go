@@ -17,20 +17,20 @@ import (
 )
 
 type UserUseCase struct {
-	repo        persistence.UserRepository
-	cache       *database.RedisCache
-	mailService *mail.MailService
+	repo      persistence.UserRepository
+	cache     *database.RedisCache
+	mailQueue *MailQueue
 }
 
-func NewUserUseCase(repo persistence.UserRepository, cache *database.RedisCache, mailService *mail.MailService) *UserUseCase {
+func NewUserUseCase(repo persistence.UserRepository, cache *database.RedisCache, mailQueue *MailQueue) *UserUseCase {
 	return &UserUseCase{
-		repo:        repo,
-		cache:       cache,
-		mailService: mailService,
+		repo:      repo,
+		cache:     cache,
+		mailQueue: mailQueue,
 	}
 }
 
-func (uc *UserUseCase) RegisterUser(ctx context.Context, username, email, password string) (*domain.User, error) {
+func (uc *UserUseCase) RegisterUser(ctx context.Context, username, email, password, languageTag string) (*domain.User, error) {
 	if username == "" || email == "" || password == "" {
 		return nil, errors.New("username, email and password are required")
 	}
@@ -43,6 +43,11 @@ func (uc *UserUseCase) RegisterUser(ctx context.Context, username, email, passwo
 		return nil, errors.New("password must be at least 8 characters long")
 	}
 
+	language, err := domain.ParseLanguage(languageTag)
+	if err != nil {
+		return nil, err
+	}
+
 	existing, err := uc.repo.GetByUsername(ctx, username)
 	if err != nil {
 		return nil, err
@@ -59,7 +64,7 @@ func (uc *UserUseCase) RegisterUser(ctx context.Context, username, email, passwo
 		return nil, errors.New("email already exists")
 	}
 
-	user, err := domain.NewUser(username, email, password)
+	user, err := domain.NewUser(username, email, password, language)
 	if err != nil {
 		return nil, err
 	}
@@ -69,14 +74,12 @@ func (uc *UserUseCase) RegisterUser(ctx context.Context, username, email, passwo
 		return nil, err
 	}
 
-	if uc.mailService != nil {
-		go func() {
-			if err := uc.mailService.SendRegistrationConfirmation(email, username); err != nil {
-				log.Printf("Failed to send registration confirmation email: %v", err)
-			} else {
-				log.Printf("Registration confirmation email sent to %s", email)
-			}
-		}()
+	if uc.mailQueue != nil {
+		_, err := uc.mailQueue.EnqueueTemplate(ctx, email, "registration", user.PreferredLanguage(),
+			mail.TemplateData{Username: username})
+		if err != nil {
+			log.Printf("Failed to queue registration confirmation email: %v", err)
+		}
 	} else {
 		log.Println("Mail service is not configured, skipping registration confirmation email")
 	}
@@ -147,7 +150,7 @@ func (uc *UserUseCase) GetUserProfile(ctx context.Context, userID string) (*doma
 	return userProfile, nil
 }
 
-func (uc *UserUseCase) UpdateUser(ctx context.Context, userID, username, email string) (*domain.Profile, error) {
+func (uc *UserUseCase) UpdateUser(ctx context.Context, userID, username, email, languageTag string) (*domain.Profile, error) {
 	if userID == "" {
 		return nil, errors.New("user ID is required")
 	}
@@ -185,6 +188,14 @@ func (uc *UserUseCase) UpdateUser(ctx context.Context, userID, username, email s
 		user.Email = email
 	}
 
+	if languageTag != "" {
+		language, err := domain.ParseLanguage(languageTag)
+		if err != nil {
+			return nil, err
+		}
+		user.Language = language
+	}
+
 	updatedUser, err := uc.repo.Update(ctx, user)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,145 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"user-service/internal/config"
+	"user-service/internal/domain"
+	"user-service/internal/infrastructure/persistence"
+	"user-service/internal/infrastructure/webhook"
+)
+
+// WebhookDispatcher sends queued webhook deliveries from a pool of workers
+// and retries failed ones with exponential backoff.
+type WebhookDispatcher struct {
+	subscriptionRepo persistence.WebhookSubscriptionRepository
+	deliveryRepo     persistence.WebhookDeliveryRepository
+	sender           webhook.Sender
+	policy           domain.RetryPolicy
+	workers          int
+	pollInterval     time.Duration
+	lease            time.Duration
+	cancel           context.CancelFunc
+	wg               sync.WaitGroup
+}
+
+func NewWebhookDispatcher(subscriptionRepo persistence.WebhookSubscriptionRepository, deliveryRepo persistence.WebhookDeliveryRepository, sender webhook.Sender, cfg config.WebhookConfig) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		subscriptionRepo: subscriptionRepo,
+		deliveryRepo:     deliveryRepo,
+		sender:           sender,
+		policy: domain.RetryPolicy{
+			MaxAttempts: cfg.MaxAttempts,
+			BaseDelay:   time.Duration(cfg.BaseDelay) * time.Second,
+			MaxDelay:    time.Duration(cfg.MaxDelay) * time.Second,
+		},
+		workers:      cfg.Workers,
+		pollInterval: time.Duration(cfg.PollInterval) * time.Second,
+		lease:        time.Duration(cfg.Lease) * time.Second,
+	}
+}
+
+func (d *WebhookDispatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+
+	for i := 0; i < d.workers; i++ {
+		d.wg.Add(1)
+		go d.work(ctx)
+	}
+
+	log.Printf("Webhook dispatcher started with %d workers", d.workers)
+}
+
+// Stop waits for the requests in flight to finish.
+func (d *WebhookDispatcher) Stop() {
+	if d.cancel == nil {
+		return
+	}
+	d.cancel()
+	d.wg.Wait()
+	log.Println("Webhook dispatcher stopped")
+}
+
+func (d *WebhookDispatcher) work(ctx context.Context) {
+	defer d.wg.Done()
+
+	for {
+		for ctx.Err() == nil && d.deliverNext(ctx) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(d.pollInterval):
+		}
+	}
+}
+
+// deliverNext sends one due delivery and reports whether there was one.
+func (d *WebhookDispatcher) deliverNext(ctx context.Context) bool {
+	now := time.Now()
+	delivery, err := d.deliveryRepo.ClaimDue(ctx, now, now.Add(d.lease))
+	if err != nil {
+		log.Printf("Failed to claim webhook delivery: %v", err)
+		return false
+	}
+	if delivery == nil {
+		return false
+	}
+
+	subscription, err := d.subscriptionRepo.GetByID(ctx, delivery.SubscriptionID)
+	if err != nil {
+		// The lease expires and the delivery is tried again.
+		log.Printf("Failed to load webhook subscription %s: %v", delivery.SubscriptionID, err)
+		return true
+	}
+
+	policy := d.policy
+	var attempt domain.WebhookAttempt
+	if subscription == nil {
+		// Nothing left to retry against.
+		policy = domain.RetryPolicy{}
+		attempt = domain.WebhookAttempt{At: time.Now(), Error: "webhook subscription was deleted"}
+	} else {
+		attempt = d.attempt(ctx, subscription, delivery)
+	}
+	delivery.RecordAttempt(attempt, policy)
+
+	if _, err := d.deliveryRepo.Update(ctx, delivery, domain.WebhookDeliveryPending); err != nil {
+		log.Printf("Failed to record webhook delivery %s: %v", delivery.ID, err)
+		return true
+	}
+
+	switch delivery.Status {
+	case domain.WebhookDeliveryDelivered:
+		log.Printf("Delivered %s webhook %s to subscription %s", delivery.EventType, delivery.ID, delivery.SubscriptionID)
+	case domain.WebhookDeliveryFailed:
+		log.Printf("Giving up on webhook %s after %d attempts: %s", delivery.ID, delivery.Attempts, attempt.Error)
+	default:
+		log.Printf("Webhook %s failed (attempt %d), retrying at %s: %s",
+			delivery.ID, delivery.Attempts, delivery.NextAttemptAt.Format(time.RFC3339), attempt.Error)
+	}
+
+	return true
+}
+
+func (d *WebhookDispatcher) attempt(ctx context.Context, subscription *domain.WebhookSubscription, delivery *domain.WebhookDelivery) domain.WebhookAttempt {
+	start := time.Now()
+	attempt := domain.WebhookAttempt{At: start}
+
+	statusCode, err := d.sender.Send(ctx, subscription, delivery)
+	attempt.StatusCode = statusCode
+	if err != nil {
+		attempt.Error = err.Error()
+	} else if !attempt.Succeeded() {
+		attempt.Error = fmt.Sprintf("unexpected status code %d", statusCode)
+	}
+
+	attempt.Duration = time.Since(start)
+	return attempt
+}
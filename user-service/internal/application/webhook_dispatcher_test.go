@@ -0,0 +1,269 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"user-service/internal/config"
+	"user-service/internal/domain"
+	"user-service/internal/infrastructure/webhook"
+)
+
+// webhookReceiver is a partner endpoint that checks signatures and answers
+// the first failures requests with 503.
+type webhookReceiver struct {
+	secret   string
+	mu       sync.Mutex
+	failures int
+	requests []webhook.Payload
+	rejected int
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := webhook.Verify(r.secret, req.Header.Get(webhook.HeaderSignature), body, 5*time.Minute, time.Now()); err != nil {
+		r.rejected++
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var payload webhook.Payload
+	if err := json.Unmarshal(body, &payload); err != nil || payload.ID != req.Header.Get(webhook.HeaderID) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.requests = append(r.requests, payload)
+
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type fakeWebhookSubscriptions struct {
+	mu            sync.Mutex
+	subscriptions map[string]*domain.WebhookSubscription
+}
+
+func (r *fakeWebhookSubscriptions) Create(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscriptions[subscription.ID] = subscription
+	return nil
+}
+
+func (r *fakeWebhookSubscriptions) GetByID(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.subscriptions[id], nil
+}
+
+func (r *fakeWebhookSubscriptions) List(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var subscriptions []*domain.WebhookSubscription
+	for _, subscription := range r.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, nil
+}
+
+func (r *fakeWebhookSubscriptions) ListByEventType(ctx context.Context, eventType string) ([]*domain.WebhookSubscription, error) {
+	all, _ := r.List(ctx)
+	var subscriptions []*domain.WebhookSubscription
+	for _, subscription := range all {
+		for _, subscribed := range subscription.EventTypes {
+			if subscribed == eventType {
+				subscriptions = append(subscriptions, subscription)
+			}
+		}
+	}
+	return subscriptions, nil
+}
+
+func (r *fakeWebhookSubscriptions) Delete(ctx context.Context, id string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.subscriptions[id]
+	delete(r.subscriptions, id)
+	return ok, nil
+}
+
+type fakeWebhookDeliveries struct {
+	mu         sync.Mutex
+	deliveries map[string]*domain.WebhookDelivery
+	leases     map[string]time.Time
+}
+
+func cloneDelivery(delivery *domain.WebhookDelivery) *domain.WebhookDelivery {
+	clone := *delivery
+	clone.Log = append([]domain.WebhookAttempt(nil), delivery.Log...)
+	return &clone
+}
+
+func (r *fakeWebhookDeliveries) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries[delivery.ID] = cloneDelivery(delivery)
+	return nil
+}
+
+func (r *fakeWebhookDeliveries) GetByID(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if delivery, ok := r.deliveries[id]; ok {
+		return cloneDelivery(delivery), nil
+	}
+	return nil, nil
+}
+
+func (r *fakeWebhookDeliveries) ClaimDue(ctx context.Context, now, leaseUntil time.Time) (*domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, delivery := range r.deliveries {
+		if delivery.Status != domain.WebhookDeliveryPending || delivery.NextAttemptAt.After(now) || r.leases[id].After(now) {
+			continue
+		}
+		r.leases[id] = leaseUntil
+		return cloneDelivery(delivery), nil
+	}
+	return nil, nil
+}
+
+func (r *fakeWebhookDeliveries) Update(ctx context.Context, delivery *domain.WebhookDelivery, from domain.WebhookDeliveryStatus) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.deliveries[delivery.ID]
+	if !ok || stored.Status != from {
+		return false, nil
+	}
+	r.deliveries[delivery.ID] = cloneDelivery(delivery)
+	delete(r.leases, delivery.ID)
+	return true, nil
+}
+
+func (r *fakeWebhookDeliveries) List(ctx context.Context, subscriptionID string, status domain.WebhookDeliveryStatus, page, limit int) ([]*domain.WebhookDelivery, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deliveries []*domain.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.SubscriptionID == subscriptionID && (status == "" || delivery.Status == status) {
+			deliveries = append(deliveries, cloneDelivery(delivery))
+		}
+	}
+	return deliveries, len(deliveries), nil
+}
+
+type webhookFixture struct {
+	receiver   *webhookReceiver
+	deliveries *fakeWebhookDeliveries
+	uc         *WebhookUseCase
+	dispatcher *WebhookDispatcher
+	url        string
+}
+
+func newWebhookFixture(t *testing.T, failures, maxAttempts int) *webhookFixture {
+	t.Helper()
+	receiver := &webhookReceiver{secret: "whsec_test", failures: failures}
+	server := httptest.NewServer(receiver)
+	t.Cleanup(server.Close)
+
+	subscriptions := &fakeWebhookSubscriptions{subscriptions: make(map[string]*domain.WebhookSubscription)}
+	deliveries := &fakeWebhookDeliveries{deliveries: make(map[string]*domain.WebhookDelivery), leases: make(map[string]time.Time)}
+	// No backoff, so a failed delivery is due again right away.
+	dispatcher := NewWebhookDispatcher(subscriptions, deliveries, webhook.NewHTTPSender(5*time.Second),
+		config.WebhookConfig{Workers: 1, Lease: 60, MaxAttempts: maxAttempts})
+
+	return &webhookFixture{
+		receiver:   receiver,
+		deliveries: deliveries,
+		uc:         NewWebhookUseCase(subscriptions, deliveries),
+		dispatcher: dispatcher,
+		url:        server.URL,
+	}
+}
+
+func (f *webhookFixture) queue(t *testing.T, secret string) *domain.WebhookDelivery {
+	t.Helper()
+	ctx := context.Background()
+	subscription, err := f.uc.CreateSubscription(ctx, f.url, []string{"order.created"}, secret)
+	if err != nil {
+		t.Fatalf("CreateSubscription: %v", err)
+	}
+	if err := f.uc.HandleEvent(ctx, "order.created", []byte(`{"order_id":"order-1"}`)); err != nil {
+		t.Fatalf("HandleEvent: %v", err)
+	}
+	if err := f.uc.HandleEvent(ctx, "stock.changed", []byte(`{"product_id":"tea"}`)); err != nil {
+		t.Fatalf("HandleEvent: %v", err)
+	}
+
+	deliveries, _, _ := f.deliveries.List(ctx, subscription.ID, "", 1, 10)
+	if len(deliveries) != 1 {
+		t.Fatalf("queued %d deliveries, want only the subscribed event", len(deliveries))
+	}
+	return deliveries[0]
+}
+
+func TestWebhookIsSignedAndRetriedUntilDelivered(t *testing.T) {
+	ctx := context.Background()
+	f := newWebhookFixture(t, 2, 5)
+	queued := f.queue(t, f.receiver.secret)
+
+	for f.dispatcher.deliverNext(ctx) {
+	}
+
+	delivery, _ := f.deliveries.GetByID(ctx, queued.ID)
+	if delivery.Status != domain.WebhookDeliveryDelivered || delivery.Attempts != 3 {
+		t.Fatalf("delivery is %s after %d attempts, want delivered after 3", delivery.Status, delivery.Attempts)
+	}
+	if len(delivery.Log) != 3 || delivery.Log[0].StatusCode != http.StatusServiceUnavailable || delivery.Log[2].StatusCode != http.StatusNoContent {
+		t.Fatalf("attempt log = %+v, want two 503s and a 204", delivery.Log)
+	}
+
+	if f.receiver.rejected != 0 {
+		t.Fatalf("receiver rejected %d signatures", f.receiver.rejected)
+	}
+	for _, payload := range f.receiver.requests {
+		if payload.ID != queued.ID || payload.Type != "order.created" || string(payload.Data) != `{"order_id":"order-1"}` {
+			t.Fatalf("receiver got %+v, want the same delivery on every attempt", payload)
+		}
+	}
+}
+
+func TestWebhookWithWrongSecretFailsAndCanBeReplayed(t *testing.T) {
+	ctx := context.Background()
+	f := newWebhookFixture(t, 0, 2)
+	queued := f.queue(t, "whsec_other")
+
+	for f.dispatcher.deliverNext(ctx) {
+	}
+
+	delivery, _ := f.deliveries.GetByID(ctx, queued.ID)
+	if delivery.Status != domain.WebhookDeliveryFailed || delivery.Attempts != 2 {
+		t.Fatalf("delivery is %s after %d attempts, want failed after 2", delivery.Status, delivery.Attempts)
+	}
+	if f.receiver.rejected != 2 || len(f.receiver.requests) != 0 {
+		t.Fatalf("receiver rejected %d and accepted %d requests, want 2 rejected", f.receiver.rejected, len(f.receiver.requests))
+	}
+
+	if _, err := f.uc.ReplayDelivery(ctx, queued.ID); err != nil {
+		t.Fatalf("ReplayDelivery: %v", err)
+	}
+	if _, err := f.uc.ReplayDelivery(ctx, queued.ID); err == nil {
+		t.Fatal("replayed a delivery that is still pending")
+	}
+	f.dispatcher.deliverNext(ctx)
+	if replayed, _ := f.deliveries.GetByID(ctx, queued.ID); len(replayed.Log) != 3 {
+		t.Fatalf("replay kept %d attempts in the log, want all 3", len(replayed.Log))
+	}
+}
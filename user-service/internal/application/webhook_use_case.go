@@ -0,0 +1,112 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"user-service/internal/domain"
+	"user-service/internal/infrastructure/persistence"
+)
+
+type WebhookUseCase struct {
+	subscriptionRepo persistence.WebhookSubscriptionRepository
+	deliveryRepo     persistence.WebhookDeliveryRepository
+}
+
+func NewWebhookUseCase(subscriptionRepo persistence.WebhookSubscriptionRepository, deliveryRepo persistence.WebhookDeliveryRepository) *WebhookUseCase {
+	return &WebhookUseCase{
+		subscriptionRepo: subscriptionRepo,
+		deliveryRepo:     deliveryRepo,
+	}
+}
+
+func (uc *WebhookUseCase) CreateSubscription(ctx context.Context, url string, eventTypes []string, secret string) (*domain.WebhookSubscription, error) {
+	subscription, err := domain.NewWebhookSubscription(url, eventTypes, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.subscriptionRepo.Create(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Created webhook subscription %s to %s for %v", subscription.ID, subscription.URL, subscription.EventTypes)
+	return subscription, nil
+}
+
+func (uc *WebhookUseCase) ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	return uc.subscriptionRepo.List(ctx)
+}
+
+// DeleteSubscription stops new deliveries. Pending ones fail on their next
+// attempt.
+func (uc *WebhookUseCase) DeleteSubscription(ctx context.Context, id string) error {
+	deleted, err := uc.subscriptionRepo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("webhook subscription not found")
+	}
+
+	log.Printf("Deleted webhook subscription %s", id)
+	return nil
+}
+
+// HandleEvent queues a delivery of the event for every subscription to its
+// subject.
+func (uc *WebhookUseCase) HandleEvent(ctx context.Context, subject string, data []byte) error {
+	subscriptions, err := uc.subscriptionRepo.ListByEventType(ctx, subject)
+	if err != nil {
+		return err
+	}
+
+	for _, subscription := range subscriptions {
+		delivery := domain.NewWebhookDelivery(subscription.ID, subject, data)
+		if err := uc.deliveryRepo.Create(ctx, delivery); err != nil {
+			return err
+		}
+	}
+
+	if len(subscriptions) > 0 {
+		log.Printf("Queued %s webhook for %d subscriptions", subject, len(subscriptions))
+	}
+	return nil
+}
+
+func (uc *WebhookUseCase) ListDeliveries(ctx context.Context, subscriptionID, status string, page, limit int) ([]*domain.WebhookDelivery, int, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	return uc.deliveryRepo.List(ctx, subscriptionID, domain.WebhookDeliveryStatus(status), page, limit)
+}
+
+// ReplayDelivery sends a delivered or failed webhook again.
+func (uc *WebhookUseCase) ReplayDelivery(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	delivery, err := uc.deliveryRepo.GetByID(ctx, id)
+	if err != nil || delivery == nil {
+		return nil, err
+	}
+
+	from := delivery.Status
+	if err := delivery.Replay(time.Now()); err != nil {
+		return nil, err
+	}
+
+	ok, err := uc.deliveryRepo.Update(ctx, delivery, from)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("webhook delivery was changed concurrently, please retry")
+	}
+
+	log.Printf("Webhook delivery %s queued for replay", delivery.ID)
+	return delivery, nil
+}
@@ -33,13 +33,76 @@ type SMTPConfig struct {
 	Username string `yaml:"username"`
 	Password string `yaml:"password"`
 	FromName string `yaml:"from_name"`
+	// FromAddress defaults to Username. Set it when a local SMTP server
+	// without authentication is used.
+	FromAddress string `yaml:"from_address"`
+}
+
+// SMSConfig points at the SMS gateway. SMS notifications are off while
+// GatewayURL is empty.
+type SMSConfig struct {
+	GatewayURL string `yaml:"gateway_url"`
+	APIKey     string `yaml:"api_key"`
+	Sender     string `yaml:"sender"`
+	// Timeout of a gateway request, in seconds.
+	Timeout int `yaml:"timeout"`
+}
+
+// PushConfig points at the push gateway. Push notifications are off while
+// GatewayURL is empty.
+type PushConfig struct {
+	GatewayURL string `yaml:"gateway_url"`
+	APIKey     string `yaml:"api_key"`
+	// Timeout of a gateway request, in seconds.
+	Timeout int `yaml:"timeout"`
+}
+
+// MailQueueConfig tunes the outbound mail queue. Durations are in seconds.
+type MailQueueConfig struct {
+	Workers      int `yaml:"workers"`
+	PollInterval int `yaml:"poll_interval"`
+	Lease        int `yaml:"lease"`
+	MaxAttempts  int `yaml:"max_attempts"`
+	BaseDelay    int `yaml:"base_delay"`
+	MaxDelay     int `yaml:"max_delay"`
+}
+
+// WebhookConfig tunes the delivery of partner webhooks. Durations are in
+// seconds.
+type WebhookConfig struct {
+	Workers      int `yaml:"workers"`
+	PollInterval int `yaml:"poll_interval"`
+	Lease        int `yaml:"lease"`
+	Timeout      int `yaml:"timeout"`
+	MaxAttempts  int `yaml:"max_attempts"`
+	BaseDelay    int `yaml:"base_delay"`
+	MaxDelay     int `yaml:"max_delay"`
+}
+
+// NATSConfig also tunes the JetStream consumers. AckWait and Backoff are in
+// seconds.
+type NATSConfig struct {
+	URL string `yaml:"url"`
+	// Durable prefixes the consumer names, so that a restarted service
+	// resumes where it stopped.
+	Durable    string `yaml:"durable"`
+	MaxDeliver int    `yaml:"max_deliver"`
+	AckWait    int    `yaml:"ack_wait"`
+	// Backoff are the delays before the redeliveries of a failed event. The
+	// last one repeats.
+	Backoff []int `yaml:"backoff"`
 }
 
 type Config struct {
-	Server  ServerConfig  `yaml:"server"`
-	MongoDB MongoDBConfig `yaml:"mongodb"`
-	Redis   RedisConfig   `yaml:"redis"`
-	SMTP    SMTPConfig    `yaml:"smtp"`
+	Server    ServerConfig    `yaml:"server"`
+	MongoDB   MongoDBConfig   `yaml:"mongodb"`
+	Redis     RedisConfig     `yaml:"redis"`
+	SMTP      SMTPConfig      `yaml:"smtp"`
+	SMS       SMSConfig       `yaml:"sms"`
+	Push      PushConfig      `yaml:"push"`
+	NATS      NATSConfig      `yaml:"nats"`
+	MailQueue MailQueueConfig `yaml:"mail_queue"`
+	Webhooks  WebhookConfig   `yaml:"webhooks"`
 }
 
 func LoadConfig() *Config {
@@ -71,11 +134,47 @@ func LoadConfig() *Config {
 			TTL:      300,
 		},
 		SMTP: SMTPConfig{
-			Host:     os.Getenv("SMTP_HOST"),
-			Port:     os.Getenv("SMTP_PORT"),
-			Username: os.Getenv("SMTP_USERNAME"),
-			Password: os.Getenv("SMTP_PASSWORD"),
-			FromName: os.Getenv("SMTP_FROM_NAME"),
+			Host:        os.Getenv("SMTP_HOST"),
+			Port:        os.Getenv("SMTP_PORT"),
+			Username:    os.Getenv("SMTP_USERNAME"),
+			Password:    os.Getenv("SMTP_PASSWORD"),
+			FromName:    os.Getenv("SMTP_FROM_NAME"),
+			FromAddress: os.Getenv("SMTP_FROM_ADDRESS"),
+		},
+		SMS: SMSConfig{
+			GatewayURL: os.Getenv("SMS_GATEWAY_URL"),
+			APIKey:     os.Getenv("SMS_API_KEY"),
+			Sender:     os.Getenv("SMS_SENDER"),
+			Timeout:    10,
+		},
+		Push: PushConfig{
+			GatewayURL: os.Getenv("PUSH_GATEWAY_URL"),
+			APIKey:     os.Getenv("PUSH_API_KEY"),
+			Timeout:    10,
+		},
+		NATS: NATSConfig{
+			URL:        "nats://localhost:4222",
+			Durable:    "user-service",
+			MaxDeliver: 5,
+			AckWait:    60,
+			Backoff:    []int{1, 5, 30, 120},
+		},
+		MailQueue: MailQueueConfig{
+			Workers:      4,
+			PollInterval: 2,
+			Lease:        60,
+			MaxAttempts:  8,
+			BaseDelay:    30,
+			MaxDelay:     3600,
+		},
+		Webhooks: WebhookConfig{
+			Workers:      4,
+			PollInterval: 2,
+			Lease:        60,
+			Timeout:      10,
+			MaxAttempts:  10,
+			BaseDelay:    10,
+			MaxDelay:     3600,
 		},
 	}
 }
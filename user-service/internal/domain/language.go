@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Language is the language a user receives emails and notifications in.
+type Language string
+
+const (
+	LanguageKK Language = "kk"
+	LanguageRU Language = "ru"
+	LanguageEN Language = "en"
+)
+
+// DefaultLanguage is used for users who have not picked one.
+const DefaultLanguage = LanguageRU
+
+var Languages = []Language{LanguageKK, LanguageRU, LanguageEN}
+
+// ParseLanguage maps a language tag such as "kk-KZ" to a supported language.
+// An empty tag gives the default language.
+func ParseLanguage(tag string) (Language, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return DefaultLanguage, nil
+	}
+	for _, l := range Languages {
+		if tag == string(l) || strings.HasPrefix(tag, string(l)+"-") {
+			return l, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported language: %s", tag)
+}
+
+// PreferredLanguage returns the user's language, or the default one.
+func (u *User) PreferredLanguage() Language {
+	if u.Language == "" {
+		return DefaultLanguage
+	}
+	return u.Language
+}
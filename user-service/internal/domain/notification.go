@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Channel is a way of reaching a customer.
+type Channel string
+
+const (
+	ChannelEmail Channel = "email"
+	ChannelSMS   Channel = "sms"
+	ChannelPush  Channel = "push"
+)
+
+var Channels = []Channel{ChannelEmail, ChannelSMS, ChannelPush}
+
+// NotificationKind names the order lifecycle step a notification is about.
+type NotificationKind string
+
+const (
+	NotificationOrderCreated    NotificationKind = "order_created"
+	NotificationOrderConfirmed  NotificationKind = "order_confirmed"
+	NotificationOrderDispatched NotificationKind = "order_dispatched"
+	NotificationOrderDelivered  NotificationKind = "order_delivered"
+	NotificationOrderCancelled  NotificationKind = "order_cancelled"
+)
+
+// NotificationPreferences holds the channels a user opted into and the
+// addresses needed to reach them. Email is on until the user opts out; SMS
+// and push need a phone number or device token first.
+type NotificationPreferences struct {
+	UserID      string
+	Channels    map[Channel]bool
+	Phone       string
+	DeviceToken string
+	UpdatedAt   time.Time
+}
+
+func DefaultNotificationPreferences(userID string) *NotificationPreferences {
+	return &NotificationPreferences{
+		UserID:   userID,
+		Channels: map[Channel]bool{ChannelEmail: true},
+	}
+}
+
+func (p *NotificationPreferences) Enabled(channel Channel) bool {
+	return p.Channels[channel]
+}
+
+// Update replaces the preferences, checking that every enabled channel can be
+// reached.
+func (p *NotificationPreferences) Update(channels map[Channel]bool, phone, deviceToken string) error {
+	for channel, enabled := range channels {
+		if channel != ChannelEmail && channel != ChannelSMS && channel != ChannelPush {
+			return fmt.Errorf("unknown notification channel: %s", channel)
+		}
+		if enabled && channel == ChannelSMS && phone == "" {
+			return fmt.Errorf("a phone number is required for %s notifications", channel)
+		}
+		if enabled && channel == ChannelPush && deviceToken == "" {
+			return fmt.Errorf("a device token is required for %s notifications", channel)
+		}
+	}
+
+	p.Channels = channels
+	p.Phone = phone
+	p.DeviceToken = deviceToken
+	p.UpdatedAt = time.Now()
+	return nil
+}
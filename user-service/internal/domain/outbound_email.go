@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EmailStatus string
+
+const (
+	EmailStatusQueued EmailStatus = "queued"
+	EmailStatusSent   EmailStatus = "sent"
+	// EmailStatusFailed is final until an admin resends the email.
+	EmailStatusFailed EmailStatus = "failed"
+)
+
+// RetryPolicy spaces out delivery attempts exponentially: BaseDelay after the
+// first failure, doubling up to MaxDelay, until MaxAttempts have failed.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// OutboundEmail is a rendered email waiting in the mail queue.
+type OutboundEmail struct {
+	ID            string
+	To            string
+	Template      string
+	Language      Language
+	Subject       string
+	Text          string
+	HTML          string
+	Status        EmailStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	SentAt        *time.Time
+}
+
+func NewOutboundEmail(to, template string, language Language, subject, text, html string) *OutboundEmail {
+	now := time.Now()
+	return &OutboundEmail{
+		ID:            uuid.New().String(),
+		To:            to,
+		Template:      template,
+		Language:      language,
+		Subject:       subject,
+		Text:          text,
+		HTML:          html,
+		Status:        EmailStatusQueued,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func (e *OutboundEmail) MarkSent(now time.Time) {
+	e.Attempts++
+	e.Status = EmailStatusSent
+	e.LastError = ""
+	e.SentAt = &now
+	e.UpdatedAt = now
+}
+
+// RecordFailure schedules the next attempt, or gives up once the policy's
+// attempts are used up.
+func (e *OutboundEmail) RecordFailure(err error, now time.Time, policy RetryPolicy) {
+	e.Attempts++
+	e.LastError = err.Error()
+	e.UpdatedAt = now
+
+	if e.Attempts >= policy.MaxAttempts {
+		e.Status = EmailStatusFailed
+		return
+	}
+	e.NextAttemptAt = now.Add(policy.Backoff(e.Attempts))
+}
+
+// Resend puts a failed email back in the queue with a fresh set of attempts.
+func (e *OutboundEmail) Resend(now time.Time) error {
+	if e.Status != EmailStatusFailed {
+		return fmt.Errorf("email %s cannot be resent in status %s", e.ID, e.Status)
+	}
+
+	e.Status = EmailStatusQueued
+	e.Attempts = 0
+	e.NextAttemptAt = now
+	e.UpdatedAt = now
+	return nil
+}
@@ -12,10 +12,11 @@ type User struct {
 	Username  string
 	Email     string
 	Password  string
+	Language  Language
 	CreatedAt time.Time
 }
 
-func NewUser(username, email, password string) (*User, error) {
+func NewUser(username, email, password string, language Language) (*User, error) {
 	hashedPassword, err := hashPassword(password)
 	if err != nil {
 		return nil, err
@@ -26,6 +27,7 @@ func NewUser(username, email, password string) (*User, error) {
 		Username:  username,
 		Email:     email,
 		Password:  hashedPassword,
+		Language:  language,
 		CreatedAt: time.Now(),
 	}, nil
 }
@@ -48,6 +50,7 @@ func (u *User) ToProfile() *Profile {
 		ID:       u.ID,
 		Username: u.Username,
 		Email:    u.Email,
+		Language: u.PreferredLanguage(),
 	}
 }
 
@@ -55,4 +58,5 @@ type Profile struct {
 	ID       string
 	Username string
 	Email    string
+	Language Language
 }
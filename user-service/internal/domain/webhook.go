@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEventTypes are the NATS subjects partners can subscribe to.
+var WebhookEventTypes = []string{
+	"order.created",
+	"order.confirmed",
+	"order.dispatched",
+	"order.delivered",
+	"order.cancelled",
+	"order.refunded",
+	"stock.changed",
+}
+
+// WebhookSubscription sends the events of EventTypes to URL, signed with
+// Secret.
+type WebhookSubscription struct {
+	ID         string
+	URL        string
+	EventTypes []string
+	Secret     string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// NewWebhookSubscription generates a secret when none is given.
+func NewWebhookSubscription(rawURL string, eventTypes []string, secret string) (*WebhookSubscription, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, errors.New("webhook URL must be an absolute http or https URL")
+	}
+
+	if len(eventTypes) == 0 {
+		return nil, errors.New("at least one event type is required")
+	}
+	seen := make(map[string]bool, len(eventTypes))
+	var types []string
+	for _, eventType := range eventTypes {
+		if !isWebhookEventType(eventType) {
+			return nil, fmt.Errorf("unknown event type: %s", eventType)
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			types = append(types, eventType)
+		}
+	}
+
+	if secret == "" {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		secret = "whsec_" + hex.EncodeToString(buf)
+	}
+
+	now := time.Now()
+	return &WebhookSubscription{
+		ID:         uuid.New().String(),
+		URL:        rawURL,
+		EventTypes: types,
+		Secret:     secret,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+func isWebhookEventType(eventType string) bool {
+	for _, known := range WebhookEventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryFailed is final until an admin replays the delivery.
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+// WebhookAttempt records one HTTP request of a delivery. StatusCode is 0 when
+// no response was received.
+type WebhookAttempt struct {
+	At         time.Time
+	StatusCode int
+	Error      string
+	Duration   time.Duration
+}
+
+func (a WebhookAttempt) Succeeded() bool {
+	return a.Error == "" && a.StatusCode >= 200 && a.StatusCode < 300
+}
+
+// WebhookDelivery is one event on its way to one subscription. Data is the
+// event as published on NATS.
+type WebhookDelivery struct {
+	ID             string
+	SubscriptionID string
+	EventType      string
+	Data           []byte
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	Log            []WebhookAttempt
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+func NewWebhookDelivery(subscriptionID, eventType string, data []byte) *WebhookDelivery {
+	now := time.Now()
+	return &WebhookDelivery{
+		ID:             uuid.New().String(),
+		SubscriptionID: subscriptionID,
+		EventType:      eventType,
+		Data:           data,
+		Status:         WebhookDeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// RecordAttempt logs the attempt and either completes the delivery, schedules
+// a retry or gives up once the policy's attempts are used up.
+func (d *WebhookDelivery) RecordAttempt(attempt WebhookAttempt, policy RetryPolicy) {
+	d.Attempts++
+	d.Log = append(d.Log, attempt)
+	d.UpdatedAt = attempt.At
+
+	switch {
+	case attempt.Succeeded():
+		d.Status = WebhookDeliveryDelivered
+		d.DeliveredAt = &attempt.At
+	case d.Attempts >= policy.MaxAttempts:
+		d.Status = WebhookDeliveryFailed
+	default:
+		d.NextAttemptAt = attempt.At.Add(policy.Backoff(d.Attempts))
+	}
+}
+
+// Replay sends a finished delivery again with a fresh set of attempts. The
+// attempt log is kept.
+func (d *WebhookDelivery) Replay(now time.Time) error {
+	if d.Status == WebhookDeliveryPending {
+		return fmt.Errorf("webhook delivery %s is still pending", d.ID)
+	}
+
+	d.Status = WebhookDeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = now
+	d.DeliveredAt = nil
+	d.UpdatedAt = now
+	return nil
+}
@@ -10,6 +10,7 @@ type UserDTO struct {
 	Username  string    `bson:"username"`
 	Email     string    `bson:"email"`
 	Password  string    `bson:"password"`
+	Language  string    `bson:"language,omitempty"`
 	CreatedAt time.Time `bson:"created_at"`
 }
 
@@ -23,3 +24,60 @@ func NewInMemoryDB() *InMemoryDB {
 		Users: make(map[string]*UserDTO),
 	}
 }
+
+type NotificationPreferencesDTO struct {
+	UserID      string          `bson:"_id"`
+	Channels    map[string]bool `bson:"channels"`
+	Phone       string          `bson:"phone,omitempty"`
+	DeviceToken string          `bson:"device_token,omitempty"`
+	UpdatedAt   time.Time       `bson:"updated_at"`
+}
+
+type OutboundEmailDTO struct {
+	ID            string     `bson:"_id"`
+	To            string     `bson:"to"`
+	Template      string     `bson:"template"`
+	Language      string     `bson:"language"`
+	Subject       string     `bson:"subject"`
+	Text          string     `bson:"text"`
+	HTML          string     `bson:"html"`
+	Status        string     `bson:"status"`
+	Attempts      int        `bson:"attempts"`
+	NextAttemptAt time.Time  `bson:"next_attempt_at"`
+	LastError     string     `bson:"last_error,omitempty"`
+	LockedUntil   *time.Time `bson:"locked_until,omitempty"`
+	CreatedAt     time.Time  `bson:"created_at"`
+	UpdatedAt     time.Time  `bson:"updated_at"`
+	SentAt        *time.Time `bson:"sent_at,omitempty"`
+}
+
+type WebhookSubscriptionDTO struct {
+	ID         string    `bson:"_id"`
+	URL        string    `bson:"url"`
+	EventTypes []string  `bson:"event_types"`
+	Secret     string    `bson:"secret"`
+	CreatedAt  time.Time `bson:"created_at"`
+	UpdatedAt  time.Time `bson:"updated_at"`
+}
+
+type WebhookAttemptDTO struct {
+	At         time.Time `bson:"at"`
+	StatusCode int       `bson:"status_code"`
+	Error      string    `bson:"error,omitempty"`
+	DurationMs int64     `bson:"duration_ms"`
+}
+
+type WebhookDeliveryDTO struct {
+	ID             string              `bson:"_id"`
+	SubscriptionID string              `bson:"subscription_id"`
+	EventType      string              `bson:"event_type"`
+	Data           string              `bson:"data"`
+	Status         string              `bson:"status"`
+	Attempts       int                 `bson:"attempts"`
+	NextAttemptAt  time.Time           `bson:"next_attempt_at"`
+	Log            []WebhookAttemptDTO `bson:"log"`
+	LockedUntil    *time.Time          `bson:"locked_until,omitempty"`
+	CreatedAt      time.Time           `bson:"created_at"`
+	UpdatedAt      time.Time           `bson:"updated_at"`
+	DeliveredAt    *time.Time          `bson:"delivered_at,omitempty"`
+}
@@ -20,6 +20,22 @@ func (m *MongoDB) UserCollection() *mongo.Collection {
 	return m.Database.Collection("users")
 }
 
+func (m *MongoDB) NotificationPreferencesCollection() *mongo.Collection {
+	return m.Database.Collection("notification_preferences")
+}
+
+func (m *MongoDB) MailQueueCollection() *mongo.Collection {
+	return m.Database.Collection("mail_queue")
+}
+
+func (m *MongoDB) WebhookSubscriptionCollection() *mongo.Collection {
+	return m.Database.Collection("webhook_subscriptions")
+}
+
+func (m *MongoDB) WebhookDeliveryCollection() *mongo.Collection {
+	return m.Database.Collection("webhook_deliveries")
+}
+
 func (m *MongoDB) initUserIndexes(ctx context.Context) error {
 	usernameIndex := mongo.IndexModel{
 		Keys:    bson.M{"username": 1},
@@ -35,6 +51,29 @@ func (m *MongoDB) initUserIndexes(ctx context.Context) error {
 		usernameIndex,
 		emailIndex,
 	})
+	if err != nil {
+		return err
+	}
+
+	_, err = m.MailQueueCollection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = m.WebhookSubscriptionCollection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.M{"event_types": 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = m.WebhookDeliveryCollection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		{Keys: bson.D{{Key: "subscription_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
 
 	return err
 }
@@ -0,0 +1,69 @@
+package mail
+
+import "user-service/internal/domain"
+
+// bundles holds the strings of every email per language. A template named X
+// has the keys X.subject and X.body, and X.heading when it does not use the
+// common greeting. The strings are text/template sources over TemplateData.
+var bundles = map[domain.Language]map[string]string{
+	domain.LanguageEN: {
+		"greeting":  "Hello, {{.Username}}!",
+		"signature": "Best regards,",
+		"team":      "The KazakhDelivery Team",
+
+		"registration.subject": "Registration Confirmation in KazakhDelivery",
+		"registration.heading": "Welcome to KazakhDelivery, {{.Username}}!",
+		"registration.body":    "Thank you for registering with our service. Your account has been successfully created. You can now log in using your email and password.",
+
+		"order_created.subject":    "Order {{.OrderID}} received",
+		"order_created.body":       "We received your order {{.OrderID}} for {{.Total}}. We will let you know once it is confirmed.",
+		"order_confirmed.subject":  "Order {{.OrderID}} confirmed",
+		"order_confirmed.body":     "Your order {{.OrderID}} is confirmed and is being prepared.",
+		"order_dispatched.subject": "Order {{.OrderID}} is on its way",
+		"order_dispatched.body":    "Your order {{.OrderID}} has been handed to the courier and is on its way.",
+		"order_delivered.subject":  "Order {{.OrderID}} delivered",
+		"order_delivered.body":     "Your order {{.OrderID}} has been delivered. Thank you for shopping with us!",
+		"order_cancelled.subject":  "Order {{.OrderID}} cancelled",
+		"order_cancelled.body":     "Your order {{.OrderID}} has been cancelled. Any payment will be refunded.",
+	},
+	domain.LanguageRU: {
+		"greeting":  "Здравствуйте, {{.Username}}!",
+		"signature": "С уважением,",
+		"team":      "команда KazakhDelivery",
+
+		"registration.subject": "Подтверждение регистрации в KazakhDelivery",
+		"registration.heading": "Добро пожаловать в KazakhDelivery, {{.Username}}!",
+		"registration.body":    "Спасибо за регистрацию в нашем сервисе. Ваш аккаунт успешно создан. Теперь вы можете войти, используя email и пароль.",
+
+		"order_created.subject":    "Заказ {{.OrderID}} принят",
+		"order_created.body":       "Мы получили ваш заказ {{.OrderID}} на сумму {{.Total}}. Мы сообщим, когда он будет подтверждён.",
+		"order_confirmed.subject":  "Заказ {{.OrderID}} подтверждён",
+		"order_confirmed.body":     "Ваш заказ {{.OrderID}} подтверждён и уже собирается.",
+		"order_dispatched.subject": "Заказ {{.OrderID}} в пути",
+		"order_dispatched.body":    "Ваш заказ {{.OrderID}} передан курьеру и уже в пути.",
+		"order_delivered.subject":  "Заказ {{.OrderID}} доставлен",
+		"order_delivered.body":     "Ваш заказ {{.OrderID}} доставлен. Спасибо, что выбрали нас!",
+		"order_cancelled.subject":  "Заказ {{.OrderID}} отменён",
+		"order_cancelled.body":     "Ваш заказ {{.OrderID}} отменён. Если он был оплачен, деньги вернутся на ваш счёт.",
+	},
+	domain.LanguageKK: {
+		"greeting":  "Сәлеметсіз бе, {{.Username}}!",
+		"signature": "Құрметпен,",
+		"team":      "KazakhDelivery командасы",
+
+		"registration.subject": "KazakhDelivery-де тіркелуді растау",
+		"registration.heading": "KazakhDelivery-ге қош келдіңіз, {{.Username}}!",
+		"registration.body":    "Біздің қызметке тіркелгеніңіз үшін рахмет. Аккаунтыңыз сәтті құрылды. Енді email және құпиясөз арқылы кіре аласыз.",
+
+		"order_created.subject":    "{{.OrderID}} тапсырысы қабылданды",
+		"order_created.body":       "{{.Total}} сомасына {{.OrderID}} тапсырысыңызды алдық. Ол расталған кезде хабарлаймыз.",
+		"order_confirmed.subject":  "{{.OrderID}} тапсырысы расталды",
+		"order_confirmed.body":     "{{.OrderID}} тапсырысыңыз расталды және жиналып жатыр.",
+		"order_dispatched.subject": "{{.OrderID}} тапсырысы жолда",
+		"order_dispatched.body":    "{{.OrderID}} тапсырысыңыз курьерге берілді және жолда.",
+		"order_delivered.subject":  "{{.OrderID}} тапсырысы жеткізілді",
+		"order_delivered.body":     "{{.OrderID}} тапсырысыңыз жеткізілді. Бізді таңдағаныңыз үшін рахмет!",
+		"order_cancelled.subject":  "{{.OrderID}} тапсырысы жойылды",
+		"order_cancelled.body":     "{{.OrderID}} тапсырысыңыз жойылды. Төлем жасалған болса, ақша шотыңызға қайтарылады.",
+	},
+}
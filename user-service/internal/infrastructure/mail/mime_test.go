@@ -0,0 +1,88 @@
+package mail
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"strings"
+	"testing"
+
+	"user-service/internal/domain"
+)
+
+func TestBuildMessageParsesBackAsMultipartAlternative(t *testing.T) {
+	email, err := Render("order_created", domain.LanguageKK, SampleData())
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	raw, err := buildMessage("KazakhDelivery", "noreply@kazakhdelivery.kz", "aigerim@example.kz", email)
+	if err != nil {
+		t.Fatalf("buildMessage: %v", err)
+	}
+	msg, err := netmail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+
+	rawSubject := msg.Header.Get("Subject")
+	if !strings.HasPrefix(rawSubject, "=?utf-8?q?") {
+		t.Fatalf("Subject %q is not Q-encoded", rawSubject)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(rawSubject)
+	if err != nil || subject != email.Subject {
+		t.Fatalf("Subject decodes to %q (%v), want %q", subject, err, email.Subject)
+	}
+	from, err := msg.Header.AddressList("From")
+	if err != nil || len(from) != 1 || from[0].Name != "KazakhDelivery" || from[0].Address != "noreply@kazakhdelivery.kz" {
+		t.Fatalf("From = %v (%v)", from, err)
+	}
+	if got := msg.Header.Get("Content-Language"); got != string(domain.LanguageKK) {
+		t.Fatalf("Content-Language = %q, want kk", got)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" || params["boundary"] == "" {
+		t.Fatalf("Content-Type = %q, %v (%v)", mediaType, params, err)
+	}
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for _, want := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", email.Text},
+		{"text/html; charset=UTF-8", email.HTML},
+	} {
+		part, err := reader.NextRawPart()
+		if err != nil {
+			t.Fatalf("NextRawPart: %v", err)
+		}
+		if got := part.Header.Get("Content-Type"); got != want.contentType {
+			t.Fatalf("part Content-Type = %q, want %q", got, want.contentType)
+		}
+		if got := part.Header.Get("Content-Transfer-Encoding"); got != "quoted-printable" {
+			t.Fatalf("part Content-Transfer-Encoding = %q, want quoted-printable", got)
+		}
+
+		encoded, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range strings.Split(string(encoded), "\r\n") {
+			if len(line) > 76 {
+				t.Fatalf("quoted-printable line of %d characters: %q", len(line), line)
+			}
+		}
+		// Line breaks of the text are sent as CRLF, as MIME requires.
+		decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(string(encoded))))
+		if text := strings.ReplaceAll(string(decoded), "\r\n", "\n"); err != nil || text != want.content {
+			t.Fatalf("%s part decodes to %q (%v), want %q", want.contentType, text, err, want.content)
+		}
+	}
+	if _, err := reader.NextRawPart(); err != io.EOF {
+		t.Fatalf("after two parts: err = %v, want io.EOF", err)
+	}
+}
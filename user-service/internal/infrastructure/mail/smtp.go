@@ -6,13 +6,23 @@ import (
 	"user-service/internal/config"
 )
 
+// Sender delivers a rendered email.
+type Sender interface {
+	Send(to string, email *Email) error
+}
+
 type MailService struct {
 	config *config.SMTPConfig
 	auth   smtp.Auth
 }
 
+// NewMailService sends through the configured SMTP server. Without a username
+// it sends unauthenticated, which suits a local stand-in such as Mailpit.
 func NewMailService(cfg *config.Config) *MailService {
-	auth := smtp.PlainAuth("", cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.Host)
+	var auth smtp.Auth
+	if cfg.SMTP.Username != "" {
+		auth = smtp.PlainAuth("", cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.Host)
+	}
 
 	return &MailService{
 		config: &cfg.SMTP,
@@ -20,45 +30,26 @@ func NewMailService(cfg *config.Config) *MailService {
 	}
 }
 
-func (s *MailService) SendRegistrationConfirmation(to, username string) error {
-	subject := "Registration Confirmation in KazakhDelivery"
-	body := fmt.Sprintf(`
-	<html>
-		<body>
-			<h2>Welcome to KazakhDelivery, %s!</h2>
-			<p>Thank you for registering with our service. Your account has been successfully created.</p>
-			<p>You can now log in using your email and password.</p>
-			<p>Best regards,<br>The KazakhDelivery Team</p>
-		</body>
-	</html>
-	`, username)
-
-	return s.sendMail(to, subject, body)
-}
-
-func (s *MailService) sendMail(to, subject, htmlBody string) error {
-	from := fmt.Sprintf("%s <%s>", s.config.FromName, s.config.Username)
-
-	headers := make(map[string]string)
-	headers["From"] = from
-	headers["To"] = to
-	headers["Subject"] = subject
-	headers["MIME-Version"] = "1.0"
-	headers["Content-Type"] = "text/html; charset=UTF-8"
-
-	message := ""
-	for k, v := range headers {
-		message += fmt.Sprintf("%s: %s\r\n", k, v)
+func (s *MailService) Send(to string, email *Email) error {
+	message, err := buildMessage(s.config.FromName, s.fromAddress(), to, email)
+	if err != nil {
+		return err
 	}
-	message += "\r\n" + htmlBody
 
 	addr := fmt.Sprintf("%s:%s", s.config.Host, s.config.Port)
 
 	return smtp.SendMail(
 		addr,
 		s.auth,
-		s.config.Username,
+		s.fromAddress(),
 		[]string{to},
-		[]byte(message),
+		message,
 	)
 }
+
+func (s *MailService) fromAddress() string {
+	if s.config.FromAddress != "" {
+		return s.config.FromAddress
+	}
+	return s.config.Username
+}
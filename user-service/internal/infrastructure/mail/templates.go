@@ -0,0 +1,152 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"sort"
+	"strings"
+	"text/template"
+
+	"user-service/internal/domain"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var (
+	htmlLayout = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/layout.html.tmpl"))
+	textLayout = template.Must(template.ParseFS(templateFS, "templates/layout.txt.tmpl"))
+)
+
+// parsedBundles holds the bundle strings parsed once at start-up, so a typo
+// in a translation fails fast instead of when the email is sent.
+var parsedBundles = parseBundles()
+
+// TemplateData is what the bundle strings can refer to.
+type TemplateData struct {
+	Username string
+	OrderID  string
+	Total    string
+}
+
+// SampleData fills the templates for previews.
+func SampleData() TemplateData {
+	return TemplateData{
+		Username: "Aigerim",
+		OrderID:  "5f8d0c1e-7a2b-4c3d-9e4f-1a2b3c4d5e6f",
+		Total:    "12500.00 KZT",
+	}
+}
+
+// Email is a rendered email. Summary is the body alone, short enough for SMS
+// and push notifications.
+type Email struct {
+	Language domain.Language
+	Subject  string
+	Text     string
+	HTML     string
+	Summary  string
+}
+
+type layoutData struct {
+	Language  domain.Language
+	Subject   string
+	Heading   string
+	Body      string
+	Signature string
+	Team      string
+}
+
+// TemplateNames lists the emails that can be rendered.
+func TemplateNames() []string {
+	var names []string
+	for key := range bundles[domain.DefaultLanguage] {
+		if name, ok := strings.CutSuffix(key, ".subject"); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render renders the named email in the given language, falling back to the
+// default language for languages without a bundle and for strings missing
+// from a bundle.
+func Render(name string, language domain.Language, data TemplateData) (*Email, error) {
+	bundle, ok := parsedBundles[language]
+	if !ok {
+		language = domain.DefaultLanguage
+		bundle = parsedBundles[language]
+	}
+	lookup := func(key string) (*template.Template, bool) {
+		if tmpl, ok := bundle[key]; ok {
+			return tmpl, true
+		}
+		tmpl, ok := parsedBundles[domain.DefaultLanguage][key]
+		return tmpl, ok
+	}
+	if _, ok := lookup(name + ".subject"); !ok {
+		return nil, fmt.Errorf("unknown email template: %s", name)
+	}
+
+	execute := func(key string) (string, error) {
+		tmpl, ok := lookup(key)
+		if !ok {
+			return "", fmt.Errorf("email template %s has no %s", name, key)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return "", fmt.Errorf("failed to render %s in %s: %w", key, language, err)
+		}
+		return buf.String(), nil
+	}
+
+	headingKey := name + ".heading"
+	if _, ok := lookup(headingKey); !ok {
+		headingKey = "greeting"
+	}
+
+	layout := layoutData{Language: language}
+	for key, dest := range map[string]*string{
+		name + ".subject": &layout.Subject,
+		headingKey:        &layout.Heading,
+		name + ".body":    &layout.Body,
+		"signature":       &layout.Signature,
+		"team":            &layout.Team,
+	} {
+		value, err := execute(key)
+		if err != nil {
+			return nil, err
+		}
+		*dest = value
+	}
+
+	var html, text bytes.Buffer
+	if err := htmlLayout.Execute(&html, layout); err != nil {
+		return nil, err
+	}
+	if err := textLayout.Execute(&text, layout); err != nil {
+		return nil, err
+	}
+
+	return &Email{
+		Language: language,
+		Subject:  layout.Subject,
+		Text:     text.String(),
+		HTML:     html.String(),
+		Summary:  layout.Body,
+	}, nil
+}
+
+func parseBundles() map[domain.Language]map[string]*template.Template {
+	parsed := make(map[domain.Language]map[string]*template.Template, len(bundles))
+	for language, bundle := range bundles {
+		parsed[language] = make(map[string]*template.Template, len(bundle))
+		for key, source := range bundle {
+			parsed[language][key] = template.Must(template.New(string(language) + "." + key).Parse(source))
+		}
+	}
+	return parsed
+}
@@ -0,0 +1,105 @@
+package mail
+
+import (
+	"strings"
+	"testing"
+
+	"user-service/internal/domain"
+)
+
+func TestEveryBundleHasEveryString(t *testing.T) {
+	reference := bundles[domain.DefaultLanguage]
+	for _, language := range domain.Languages {
+		bundle, ok := bundles[language]
+		if !ok {
+			t.Errorf("no bundle for %s", language)
+			continue
+		}
+		for key := range reference {
+			if _, ok := bundle[key]; !ok {
+				t.Errorf("%s bundle is missing %s", language, key)
+			}
+		}
+		for key := range bundle {
+			if _, ok := reference[key]; !ok {
+				t.Errorf("%s bundle has %s, which %s does not", language, key, domain.DefaultLanguage)
+			}
+		}
+	}
+
+	for _, name := range TemplateNames() {
+		if _, ok := reference[name+".body"]; !ok {
+			t.Errorf("template %s has a subject but no body", name)
+		}
+	}
+}
+
+func TestRenderFillsTheLayoutInEveryLanguage(t *testing.T) {
+	data := SampleData()
+	data.Username = "<Aigerim>"
+
+	for _, language := range domain.Languages {
+		for _, name := range TemplateNames() {
+			email, err := Render(name, language, data)
+			if err != nil {
+				t.Fatalf("Render(%s, %s): %v", name, language, err)
+			}
+			if email.Language != language || email.Subject == "" || !strings.Contains(email.Text, email.Summary) {
+				t.Errorf("%s in %s: language %s, subject %q, text %q", name, language, email.Language, email.Subject, email.Text)
+			}
+			if strings.Contains(email.HTML, "<Aigerim>") {
+				t.Errorf("%s in %s: the user name is not escaped in HTML", name, language)
+			}
+		}
+	}
+}
+
+func TestRenderFallsBackToTheDefaultLanguage(t *testing.T) {
+	email, err := Render("order_created", "de", SampleData())
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	want, _ := Render("order_created", domain.DefaultLanguage, SampleData())
+	if email.Language != domain.DefaultLanguage || email.Subject != want.Subject {
+		t.Fatalf("email in %s with subject %q, want %s %q", email.Language, email.Subject, domain.DefaultLanguage, want.Subject)
+	}
+
+	if _, err := Render("no_such_email", domain.LanguageEN, SampleData()); err == nil {
+		t.Fatal("an unknown template was rendered")
+	}
+}
+
+func TestRenderFallsBackForAStringMissingFromABundle(t *testing.T) {
+	defaultBody := parsedBundles[domain.DefaultLanguage]["order_created.body"]
+
+	for _, language := range domain.Languages {
+		t.Run(string(language), func(t *testing.T) {
+			bundle := parsedBundles[language]
+			body := bundle["order_created.body"]
+			delete(bundle, "order_created.body")
+			t.Cleanup(func() { bundle["order_created.body"] = body })
+
+			email, err := Render("order_created", language, SampleData())
+			if language == domain.DefaultLanguage {
+				if err == nil {
+					t.Fatal("rendered an email whose body is missing from the default bundle")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+
+			var want strings.Builder
+			if err := defaultBody.Execute(&want, SampleData()); err != nil {
+				t.Fatal(err)
+			}
+			if email.Summary != want.String() {
+				t.Fatalf("body = %q, want the %s one %q", email.Summary, domain.DefaultLanguage, want.String())
+			}
+			if own, _ := Render("order_confirmed", language, SampleData()); email.Language != language || own.Language != language {
+				t.Fatalf("email language = %s, want %s kept for the strings it has", email.Language, language)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"infra/streams"
+	"proto/events"
+	"user-service/internal/config"
+
+	"github.com/nats-io/nats.go"
+)
+
+// OrderEventHandler receives an order event together with the subject it
+// arrived on.
+type OrderEventHandler func(ctx context.Context, subject string, event *OrderEvent) error
+
+// RawEventHandler receives the type of an event and the event in JSON, as
+// partners receive it.
+type RawEventHandler func(ctx context.Context, eventType string, data []byte) error
+
+type EventConsumer interface {
+	SubscribeToOrderEvents(handler OrderEventHandler) error
+	// SubscribeToRawEvents gives the subscription its own durable consumers
+	// under name, so that it does not share events with other subscribers.
+	SubscribeToRawEvents(name string, subjects []string, handler RawEventHandler) error
+	Close()
+}
+
+// NATSConsumer reads events from durable JetStream consumers. An event is
+// acknowledged once handled, and redelivered with a backoff when its handler
+// fails.
+type NATSConsumer struct {
+	conn    *nats.Conn
+	js      nats.JetStreamContext
+	durable string
+	policy  DeliveryPolicy
+
+	// failures remembers when events being retried first failed. It is lost
+	// on restart, the dead letter then gives the time of the first failure
+	// seen by this process.
+	mu       sync.Mutex
+	failures map[string]time.Time
+}
+
+func NewNATSConsumer(cfg *config.Config) (*NATSConsumer, error) {
+	nc, err := nats.Connect(cfg.NATS.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := nc.JetStream()
+	if err == nil {
+		err = streams.EnsureStream(js, streams.OrderStream)
+	}
+	if err == nil {
+		err = streams.EnsureStream(js, streams.StockStream)
+	}
+	if err == nil {
+		err = streams.EnsureStream(js, streams.DeadLetterStream)
+	}
+	if err != nil {
+		nc.Close()
+		return nil, err
+	}
+
+	log.Printf("Connected to NATS at %s", cfg.NATS.URL)
+	return &NATSConsumer{
+		conn:     nc,
+		js:       js,
+		durable:  cfg.NATS.Durable,
+		policy:   NewDeliveryPolicy(cfg.NATS),
+		failures: make(map[string]time.Time),
+	}, nil
+}
+
+func (c *NATSConsumer) SubscribeToOrderEvents(handler OrderEventHandler) error {
+	for _, subject := range OrderSubjects {
+		err := c.subscribe("notifications", subject, func(msg *nats.Msg) {
+			envelope, err := events.Parse(msg.Subject, msg.Header, msg.Data)
+			var event *OrderEvent
+			if err == nil {
+				event, err = decodeOrderEvent(envelope)
+			}
+			if err != nil {
+				log.Printf("Error decoding %s message: %v", msg.Subject, err)
+				c.deadLetter(msg, err)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			err = handler(ctx, envelope.Type, event)
+			if err != nil {
+				log.Printf("Error handling %s event for order %s: %v", msg.Subject, event.OrderID, err)
+			}
+			c.settle(msg, err)
+		})
+		if err != nil {
+			log.Printf("Error subscribing to subject %s: %v", subject, err)
+			return err
+		}
+
+		log.Printf("Subscribed to %s events", subject)
+	}
+
+	return nil
+}
+
+func (c *NATSConsumer) SubscribeToRawEvents(name string, subjects []string, handler RawEventHandler) error {
+	for _, subject := range subjects {
+		err := c.subscribe(name, subject, func(msg *nats.Msg) {
+			envelope, err := events.Parse(msg.Subject, msg.Header, msg.Data)
+			var data []byte
+			if err == nil {
+				data, err = eventJSON(envelope)
+			}
+			if err != nil {
+				log.Printf("Error decoding %s message: %v", msg.Subject, err)
+				c.deadLetter(msg, err)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			err = handler(ctx, envelope.Type, data)
+			if err != nil {
+				log.Printf("Error handling %s event: %v", msg.Subject, err)
+			}
+			c.settle(msg, err)
+		})
+		if err != nil {
+			log.Printf("Error subscribing to subject %s: %v", subject, err)
+			return err
+		}
+
+		log.Printf("Subscribed to %s events for %s", subject, name)
+	}
+
+	return nil
+}
+
+// decodeOrderEvent dispatches on the type of the event. Types and schema
+// versions this service does not know are dead-lettered, to be redriven after
+// an upgrade.
+func decodeOrderEvent(envelope *events.Envelope) (*OrderEvent, error) {
+	switch envelope.Type {
+	case events.TypeOrderCreated:
+		var event events.OrderCreated
+		if err := envelope.Decode(&event); err != nil {
+			return nil, err
+		}
+		return &OrderEvent{
+			OrderID:   event.OrderID,
+			UserID:    event.UserID,
+			Total:     event.Total,
+			Timestamp: event.Timestamp,
+		}, nil
+	case events.TypeOrderConfirmed, events.TypeOrderDispatched, events.TypeOrderDelivered, events.TypeOrderCancelled:
+		var event events.OrderStatusChanged
+		if err := envelope.Decode(&event); err != nil {
+			return nil, err
+		}
+		return &OrderEvent{
+			OrderID:   event.OrderID,
+			UserID:    event.UserID,
+			Status:    event.Status,
+			Total:     event.Total,
+			Timestamp: event.Timestamp,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unexpected event type %s", envelope.Type)
+	}
+}
+
+// eventJSON returns the event of the envelope in JSON, whatever it was
+// published in.
+func eventJSON(envelope *events.Envelope) ([]byte, error) {
+	if envelope.DataContentType == events.ContentTypeJSON {
+		return envelope.Data, nil
+	}
+	event, err := envelope.Event()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(event)
+}
+
+// subscribe joins the queue group of the durable consumer, so that every event
+// is handled by one replica.
+func (c *NATSConsumer) subscribe(name, subject string, handler nats.MsgHandler) error {
+	durable := durableName(c.durable+"-"+name, subject)
+	_, err := c.js.QueueSubscribe(subject, durable, func(msg *nats.Msg) {
+		if target := msg.Header.Get(HeaderRedriveConsumer); target != "" && target != durable {
+			// Redriven for another consumer, this one handled it already.
+			msg.Ack()
+			return
+		}
+		handler(msg)
+	}, c.policy.subscribeOptions(durable)...)
+	return err
+}
+
+// settle acknowledges a handled event. A failed one is delivered again after a
+// backoff, and moved to the dead letter queue on its last delivery.
+func (c *NATSConsumer) settle(msg *nats.Msg, err error) {
+	if err == nil {
+		c.forgetFailure(msg)
+		if err := msg.Ack(); err != nil {
+			log.Printf("Error acknowledging %s message: %v", msg.Subject, err)
+		}
+		return
+	}
+
+	meta, metaErr := msg.Metadata()
+	if metaErr != nil || (c.policy.MaxDeliver > 0 && meta.NumDelivered >= uint64(c.policy.MaxDeliver)) {
+		c.deadLetter(msg, err)
+		return
+	}
+
+	c.mu.Lock()
+	if _, ok := c.failures[failureKey(meta)]; !ok {
+		c.failures[failureKey(meta)] = time.Now()
+	}
+	c.mu.Unlock()
+
+	delay := c.policy.Delay(meta.NumDelivered)
+	log.Printf("Redelivering %s message in %v (delivery %d of %d)", msg.Subject, delay, meta.NumDelivered, c.policy.MaxDeliver)
+	if err := msg.NakWithDelay(delay); err != nil {
+		log.Printf("Error rejecting %s message: %v", msg.Subject, err)
+	}
+}
+
+// deadLetter moves an event that cannot be handled to the dead letter queue
+// and stops its redelivery.
+func (c *NATSConsumer) deadLetter(msg *nats.Msg, cause error) {
+	consumer, attempts := "", 1
+	firstFailure := c.forgetFailure(msg)
+	if meta, err := msg.Metadata(); err == nil {
+		consumer, attempts = meta.Consumer, int(meta.NumDelivered)
+	}
+
+	if _, err := c.js.PublishMsg(newDeadLetterMsg(msg, consumer, attempts, firstFailure, cause)); err != nil {
+		// Without a dead letter the event is not terminated, so it comes
+		// back after the ack wait if it has deliveries left.
+		log.Printf("Error publishing to DLQ: %v", err)
+		return
+	}
+	log.Printf("Moved %s message to DLQ after %d attempts: %v", msg.Subject, attempts, cause)
+
+	if err := msg.Term(); err != nil {
+		log.Printf("Error terminating %s message: %v", msg.Subject, err)
+	}
+}
+
+// forgetFailure returns when the event first failed, or now when it did not
+// fail before.
+func (c *NATSConsumer) forgetFailure(msg *nats.Msg) time.Time {
+	meta, err := msg.Metadata()
+	if err != nil {
+		return time.Now()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	firstFailure, ok := c.failures[failureKey(meta)]
+	if !ok {
+		return time.Now()
+	}
+	delete(c.failures, failureKey(meta))
+	return firstFailure
+}
+
+func failureKey(meta *nats.MsgMetadata) string {
+	return meta.Consumer + "/" + strconv.FormatUint(meta.Sequence.Stream, 10)
+}
+
+// Close leaves the durable consumers in place: unsubscribing would delete
+// them together with the events they hold for this service.
+func (c *NATSConsumer) Close() {
+	if c.conn != nil {
+		c.conn.Close()
+		log.Println("NATS consumer connection closed")
+	}
+}
@@ -0,0 +1,42 @@
+package messaging
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Headers of a dead letter. The body is the event as it was published. The
+// inventory service lists and redrives dead letters.
+const (
+	HeaderDeadLetterSubject      = "Dlq-Source-Subject"
+	HeaderDeadLetterConsumer     = "Dlq-Consumer"
+	HeaderDeadLetterError        = "Dlq-Error"
+	HeaderDeadLetterAttempts     = "Dlq-Attempts"
+	HeaderDeadLetterFirstFailure = "Dlq-First-Failure"
+	// HeaderRedriveConsumer marks a redriven event. Only the named consumer
+	// handles it, the others already did.
+	HeaderRedriveConsumer = "Dlq-Redrive-Consumer"
+)
+
+// newDeadLetterMsg wraps a failed event. The headers of the event are kept so
+// that a redrive publishes it unchanged.
+func newDeadLetterMsg(msg *nats.Msg, consumer string, attempts int, firstFailure time.Time, cause error) *nats.Msg {
+	dead := nats.NewMsg(SubjectDeadLetter)
+	for key, values := range msg.Header {
+		dead.Header[key] = values
+	}
+	if previous := msg.Header.Get(HeaderDeadLetterFirstFailure); previous != "" {
+		// A redriven event keeps the time of its first failure.
+		firstFailure, _ = time.Parse(time.RFC3339Nano, previous)
+	}
+	dead.Header.Del(HeaderRedriveConsumer)
+	dead.Header.Set(HeaderDeadLetterSubject, msg.Subject)
+	dead.Header.Set(HeaderDeadLetterConsumer, consumer)
+	dead.Header.Set(HeaderDeadLetterError, cause.Error())
+	dead.Header.Set(HeaderDeadLetterAttempts, strconv.Itoa(attempts))
+	dead.Header.Set(HeaderDeadLetterFirstFailure, firstFailure.UTC().Format(time.RFC3339Nano))
+	dead.Data = msg.Data
+	return dead
+}
@@ -0,0 +1,35 @@
+package messaging
+
+import (
+	"proto/events"
+	"proto/money"
+)
+
+// OrderEvent holds the fields notifications need from the order events. Status
+// is empty for order.created.
+type OrderEvent struct {
+	OrderID   string
+	UserID    string
+	Status    string
+	Total     money.Money
+	Timestamp int64
+}
+
+const (
+	SubjectOrderCreated    = events.TypeOrderCreated
+	SubjectOrderConfirmed  = events.TypeOrderConfirmed
+	SubjectOrderDispatched = events.TypeOrderDispatched
+	SubjectOrderDelivered  = events.TypeOrderDelivered
+	SubjectOrderCancelled  = events.TypeOrderCancelled
+
+	SubjectDeadLetter = events.SubjectDeadLetter
+)
+
+// OrderSubjects are the order lifecycle subjects customers are notified about.
+var OrderSubjects = []string{
+	SubjectOrderCreated,
+	SubjectOrderConfirmed,
+	SubjectOrderDispatched,
+	SubjectOrderDelivered,
+	SubjectOrderCancelled,
+}
@@ -0,0 +1,94 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// SMSProvider sends text messages to phone numbers.
+type SMSProvider interface {
+	SendSMS(ctx context.Context, phone, text string) error
+}
+
+// PushProvider sends push notifications to a registered device.
+type PushProvider interface {
+	SendPush(ctx context.Context, deviceToken, title, body string) error
+}
+
+// HTTPSMSProvider sends text messages through an SMS gateway that takes a
+// JSON {"from", "to", "text"} POST authorized with a bearer API key.
+type HTTPSMSProvider struct {
+	url    string
+	apiKey string
+	sender string
+	client *http.Client
+}
+
+func NewHTTPSMSProvider(url, apiKey, sender string, timeout time.Duration) *HTTPSMSProvider {
+	return &HTTPSMSProvider{url: url, apiKey: apiKey, sender: sender, client: &http.Client{Timeout: timeout}}
+}
+
+func (p *HTTPSMSProvider) SendSMS(ctx context.Context, phone, text string) error {
+	return post(ctx, p.client, p.url, p.apiKey, map[string]string{
+		"from": p.sender,
+		"to":   phone,
+		"text": text,
+	})
+}
+
+// HTTPPushProvider sends push notifications through a push gateway that takes
+// a JSON {"token", "title", "body"} POST authorized with a bearer API key.
+type HTTPPushProvider struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+func NewHTTPPushProvider(url, apiKey string, timeout time.Duration) *HTTPPushProvider {
+	return &HTTPPushProvider{url: url, apiKey: apiKey, client: &http.Client{Timeout: timeout}}
+}
+
+func (p *HTTPPushProvider) SendPush(ctx context.Context, deviceToken, title, body string) error {
+	return post(ctx, p.client, p.url, p.apiKey, map[string]string{
+		"token": deviceToken,
+		"title": title,
+		"body":  body,
+	})
+}
+
+// post sends the message and fails on any non-2xx answer. Errors name the
+// gateway status only, never the recipient, since they end up in logs.
+func post(ctx context.Context, client *http.Client, url, apiKey string, message map[string]string) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so the connection can be reused.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("gateway answered with status %d", resp.StatusCode)
+	}
+	return nil
+}
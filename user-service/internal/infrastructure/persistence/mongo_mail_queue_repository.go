@@ -0,0 +1,162 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"user-service/internal/domain"
+	"user-service/internal/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoMailQueueRepository struct {
+	db *database.MongoDB
+}
+
+func NewMongoMailQueueRepository(db *database.MongoDB) *mongoMailQueueRepository {
+	return &mongoMailQueueRepository{db: db}
+}
+
+func (r *mongoMailQueueRepository) Enqueue(ctx context.Context, email *domain.OutboundEmail) error {
+	_, err := r.db.MailQueueCollection().InsertOne(ctx, toOutboundEmailDTO(email))
+	return err
+}
+
+func (r *mongoMailQueueRepository) GetByID(ctx context.Context, id string) (*domain.OutboundEmail, error) {
+	var dto database.OutboundEmailDTO
+
+	err := r.db.MailQueueCollection().FindOne(ctx, bson.M{"_id": id}).Decode(&dto)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toOutboundEmailDomain(dto), nil
+}
+
+func (r *mongoMailQueueRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time) (*domain.OutboundEmail, error) {
+	filter := bson.M{
+		"status":          string(domain.EmailStatusQueued),
+		"next_attempt_at": bson.M{"$lte": now},
+		"$or": bson.A{
+			bson.M{"locked_until": bson.M{"$exists": false}},
+			bson.M{"locked_until": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"locked_until": leaseUntil}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var dto database.OutboundEmailDTO
+	err := r.db.MailQueueCollection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&dto)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toOutboundEmailDomain(dto), nil
+}
+
+func (r *mongoMailQueueRepository) Update(ctx context.Context, email *domain.OutboundEmail, from domain.EmailStatus) (bool, error) {
+	filter := bson.M{"_id": email.ID, "status": string(from)}
+	update := bson.M{
+		"$set": bson.M{
+			"status":          string(email.Status),
+			"attempts":        email.Attempts,
+			"next_attempt_at": email.NextAttemptAt,
+			"last_error":      email.LastError,
+			"sent_at":         email.SentAt,
+			"updated_at":      email.UpdatedAt,
+		},
+		"$unset": bson.M{"locked_until": ""},
+	}
+
+	result, err := r.db.MailQueueCollection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+func (r *mongoMailQueueRepository) List(ctx context.Context, status domain.EmailStatus, page, limit int) ([]*domain.OutboundEmail, int, error) {
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = string(status)
+	}
+
+	count, err := r.db.MailQueueCollection().CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOptions := options.Find().
+		SetLimit(int64(limit)).
+		SetSkip(int64((page - 1) * limit)).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.db.MailQueueCollection().Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var dtos []database.OutboundEmailDTO
+	if err := cursor.All(ctx, &dtos); err != nil {
+		return nil, 0, err
+	}
+
+	emails := make([]*domain.OutboundEmail, len(dtos))
+	for i, dto := range dtos {
+		emails[i] = toOutboundEmailDomain(dto)
+	}
+
+	return emails, int(count), nil
+}
+
+func toOutboundEmailDTO(email *domain.OutboundEmail) *database.OutboundEmailDTO {
+	return &database.OutboundEmailDTO{
+		ID:            email.ID,
+		To:            email.To,
+		Template:      email.Template,
+		Language:      string(email.Language),
+		Subject:       email.Subject,
+		Text:          email.Text,
+		HTML:          email.HTML,
+		Status:        string(email.Status),
+		Attempts:      email.Attempts,
+		NextAttemptAt: email.NextAttemptAt,
+		LastError:     email.LastError,
+		CreatedAt:     email.CreatedAt,
+		UpdatedAt:     email.UpdatedAt,
+		SentAt:        email.SentAt,
+	}
+}
+
+func toOutboundEmailDomain(dto database.OutboundEmailDTO) *domain.OutboundEmail {
+	return &domain.OutboundEmail{
+		ID:            dto.ID,
+		To:            dto.To,
+		Template:      dto.Template,
+		Language:      domain.Language(dto.Language),
+		Subject:       dto.Subject,
+		Text:          dto.Text,
+		HTML:          dto.HTML,
+		Status:        domain.EmailStatus(dto.Status),
+		Attempts:      dto.Attempts,
+		NextAttemptAt: dto.NextAttemptAt,
+		LastError:     dto.LastError,
+		CreatedAt:     dto.CreatedAt,
+		UpdatedAt:     dto.UpdatedAt,
+		SentAt:        dto.SentAt,
+	}
+}
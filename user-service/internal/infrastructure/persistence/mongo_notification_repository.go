@@ -0,0 +1,65 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"user-service/internal/domain"
+	"user-service/internal/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoNotificationPreferencesRepository struct {
+	db *database.MongoDB
+}
+
+func NewMongoNotificationPreferencesRepository(db *database.MongoDB) *mongoNotificationPreferencesRepository {
+	return &mongoNotificationPreferencesRepository{db: db}
+}
+
+func (r *mongoNotificationPreferencesRepository) Get(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+	var dto database.NotificationPreferencesDTO
+
+	err := r.db.NotificationPreferencesCollection().FindOne(ctx, bson.M{"_id": userID}).Decode(&dto)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	channels := make(map[domain.Channel]bool, len(dto.Channels))
+	for channel, enabled := range dto.Channels {
+		channels[domain.Channel(channel)] = enabled
+	}
+
+	return &domain.NotificationPreferences{
+		UserID:      dto.UserID,
+		Channels:    channels,
+		Phone:       dto.Phone,
+		DeviceToken: dto.DeviceToken,
+		UpdatedAt:   dto.UpdatedAt,
+	}, nil
+}
+
+func (r *mongoNotificationPreferencesRepository) Save(ctx context.Context, prefs *domain.NotificationPreferences) error {
+	channels := make(map[string]bool, len(prefs.Channels))
+	for channel, enabled := range prefs.Channels {
+		channels[string(channel)] = enabled
+	}
+
+	dto := &database.NotificationPreferencesDTO{
+		UserID:      prefs.UserID,
+		Channels:    channels,
+		Phone:       prefs.Phone,
+		DeviceToken: prefs.DeviceToken,
+		UpdatedAt:   prefs.UpdatedAt,
+	}
+
+	_, err := r.db.NotificationPreferencesCollection().ReplaceOne(ctx,
+		bson.M{"_id": prefs.UserID}, dto, options.Replace().SetUpsert(true))
+	return err
+}
@@ -25,6 +25,7 @@ func (r *mongoUserRepository) Create(ctx context.Context, user *domain.User) (*d
 		Username:  user.Username,
 		Email:     user.Email,
 		Password:  user.Password,
+		Language:  string(user.Language),
 		CreatedAt: user.CreatedAt,
 	}
 
@@ -57,6 +58,7 @@ func (r *mongoUserRepository) GetByID(ctx context.Context, id string) (*domain.U
 		Username:  userDTO.Username,
 		Email:     userDTO.Email,
 		Password:  userDTO.Password,
+		Language:  domain.Language(userDTO.Language),
 		CreatedAt: userDTO.CreatedAt,
 	}, nil
 }
@@ -79,6 +81,7 @@ func (r *mongoUserRepository) GetByUsername(ctx context.Context, username string
 		Username:  userDTO.Username,
 		Email:     userDTO.Email,
 		Password:  userDTO.Password,
+		Language:  domain.Language(userDTO.Language),
 		CreatedAt: userDTO.CreatedAt,
 	}, nil
 }
@@ -101,6 +104,7 @@ func (r *mongoUserRepository) GetByEmail(ctx context.Context, email string) (*do
 		Username:  userDTO.Username,
 		Email:     userDTO.Email,
 		Password:  userDTO.Password,
+		Language:  domain.Language(userDTO.Language),
 		CreatedAt: userDTO.CreatedAt,
 	}, nil
 }
@@ -111,6 +115,7 @@ func (r *mongoUserRepository) Update(ctx context.Context, user *domain.User) (*d
 		Username:  user.Username,
 		Email:     user.Email,
 		Password:  user.Password,
+		Language:  string(user.Language),
 		CreatedAt: user.CreatedAt,
 	}
 
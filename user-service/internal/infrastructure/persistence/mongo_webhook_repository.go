@@ -0,0 +1,268 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"user-service/internal/domain"
+	"user-service/internal/infrastructure/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoWebhookSubscriptionRepository struct {
+	db *database.MongoDB
+}
+
+func NewMongoWebhookSubscriptionRepository(db *database.MongoDB) *mongoWebhookSubscriptionRepository {
+	return &mongoWebhookSubscriptionRepository{db: db}
+}
+
+func (r *mongoWebhookSubscriptionRepository) Create(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	_, err := r.db.WebhookSubscriptionCollection().InsertOne(ctx, toWebhookSubscriptionDTO(subscription))
+	return err
+}
+
+func (r *mongoWebhookSubscriptionRepository) GetByID(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	var dto database.WebhookSubscriptionDTO
+
+	err := r.db.WebhookSubscriptionCollection().FindOne(ctx, bson.M{"_id": id}).Decode(&dto)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toWebhookSubscriptionDomain(dto), nil
+}
+
+func (r *mongoWebhookSubscriptionRepository) List(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	return r.find(ctx, bson.M{})
+}
+
+func (r *mongoWebhookSubscriptionRepository) ListByEventType(ctx context.Context, eventType string) ([]*domain.WebhookSubscription, error) {
+	return r.find(ctx, bson.M{"event_types": eventType})
+}
+
+func (r *mongoWebhookSubscriptionRepository) Delete(ctx context.Context, id string) (bool, error) {
+	result, err := r.db.WebhookSubscriptionCollection().DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return false, err
+	}
+
+	return result.DeletedCount > 0, nil
+}
+
+func (r *mongoWebhookSubscriptionRepository) find(ctx context.Context, filter bson.M) ([]*domain.WebhookSubscription, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cursor, err := r.db.WebhookSubscriptionCollection().Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var dtos []database.WebhookSubscriptionDTO
+	if err := cursor.All(ctx, &dtos); err != nil {
+		return nil, err
+	}
+
+	subscriptions := make([]*domain.WebhookSubscription, len(dtos))
+	for i, dto := range dtos {
+		subscriptions[i] = toWebhookSubscriptionDomain(dto)
+	}
+
+	return subscriptions, nil
+}
+
+type mongoWebhookDeliveryRepository struct {
+	db *database.MongoDB
+}
+
+func NewMongoWebhookDeliveryRepository(db *database.MongoDB) *mongoWebhookDeliveryRepository {
+	return &mongoWebhookDeliveryRepository{db: db}
+}
+
+func (r *mongoWebhookDeliveryRepository) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	_, err := r.db.WebhookDeliveryCollection().InsertOne(ctx, toWebhookDeliveryDTO(delivery))
+	return err
+}
+
+func (r *mongoWebhookDeliveryRepository) GetByID(ctx context.Context, id string) (*domain.WebhookDelivery, error) {
+	var dto database.WebhookDeliveryDTO
+
+	err := r.db.WebhookDeliveryCollection().FindOne(ctx, bson.M{"_id": id}).Decode(&dto)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toWebhookDeliveryDomain(dto), nil
+}
+
+func (r *mongoWebhookDeliveryRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time) (*domain.WebhookDelivery, error) {
+	filter := bson.M{
+		"status":          string(domain.WebhookDeliveryPending),
+		"next_attempt_at": bson.M{"$lte": now},
+		"$or": bson.A{
+			bson.M{"locked_until": bson.M{"$exists": false}},
+			bson.M{"locked_until": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"locked_until": leaseUntil}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var dto database.WebhookDeliveryDTO
+	err := r.db.WebhookDeliveryCollection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&dto)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return toWebhookDeliveryDomain(dto), nil
+}
+
+func (r *mongoWebhookDeliveryRepository) Update(ctx context.Context, delivery *domain.WebhookDelivery, from domain.WebhookDeliveryStatus) (bool, error) {
+	dto := toWebhookDeliveryDTO(delivery)
+	filter := bson.M{"_id": delivery.ID, "status": string(from)}
+	update := bson.M{
+		"$set": bson.M{
+			"status":          dto.Status,
+			"attempts":        dto.Attempts,
+			"next_attempt_at": dto.NextAttemptAt,
+			"log":             dto.Log,
+			"updated_at":      dto.UpdatedAt,
+			"delivered_at":    dto.DeliveredAt,
+		},
+		"$unset": bson.M{"locked_until": ""},
+	}
+
+	result, err := r.db.WebhookDeliveryCollection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+func (r *mongoWebhookDeliveryRepository) List(ctx context.Context, subscriptionID string, status domain.WebhookDeliveryStatus, page, limit int) ([]*domain.WebhookDelivery, int, error) {
+	filter := bson.M{}
+	if subscriptionID != "" {
+		filter["subscription_id"] = subscriptionID
+	}
+	if status != "" {
+		filter["status"] = string(status)
+	}
+
+	count, err := r.db.WebhookDeliveryCollection().CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOptions := options.Find().
+		SetLimit(int64(limit)).
+		SetSkip(int64((page - 1) * limit)).
+		SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := r.db.WebhookDeliveryCollection().Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var dtos []database.WebhookDeliveryDTO
+	if err := cursor.All(ctx, &dtos); err != nil {
+		return nil, 0, err
+	}
+
+	deliveries := make([]*domain.WebhookDelivery, len(dtos))
+	for i, dto := range dtos {
+		deliveries[i] = toWebhookDeliveryDomain(dto)
+	}
+
+	return deliveries, int(count), nil
+}
+
+func toWebhookSubscriptionDTO(subscription *domain.WebhookSubscription) *database.WebhookSubscriptionDTO {
+	return &database.WebhookSubscriptionDTO{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		Secret:     subscription.Secret,
+		CreatedAt:  subscription.CreatedAt,
+		UpdatedAt:  subscription.UpdatedAt,
+	}
+}
+
+func toWebhookSubscriptionDomain(dto database.WebhookSubscriptionDTO) *domain.WebhookSubscription {
+	return &domain.WebhookSubscription{
+		ID:         dto.ID,
+		URL:        dto.URL,
+		EventTypes: dto.EventTypes,
+		Secret:     dto.Secret,
+		CreatedAt:  dto.CreatedAt,
+		UpdatedAt:  dto.UpdatedAt,
+	}
+}
+
+func toWebhookDeliveryDTO(delivery *domain.WebhookDelivery) *database.WebhookDeliveryDTO {
+	log := make([]database.WebhookAttemptDTO, len(delivery.Log))
+	for i, attempt := range delivery.Log {
+		log[i] = database.WebhookAttemptDTO{
+			At:         attempt.At,
+			StatusCode: attempt.StatusCode,
+			Error:      attempt.Error,
+			DurationMs: attempt.Duration.Milliseconds(),
+		}
+	}
+
+	return &database.WebhookDeliveryDTO{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventType:      delivery.EventType,
+		Data:           string(delivery.Data),
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		Log:            log,
+		CreatedAt:      delivery.CreatedAt,
+		UpdatedAt:      delivery.UpdatedAt,
+		DeliveredAt:    delivery.DeliveredAt,
+	}
+}
+
+func toWebhookDeliveryDomain(dto database.WebhookDeliveryDTO) *domain.WebhookDelivery {
+	log := make([]domain.WebhookAttempt, len(dto.Log))
+	for i, attempt := range dto.Log {
+		log[i] = domain.WebhookAttempt{
+			At:         attempt.At,
+			StatusCode: attempt.StatusCode,
+			Error:      attempt.Error,
+			Duration:   time.Duration(attempt.DurationMs) * time.Millisecond,
+		}
+	}
+
+	return &domain.WebhookDelivery{
+		ID:             dto.ID,
+		SubscriptionID: dto.SubscriptionID,
+		EventType:      dto.EventType,
+		Data:           []byte(dto.Data),
+		Status:         domain.WebhookDeliveryStatus(dto.Status),
+		Attempts:       dto.Attempts,
+		NextAttemptAt:  dto.NextAttemptAt,
+		Log:            log,
+		CreatedAt:      dto.CreatedAt,
+		UpdatedAt:      dto.UpdatedAt,
+		DeliveredAt:    dto.DeliveredAt,
+	}
+}
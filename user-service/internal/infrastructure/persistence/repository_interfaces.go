@@ -2,6 +2,7 @@ package persistence
 
 import (
 	"context"
+	"time"
 	"user-service/internal/domain"
 )
 
@@ -12,3 +13,41 @@ type UserRepository interface {
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
 	Update(ctx context.Context, user *domain.User) (*domain.User, error)
 }
+
+type NotificationPreferencesRepository interface {
+	// Get returns nil when the user never changed the defaults.
+	Get(ctx context.Context, userID string) (*domain.NotificationPreferences, error)
+	Save(ctx context.Context, prefs *domain.NotificationPreferences) error
+}
+
+type MailQueueRepository interface {
+	Enqueue(ctx context.Context, email *domain.OutboundEmail) error
+	GetByID(ctx context.Context, id string) (*domain.OutboundEmail, error)
+	// ClaimDue leases the oldest queued email whose attempt is due until
+	// leaseUntil, so that only one worker sends it. It returns nil when
+	// nothing is due.
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time) (*domain.OutboundEmail, error)
+	// Update stores the email and releases its lease if it is still in
+	// status from.
+	Update(ctx context.Context, email *domain.OutboundEmail, from domain.EmailStatus) (bool, error)
+	List(ctx context.Context, status domain.EmailStatus, page, limit int) ([]*domain.OutboundEmail, int, error)
+}
+
+type WebhookSubscriptionRepository interface {
+	Create(ctx context.Context, subscription *domain.WebhookSubscription) error
+	GetByID(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+	List(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	ListByEventType(ctx context.Context, eventType string) ([]*domain.WebhookSubscription, error)
+	// Delete reports whether the subscription existed.
+	Delete(ctx context.Context, id string) (bool, error)
+}
+
+type WebhookDeliveryRepository interface {
+	Create(ctx context.Context, delivery *domain.WebhookDelivery) error
+	GetByID(ctx context.Context, id string) (*domain.WebhookDelivery, error)
+	// ClaimDue leases the pending delivery that is due the longest, see
+	// MailQueueRepository.ClaimDue.
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time) (*domain.WebhookDelivery, error)
+	Update(ctx context.Context, delivery *domain.WebhookDelivery, from domain.WebhookDeliveryStatus) (bool, error)
+	List(ctx context.Context, subscriptionID string, status domain.WebhookDeliveryStatus, page, limit int) ([]*domain.WebhookDelivery, int, error)
+}
@@ -26,6 +26,7 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) (*domain
 		Username:  user.Username,
 		Email:     user.Email,
 		Password:  user.Password,
+		Language:  string(user.Language),
 		CreatedAt: user.CreatedAt,
 	}
 
@@ -36,6 +37,7 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) (*domain
 		Username:  dto.Username,
 		Email:     dto.Email,
 		Password:  dto.Password,
+		Language:  domain.Language(dto.Language),
 		CreatedAt: dto.CreatedAt,
 	}, nil
 }
@@ -54,6 +56,7 @@ func (r *userRepository) GetByID(ctx context.Context, id string) (*domain.User,
 		Username:  dto.Username,
 		Email:     dto.Email,
 		Password:  dto.Password,
+		Language:  domain.Language(dto.Language),
 		CreatedAt: dto.CreatedAt,
 	}, nil
 }
@@ -69,6 +72,7 @@ func (r *userRepository) GetByUsername(ctx context.Context, username string) (*d
 				Username:  dto.Username,
 				Email:     dto.Email,
 				Password:  dto.Password,
+				Language:  domain.Language(dto.Language),
 				CreatedAt: dto.CreatedAt,
 			}, nil
 		}
@@ -88,6 +92,7 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.
 				Username:  dto.Username,
 				Email:     dto.Email,
 				Password:  dto.Password,
+				Language:  domain.Language(dto.Language),
 				CreatedAt: dto.CreatedAt,
 			}, nil
 		}
@@ -110,6 +115,7 @@ func (r *userRepository) Update(ctx context.Context, user *domain.User) (*domain
 		Username:  user.Username,
 		Email:     user.Email,
 		Password:  user.Password,
+		Language:  string(user.Language),
 		CreatedAt: user.CreatedAt,
 	}
 
@@ -0,0 +1,28 @@
+package webhook
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerifyAcceptsOnlyFreshSignaturesOfTheBody(t *testing.T) {
+	now := time.Unix(1767225600, 0)
+	body := []byte(`{"id":"delivery-1"}`)
+	header := Sign("whsec_test", now, body)
+
+	if err := Verify("whsec_test", header, body, 5*time.Minute, now.Add(time.Minute)); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	for name, err := range map[string]error{
+		"other secret":   Verify("whsec_other", header, body, 5*time.Minute, now),
+		"changed body":   Verify("whsec_test", header, []byte(`{"id":"delivery-2"}`), 5*time.Minute, now),
+		"old timestamp":  Verify("whsec_test", header, body, 5*time.Minute, now.Add(10*time.Minute)),
+		"missing header": Verify("whsec_test", "", body, 5*time.Minute, now),
+	} {
+		if !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: err = %v, want ErrInvalidSignature", name, err)
+		}
+	}
+}
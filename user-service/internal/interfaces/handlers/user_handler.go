@@ -2,25 +2,33 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"proto/user"
 	"user-service/internal/application"
+	"user-service/internal/domain"
 )
 
 type UserHandler struct {
 	user.UnimplementedUserServiceServer
-	userUseCase *application.UserUseCase
+	userUseCase         *application.UserUseCase
+	notificationUseCase *application.NotificationUseCase
+	mailQueue           *application.MailQueue
+	webhookUseCase      *application.WebhookUseCase
 }
 
-func NewUserHandler(userUseCase *application.UserUseCase) *UserHandler {
+func NewUserHandler(userUseCase *application.UserUseCase, notificationUseCase *application.NotificationUseCase, mailQueue *application.MailQueue, webhookUseCase *application.WebhookUseCase) *UserHandler {
 	return &UserHandler{
-		userUseCase: userUseCase,
+		userUseCase:         userUseCase,
+		notificationUseCase: notificationUseCase,
+		mailQueue:           mailQueue,
+		webhookUseCase:      webhookUseCase,
 	}
 }
 
 func (h *UserHandler) RegisterUser(ctx context.Context, req *user.UserRequest) (*user.UserResponse, error) {
-	domainUser, err := h.userUseCase.RegisterUser(ctx, req.User.Username, req.User.Email, req.User.Password)
+	domainUser, err := h.userUseCase.RegisterUser(ctx, req.User.Username, req.User.Email, req.User.Password, req.User.Language)
 	if err != nil {
 		return nil, err
 	}
@@ -30,6 +38,7 @@ func (h *UserHandler) RegisterUser(ctx context.Context, req *user.UserRequest) (
 			Id:        domainUser.ID,
 			Username:  domainUser.Username,
 			Email:     domainUser.Email,
+			Language:  string(domainUser.PreferredLanguage()),
 			CreatedAt: domainUser.CreatedAt.Format(time.RFC3339),
 		},
 	}, nil
@@ -59,11 +68,12 @@ func (h *UserHandler) GetUserProfile(ctx context.Context, req *user.UserID) (*us
 		Id:       profile.ID,
 		Username: profile.Username,
 		Email:    profile.Email,
+		Language: string(profile.Language),
 	}, nil
 }
 
 func (h *UserHandler) UpdateUserProfile(ctx context.Context, req *user.UpdateUserRequest) (*user.UserProfile, error) {
-	profile, err := h.userUseCase.UpdateUser(ctx, req.Id, req.Username, req.Email)
+	profile, err := h.userUseCase.UpdateUser(ctx, req.Id, req.Username, req.Email, req.Language)
 	if err != nil {
 		return nil, err
 	}
@@ -72,5 +82,236 @@ func (h *UserHandler) UpdateUserProfile(ctx context.Context, req *user.UpdateUse
 		Id:       profile.ID,
 		Username: profile.Username,
 		Email:    profile.Email,
+		Language: string(profile.Language),
 	}, nil
 }
+
+func (h *UserHandler) GetNotificationPreferences(ctx context.Context, req *user.UserID) (*user.NotificationPreferences, error) {
+	if h.notificationUseCase == nil {
+		return nil, errors.New("notifications are not available")
+	}
+
+	prefs, err := h.notificationUseCase.GetPreferences(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToProtoPreferences(prefs), nil
+}
+
+func (h *UserHandler) UpdateNotificationPreferences(ctx context.Context, req *user.NotificationPreferences) (*user.NotificationPreferences, error) {
+	if h.notificationUseCase == nil {
+		return nil, errors.New("notifications are not available")
+	}
+
+	channels := map[domain.Channel]bool{
+		domain.ChannelEmail: req.Email,
+		domain.ChannelSMS:   req.Sms,
+		domain.ChannelPush:  req.Push,
+	}
+
+	prefs, err := h.notificationUseCase.UpdatePreferences(ctx, req.UserId, channels, req.Phone, req.DeviceToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertToProtoPreferences(prefs), nil
+}
+
+func (h *UserHandler) PreviewEmail(ctx context.Context, req *user.PreviewEmailRequest) (*user.EmailPreview, error) {
+	if h.notificationUseCase == nil {
+		return nil, errors.New("notifications are not available")
+	}
+
+	email, err := h.notificationUseCase.PreviewEmail(req.Template, req.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user.EmailPreview{
+		Subject:  email.Subject,
+		Html:     email.HTML,
+		Text:     email.Text,
+		Language: string(email.Language),
+	}, nil
+}
+
+func (h *UserHandler) ListQueuedEmails(ctx context.Context, req *user.ListQueuedEmailsRequest) (*user.ListQueuedEmailsResponse, error) {
+	if h.mailQueue == nil {
+		return nil, errors.New("mail service is not configured")
+	}
+
+	emails, total, err := h.mailQueue.List(ctx, req.Status, int(req.Page), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	response := &user.ListQueuedEmailsResponse{Total: int32(total)}
+	for _, email := range emails {
+		response.Emails = append(response.Emails, convertToProtoQueuedEmail(email))
+	}
+
+	return response, nil
+}
+
+func (h *UserHandler) ResendEmail(ctx context.Context, req *user.ResendEmailRequest) (*user.QueuedEmail, error) {
+	if h.mailQueue == nil {
+		return nil, errors.New("mail service is not configured")
+	}
+
+	email, err := h.mailQueue.Resend(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if email == nil {
+		return nil, errors.New("email not found")
+	}
+
+	return convertToProtoQueuedEmail(email), nil
+}
+
+func (h *UserHandler) CreateWebhook(ctx context.Context, req *user.CreateWebhookRequest) (*user.WebhookSubscription, error) {
+	if h.webhookUseCase == nil {
+		return nil, errors.New("webhooks are not available")
+	}
+
+	subscription, err := h.webhookUseCase.CreateSubscription(ctx, req.Url, req.EventTypes, req.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	protoSubscription := convertToProtoWebhook(subscription)
+	protoSubscription.Secret = subscription.Secret
+	return protoSubscription, nil
+}
+
+func (h *UserHandler) ListWebhooks(ctx context.Context, req *user.ListWebhooksRequest) (*user.ListWebhooksResponse, error) {
+	if h.webhookUseCase == nil {
+		return nil, errors.New("webhooks are not available")
+	}
+
+	subscriptions, err := h.webhookUseCase.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &user.ListWebhooksResponse{}
+	for _, subscription := range subscriptions {
+		response.Webhooks = append(response.Webhooks, convertToProtoWebhook(subscription))
+	}
+
+	return response, nil
+}
+
+func (h *UserHandler) DeleteWebhook(ctx context.Context, req *user.WebhookID) (*user.Empty, error) {
+	if h.webhookUseCase == nil {
+		return nil, errors.New("webhooks are not available")
+	}
+
+	if err := h.webhookUseCase.DeleteSubscription(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
+	return &user.Empty{}, nil
+}
+
+func (h *UserHandler) ListWebhookDeliveries(ctx context.Context, req *user.ListWebhookDeliveriesRequest) (*user.ListWebhookDeliveriesResponse, error) {
+	if h.webhookUseCase == nil {
+		return nil, errors.New("webhooks are not available")
+	}
+
+	deliveries, total, err := h.webhookUseCase.ListDeliveries(ctx, req.SubscriptionId, req.Status, int(req.Page), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	response := &user.ListWebhookDeliveriesResponse{Total: int32(total)}
+	for _, delivery := range deliveries {
+		response.Deliveries = append(response.Deliveries, convertToProtoWebhookDelivery(delivery))
+	}
+
+	return response, nil
+}
+
+func (h *UserHandler) ReplayWebhookDelivery(ctx context.Context, req *user.WebhookID) (*user.WebhookDelivery, error) {
+	if h.webhookUseCase == nil {
+		return nil, errors.New("webhooks are not available")
+	}
+
+	delivery, err := h.webhookUseCase.ReplayDelivery(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, errors.New("webhook delivery not found")
+	}
+
+	return convertToProtoWebhookDelivery(delivery), nil
+}
+
+// convertToProtoWebhook leaves out the secret.
+func convertToProtoWebhook(subscription *domain.WebhookSubscription) *user.WebhookSubscription {
+	return &user.WebhookSubscription{
+		Id:         subscription.ID,
+		Url:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		CreatedAt:  subscription.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func convertToProtoWebhookDelivery(delivery *domain.WebhookDelivery) *user.WebhookDelivery {
+	protoDelivery := &user.WebhookDelivery{
+		Id:             delivery.ID,
+		SubscriptionId: delivery.SubscriptionID,
+		EventType:      delivery.EventType,
+		Data:           string(delivery.Data),
+		Status:         string(delivery.Status),
+		Attempts:       int32(delivery.Attempts),
+		NextAttemptAt:  delivery.NextAttemptAt.Format(time.RFC3339),
+		CreatedAt:      delivery.CreatedAt.Format(time.RFC3339),
+	}
+	if delivery.DeliveredAt != nil {
+		protoDelivery.DeliveredAt = delivery.DeliveredAt.Format(time.RFC3339)
+	}
+	for _, attempt := range delivery.Log {
+		protoDelivery.Log = append(protoDelivery.Log, &user.WebhookAttempt{
+			AttemptedAt: attempt.At.Format(time.RFC3339),
+			StatusCode:  int32(attempt.StatusCode),
+			Error:       attempt.Error,
+			DurationMs:  attempt.Duration.Milliseconds(),
+		})
+	}
+
+	return protoDelivery
+}
+
+func convertToProtoQueuedEmail(email *domain.OutboundEmail) *user.QueuedEmail {
+	protoEmail := &user.QueuedEmail{
+		Id:            email.ID,
+		To:            email.To,
+		Template:      email.Template,
+		Language:      string(email.Language),
+		Subject:       email.Subject,
+		Status:        string(email.Status),
+		Attempts:      int32(email.Attempts),
+		LastError:     email.LastError,
+		NextAttemptAt: email.NextAttemptAt.Format(time.RFC3339),
+		CreatedAt:     email.CreatedAt.Format(time.RFC3339),
+	}
+	if email.SentAt != nil {
+		protoEmail.SentAt = email.SentAt.Format(time.RFC3339)
+	}
+
+	return protoEmail
+}
+
+func convertToProtoPreferences(prefs *domain.NotificationPreferences) *user.NotificationPreferences {
+	return &user.NotificationPreferences{
+		UserId:      prefs.UserID,
+		Email:       prefs.Enabled(domain.ChannelEmail),
+		Sms:         prefs.Enabled(domain.ChannelSMS),
+		Push:        prefs.Enabled(domain.ChannelPush),
+		Phone:       prefs.Phone,
+		DeviceToken: prefs.DeviceToken,
+	}
+}
@@ -2,11 +2,16 @@ package routes
 
 import (
 	"log"
+	"time"
 	"user-service/internal/application"
 	"user-service/internal/config"
+	"user-service/internal/domain"
 	"user-service/internal/infrastructure/database"
 	"user-service/internal/infrastructure/mail"
+	"user-service/internal/infrastructure/messaging"
+	"user-service/internal/infrastructure/notify"
 	"user-service/internal/infrastructure/persistence"
+	"user-service/internal/infrastructure/webhook"
 	"user-service/internal/interfaces/handlers"
 
 	"proto/user"
@@ -15,11 +20,14 @@ import (
 )
 
 type Services struct {
-	RedisCache  *database.RedisCache
-	MailService *mail.MailService
+	RedisCache        *database.RedisCache
+	MailQueue         *application.MailQueue
+	WebhookDispatcher *application.WebhookDispatcher
 }
 
-func RegisterGRPCServices(grpcServer *grpc.Server, db *database.MongoDB, cfg *config.Config) *Services {
+// RegisterGRPCServices wires the user service. Without a consumer no order
+// notifications are sent.
+func RegisterGRPCServices(grpcServer *grpc.Server, db *database.MongoDB, cfg *config.Config, consumer messaging.EventConsumer) *Services {
 	redisCache, err := database.NewRedisCache(cfg)
 	if err != nil {
 		log.Printf("Warning: Failed to connect to Redis: %v. Continuing without caching.", err)
@@ -28,26 +36,69 @@ func RegisterGRPCServices(grpcServer *grpc.Server, db *database.MongoDB, cfg *co
 		log.Println("Successfully connected to Redis")
 	}
 
-	mailService := mail.NewMailService(cfg)
-	if cfg.SMTP.Username == "" || cfg.SMTP.Password == "" {
-		log.Println("Warning: SMTP credentials not provided. Email functionality will be disabled.")
-		mailService = nil
+	// Credentials are optional so a local SMTP stand-in can be used.
+	var mailQueue *application.MailQueue
+	if cfg.SMTP.Host == "" {
+		log.Println("Warning: SMTP host not provided. Email functionality will be disabled.")
 	} else {
+		mailQueue = application.NewMailQueue(persistence.NewMongoMailQueueRepository(db),
+			mail.NewMailService(cfg), cfg.MailQueue)
+		mailQueue.Start()
 		log.Println("Mail service configured successfully")
 	}
 
 	userRepo := persistence.NewMongoUserRepository(db)
 
-	userUseCase := application.NewUserUseCase(userRepo, redisCache, mailService)
+	prefsRepo := persistence.NewMongoNotificationPreferencesRepository(db)
+	webhookSubscriptionRepo := persistence.NewMongoWebhookSubscriptionRepository(db)
+	webhookDeliveryRepo := persistence.NewMongoWebhookDeliveryRepository(db)
 
-	userHandler := handlers.NewUserHandler(userUseCase)
+	userUseCase := application.NewUserUseCase(userRepo, redisCache, mailQueue)
+	notificationUseCase := application.NewNotificationUseCase(prefsRepo, userRepo, mailQueue,
+		newSMSProvider(cfg), newPushProvider(cfg))
+
+	webhookUseCase := application.NewWebhookUseCase(webhookSubscriptionRepo, webhookDeliveryRepo)
+
+	webhookDispatcher := application.NewWebhookDispatcher(webhookSubscriptionRepo, webhookDeliveryRepo,
+		webhook.NewHTTPSender(time.Duration(cfg.Webhooks.Timeout)*time.Second), cfg.Webhooks)
+	webhookDispatcher.Start()
+
+	userHandler := handlers.NewUserHandler(userUseCase, notificationUseCase, mailQueue, webhookUseCase)
 
 	user.RegisterUserServiceServer(grpcServer, userHandler)
 
+	if consumer != nil {
+		if err := consumer.SubscribeToOrderEvents(notificationUseCase.HandleOrderEvent); err != nil {
+			log.Printf("Warning: failed to subscribe to order events: %v. Notifications are disabled.", err)
+		}
+		if err := consumer.SubscribeToRawEvents("webhooks", domain.WebhookEventTypes, webhookUseCase.HandleEvent); err != nil {
+			log.Printf("Warning: failed to subscribe to webhook events: %v. Webhooks are disabled.", err)
+		}
+	}
+
 	return &Services{
-		RedisCache:  redisCache,
-		MailService: mailService,
+		RedisCache:        redisCache,
+		MailQueue:         mailQueue,
+		WebhookDispatcher: webhookDispatcher,
+	}
+}
+
+func newSMSProvider(cfg *config.Config) notify.SMSProvider {
+	if cfg.SMS.GatewayURL == "" {
+		log.Println("Warning: SMS gateway not provided. SMS notifications will be disabled.")
+		return nil
+	}
+	return notify.NewHTTPSMSProvider(cfg.SMS.GatewayURL, cfg.SMS.APIKey, cfg.SMS.Sender,
+		time.Duration(cfg.SMS.Timeout)*time.Second)
+}
+
+func newPushProvider(cfg *config.Config) notify.PushProvider {
+	if cfg.Push.GatewayURL == "" {
+		log.Println("Warning: push gateway not provided. Push notifications will be disabled.")
+		return nil
 	}
+	return notify.NewHTTPPushProvider(cfg.Push.GatewayURL, cfg.Push.APIKey,
+		time.Duration(cfg.Push.Timeout)*time.Second)
 }
 
 func RegisterGRPCServicesWithInMemoryDB(grpcServer *grpc.Server, db *database.InMemoryDB) *Services {
@@ -55,12 +106,13 @@ func RegisterGRPCServicesWithInMemoryDB(grpcServer *grpc.Server, db *database.In
 
 	userUseCase := application.NewUserUseCase(userRepo, nil, nil)
 
-	userHandler := handlers.NewUserHandler(userUseCase)
+	userHandler := handlers.NewUserHandler(userUseCase, nil, nil, nil)
 
 	user.RegisterUserServiceServer(grpcServer, userHandler)
 
 	return &Services{
-		RedisCache:  nil,
-		MailService: nil,
+		RedisCache:        nil,
+		MailQueue:         nil,
+		WebhookDispatcher: nil,
 	}
 }